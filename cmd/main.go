@@ -1,56 +1,61 @@
 package main
 
 import (
-	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
-	"os/signal"
-	"time"
 
 	"github.com/joho/godotenv"
 
 	"github.com/akosgarai/projectregister/pkg/application"
+	"github.com/akosgarai/projectregister/pkg/cli"
+	"github.com/akosgarai/projectregister/pkg/config"
 )
 
 var (
-	wait       time.Duration = 15 * time.Second
-	dotEnvFile               = ".env"
+	dotEnvFile = ".env"
 )
 
 func main() {
-	dotenvConfig := make(map[string]string)
+	args := os.Args[1:]
+	if len(args) > 0 && (args[0] == "help" || args[0] == "-h" || args[0] == "--help") {
+		fmt.Print(cli.Usage)
+		return
+	}
 	dotenvConfig, err := godotenv.Read(dotEnvFile)
 	if err != nil {
 		log.Println("Error loading .env file")
 	}
-	// load .env file
-	app := application.New(dotenvConfig)
-	app.Initialize()
-
-	// Run our server in a goroutine so that it doesn't block.
-	go func() {
-		if err := app.Run(); err != nil {
-			log.Println(err)
+	// The config file, the .env file and the process environment are merged.
+	envConfig, err := config.Load(dotenvConfig, os.Environ())
+	if err != nil {
+		log.Fatalf("invalid configuration:\n%v", err)
+	}
+	// Without arguments the server is started.
+	if len(args) == 0 || args[0] == cli.CommandServe {
+		serve(envConfig)
+		return
+	}
+	// The other commands are the management commands.
+	if err := cli.New(config.NewEnvironment(envConfig), os.Stdin, os.Stdout).Run(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if errors.Is(err, cli.ErrUsage) {
+			fmt.Fprint(os.Stderr, cli.Usage)
+			os.Exit(2)
 		}
-	}()
-
-	c := make(chan os.Signal, 1)
-	// We'll accept graceful shutdowns when quit via SIGINT (Ctrl+C)
-	// SIGKILL, SIGQUIT or SIGTERM (Ctrl+/) will not be caught.
-	signal.Notify(c, os.Interrupt)
-
-	// Block until we receive our signal.
-	<-c
+		os.Exit(1)
+	}
+}
 
-	// Create a deadline to wait for.
-	ctx, cancel := context.WithTimeout(context.Background(), wait)
-	defer cancel()
-	// Doesn't block if no connections, but will otherwise wait
-	// until the timeout deadline.
-	app.Server.Shutdown(ctx)
-	// Optionally, you could run srv.Shutdown in a goroutine and block on
-	// <-ctx.Done() if your application should wait for other services
-	// to finalize based on context cancellation.
-	log.Println("shutting down")
-	os.Exit(0)
+// serve starts the server, and stops it gracefully on the SIGINT or the SIGTERM signal.
+func serve(envConfig map[string]string) {
+	app := application.New(envConfig)
+	if err := app.Initialize(); err != nil {
+		log.Fatalln(err)
+	}
+	if err := app.Serve(); err != nil {
+		log.Fatalln(err)
+	}
+	log.Println("shut down")
 }
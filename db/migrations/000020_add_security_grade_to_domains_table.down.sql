@@ -0,0 +1,3 @@
+ALTER TABLE domains DROP COLUMN security_findings;
+ALTER TABLE domains DROP COLUMN security_grade;
+ALTER TABLE domains DROP COLUMN security_score;
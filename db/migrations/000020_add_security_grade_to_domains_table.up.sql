@@ -0,0 +1,4 @@
+-- Add the security audit result columns to the domains table.
+ALTER TABLE domains ADD COLUMN security_score INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE domains ADD COLUMN security_grade VARCHAR(2) NOT NULL DEFAULT '';
+ALTER TABLE domains ADD COLUMN security_findings TEXT NOT NULL DEFAULT '';
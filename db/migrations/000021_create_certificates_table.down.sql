@@ -0,0 +1,6 @@
+DROP TABLE certificate_to_domains;
+DROP TABLE certificates;
+
+ALTER TABLE domains DROP COLUMN live_certificate_fingerprint;
+
+DELETE FROM resources WHERE name IN ('certificates.view', 'certificates.create', 'certificates.update', 'certificates.delete');
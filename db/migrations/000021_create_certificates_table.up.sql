@@ -0,0 +1,35 @@
+CREATE TABLE certificates (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	common_name VARCHAR(255) NOT NULL DEFAULT '',
+	issuer TEXT NOT NULL DEFAULT '',
+	serial_number VARCHAR(255) NOT NULL DEFAULT '',
+	fingerprint VARCHAR(64) NOT NULL,
+	dns_names TEXT NOT NULL DEFAULT '',
+	not_before TIMESTAMP WITH TIME ZONE NOT NULL,
+	not_after TIMESTAMP WITH TIME ZONE NOT NULL,
+	pem TEXT NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX certificates_fingerprint_unique ON certificates (fingerprint);
+
+CREATE TABLE certificate_to_domains (
+	certificate_id INT NOT NULL,
+	domain_id INT NOT NULL,
+	PRIMARY KEY (certificate_id, domain_id),
+	FOREIGN KEY (certificate_id) REFERENCES certificates (id),
+	FOREIGN KEY (domain_id) REFERENCES domains (id) ON DELETE CASCADE
+);
+
+-- The fingerprint of the certificate that is served by the domain.
+-- It is set by the ssl check.
+ALTER TABLE domains ADD COLUMN live_certificate_fingerprint VARCHAR(64) NOT NULL DEFAULT '';
+
+INSERT INTO resources (name) VALUES ('certificates.view'), ('certificates.create'), ('certificates.update'), ('certificates.delete');
+
+-- Add the new resources to the admin role
+WITH admin_role_id AS (SELECT id FROM roles WHERE name = 'admin')
+	INSERT INTO role_to_resources (role_id, resource_id)
+		SELECT admin_role_id.id, resources.id FROM resources, admin_role_id WHERE resources.name IN ('certificates.view', 'certificates.create', 'certificates.update', 'certificates.delete');
@@ -0,0 +1 @@
+DROP TABLE dns_records;
@@ -0,0 +1,14 @@
+CREATE TABLE dns_records (
+	id SERIAL PRIMARY KEY,
+	domain_id INT NOT NULL,
+	name VARCHAR(255) NOT NULL DEFAULT '@',
+	type VARCHAR(10) NOT NULL,
+	ttl INTEGER NOT NULL DEFAULT 3600,
+	priority INTEGER NOT NULL DEFAULT 0,
+	value TEXT NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (domain_id) REFERENCES domains (id) ON DELETE CASCADE
+);
+
+CREATE INDEX dns_records_domain_id_index ON dns_records (domain_id);
@@ -0,0 +1,7 @@
+DROP INDEX domains_expires_at_index;
+
+ALTER TABLE domains DROP COLUMN billing_client_id;
+ALTER TABLE domains DROP COLUMN auto_renew;
+ALTER TABLE domains DROP COLUMN expires_at;
+ALTER TABLE domains DROP COLUMN registered_at;
+ALTER TABLE domains DROP COLUMN registrar;
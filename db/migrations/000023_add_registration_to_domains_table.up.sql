@@ -0,0 +1,8 @@
+ALTER TABLE domains ADD COLUMN registrar VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE domains ADD COLUMN registered_at DATE;
+ALTER TABLE domains ADD COLUMN expires_at DATE;
+ALTER TABLE domains ADD COLUMN auto_renew BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE domains ADD COLUMN billing_client_id INT;
+ALTER TABLE domains ADD FOREIGN KEY (billing_client_id) REFERENCES clients (id) ON DELETE SET NULL;
+
+CREATE INDEX domains_expires_at_index ON domains (expires_at);
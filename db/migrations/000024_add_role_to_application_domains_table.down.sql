@@ -0,0 +1,6 @@
+DROP INDEX application_to_domains_primary_index;
+
+ALTER TABLE application_to_domains DROP CONSTRAINT application_to_domains_role_check;
+ALTER TABLE application_to_domains DROP COLUMN redirect_status;
+ALTER TABLE application_to_domains DROP COLUMN redirect_target;
+ALTER TABLE application_to_domains DROP COLUMN role;
@@ -0,0 +1,11 @@
+ALTER TABLE application_to_domains ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'alias';
+ALTER TABLE application_to_domains ADD COLUMN redirect_target VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE application_to_domains ADD COLUMN redirect_status INT NOT NULL DEFAULT 0;
+ALTER TABLE application_to_domains ADD CONSTRAINT application_to_domains_role_check CHECK (role IN ('primary', 'alias', 'redirect'));
+
+-- The first domain of the existing applications is the primary one.
+UPDATE application_to_domains SET role = 'primary'
+	WHERE (application_id, domain_id) IN (SELECT application_id, MIN(domain_id) FROM application_to_domains GROUP BY application_id);
+
+-- An application can have only one primary domain.
+CREATE UNIQUE INDEX application_to_domains_primary_index ON application_to_domains (application_id) WHERE role = 'primary';
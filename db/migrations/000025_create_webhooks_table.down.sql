@@ -0,0 +1,4 @@
+DROP TABLE webhook_deliveries;
+DROP TABLE webhooks;
+
+DELETE FROM resources WHERE name IN ('webhooks.view', 'webhooks.create', 'webhooks.update', 'webhooks.delete');
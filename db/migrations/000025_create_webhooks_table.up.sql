@@ -0,0 +1,34 @@
+CREATE TABLE webhooks (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	url TEXT NOT NULL,
+	secret VARCHAR(255) NOT NULL DEFAULT '',
+	event_types TEXT NOT NULL DEFAULT '',
+	active BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Every delivery attempt is logged with the response status.
+CREATE TABLE webhook_deliveries (
+	id SERIAL PRIMARY KEY,
+	webhook_id INT NOT NULL,
+	event_id VARCHAR(64) NOT NULL,
+	event_type VARCHAR(64) NOT NULL,
+	payload TEXT NOT NULL,
+	attempt INT NOT NULL DEFAULT 1,
+	status_code INT NOT NULL DEFAULT 0,
+	error TEXT NOT NULL DEFAULT '',
+	duration_ms BIGINT NOT NULL DEFAULT 0,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE
+);
+
+CREATE INDEX webhook_deliveries_webhook_id_index ON webhook_deliveries (webhook_id, created_at);
+
+INSERT INTO resources (name) VALUES ('webhooks.view'), ('webhooks.create'), ('webhooks.update'), ('webhooks.delete');
+
+-- Add the new resources to the admin role
+WITH admin_role_id AS (SELECT id FROM roles WHERE name = 'admin')
+	INSERT INTO role_to_resources (role_id, resource_id)
+		SELECT admin_role_id.id, resources.id FROM resources, admin_role_id WHERE resources.name IN ('webhooks.view', 'webhooks.create', 'webhooks.update', 'webhooks.delete');
@@ -0,0 +1,4 @@
+DROP TABLE notification_deliveries;
+DROP TABLE notification_subscriptions;
+
+ALTER TABLE domains DROP COLUMN ssl_checked_at;
@@ -0,0 +1,24 @@
+-- The time of the latest ssl check, it is null until the first check.
+ALTER TABLE domains ADD COLUMN ssl_checked_at TIMESTAMP NULL;
+
+CREATE TABLE notification_subscriptions (
+	id SERIAL PRIMARY KEY,
+	user_id INT NOT NULL,
+	rule VARCHAR(64) NOT NULL,
+	threshold INT NOT NULL DEFAULT 0,
+	digest BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+	UNIQUE (user_id, rule)
+);
+
+-- The already notified alerts and the sent digests of the users.
+CREATE TABLE notification_deliveries (
+	id SERIAL PRIMARY KEY,
+	user_id INT NOT NULL,
+	delivery_key VARCHAR(255) NOT NULL,
+	sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+	UNIQUE (user_id, delivery_key)
+);
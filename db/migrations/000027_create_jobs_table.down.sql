@@ -0,0 +1,3 @@
+DROP TABLE jobs;
+
+DELETE FROM resources WHERE name IN ('jobs.view', 'jobs.update');
@@ -0,0 +1,27 @@
+-- The background jobs, they are claimed by the workers with SELECT ... FOR UPDATE SKIP LOCKED.
+CREATE TABLE jobs (
+	id SERIAL PRIMARY KEY,
+	type VARCHAR(64) NOT NULL,
+	payload TEXT NOT NULL DEFAULT '',
+	status VARCHAR(16) NOT NULL DEFAULT 'queued',
+	progress INT NOT NULL DEFAULT 0,
+	total INT NOT NULL DEFAULT 0,
+	log TEXT NOT NULL DEFAULT '',
+	error TEXT NOT NULL DEFAULT '',
+	attempts INT NOT NULL DEFAULT 0,
+	created_by INT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	started_at TIMESTAMP NULL,
+	finished_at TIMESTAMP NULL,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL
+);
+
+CREATE INDEX jobs_status_index ON jobs (status, id);
+
+INSERT INTO resources (name) VALUES ('jobs.view'), ('jobs.update');
+
+-- Add the new resources to the admin role
+WITH admin_role_id AS (SELECT id FROM roles WHERE name = 'admin')
+	INSERT INTO role_to_resources (role_id, resource_id)
+		SELECT admin_role_id.id, resources.id FROM resources, admin_role_id WHERE resources.name IN ('jobs.view', 'jobs.update');
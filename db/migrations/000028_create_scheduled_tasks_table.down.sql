@@ -0,0 +1,4 @@
+DROP TABLE statistics_snapshots;
+DROP TABLE scheduled_tasks;
+
+DELETE FROM resources WHERE name IN ('scheduled_tasks.view', 'scheduled_tasks.update');
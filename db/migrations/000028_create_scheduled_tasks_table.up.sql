@@ -0,0 +1,43 @@
+-- The periodic maintenance tasks. The runs are guarded by advisory locks, so that a task runs
+-- only on one instance, and the next_run_at is moved forward on start.
+CREATE TABLE scheduled_tasks (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(64) NOT NULL UNIQUE,
+	description TEXT NOT NULL DEFAULT '',
+	schedule VARCHAR(255) NOT NULL,
+	enabled BOOLEAN NOT NULL DEFAULT TRUE,
+	last_run_at TIMESTAMP NULL,
+	last_status VARCHAR(16) NOT NULL DEFAULT '',
+	last_message TEXT NOT NULL DEFAULT '',
+	last_duration_ms BIGINT NOT NULL DEFAULT 0,
+	next_run_at TIMESTAMP NULL,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO scheduled_tasks (name, description, schedule) VALUES
+	('domain_checks', 'Queues the ssl and the security checks of every domain.', '0 3 * * *'),
+	('upload_cleanup', 'Deletes the uploaded files that are older than a day.', '30 * * * *'),
+	('expiry_notifications', 'Sends the certificate, domain and application alerts to the subscribed users. It needs the smtp settings.', '*/15 * * * *'),
+	('statistics_snapshot', 'Stores the inventory counts.', '0 0 * * *');
+
+-- The inventory counts of the statistics snapshot task.
+CREATE TABLE statistics_snapshots (
+	id SERIAL PRIMARY KEY,
+	clients INT NOT NULL DEFAULT 0,
+	projects INT NOT NULL DEFAULT 0,
+	environments INT NOT NULL DEFAULT 0,
+	applications INT NOT NULL DEFAULT 0,
+	domains INT NOT NULL DEFAULT 0,
+	domains_without_ssl INT NOT NULL DEFAULT 0,
+	servers INT NOT NULL DEFAULT 0,
+	databases INT NOT NULL DEFAULT 0,
+	certificates INT NOT NULL DEFAULT 0,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO resources (name) VALUES ('scheduled_tasks.view'), ('scheduled_tasks.update');
+
+-- Add the new resources to the admin role
+WITH admin_role_id AS (SELECT id FROM roles WHERE name = 'admin')
+	INSERT INTO role_to_resources (role_id, resource_id)
+		SELECT admin_role_id.id, resources.id FROM resources, admin_role_id WHERE resources.name IN ('scheduled_tasks.view', 'scheduled_tasks.update');
@@ -0,0 +1 @@
+UPDATE scheduled_tasks SET description = 'Deletes the uploaded files that are older than a day.' WHERE name = 'upload_cleanup';
@@ -0,0 +1,2 @@
+-- The running jobs are owned by their worker until the lease expires, the jobs of the crashed workers are claimed again.
+ALTER TABLE jobs ADD COLUMN lease_expires_at TIMESTAMP NULL;
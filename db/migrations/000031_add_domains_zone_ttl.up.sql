@@ -0,0 +1,2 @@
+-- The default ttl ($TTL) of the zone of the domain, it is set by the zone import and written by the zone export.
+ALTER TABLE domains ADD COLUMN zone_ttl INT NOT NULL DEFAULT 3600;
@@ -1,27 +1,56 @@
 package application
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 
+	"github.com/akosgarai/projectregister/pkg/certificate"
 	"github.com/akosgarai/projectregister/pkg/config"
 	"github.com/akosgarai/projectregister/pkg/database"
 	"github.com/akosgarai/projectregister/pkg/database/repository"
+	"github.com/akosgarai/projectregister/pkg/event"
+	"github.com/akosgarai/projectregister/pkg/health"
+	"github.com/akosgarai/projectregister/pkg/jobqueue"
+	"github.com/akosgarai/projectregister/pkg/mailer"
+	"github.com/akosgarai/projectregister/pkg/metrics"
+	"github.com/akosgarai/projectregister/pkg/model"
+	"github.com/akosgarai/projectregister/pkg/notification"
+	"github.com/akosgarai/projectregister/pkg/rdap"
 	"github.com/akosgarai/projectregister/pkg/render"
 	"github.com/akosgarai/projectregister/pkg/router"
+	"github.com/akosgarai/projectregister/pkg/scheduler"
 	"github.com/akosgarai/projectregister/pkg/session"
 	"github.com/akosgarai/projectregister/pkg/storage"
+	"github.com/akosgarai/projectregister/pkg/webhook"
+	"github.com/akosgarai/projectregister/web"
 )
 
+// certificateReloadInterval is the interval of the certificate file checks.
+const certificateReloadInterval = time.Minute
+
 // App is a struct that holds the application configuration.
 type App struct {
-	envConfig *config.Environment
-	db        *database.DB
+	envConfig  *config.Environment
+	db         *database.DB
+	dispatcher *webhook.Dispatcher
+	// certificates is set if the server is started with https.
+	certificates *certificate.Reloader
+	// workers are the background workers by name.
+	workers map[string]Worker
 
 	Server *http.Server
-	Router *mux.Router
+	// RedirectServer redirects the http requests to the https server. It is nil if the redirect is disabled.
+	RedirectServer *http.Server
+	Router         *mux.Router
 }
 
 // New creates a new instance of the application.
@@ -31,26 +60,139 @@ func New(envConfig map[string]string) *App {
 	}
 }
 
-// Initialize initializes the application, runs the database migrations, and sets up the routes.
-func (a *App) Initialize() {
+// Initialize initializes the application, runs the database migrations if the auto migration is enabled, and sets up the routes.
+// It returns an error if the configuration is invalid, the migrations fail or the database is not reachable.
+func (a *App) Initialize() error {
+	if err := a.envConfig.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
+	}
 	// execute the migrations
-	a.executeMigrations()
+	if a.envConfig.GetAutoMigrate() {
+		if err := database.NewMigration(a.envConfig).Up(); err != nil {
+			return fmt.Errorf("failed to execute the migrations: %w", err)
+		}
+	}
 	// create a new database
 	a.db = database.NewDB(a.envConfig)
 	// connect to the database
-	err := a.db.Connect()
+	if err := a.db.Connect(); err != nil {
+		return fmt.Errorf("failed to connect to the database: %w", err)
+	}
+	// The uploaded files are stored in the configured blob storage, so that they could be shared between the instances.
+	blobStorage, err := storage.NewBlobStorage(a.envConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create the storage: %w", err)
+	}
+	csvFileStorage := storage.NewCSVFileStorage(a.envConfig, blobStorage)
+	// The embedded templates are used, unless the assets are loaded from the disk for development.
+	templates := render.NewTemplates()
+	templateFiles := web.Templates()
+	if a.envConfig.GetAssetsFromDisk() {
+		templateFiles = os.DirFS(a.envConfig.GetRenderTemplateDirectoryPath())
+		templates = render.NewTemplatesFS(templateFiles, true)
+	}
+	renderer := render.NewRenderer(a.envConfig, templates)
+	// The repositories publish the resource changes to the event bus,
+	// the webhook dispatcher sends them to the subscribed webhooks.
+	events := event.NewBus()
+	repositoryContainer := repository.NewContainerRepository(a.db, events)
+	logger := renderer.GetLogger()
+	// The standard logger is also redirected to the structured logger.
+	slog.SetDefault(logger)
+	a.dispatcher = webhook.NewDispatcher(
+		repositoryContainer.GetWebhookRepository(),
+		repositoryContainer.GetWebhookDeliveryRepository(),
+		nil,
+		logger,
+	)
+	events.Subscribe(a.dispatcher.Handle)
+	// The email notifications are disabled if the smtp host is not set.
+	var notifier *notification.Notifier
+	if a.envConfig.GetSMTPHost() != "" {
+		smtpMailer := mailer.NewSMTPMailer(
+			a.envConfig.GetSMTPHost(),
+			a.envConfig.GetSMTPPort(),
+			a.envConfig.GetSMTPUsername(),
+			a.envConfig.GetSMTPPassword(),
+			a.envConfig.GetSMTPFrom(),
+		)
+		notifier = notification.NewNotifier(
+			repositoryContainer,
+			smtpMailer,
+			notification.NewTemplates(templateFiles),
+			a.envConfig.GetPublicURL(),
+			a.envConfig.GetNotificationDigestHour(),
+			logger,
+		)
+	}
+	// The registration lookup is disabled if the rdap base url is not set.
+	var registrationLookup rdap.Lookup
+	if a.envConfig.GetRDAPBaseURL() != "" {
+		registrationLookup = rdap.NewClient(a.envConfig.GetRDAPBaseURL(), 10*time.Second)
+	}
+	sessionStore := session.NewStore(a.envConfig)
+	// The metrics registry collects the pool, the session and the inventory metrics on every scrape,
+	// the http metrics are registered by the router.
+	metricsRegistry := metrics.NewRegistry()
+	metricsRegistry.Register(metrics.NewDBStatsCollector(a.db.Stats))
+	metricsRegistry.Register(metrics.NewGaugeFunc("active_sessions", "The number of the active user sessions.", func() float64 {
+		return float64(sessionStore.Count())
+	}))
+	metricsRegistry.Register(metrics.NewBusinessCollector(repositoryContainer, a.envConfig.GetMetricsScoreThreshold()))
+	// The readiness checks the database connection, the migration version and the upload directory or the bucket.
+	healthChecker := health.NewChecker(time.Second * time.Duration(a.envConfig.GetHealthCheckTimeout()))
+	healthChecker.Add("database", a.db.Ping)
+	latestMigration, err := database.NewMigration(a.envConfig).LatestVersion()
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("failed to read the migrations: %w", err)
+	}
+	healthChecker.Add("migrations", health.MigrationVersion(a.db.MigrationVersion, latestMigration))
+	// The upload directory is used only by the local storage, the s3 storage is checked with listing the uploaded files.
+	if strings.EqualFold(a.envConfig.GetStorageBackend(), config.StorageBackendS3) {
+		healthChecker.Add("upload_storage", csvFileStorage.Check)
+	} else {
+		healthChecker.Add("upload_directory", health.DirectoryWritable(a.envConfig.GetUploadDirectoryPath()))
+	}
+	// The long operations run in the background jobs, the handlers are registered by the router.
+	jobQueue := jobqueue.NewQueue(repositoryContainer.GetJobRepository(), logger)
+	jobPollInterval := time.Second * time.Duration(a.envConfig.GetJobPollInterval())
+	for i := int64(1); i <= a.envConfig.GetJobWorkers(); i++ {
+		a.AddWorker(fmt.Sprintf("job_worker_%d", i), func(ctx context.Context) {
+			jobQueue.Work(ctx, jobPollInterval)
+		})
+	}
+	// The periodic maintenance tasks are executed by the scheduler. The advisory locks of the database
+	// guarantee that a task runs only on one instance.
+	taskScheduler := scheduler.NewScheduler(repositoryContainer.GetScheduledTaskRepository(), a.db.WithAdvisoryLock, logger)
+	taskScheduler.Register(model.ScheduledTaskDomainChecks, domainChecksTask(repositoryContainer, jobQueue))
+	taskScheduler.Register(model.ScheduledTaskUploadCleanup, uploadCleanupTask(csvFileStorage, repositoryContainer.GetJobRepository(), time.Duration(a.envConfig.GetUploadRetention())*time.Hour))
+	taskScheduler.Register(model.ScheduledTaskStatisticsSnapshot, statisticsSnapshotTask(repositoryContainer))
+	if notifier != nil {
+		taskScheduler.Register(model.ScheduledTaskExpiryNotifications, notificationsTask(notifier))
+	}
+	// The deprecated notification check interval overrides the schedule of the notification task.
+	if schedule := a.envConfig.GetNotificationCheckSchedule(); schedule != "" {
+		logger.Warn("config: "+config.NotificationCheckIntervalEnvName+" is deprecated, set the schedule of the "+model.ScheduledTaskExpiryNotifications+" task instead",
+			"schedule", schedule)
+		if err := overrideTaskSchedule(repositoryContainer.GetScheduledTaskRepository(), model.ScheduledTaskExpiryNotifications, schedule, time.Now()); err != nil {
+			return fmt.Errorf("failed to override the notification schedule: %w", err)
+		}
 	}
-	// Create a csv file storage.
-	csvFileStorage := storage.NewCSVFileStorage(a.envConfig)
+	a.AddWorker("scheduler", func(ctx context.Context) {
+		taskScheduler.Run(ctx, schedulerCheckInterval)
+	})
 	// create a new router
-	repositoryContainer := repository.NewContainerRepository(a.db)
 	a.Router = router.New(
 		repositoryContainer,
-		session.NewStore(a.envConfig),
+		sessionStore,
 		csvFileStorage,
-		render.NewRenderer(a.envConfig, render.NewTemplates()),
+		renderer,
+		registrationLookup,
+		a.envConfig.GetCalendarFeedToken(),
+		metricsRegistry,
+		a.envConfig.GetMetricsToken(),
+		healthChecker,
+		jobQueue,
 	)
 	// create a new server
 	a.Server = &http.Server{
@@ -61,21 +203,62 @@ func (a *App) Initialize() {
 		IdleTimeout:  time.Second * time.Duration(a.envConfig.GetServerIdleTimeout()),
 		Handler:      a.Router, // Pass our instance of gorilla/mux in.
 	}
+	if a.envConfig.GetTLSEnabled() {
+		if err := a.initializeTLS(logger); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// Run starts the application. Returns an error if the server fails to start.
-func (a *App) Run() error {
-	if err := a.Server.ListenAndServe(); err != nil {
+// initializeTLS loads the certificate, and sets up its reload and the optional http to https redirect server.
+func (a *App) initializeTLS(logger *slog.Logger) error {
+	certificates, err := certificate.NewReloader(a.envConfig.GetTLSCertFile(), a.envConfig.GetTLSKeyFile(), logger)
+	if err != nil {
 		return err
 	}
+	a.certificates = certificates
+	a.AddWorker("certificate_reloader", func(ctx context.Context) {
+		certificates.Watch(ctx, certificateReloadInterval)
+	})
+	a.Server.TLSConfig = &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: a.certificates.GetCertificate,
+	}
+	if a.envConfig.GetTLSRedirectPort() != "" {
+		a.RedirectServer = &http.Server{
+			Addr:         a.envConfig.GetServerAddr() + ":" + a.envConfig.GetTLSRedirectPort(),
+			WriteTimeout: a.Server.WriteTimeout,
+			ReadTimeout:  a.Server.ReadTimeout,
+			IdleTimeout:  a.Server.IdleTimeout,
+			Handler:      router.RedirectHTTPSHandler(a.envConfig.GetServerPort()),
+		}
+	}
 	return nil
 }
 
-// execute the migrations
-func (a *App) executeMigrations() {
-	migration := database.NewMigration(a.envConfig)
-	err := migration.Up()
-	if err != nil {
-		panic(err)
+// Run starts the application. Returns an error if the server fails to start.
+// With https the redirect server is started in the background, its failure is logged.
+func (a *App) Run() error {
+	if a.certificates == nil {
+		return a.Server.ListenAndServe()
+	}
+	if a.RedirectServer != nil {
+		go func() {
+			if err := a.RedirectServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("the https redirect server failed", "error", err)
+			}
+		}()
+	}
+	// The certificate is served by the tls config, so that the files are not needed here.
+	return a.Server.ListenAndServeTLS("", "")
+}
+
+// Shutdown gracefully stops the servers.
+func (a *App) Shutdown(ctx context.Context) error {
+	var redirectErr error
+	if a.RedirectServer != nil {
+		redirectErr = a.RedirectServer.Shutdown(ctx)
 	}
+	return errors.Join(redirectErr, a.Server.Shutdown(ctx))
 }
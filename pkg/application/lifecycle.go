@@ -0,0 +1,132 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Worker is a background task of the application, eg. a scheduler or a checker.
+// It has to return when the context is cancelled.
+type Worker func(ctx context.Context)
+
+// AddWorker registers a background worker. The workers are started with the server,
+// and they are cancelled after the http connections are drained.
+func (a *App) AddWorker(name string, worker Worker) {
+	if a.workers == nil {
+		a.workers = make(map[string]Worker)
+	}
+	a.workers[name] = worker
+}
+
+// Serve starts the server and the background workers, and blocks until the SIGINT or the SIGTERM signal,
+// or the failure of the server. Then the application is stopped gracefully within the shutdown timeout.
+func (a *App) Serve() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return a.serve(ctx, time.Second*time.Duration(a.envConfig.GetShutdownTimeout()))
+}
+
+// serve runs the application until the context is done, then it stops the application.
+// The http connections are drained first, then the workers are cancelled, and the database is closed at the end,
+// so that the running requests and workers could still use it. The database is not closed if a worker missed the deadline.
+func (a *App) serve(ctx context.Context, shutdownTimeout time.Duration) error {
+	workerCtx, cancelWorkers := context.WithCancel(context.Background())
+	defer cancelWorkers()
+	running := newRunningSet()
+	for name, worker := range a.workers {
+		running.start(name, func() { worker(workerCtx) })
+	}
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- a.Run()
+	}()
+
+	var errs []error
+	select {
+	case <-ctx.Done():
+		slog.Info("shutting down")
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			errs = append(errs, fmt.Errorf("the server failed: %w", err))
+		}
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := a.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to drain the connections: %w", err))
+	}
+	cancelWorkers()
+	// The running webhook deliveries are also waited for, their retries are stopped.
+	if a.dispatcher != nil {
+		running.start("webhook_deliveries", a.dispatcher.Stop)
+	}
+	if err := running.wait(shutdownCtx); err != nil {
+		// the workers that missed the deadline could still use the database, so that it is left to the process exit.
+		slog.Error("the database is not closed, as the workers did not stop in time", "error", err)
+		errs = append(errs, err)
+	} else if a.db != nil {
+		if err := a.db.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close the database: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runningSet tracks the running goroutines by name, so that the ones that miss the deadline could be reported.
+type runningSet struct {
+	mu    sync.Mutex
+	names map[string]struct{}
+	wg    sync.WaitGroup
+}
+
+// newRunningSet creates an empty runningSet.
+func newRunningSet() *runningSet {
+	return &runningSet{names: make(map[string]struct{})}
+}
+
+// start runs the function in a new goroutine.
+func (r *runningSet) start(name string, fn func()) {
+	r.mu.Lock()
+	r.names[name] = struct{}{}
+	r.mu.Unlock()
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		fn()
+		r.mu.Lock()
+		delete(r.names, name)
+		r.mu.Unlock()
+	}()
+}
+
+// wait waits for the goroutines until the context is done. It returns an error with the names of the still running ones.
+func (r *runningSet) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.names))
+	for name := range r.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("the workers did not stop in time: %v", names)
+}
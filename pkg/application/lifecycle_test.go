@@ -0,0 +1,74 @@
+package application
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/config"
+)
+
+// newTestApp returns an application with a server on a random local port.
+func newTestApp() *App {
+	return &App{
+		envConfig: config.DefaultEnvironment(),
+		Server:    &http.Server{Addr: "127.0.0.1:0", Handler: http.NotFoundHandler()},
+	}
+}
+
+// TestServeStopsWorkers tests that the workers are started, and they are cancelled on shutdown.
+func TestServeStopsWorkers(t *testing.T) {
+	app := newTestApp()
+	started := make(chan struct{})
+	stopped := make(chan struct{})
+	app.AddWorker("test", func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		close(stopped)
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- app.serve(ctx, time.Second)
+	}()
+	<-started
+	cancel()
+	if err := <-result; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	select {
+	case <-stopped:
+	default:
+		t.Error("The worker is not stopped.")
+	}
+}
+
+// TestServeShutdownDeadline tests that the workers that miss the deadline are reported.
+func TestServeShutdownDeadline(t *testing.T) {
+	app := newTestApp()
+	block := make(chan struct{})
+	defer close(block)
+	app.AddWorker("stuck", func(ctx context.Context) {
+		<-block
+	})
+	app.AddWorker("polite", func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := app.serve(ctx, 50*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "[stuck]") {
+		t.Errorf("Expected the stuck worker in the error, got %v", err)
+	}
+}
+
+// TestServeServerFailure tests that the server failure stops the application.
+func TestServeServerFailure(t *testing.T) {
+	app := newTestApp()
+	app.Server.Addr = "invalid-address"
+	if err := app.serve(context.Background(), time.Second); err == nil || !strings.Contains(err.Error(), "the server failed") {
+		t.Errorf("Expected server failure, got %v", err)
+	}
+}
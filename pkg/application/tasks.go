@@ -0,0 +1,134 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/jobqueue"
+	"github.com/akosgarai/projectregister/pkg/model"
+	"github.com/akosgarai/projectregister/pkg/notification"
+	"github.com/akosgarai/projectregister/pkg/scheduler"
+	"github.com/akosgarai/projectregister/pkg/storage"
+)
+
+// schedulerCheckInterval is the interval of the due task checks.
+const schedulerCheckInterval = 30 * time.Second
+
+// domainChecksTask queues a domain check job with the ssl and the security checks of every domain.
+func domainChecksTask(repositoryContainer model.RepositoryContainer, jobQueue *jobqueue.Queue) scheduler.Task {
+	return func(ctx context.Context) (string, error) {
+		domains, err := repositoryContainer.GetDomainRepository().GetDomains(model.NewDomainFilter())
+		if err != nil {
+			return "", err
+		}
+		if len(*domains) == 0 {
+			return "there is no domain to check", nil
+		}
+		payload := &model.DomainCheckPayload{SSL: true, Security: true}
+		for _, domain := range *domains {
+			payload.DomainIDs = append(payload.DomainIDs, domain.ID)
+		}
+		// the job is created by the system.
+		job, err := jobQueue.Enqueue(model.JobTypeDomainCheck, payload, 0)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("job %d is queued for %d domains", job.ID, len(payload.DomainIDs)), nil
+	}
+}
+
+// uploadCleanupTask deletes the uploaded files that are older than the upload retention.
+// The files of the import jobs that are not succeeded are kept, as the queued, the running and the retried jobs read them.
+func uploadCleanupTask(uploads *storage.CSVFileStorage, jobs model.JobRepository, retention time.Duration) scheduler.Task {
+	return func(ctx context.Context) (string, error) {
+		keep, err := importJobFiles(jobs)
+		if err != nil {
+			return "", err
+		}
+		deleted, err := uploads.DeleteOlderThan(ctx, time.Now().Add(-retention), keep)
+		if err != nil {
+			return "", fmt.Errorf("%d files are deleted before the failure: %w", deleted, err)
+		}
+		return fmt.Sprintf("%d files are deleted, the files of %d unfinished imports are kept", deleted, len(keep)), nil
+	}
+}
+
+// importJobFiles returns the uploaded files of the import jobs that are not succeeded.
+// The succeeded jobs delete their file, the others could be still running or retried.
+func importJobFiles(jobs model.JobRepository) ([]storage.UploadedFile, error) {
+	files := []storage.UploadedFile{}
+	for _, jobType := range []string{model.JobTypeApplicationImport, model.JobTypeResourceImport} {
+		filter := model.NewJobFilter()
+		filter.Type = jobType
+		importJobs, err := jobs.GetJobs(filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, job := range *importJobs {
+			if job.Status == model.JobStatusSucceeded {
+				continue
+			}
+			// the payload of both import job types contains the id of the uploaded file.
+			var payload struct {
+				FileID string `json:"file_id"`
+			}
+			if err := jobqueue.DecodePayload(job, &payload); err != nil {
+				return nil, fmt.Errorf("job %d: %w", job.ID, err)
+			}
+			files = append(files, storage.UploadedFile{OwnerID: job.CreatedBy, FileID: payload.FileID})
+		}
+	}
+	return files, nil
+}
+
+// notificationsTask evaluates the notification rules and sends the emails.
+func notificationsTask(notifier *notification.Notifier) scheduler.Task {
+	return func(ctx context.Context) (string, error) {
+		if err := notifier.Run(time.Now()); err != nil {
+			return "", err
+		}
+		return "the notification rules are checked", nil
+	}
+}
+
+// statisticsSnapshotTask stores the inventory counts.
+func statisticsSnapshotTask(repositoryContainer model.RepositoryContainer) scheduler.Task {
+	return func(ctx context.Context) (string, error) {
+		snapshot, err := repositoryContainer.GetStatisticsSnapshotRepository().CreateStatisticsSnapshot()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d clients, %d projects, %d environments, %d applications, %d domains (%d without ssl), %d servers, %d databases, %d certificates",
+			snapshot.Clients, snapshot.Projects, snapshot.Environments, snapshot.Applications, snapshot.Domains, snapshot.DomainsWithoutSSL,
+			snapshot.Servers, snapshot.Databases, snapshot.Certificates), nil
+	}
+}
+
+// overrideTaskSchedule sets the schedule of the named task, eg. from a deprecated configuration variable.
+// The next run of the enabled task is moved to the next time of the schedule. The unchanged schedule is kept as it is.
+func overrideTaskSchedule(tasks model.ScheduledTaskRepository, name, expression string, now time.Time) error {
+	schedule, err := scheduler.ParseSchedule(expression)
+	if err != nil {
+		return err
+	}
+	all, err := tasks.GetScheduledTasks()
+	if err != nil {
+		return err
+	}
+	for _, task := range *all {
+		if task.Name != name {
+			continue
+		}
+		if task.Schedule == expression {
+			return nil
+		}
+		nextRunAt := time.Time{}
+		if task.Enabled {
+			nextRunAt = schedule.Next(now)
+		}
+		_, err := tasks.UpdateScheduledTask(task.ID, expression, task.Enabled, nextRunAt)
+		return err
+	}
+	return fmt.Errorf("the %s task is not found: %w", name, model.ErrNotFound)
+}
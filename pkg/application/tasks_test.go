@@ -0,0 +1,167 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/config"
+	"github.com/akosgarai/projectregister/pkg/model"
+	"github.com/akosgarai/projectregister/pkg/storage"
+)
+
+// importJobsMock returns the jobs for the upload cleanup tests. The other methods are not used.
+type importJobsMock struct {
+	model.JobRepository
+	jobs model.Jobs
+}
+
+// GetJobs returns the jobs of the filtered type.
+func (m *importJobsMock) GetJobs(filter *model.JobFilter) (*model.Jobs, error) {
+	jobs := model.Jobs{}
+	for _, job := range m.jobs {
+		if job.Type == filter.Type {
+			jobs = append(jobs, job)
+		}
+	}
+	return &jobs, nil
+}
+
+// TestUploadCleanupTask tests that only the stale uploaded files are deleted, the hidden files, the other files
+// and the files of the unfinished import jobs are kept.
+func TestUploadCleanupTask(t *testing.T) {
+	directory := t.TempDir()
+	retention := 24 * time.Hour
+	stale := time.Now().Add(-retention - time.Hour)
+	failedFileID := strings.Repeat("a", 64)
+	succeededFileID := strings.Repeat("b", 64)
+	files := map[string]time.Time{
+		"imports/stale.csv":            stale,
+		"imports/fresh.csv":            time.Now(),
+		"imports/.gitkeep":             stale,
+		"imports/stale2.csv":           stale,
+		"imports/7_" + failedFileID:    stale,
+		"imports/7_" + succeededFileID: stale,
+		"stale.csv":                    stale,
+	}
+	if err := os.Mkdir(filepath.Join(directory, "imports"), 0700); err != nil {
+		t.Fatalf("Failed to create the directory: %v", err)
+	}
+	for name, modified := range files {
+		path := filepath.Join(directory, name)
+		if err := os.WriteFile(path, []byte("a;b"), 0600); err != nil {
+			t.Fatalf("Failed to create the file: %v", err)
+		}
+		if err := os.Chtimes(path, modified, modified); err != nil {
+			t.Fatalf("Failed to set the file time: %v", err)
+		}
+	}
+	jobs := &importJobsMock{jobs: model.Jobs{
+		{ID: 1, Type: model.JobTypeResourceImport, Status: model.JobStatusFailed, CreatedBy: 7, Payload: `{"file_id":"` + failedFileID + `"}`},
+		{ID: 2, Type: model.JobTypeApplicationImport, Status: model.JobStatusSucceeded, CreatedBy: 7, Payload: `{"file_id":"` + succeededFileID + `"}`},
+		{ID: 3, Type: model.JobTypeDomainCheck, Status: model.JobStatusQueued, Payload: `{"domain_ids":[1]}`},
+	}}
+	uploads := storage.NewCSVFileStorage(config.NewEnvironment(map[string]string{}), storage.NewLocalBlobStorage(directory))
+	message, err := uploadCleanupTask(uploads, jobs, retention)(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if message != "3 files are deleted, the files of 1 unfinished imports are kept" {
+		t.Errorf("Unexpected message: %s", message)
+	}
+	for name, expected := range map[string]bool{
+		"imports/stale.csv":            false,
+		"imports/stale2.csv":           false,
+		"imports/7_" + succeededFileID: false,
+		"imports/7_" + failedFileID:    true,
+		"imports/fresh.csv":            true,
+		"imports/.gitkeep":             true,
+		"stale.csv":                    true,
+	} {
+		_, err := os.Stat(filepath.Join(directory, name))
+		if exists := err == nil; exists != expected {
+			t.Errorf("Expected %s to exist: %t", name, expected)
+		}
+	}
+}
+
+// scheduledTasksMock stores the scheduled tasks for the schedule override tests.
+type scheduledTasksMock struct {
+	tasks   model.ScheduledTasks
+	updates int
+}
+
+// GetScheduledTaskByID returns the task with the id.
+func (m *scheduledTasksMock) GetScheduledTaskByID(id int64) (*model.ScheduledTask, error) {
+	for _, task := range m.tasks {
+		if task.ID == id {
+			return task, nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+// GetScheduledTasks returns the tasks.
+func (m *scheduledTasksMock) GetScheduledTasks() (*model.ScheduledTasks, error) {
+	return &m.tasks, nil
+}
+
+// UpdateScheduledTask sets the schedule, the enabled flag and the next run of the task.
+func (m *scheduledTasksMock) UpdateScheduledTask(id int64, schedule string, enabled bool, nextRunAt time.Time) (*model.ScheduledTask, error) {
+	task, err := m.GetScheduledTaskByID(id)
+	if err != nil {
+		return nil, err
+	}
+	m.updates++
+	task.Schedule = schedule
+	task.Enabled = enabled
+	task.NextRunAt = ""
+	if !nextRunAt.IsZero() {
+		task.NextRunAt = nextRunAt.Format(time.DateTime)
+	}
+	return task, nil
+}
+
+// StartScheduledTask is not used by the override.
+func (m *scheduledTasksMock) StartScheduledTask(id int64, startedAt, nextRunAt time.Time) (*model.ScheduledTask, error) {
+	return nil, model.ErrNotFound
+}
+
+// FinishScheduledTask is not used by the override.
+func (m *scheduledTasksMock) FinishScheduledTask(id int64, status, message string, durationMs int64) (*model.ScheduledTask, error) {
+	return nil, model.ErrNotFound
+}
+
+// TestOverrideTaskSchedule tests that the schedule and the next run of the named task is set only if it is changed.
+func TestOverrideTaskSchedule(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 7, 0, 0, time.UTC)
+	tasks := &scheduledTasksMock{tasks: model.ScheduledTasks{
+		{ID: 1, Name: model.ScheduledTaskDomainChecks, Schedule: "0 3 * * *", Enabled: true},
+		{ID: 2, Name: model.ScheduledTaskExpiryNotifications, Schedule: "0 * * * *", Enabled: true},
+	}}
+	if err := overrideTaskSchedule(tasks, model.ScheduledTaskExpiryNotifications, "*/15 * * * *", now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if task := tasks.tasks[1]; task.Schedule != "*/15 * * * *" || task.NextRunAt != "2024-05-01 10:15:00" {
+		t.Errorf("Unexpected task: %+v", task)
+	}
+	if tasks.tasks[0].Schedule != "0 3 * * *" {
+		t.Errorf("Unexpected other task: %+v", tasks.tasks[0])
+	}
+	if err := overrideTaskSchedule(tasks, model.ScheduledTaskExpiryNotifications, "*/15 * * * *", now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if tasks.updates != 1 {
+		t.Errorf("Expected 1 update, got %d", tasks.updates)
+	}
+	if err := overrideTaskSchedule(tasks, "missing", "*/15 * * * *", now); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+	if err := overrideTaskSchedule(tasks, model.ScheduledTaskExpiryNotifications, "every minute", now); err == nil {
+		t.Error("Expected invalid schedule error.")
+	}
+}
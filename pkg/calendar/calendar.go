@@ -0,0 +1,109 @@
+package calendar
+
+// This package contains the iCalendar (RFC 5545) feed writer.
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// productID is the identifier of the calendar producer.
+	productID = "-//akosgarai//projectregister//EN"
+	// maxLineLength is the maximum length of a content line in octets, without the line break.
+	maxLineLength = 75
+	// dateFormat is the format of the DATE values.
+	dateFormat = "20060102"
+	// dateTimeFormat is the format of the UTC DATE-TIME values.
+	dateTimeFormat = "20060102T150405Z"
+)
+
+// Event type is an all day event of the calendar.
+// The Reminder is the duration before the event when the alarm is triggered, zero means no alarm.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Date        time.Time
+	Reminder    time.Duration
+}
+
+// Write writes the calendar with the events in iCalendar format.
+// The now is used as the timestamp of the events.
+func Write(w io.Writer, name string, events []*Event, now time.Time) error {
+	writer := &lineWriter{w: bufio.NewWriter(w)}
+	writer.line("BEGIN:VCALENDAR")
+	writer.line("VERSION:2.0")
+	writer.line("PRODID:" + productID)
+	writer.line("CALSCALE:GREGORIAN")
+	writer.line("METHOD:PUBLISH")
+	writer.line("X-WR-CALNAME:" + escapeText(name))
+	stamp := now.UTC().Format(dateTimeFormat)
+	for _, event := range events {
+		writer.line("BEGIN:VEVENT")
+		writer.line("UID:" + escapeText(event.UID))
+		writer.line("DTSTAMP:" + stamp)
+		writer.line("DTSTART;VALUE=DATE:" + event.Date.Format(dateFormat))
+		writer.line("DTEND;VALUE=DATE:" + event.Date.AddDate(0, 0, 1).Format(dateFormat))
+		writer.line("SUMMARY:" + escapeText(event.Summary))
+		if event.Description != "" {
+			writer.line("DESCRIPTION:" + escapeText(event.Description))
+		}
+		if event.URL != "" {
+			writer.line("URL:" + event.URL)
+		}
+		writer.line("TRANSP:TRANSPARENT")
+		if event.Reminder > 0 {
+			writer.line("BEGIN:VALARM")
+			writer.line("ACTION:DISPLAY")
+			writer.line("DESCRIPTION:" + escapeText(event.Summary))
+			writer.line(fmt.Sprintf("TRIGGER:-PT%dM", int(event.Reminder.Minutes())))
+			writer.line("END:VALARM")
+		}
+		writer.line("END:VEVENT")
+	}
+	writer.line("END:VCALENDAR")
+	if writer.err != nil {
+		return writer.err
+	}
+	return writer.w.Flush()
+}
+
+// lineWriter writes the folded content lines. It keeps the first error.
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line writes the content line with CRLF line break. The long lines are folded
+// to 75 octets, the continuation lines start with a space. The UTF-8 sequences are not split.
+func (l *lineWriter) line(content string) {
+	if l.err != nil {
+		return
+	}
+	limit := maxLineLength
+	for len(content) > limit {
+		cut := limit
+		// step back to the start of the UTF-8 sequence
+		for cut > 0 && content[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		if _, l.err = l.w.WriteString(content[:cut] + "\r\n "); l.err != nil {
+			return
+		}
+		content = content[cut:]
+		// the leading space of the continuation line is counted
+		limit = maxLineLength - 1
+	}
+	_, l.err = l.w.WriteString(content + "\r\n")
+}
+
+// escapeText escapes the TEXT value.
+func escapeText(value string) string {
+	replacer := strings.NewReplacer("\\", "\\\\", ";", "\\;", ",", "\\,", "\r\n", "\\n", "\n", "\\n")
+	return replacer.Replace(value)
+}
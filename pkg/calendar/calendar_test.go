@@ -0,0 +1,65 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// TestWrite tests the calendar output.
+func TestWrite(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	events := []*Event{
+		{
+			UID:         "domain-1@projectregister",
+			Summary:     "Domain expires: example.com",
+			Description: "Registrar: Example, Inc.\nAuto renew: false",
+			Date:        time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC),
+			Reminder:    14 * 24 * time.Hour,
+		},
+	}
+	var buffer bytes.Buffer
+	if err := Write(&buffer, "Expiry", events, now); err != nil {
+		t.Fatal(err)
+	}
+	output := buffer.String()
+	expectedLines := []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Expiry\r\n",
+		"UID:domain-1@projectregister\r\n",
+		"DTSTAMP:20240501T103000Z\r\n",
+		"DTSTART;VALUE=DATE:20240630\r\n",
+		"DTEND;VALUE=DATE:20240701\r\n",
+		"DESCRIPTION:Registrar: Example\\, Inc.\\nAuto renew: false\r\n",
+		"TRIGGER:-PT20160M\r\n",
+		"END:VCALENDAR\r\n",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(output, line) {
+			t.Errorf("Output does not contain %q. Got: %s", line, output)
+		}
+	}
+}
+
+// TestWriteFoldsLongLines tests the line folding.
+func TestWriteFoldsLongLines(t *testing.T) {
+	events := []*Event{{UID: "1", Summary: strings.Repeat("é", 100), Date: time.Now()}}
+	var buffer bytes.Buffer
+	if err := Write(&buffer, "Expiry", events, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	unfolded := strings.ReplaceAll(buffer.String(), "\r\n ", "")
+	if !strings.Contains(unfolded, "SUMMARY:"+strings.Repeat("é", 100)+"\r\n") {
+		t.Errorf("The unfolded summary is invalid. Got: %s", unfolded)
+	}
+	for _, line := range strings.Split(buffer.String(), "\r\n") {
+		if len(line) > maxLineLength {
+			t.Errorf("The line is too long: %d", len(line))
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("The line contains broken UTF-8 sequence: %q", line)
+		}
+	}
+}
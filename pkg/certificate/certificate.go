@@ -0,0 +1,86 @@
+package certificate
+
+// This package contains the certificate parsing related functions.
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+const (
+	// pemCertificateBlockType is the type of the PEM blocks that contain certificate.
+	pemCertificateBlockType = "CERTIFICATE"
+)
+
+var (
+	// ErrNoCertificate is returned if the PEM data does not contain any certificate.
+	ErrNoCertificate = errors.New("the PEM data does not contain certificate")
+)
+
+// ParsePEM parses the PEM encoded data and returns the certificate model of the first (leaf) certificate.
+// The rest of the certificate blocks (the chain) are kept in the PEM field of the model.
+// Every other block type (for example the private keys) is dropped, so it is never stored.
+func ParsePEM(data []byte) (*model.Certificate, error) {
+	var leaf *x509.Certificate
+	chain := []string{}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != pemCertificateBlockType {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if leaf == nil {
+			leaf = cert
+		}
+		chain = append(chain, string(pem.EncodeToMemory(&pem.Block{Type: pemCertificateBlockType, Bytes: block.Bytes})))
+	}
+	if leaf == nil {
+		return nil, ErrNoCertificate
+	}
+	dnsNames := leaf.DNSNames
+	// Legacy certificates might contain the domain name only in the common name.
+	if len(dnsNames) == 0 && leaf.Subject.CommonName != "" {
+		dnsNames = []string{leaf.Subject.CommonName}
+	}
+	return &model.Certificate{
+		CommonName:   leaf.Subject.CommonName,
+		Issuer:       leaf.Issuer.String(),
+		SerialNumber: leaf.SerialNumber.String(),
+		Fingerprint:  Fingerprint(leaf),
+		DNSNames:     dnsNames,
+		NotBefore:    leaf.NotBefore.UTC().Format(time.RFC3339),
+		NotAfter:     leaf.NotAfter.UTC().Format(time.RFC3339),
+		PEM:          strings.Join(chain, ""),
+	}, nil
+}
+
+// Fingerprint returns the hex encoded SHA-256 fingerprint of the certificate.
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// CoveredDomains returns the domains that are covered by the SAN entries of the certificate.
+func CoveredDomains(cert *model.Certificate, domains model.Domains) model.Domains {
+	covered := model.Domains{}
+	for _, domain := range domains {
+		if cert.Covers(domain.Name) {
+			covered = append(covered, domain)
+		}
+	}
+	return covered
+}
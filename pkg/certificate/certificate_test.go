@@ -0,0 +1,105 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// newTestPEM returns a self signed certificate and its private key in PEM format.
+func newTestPEM(t *testing.T, commonName string, dnsNames []string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	return append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+}
+
+// TestParsePEM tests the certificate parsing.
+func TestParsePEM(t *testing.T) {
+	cert, err := ParsePEM(newTestPEM(t, "example.com", []string{"example.com", "*.example.com"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.CommonName != "example.com" {
+		t.Errorf("CommonName is not set properly. Got: %s", cert.CommonName)
+	}
+	if cert.SerialNumber != "42" {
+		t.Errorf("SerialNumber is not set properly. Got: %s", cert.SerialNumber)
+	}
+	if len(cert.DNSNames) != 2 {
+		t.Errorf("DNSNames are not set properly. Got: %v", cert.DNSNames)
+	}
+	if cert.NotAfter != "2025-01-01T00:00:00Z" {
+		t.Errorf("NotAfter is not set properly. Got: %s", cert.NotAfter)
+	}
+	if len(cert.Fingerprint) != 64 {
+		t.Errorf("Fingerprint is not set properly. Got: %s", cert.Fingerprint)
+	}
+	if strings.Contains(cert.PEM, "PRIVATE KEY") || !strings.Contains(cert.PEM, "BEGIN CERTIFICATE") {
+		t.Errorf("PEM is not set properly. Got: %s", cert.PEM)
+	}
+}
+
+// TestParsePEMCommonNameFallback tests that the common name is used without SAN entries.
+func TestParsePEMCommonNameFallback(t *testing.T) {
+	cert, err := ParsePEM(newTestPEM(t, "legacy.example.com", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cert.DNSNames) != 1 || cert.DNSNames[0] != "legacy.example.com" {
+		t.Errorf("DNSNames are not set properly. Got: %v", cert.DNSNames)
+	}
+}
+
+// TestParsePEMWithoutCertificate tests the parsing of data without certificate block.
+func TestParsePEMWithoutCertificate(t *testing.T) {
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")})
+	if _, err := ParsePEM(data); err != ErrNoCertificate {
+		t.Errorf("Invalid error. Expected: %v, got: %v", ErrNoCertificate, err)
+	}
+	if _, err := ParsePEM([]byte("not a pem")); err != ErrNoCertificate {
+		t.Errorf("Invalid error. Expected: %v, got: %v", ErrNoCertificate, err)
+	}
+}
+
+// TestCoveredDomains tests the SAN matching of the domains.
+func TestCoveredDomains(t *testing.T) {
+	cert := &model.Certificate{DNSNames: []string{"example.com", "*.example.com"}}
+	domains := model.Domains{
+		{ID: 1, Name: "example.com"},
+		{ID: 2, Name: "www.example.com"},
+		{ID: 3, Name: "a.b.example.com"},
+		{ID: 4, Name: "example.org"},
+		{ID: 5, Name: "WWW.Example.com."},
+	}
+	covered := CoveredDomains(cert, domains)
+	if len(covered) != 3 || covered[0].ID != 1 || covered[1].ID != 2 || covered[2].ID != 5 {
+		t.Errorf("Covered domains are not set properly. Got: %v", covered)
+	}
+}
@@ -0,0 +1,101 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeKeyPair writes a self signed certificate with the given common name, and sets the modification time of the files.
+func writeKeyPair(t *testing.T, certFile, keyFile, commonName string, modTime time.Time) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{certFile, keyFile} {
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// commonName returns the common name of the current certificate of the reloader.
+func commonName(t *testing.T, r *Reloader) string {
+	t.Helper()
+	certificate, _ := r.GetCertificate(nil)
+	parsed, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.Subject.CommonName
+}
+
+// TestReload tests that the changed key pair is loaded, and the invalid one is skipped.
+func TestReload(t *testing.T) {
+	directory := t.TempDir()
+	certFile := filepath.Join(directory, "cert.pem")
+	keyFile := filepath.Join(directory, "key.pem")
+	modTime := time.Now().Add(-time.Minute)
+	writeKeyPair(t, certFile, keyFile, "first", modTime)
+	r, err := NewReloader(certFile, keyFile, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reloaded, err := r.Reload(); reloaded || err != nil {
+		t.Errorf("Expected no reload for the unchanged files, got %t, %v", reloaded, err)
+	}
+	writeKeyPair(t, certFile, keyFile, "second", modTime.Add(time.Second))
+	if reloaded, err := r.Reload(); !reloaded || err != nil {
+		t.Errorf("Expected reload for the changed files, got %t, %v", reloaded, err)
+	}
+	if name := commonName(t, r); name != "second" {
+		t.Errorf("Expected second certificate, got %s", name)
+	}
+	if err := os.WriteFile(keyFile, []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded, err := r.Reload(); reloaded || err == nil {
+		t.Errorf("Expected error for the invalid key, got %t, %v", reloaded, err)
+	}
+	if name := commonName(t, r); name != "second" {
+		t.Errorf("Expected the previous certificate to be kept, got %s", name)
+	}
+}
+
+// TestNewReloaderMissingFiles tests that the missing key pair is reported.
+func TestNewReloaderMissingFiles(t *testing.T) {
+	if _, err := NewReloader("/missing/cert.pem", "/missing/key.pem", slog.Default()); err == nil {
+		t.Error("Expected error for the missing files.")
+	}
+}
@@ -0,0 +1,312 @@
+package cli
+
+// This package contains the management commands of the application.
+// The commands are used for the bootstrap and the recovery of an installation without the web interface.
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/akosgarai/projectregister/pkg/config"
+	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/database/repository"
+	"github.com/akosgarai/projectregister/pkg/event"
+	"github.com/akosgarai/projectregister/pkg/model"
+	"github.com/akosgarai/projectregister/pkg/passwd"
+)
+
+const (
+	// CommandServe is the command that starts the web server. It is the default command.
+	CommandServe = "serve"
+
+	// Usage is the help text of the commands.
+	Usage = `Usage:
+  serve                                             start the web server (default)
+  migrate up                                        apply every pending migration
+  migrate down [-steps N]                           roll back the last N migrations (default 1)
+  migrate status                                    show the applied and the latest migration version
+  migrate force VERSION                             set the migration version and clear the dirty flag
+  user create -email EMAIL -role ROLE [-name NAME] [-password PASSWORD]
+                                                    create a user
+  user reset-password -email EMAIL [-password PASSWORD]
+                                                    set the password of a user
+  role grant -email EMAIL -role ROLE                assign the role to a user
+  config print                                      print the configuration with the secrets redacted, and validate it
+
+The password is read from the standard input if it is not given.
+`
+)
+
+var (
+	// ErrUsage is returned if the command or its arguments are invalid.
+	ErrUsage = errors.New("invalid command")
+)
+
+// Migrator interface is the migration operations of the migrate command.
+type Migrator interface {
+	Up() error
+	Down(steps int) error
+	Force(version int) error
+	Version() (uint, bool, error)
+	LatestVersion() (uint, error)
+}
+
+// CLI type runs the management commands.
+type CLI struct {
+	stdin  *bufio.Reader
+	stdout io.Writer
+
+	envConfig *config.Environment
+	migrator  Migrator
+	// repositories connects to the database. It returns the repository container and the close function.
+	repositories func() (model.RepositoryContainer, func() error, error)
+}
+
+// New creates a new CLI that uses the database of the configuration.
+func New(envConfig *config.Environment, stdin io.Reader, stdout io.Writer) *CLI {
+	return &CLI{
+		stdin:     bufio.NewReader(stdin),
+		stdout:    stdout,
+		envConfig: envConfig,
+		migrator:  database.NewMigration(envConfig),
+		repositories: func() (model.RepositoryContainer, func() error, error) {
+			db := database.NewDB(envConfig)
+			if err := db.Connect(); err != nil {
+				return nil, nil, err
+			}
+			return repository.NewContainerRepository(db, event.NewBus()), db.Close, nil
+		},
+	}
+}
+
+// Run executes the command of the arguments. The arguments are without the program name.
+func (c *CLI) Run(args []string) error {
+	if len(args) < 2 {
+		return ErrUsage
+	}
+	switch args[0] + " " + args[1] {
+	case "migrate up":
+		return c.migrateUp(args[2:])
+	case "migrate down":
+		return c.migrateDown(args[2:])
+	case "migrate status":
+		return c.migrateStatus(args[2:])
+	case "migrate force":
+		return c.migrateForce(args[2:])
+	case "user create":
+		return c.userCreate(args[2:])
+	case "user reset-password":
+		return c.userResetPassword(args[2:])
+	case "role grant":
+		return c.roleGrant(args[2:])
+	case "config print":
+		return c.configPrint(args[2:])
+	}
+	return ErrUsage
+}
+
+// parseFlags parses the flags of a command. The positional arguments are not allowed.
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	flags.SetOutput(io.Discard)
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w: %w", ErrUsage, err)
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("%w: unexpected argument %s", ErrUsage, flags.Arg(0))
+	}
+	return nil
+}
+
+// migrateUp applies the pending migrations.
+func (c *CLI) migrateUp(args []string) error {
+	if err := parseFlags(flag.NewFlagSet("migrate up", flag.ContinueOnError), args); err != nil {
+		return err
+	}
+	if err := c.migrator.Up(); err != nil {
+		return err
+	}
+	return c.migrateStatus(nil)
+}
+
+// migrateDown rolls back the given number of migrations.
+func (c *CLI) migrateDown(args []string) error {
+	flags := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+	steps := flags.Int("steps", 1, "the number of the migrations to roll back")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if err := c.migrator.Down(*steps); err != nil {
+		return err
+	}
+	return c.migrateStatus(nil)
+}
+
+// migrateStatus prints the applied and the latest migration version.
+func (c *CLI) migrateStatus(args []string) error {
+	if err := parseFlags(flag.NewFlagSet("migrate status", flag.ContinueOnError), args); err != nil {
+		return err
+	}
+	version, dirty, err := c.migrator.Version()
+	if err != nil {
+		return err
+	}
+	latest, err := c.migrator.LatestVersion()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stdout, "version: %d\ndirty: %t\nlatest: %d\n", version, dirty, latest)
+	return nil
+}
+
+// migrateForce sets the migration version without executing the migrations.
+func (c *CLI) migrateForce(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: the version is required", ErrUsage)
+	}
+	version, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: invalid version %s", ErrUsage, args[0])
+	}
+	if err := c.migrator.Force(version); err != nil {
+		return err
+	}
+	return c.migrateStatus(nil)
+}
+
+// userCreate creates a user with the given role.
+func (c *CLI) userCreate(args []string) error {
+	flags := flag.NewFlagSet("user create", flag.ContinueOnError)
+	email := flags.String("email", "", "the email of the user")
+	name := flags.String("name", "", "the name of the user, the email is used if it is empty")
+	roleName := flags.String("role", "", "the name of the role")
+	password := flags.String("password", "", "the password of the user")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *email == "" || *roleName == "" {
+		return fmt.Errorf("%w: the email and the role are required", ErrUsage)
+	}
+	if *name == "" {
+		*name = *email
+	}
+	hashedPassword, err := c.hashedPassword(*password)
+	if err != nil {
+		return err
+	}
+	return c.withRepositories(func(repositories model.RepositoryContainer) error {
+		role, err := repositories.GetRoleRepository().GetRoleByName(*roleName)
+		if err != nil {
+			return fmt.Errorf("failed to get the role %s: %w", *roleName, err)
+		}
+		user, err := repositories.GetUserRepository().CreateUser(*name, *email, hashedPassword, role.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create the user %s: %w", *email, err)
+		}
+		fmt.Fprintf(c.stdout, "user %s is created with id %d and role %s\n", user.Email, user.ID, role.Name)
+		return nil
+	})
+}
+
+// userResetPassword sets the password of the user.
+func (c *CLI) userResetPassword(args []string) error {
+	flags := flag.NewFlagSet("user reset-password", flag.ContinueOnError)
+	email := flags.String("email", "", "the email of the user")
+	password := flags.String("password", "", "the new password of the user")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return fmt.Errorf("%w: the email is required", ErrUsage)
+	}
+	hashedPassword, err := c.hashedPassword(*password)
+	if err != nil {
+		return err
+	}
+	return c.withRepositories(func(repositories model.RepositoryContainer) error {
+		user, err := repositories.GetUserRepository().GetUserByEmail(*email)
+		if err != nil {
+			return fmt.Errorf("failed to get the user %s: %w", *email, err)
+		}
+		user.Password = hashedPassword
+		if err := repositories.GetUserRepository().UpdateUser(user); err != nil {
+			return fmt.Errorf("failed to update the user %s: %w", *email, err)
+		}
+		fmt.Fprintf(c.stdout, "the password of user %s is updated\n", user.Email)
+		return nil
+	})
+}
+
+// roleGrant assigns the role to the user.
+func (c *CLI) roleGrant(args []string) error {
+	flags := flag.NewFlagSet("role grant", flag.ContinueOnError)
+	email := flags.String("email", "", "the email of the user")
+	roleName := flags.String("role", "", "the name of the role")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *email == "" || *roleName == "" {
+		return fmt.Errorf("%w: the email and the role are required", ErrUsage)
+	}
+	return c.withRepositories(func(repositories model.RepositoryContainer) error {
+		role, err := repositories.GetRoleRepository().GetRoleByName(*roleName)
+		if err != nil {
+			return fmt.Errorf("failed to get the role %s: %w", *roleName, err)
+		}
+		user, err := repositories.GetUserRepository().GetUserByEmail(*email)
+		if err != nil {
+			return fmt.Errorf("failed to get the user %s: %w", *email, err)
+		}
+		user.Role = role
+		if err := repositories.GetUserRepository().UpdateUser(user); err != nil {
+			return fmt.Errorf("failed to update the user %s: %w", *email, err)
+		}
+		fmt.Fprintf(c.stdout, "role %s is granted to user %s\n", role.Name, user.Email)
+		return nil
+	})
+}
+
+// configPrint prints the configuration variables, and returns the validation error.
+func (c *CLI) configPrint(args []string) error {
+	if err := parseFlags(flag.NewFlagSet("config print", flag.ContinueOnError), args); err != nil {
+		return err
+	}
+	for _, v := range c.envConfig.Redacted() {
+		fmt.Fprintf(c.stdout, "%s=%s\n", v.Name, v.Value)
+	}
+	if err := c.envConfig.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return nil
+}
+
+// withRepositories connects to the database, and executes the action with the repositories.
+func (c *CLI) withRepositories(action func(repositories model.RepositoryContainer) error) error {
+	repositories, closeDB, err := c.repositories()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the database: %w", err)
+	}
+	defer closeDB()
+	return action(repositories)
+}
+
+// hashedPassword returns the hash of the password. The password is read from the standard input if it is empty.
+func (c *CLI) hashedPassword(password string) (string, error) {
+	if password == "" {
+		fmt.Fprint(c.stdout, "Password: ")
+		line, err := c.stdin.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		fmt.Fprintln(c.stdout)
+		password = strings.TrimRight(line, "\r\n")
+	}
+	if password == "" {
+		return "", fmt.Errorf("%w: the password is required", ErrUsage)
+	}
+	return passwd.HashPassword(password)
+}
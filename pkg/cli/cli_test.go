@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/akosgarai/projectregister/pkg/config"
+	"github.com/akosgarai/projectregister/pkg/model"
+	"github.com/akosgarai/projectregister/pkg/passwd"
+)
+
+// migratorMock records the called migration operations.
+type migratorMock struct {
+	calls []string
+}
+
+func (m *migratorMock) Up() error {
+	m.calls = append(m.calls, "up")
+	return nil
+}
+
+func (m *migratorMock) Down(steps int) error {
+	m.calls = append(m.calls, fmt.Sprintf("down %d", steps))
+	return nil
+}
+
+func (m *migratorMock) Force(version int) error {
+	m.calls = append(m.calls, fmt.Sprintf("force %d", version))
+	return nil
+}
+
+func (m *migratorMock) Version() (uint, bool, error) {
+	return 25, true, nil
+}
+
+func (m *migratorMock) LatestVersion() (uint, error) {
+	return 26, nil
+}
+
+// userRepositoryMock stores the users in memory.
+type userRepositoryMock struct {
+	model.UserRepository
+	users map[string]*model.User
+}
+
+func (r *userRepositoryMock) CreateUser(username, email, password string, roleID int64) (*model.User, error) {
+	if _, ok := r.users[email]; ok {
+		return nil, model.ErrConflict
+	}
+	user := &model.User{ID: int64(len(r.users) + 1), Name: username, Email: email, Password: password, Role: &model.Role{ID: roleID}}
+	r.users[email] = user
+	return user, nil
+}
+
+func (r *userRepositoryMock) GetUserByEmail(email string) (*model.User, error) {
+	user, ok := r.users[email]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return user, nil
+}
+
+func (r *userRepositoryMock) UpdateUser(user *model.User) error {
+	r.users[user.Email] = user
+	return nil
+}
+
+// roleRepositoryMock returns the admin role.
+type roleRepositoryMock struct {
+	model.RoleRepository
+}
+
+func (r *roleRepositoryMock) GetRoleByName(name string) (*model.Role, error) {
+	if name != "admin" {
+		return nil, model.ErrNotFound
+	}
+	return &model.Role{ID: 1, Name: "admin"}, nil
+}
+
+// repositoryContainerMock returns the user and the role repository mocks.
+type repositoryContainerMock struct {
+	model.RepositoryContainer
+	users *userRepositoryMock
+}
+
+func (c *repositoryContainerMock) GetUserRepository() model.UserRepository {
+	return c.users
+}
+
+func (c *repositoryContainerMock) GetRoleRepository() model.RoleRepository {
+	return &roleRepositoryMock{}
+}
+
+// newTestCLI returns a cli with the mocks and the given standard input.
+func newTestCLI(stdin string) (*CLI, *migratorMock, *userRepositoryMock, *bytes.Buffer) {
+	migrator := &migratorMock{}
+	users := &userRepositoryMock{users: map[string]*model.User{
+		"system@admin": {ID: 1, Name: "Admin", Email: "system@admin", Role: &model.Role{ID: 2, Name: "viewer"}},
+	}}
+	stdout := &bytes.Buffer{}
+	c := &CLI{
+		stdin:    bufio.NewReader(strings.NewReader(stdin)),
+		stdout:   stdout,
+		migrator: migrator,
+		repositories: func() (model.RepositoryContainer, func() error, error) {
+			return &repositoryContainerMock{users: users}, func() error { return nil }, nil
+		},
+	}
+	return c, migrator, users, stdout
+}
+
+// TestRunUsage tests the invalid commands and arguments.
+func TestRunUsage(t *testing.T) {
+	testData := [][]string{
+		{},
+		{"migrate"},
+		{"migrate", "sideways"},
+		{"migrate", "force"},
+		{"migrate", "force", "x"},
+		{"migrate", "down", "-steps", "x"},
+		{"migrate", "status", "extra"},
+		{"user", "create", "-email", "new@admin"},
+		{"role", "grant", "-role", "admin"},
+	}
+	for _, args := range testData {
+		c, _, _, _ := newTestCLI("")
+		if err := c.Run(args); !errors.Is(err, ErrUsage) {
+			t.Errorf("Expected usage error for %v. Got: %v", args, err)
+		}
+	}
+}
+
+// TestRunMigrate tests the migrate commands.
+func TestRunMigrate(t *testing.T) {
+	c, migrator, _, stdout := newTestCLI("")
+	for _, args := range [][]string{{"migrate", "up"}, {"migrate", "down", "-steps", "2"}, {"migrate", "force", "25"}} {
+		if err := c.Run(args); err != nil {
+			t.Fatalf("Unexpected error for %v: %v", args, err)
+		}
+	}
+	if strings.Join(migrator.calls, ",") != "up,down 2,force 25" {
+		t.Errorf("Invalid migration calls: %v", migrator.calls)
+	}
+	if !strings.Contains(stdout.String(), "version: 25\ndirty: true\nlatest: 26\n") {
+		t.Errorf("Invalid status output: %s", stdout.String())
+	}
+}
+
+// TestRunUser tests the user create, the password reset and the role grant commands.
+func TestRunUser(t *testing.T) {
+	c, _, users, _ := newTestCLI("secret\n")
+	if err := c.Run([]string{"user", "create", "-email", "new@admin", "-role", "admin"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	user := users.users["new@admin"]
+	if user == nil || user.Name != "new@admin" || user.Role.ID != 1 || !passwd.ComparePassword("secret", user.Password) {
+		t.Errorf("Invalid created user: %+v", user)
+	}
+	if err := c.Run([]string{"user", "create", "-email", "new@admin", "-role", "admin", "-password", "secret"}); !errors.Is(err, model.ErrConflict) {
+		t.Errorf("Expected conflict error. Got: %v", err)
+	}
+	if err := c.Run([]string{"user", "create", "-email", "other@admin", "-role", "missing", "-password", "secret"}); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("Expected not found error. Got: %v", err)
+	}
+	if err := c.Run([]string{"user", "reset-password", "-email", "system@admin", "-password", "changed"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !passwd.ComparePassword("changed", users.users["system@admin"].Password) {
+		t.Error("The password is not updated.")
+	}
+	if err := c.Run([]string{"user", "reset-password", "-email", "missing@admin", "-password", "changed"}); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("Expected not found error. Got: %v", err)
+	}
+	if err := c.Run([]string{"role", "grant", "-email", "system@admin", "-role", "admin"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if users.users["system@admin"].Role.Name != "admin" {
+		t.Errorf("The role is not granted: %+v", users.users["system@admin"].Role)
+	}
+}
+
+// TestRunEmptyPassword tests that the empty password is rejected.
+func TestRunEmptyPassword(t *testing.T) {
+	c, _, _, _ := newTestCLI("\n")
+	if err := c.Run([]string{"user", "reset-password", "-email", "system@admin"}); !errors.Is(err, ErrUsage) {
+		t.Errorf("Expected usage error. Got: %v", err)
+	}
+}
+
+// TestRunConfigPrint tests that the secrets are redacted, and the validation errors are returned.
+func TestRunConfigPrint(t *testing.T) {
+	c, _, _, stdout := newTestCLI("")
+	c.envConfig = config.NewEnvironment(map[string]string{
+		config.DatabasePasswordEnvName:    "secret",
+		config.UploadDirectoryPathEnvName: t.TempDir(),
+	})
+	if err := c.Run([]string{"config", "print"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "DATABASE_PASSWORD="+config.RedactedValue+"\n") || strings.Contains(stdout.String(), "secret") {
+		t.Errorf("The password is not redacted: %s", stdout.String())
+	}
+	c.envConfig = config.NewEnvironment(map[string]string{config.ServerPortEnvName: "http"})
+	if err := c.Run([]string{"config", "print"}); err == nil || !strings.Contains(err.Error(), config.ServerPortEnvName) {
+		t.Errorf("Expected validation error. Got: %v", err)
+	}
+}
@@ -37,6 +37,79 @@ const (
 	DefaultStaticDirectoryPath = "./web/public"
 	// DefaultUploadDirectoryPath is the default upload directory path.
 	DefaultUploadDirectoryPath = "./uploads"
+	// DefaultUploadMaxSize is the default maximum size of the uploaded files in megabytes.
+	DefaultUploadMaxSize = 10
+	// DefaultUploadRetention is the default retention of the uploaded files in hours.
+	DefaultUploadRetention = 24
+	// DefaultRDAPBaseURL is the default RDAP server url. The empty value disables the lookup.
+	DefaultRDAPBaseURL = "https://rdap.org"
+	// DefaultCalendarFeedToken is the default token of the calendar feed. The empty value disables the feed.
+	DefaultCalendarFeedToken = ""
+	// DefaultSMTPHost is the default smtp server host. The empty value disables the email notifications.
+	DefaultSMTPHost = ""
+	// DefaultSMTPPort is the default smtp server port.
+	DefaultSMTPPort = "25"
+	// DefaultSMTPUsername is the default smtp username. The empty value disables the authentication.
+	DefaultSMTPUsername = ""
+	// DefaultSMTPPassword is the default smtp password.
+	DefaultSMTPPassword = ""
+	// DefaultSMTPFrom is the default sender address of the emails.
+	DefaultSMTPFrom = "projectregister@localhost"
+	// DefaultPublicURL is the default public url of the application. It is used for the links in the emails.
+	DefaultPublicURL = "http://localhost:8090"
+	// DefaultNotificationCheckInterval is the default interval of the notification rule checks in minutes.
+	// It is deprecated, the 0 keeps the schedule of the expiry notifications task.
+	DefaultNotificationCheckInterval = 0
+	// DefaultNotificationDigestHour is the default hour of the day when the daily digest is sent.
+	DefaultNotificationDigestHour = 8
+	// DefaultMetricsToken is the default bearer token of the metrics and the service discovery endpoints.
+	// The empty value disables the authentication of the metrics and disables the service discovery.
+	DefaultMetricsToken = ""
+	// DefaultMetricsScoreThreshold is the default score threshold of the low score applications gauge.
+	DefaultMetricsScoreThreshold = 50
+	// DefaultLogFormat is the default log format. It could be text or json.
+	DefaultLogFormat = "text"
+	// DefaultLogLevel is the default minimum log level. It could be debug, info, warn or error.
+	DefaultLogLevel = "info"
+	// DefaultAppEnv is the default application environment.
+	DefaultAppEnv = AppEnvProduction
+	// DefaultHealthCheckTimeout is the default timeout of the readiness checks in seconds.
+	DefaultHealthCheckTimeout = 2
+	// DefaultAutoMigrate is the default value of the automatic migration on server start.
+	DefaultAutoMigrate = true
+	// DefaultAssetsFromDisk is the default value of the disk based templates, static files and migrations.
+	// The embedded files are used by default.
+	DefaultAssetsFromDisk = false
+	// DefaultShutdownTimeout is the default deadline of the graceful shutdown in seconds.
+	DefaultShutdownTimeout = 15
+	// DefaultJobWorkers is the default number of the background job workers.
+	DefaultJobWorkers = 2
+	// DefaultJobPollInterval is the default interval of the queued job checks in seconds.
+	DefaultJobPollInterval = 5
+	// DefaultTLSCertFile is the default certificate file of the https server. The empty value disables the https.
+	DefaultTLSCertFile = ""
+	// DefaultTLSKeyFile is the default private key file of the https server.
+	DefaultTLSKeyFile = ""
+	// DefaultTLSRedirectPort is the default port of the http to https redirect listener. The empty value disables the listener.
+	DefaultTLSRedirectPort = ""
+	// DefaultStorageBackend is the default backend of the uploaded files.
+	DefaultStorageBackend = StorageBackendLocal
+	// DefaultS3Endpoint is the default url of the s3 compatible object storage.
+	DefaultS3Endpoint = ""
+	// DefaultS3Region is the default region of the s3 bucket.
+	DefaultS3Region = "us-east-1"
+	// DefaultS3Bucket is the default s3 bucket of the uploaded files.
+	DefaultS3Bucket = ""
+	// DefaultS3AccessKey is the default s3 access key.
+	DefaultS3AccessKey = ""
+	// DefaultS3SecretKey is the default s3 secret key.
+	DefaultS3SecretKey = ""
+	// MinSessionNameLength is the minimum length of the session names.
+	MinSessionNameLength = 16
+	// MinSessionNameAlphabetLength is the minimum number of the distinct characters of the session name alphabet.
+	MinSessionNameAlphabetLength = 16
+	// RedactedValue is printed instead of the secrets.
+	RedactedValue = "******"
 
 	// environment variables
 
@@ -76,4 +149,83 @@ const (
 	StaticDirectoryPathEnvName = "STATIC_DIRECTORY_PATH"
 	// UploadDirectoryPathEnvName is the upload directory path environment variable name.
 	UploadDirectoryPathEnvName = "UPLOAD_DIRECTORY_PATH"
+	// UploadMaxSizeEnvName is the upload max size environment variable name.
+	UploadMaxSizeEnvName = "UPLOAD_MAX_SIZE"
+	// UploadRetentionEnvName is the upload retention environment variable name.
+	UploadRetentionEnvName = "UPLOAD_RETENTION"
+	// RDAPBaseURLEnvName is the RDAP server url environment variable name.
+	RDAPBaseURLEnvName = "RDAP_BASE_URL"
+	// CalendarFeedTokenEnvName is the calendar feed token environment variable name.
+	CalendarFeedTokenEnvName = "CALENDAR_FEED_TOKEN"
+	// SMTPHostEnvName is the smtp host environment variable name.
+	SMTPHostEnvName = "SMTP_HOST"
+	// SMTPPortEnvName is the smtp port environment variable name.
+	SMTPPortEnvName = "SMTP_PORT"
+	// SMTPUsernameEnvName is the smtp username environment variable name.
+	SMTPUsernameEnvName = "SMTP_USERNAME"
+	// SMTPPasswordEnvName is the smtp password environment variable name.
+	SMTPPasswordEnvName = "SMTP_PASSWORD"
+	// SMTPFromEnvName is the email sender address environment variable name.
+	SMTPFromEnvName = "SMTP_FROM"
+	// PublicURLEnvName is the public url environment variable name.
+	PublicURLEnvName = "PUBLIC_URL"
+	// NotificationCheckIntervalEnvName is the deprecated notification check interval environment variable name.
+	// It overrides the schedule of the expiry notifications task.
+	NotificationCheckIntervalEnvName = "NOTIFICATION_CHECK_INTERVAL"
+	// NotificationDigestHourEnvName is the notification digest hour environment variable name.
+	NotificationDigestHourEnvName = "NOTIFICATION_DIGEST_HOUR"
+	// MetricsTokenEnvName is the metrics token environment variable name.
+	MetricsTokenEnvName = "METRICS_TOKEN"
+	// MetricsScoreThresholdEnvName is the metrics score threshold environment variable name.
+	MetricsScoreThresholdEnvName = "METRICS_SCORE_THRESHOLD"
+	// LogFormatEnvName is the log format environment variable name.
+	LogFormatEnvName = "LOG_FORMAT"
+	// LogLevelEnvName is the log level environment variable name.
+	LogLevelEnvName = "LOG_LEVEL"
+	// AppEnvEnvName is the application environment environment variable name.
+	AppEnvEnvName = "APP_ENV"
+	// HealthCheckTimeoutEnvName is the health check timeout environment variable name.
+	HealthCheckTimeoutEnvName = "HEALTH_CHECK_TIMEOUT"
+	// AutoMigrateEnvName is the automatic migration environment variable name.
+	AutoMigrateEnvName = "AUTO_MIGRATE"
+	// AssetsFromDiskEnvName is the disk based assets environment variable name.
+	AssetsFromDiskEnvName = "ASSETS_FROM_DISK"
+	// ShutdownTimeoutEnvName is the shutdown timeout environment variable name.
+	ShutdownTimeoutEnvName = "SHUTDOWN_TIMEOUT"
+	// JobWorkersEnvName is the job workers environment variable name.
+	JobWorkersEnvName = "JOB_WORKERS"
+	// JobPollIntervalEnvName is the job poll interval environment variable name.
+	JobPollIntervalEnvName = "JOB_POLL_INTERVAL"
+	// TLSCertFileEnvName is the tls certificate file environment variable name.
+	TLSCertFileEnvName = "TLS_CERT_FILE"
+	// TLSKeyFileEnvName is the tls private key file environment variable name.
+	TLSKeyFileEnvName = "TLS_KEY_FILE"
+	// TLSRedirectPortEnvName is the https redirect port environment variable name.
+	TLSRedirectPortEnvName = "TLS_REDIRECT_PORT"
+	// StorageBackendEnvName is the storage backend environment variable name.
+	StorageBackendEnvName = "STORAGE_BACKEND"
+	// S3EndpointEnvName is the s3 endpoint environment variable name.
+	S3EndpointEnvName = "S3_ENDPOINT"
+	// S3RegionEnvName is the s3 region environment variable name.
+	S3RegionEnvName = "S3_REGION"
+	// S3BucketEnvName is the s3 bucket environment variable name.
+	S3BucketEnvName = "S3_BUCKET"
+	// S3AccessKeyEnvName is the s3 access key environment variable name.
+	S3AccessKeyEnvName = "S3_ACCESS_KEY"
+	// S3SecretKeyEnvName is the s3 secret key environment variable name.
+	S3SecretKeyEnvName = "S3_SECRET_KEY"
+	// ConfigFileEnvName is the config file path environment variable name.
+	ConfigFileEnvName = "CONFIG_FILE"
+	// FileEnvNameSuffix is the suffix of the environment variables that contain the path of a file with the value.
+	FileEnvNameSuffix = "_FILE"
+
+	// AppEnvProduction is the production application environment. The error details are hidden from the users.
+	AppEnvProduction = "production"
+	// AppEnvDevelopment is the development application environment. The error details are shown to the users.
+	AppEnvDevelopment = "development"
+
+	// StorageBackendLocal stores the uploaded files in the upload directory.
+	StorageBackendLocal = "local"
+	// StorageBackendS3 stores the uploaded files in an s3 compatible object storage, so that they are shared between the instances.
+	StorageBackendS3 = "s3"
 )
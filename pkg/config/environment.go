@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"strconv"
 )
 
@@ -13,6 +14,7 @@ type Environment struct {
 	serverPort         string
 
 	migrationDirectoryPath string
+	autoMigrate            bool
 
 	databaseHost     string
 	databasePort     string
@@ -29,6 +31,50 @@ type Environment struct {
 
 	staticDirectoryPath string
 	uploadDirectoryPath string
+	uploadMaxSize       int64
+	uploadRetention     int64
+	assetsFromDisk      bool
+
+	rdapBaseURL       string
+	calendarFeedToken string
+
+	smtpHost     string
+	smtpPort     string
+	smtpUsername string
+	smtpPassword string
+	smtpFrom     string
+	publicURL    string
+
+	notificationCheckInterval int64
+	notificationDigestHour    int
+
+	metricsToken          string
+	metricsScoreThreshold int
+
+	logFormat string
+	logLevel  string
+
+	appEnv string
+
+	healthCheckTimeout int64
+	shutdownTimeout    int64
+
+	jobWorkers      int64
+	jobPollInterval int64
+
+	tlsCertFile     string
+	tlsKeyFile      string
+	tlsRedirectPort string
+
+	storageBackend string
+	s3Endpoint     string
+	s3Region       string
+	s3Bucket       string
+	s3AccessKey    string
+	s3SecretKey    string
+
+	// parseErrors holds the invalid numbers and booleans, they are reported by the validation.
+	parseErrors []error
 }
 
 // DefaultEnvironment creates a new instance of the environment with default values.
@@ -41,6 +87,7 @@ func DefaultEnvironment() *Environment {
 		serverPort:         DefaultServerPort,
 
 		migrationDirectoryPath: DefaultMigrationDirectoryPath,
+		autoMigrate:            DefaultAutoMigrate,
 
 		databaseHost:     DefaultDatabaseHost,
 		databasePort:     DefaultDatabasePort,
@@ -57,6 +104,47 @@ func DefaultEnvironment() *Environment {
 
 		staticDirectoryPath: DefaultStaticDirectoryPath,
 		uploadDirectoryPath: DefaultUploadDirectoryPath,
+		uploadMaxSize:       DefaultUploadMaxSize,
+		uploadRetention:     DefaultUploadRetention,
+		assetsFromDisk:      DefaultAssetsFromDisk,
+
+		rdapBaseURL:       DefaultRDAPBaseURL,
+		calendarFeedToken: DefaultCalendarFeedToken,
+
+		smtpHost:     DefaultSMTPHost,
+		smtpPort:     DefaultSMTPPort,
+		smtpUsername: DefaultSMTPUsername,
+		smtpPassword: DefaultSMTPPassword,
+		smtpFrom:     DefaultSMTPFrom,
+		publicURL:    DefaultPublicURL,
+
+		notificationCheckInterval: DefaultNotificationCheckInterval,
+		notificationDigestHour:    DefaultNotificationDigestHour,
+
+		metricsToken:          DefaultMetricsToken,
+		metricsScoreThreshold: DefaultMetricsScoreThreshold,
+
+		logFormat: DefaultLogFormat,
+		logLevel:  DefaultLogLevel,
+
+		appEnv: DefaultAppEnv,
+
+		healthCheckTimeout: DefaultHealthCheckTimeout,
+		shutdownTimeout:    DefaultShutdownTimeout,
+
+		jobWorkers:      DefaultJobWorkers,
+		jobPollInterval: DefaultJobPollInterval,
+
+		tlsCertFile:     DefaultTLSCertFile,
+		tlsKeyFile:      DefaultTLSKeyFile,
+		tlsRedirectPort: DefaultTLSRedirectPort,
+
+		storageBackend: DefaultStorageBackend,
+		s3Endpoint:     DefaultS3Endpoint,
+		s3Region:       DefaultS3Region,
+		s3Bucket:       DefaultS3Bucket,
+		s3AccessKey:    DefaultS3AccessKey,
+		s3SecretKey:    DefaultS3SecretKey,
 	}
 }
 
@@ -90,6 +178,11 @@ func (e *Environment) GetMigrationDirectoryPath() string {
 	return e.migrationDirectoryPath
 }
 
+// GetAutoMigrate returns true if the migrations are executed on server start.
+func (e *Environment) GetAutoMigrate() bool {
+	return e.autoMigrate
+}
+
 // GetDatabaseHost returns the database host.
 func (e *Environment) GetDatabaseHost() string {
 	return e.databaseHost
@@ -145,22 +238,191 @@ func (e *Environment) GetStaticDirectoryPath() string {
 	return e.staticDirectoryPath
 }
 
+// GetAssetsFromDisk returns true if the templates, the static files and the migrations are loaded
+// from the configured directories instead of the embedded files. The templates are reloaded on every render.
+func (e *Environment) GetAssetsFromDisk() bool {
+	return e.assetsFromDisk
+}
+
 // GetUploadDirectoryPath returns the upload directory path.
 func (e *Environment) GetUploadDirectoryPath() string {
 	return e.uploadDirectoryPath
 }
 
+// GetUploadMaxSize returns the maximum size of the uploaded files in megabytes.
+func (e *Environment) GetUploadMaxSize() int64 {
+	return e.uploadMaxSize
+}
+
+// GetUploadRetention returns the retention of the uploaded files in hours.
+// The older files are deleted by the upload cleanup task.
+func (e *Environment) GetUploadRetention() int64 {
+	return e.uploadRetention
+}
+
+// GetRDAPBaseURL returns the RDAP server url.
+func (e *Environment) GetRDAPBaseURL() string {
+	return e.rdapBaseURL
+}
+
+// GetCalendarFeedToken returns the token of the calendar feed.
+func (e *Environment) GetCalendarFeedToken() string {
+	return e.calendarFeedToken
+}
+
+// GetSMTPHost returns the smtp server host.
+func (e *Environment) GetSMTPHost() string {
+	return e.smtpHost
+}
+
+// GetSMTPPort returns the smtp server port.
+func (e *Environment) GetSMTPPort() string {
+	return e.smtpPort
+}
+
+// GetSMTPUsername returns the smtp username.
+func (e *Environment) GetSMTPUsername() string {
+	return e.smtpUsername
+}
+
+// GetSMTPPassword returns the smtp password.
+func (e *Environment) GetSMTPPassword() string {
+	return e.smtpPassword
+}
+
+// GetSMTPFrom returns the sender address of the emails.
+func (e *Environment) GetSMTPFrom() string {
+	return e.smtpFrom
+}
+
+// GetPublicURL returns the public url of the application.
+func (e *Environment) GetPublicURL() string {
+	return e.publicURL
+}
+
+// GetNotificationCheckInterval returns the deprecated notification check interval in minutes. The 0 means that it is not set.
+func (e *Environment) GetNotificationCheckInterval() int64 {
+	return e.notificationCheckInterval
+}
+
+// GetNotificationCheckSchedule returns the cron expression of the deprecated notification check interval.
+// It is empty if the interval is not set, or it could not be expressed with a cron expression.
+func (e *Environment) GetNotificationCheckSchedule() string {
+	schedule, _ := intervalSchedule(e.notificationCheckInterval)
+	return schedule
+}
+
+// GetNotificationDigestHour returns the hour of the day when the daily digest is sent.
+func (e *Environment) GetNotificationDigestHour() int {
+	return e.notificationDigestHour
+}
+
+// GetMetricsToken returns the bearer token of the metrics endpoint.
+func (e *Environment) GetMetricsToken() string {
+	return e.metricsToken
+}
+
+// GetMetricsScoreThreshold returns the score threshold of the low score applications gauge.
+func (e *Environment) GetMetricsScoreThreshold() int {
+	return e.metricsScoreThreshold
+}
+
+// GetLogFormat returns the log format.
+func (e *Environment) GetLogFormat() string {
+	return e.logFormat
+}
+
+// GetLogLevel returns the minimum log level.
+func (e *Environment) GetLogLevel() string {
+	return e.logLevel
+}
+
+// GetAppEnv returns the application environment.
+func (e *Environment) GetAppEnv() string {
+	return e.appEnv
+}
+
+// GetHealthCheckTimeout returns the timeout of the readiness checks in seconds.
+func (e *Environment) GetHealthCheckTimeout() int64 {
+	return e.healthCheckTimeout
+}
+
+// GetShutdownTimeout returns the deadline of the graceful shutdown in seconds.
+func (e *Environment) GetShutdownTimeout() int64 {
+	return e.shutdownTimeout
+}
+
+// GetJobWorkers returns the number of the background job workers.
+func (e *Environment) GetJobWorkers() int64 {
+	return e.jobWorkers
+}
+
+// GetJobPollInterval returns the interval of the queued job checks in seconds.
+func (e *Environment) GetJobPollInterval() int64 {
+	return e.jobPollInterval
+}
+
+// GetTLSCertFile returns the certificate file of the https server.
+func (e *Environment) GetTLSCertFile() string {
+	return e.tlsCertFile
+}
+
+// GetTLSKeyFile returns the private key file of the https server.
+func (e *Environment) GetTLSKeyFile() string {
+	return e.tlsKeyFile
+}
+
+// GetTLSEnabled returns true if the server is started with https. It needs both the certificate and the key file.
+func (e *Environment) GetTLSEnabled() bool {
+	return e.tlsCertFile != "" && e.tlsKeyFile != ""
+}
+
+// GetTLSRedirectPort returns the port of the http to https redirect listener.
+func (e *Environment) GetTLSRedirectPort() string {
+	return e.tlsRedirectPort
+}
+
+// GetStorageBackend returns the backend of the uploaded files.
+func (e *Environment) GetStorageBackend() string {
+	return e.storageBackend
+}
+
+// GetS3Endpoint returns the url of the s3 compatible object storage.
+func (e *Environment) GetS3Endpoint() string {
+	return e.s3Endpoint
+}
+
+// GetS3Region returns the region of the s3 bucket.
+func (e *Environment) GetS3Region() string {
+	return e.s3Region
+}
+
+// GetS3Bucket returns the s3 bucket of the uploaded files.
+func (e *Environment) GetS3Bucket() string {
+	return e.s3Bucket
+}
+
+// GetS3AccessKey returns the s3 access key.
+func (e *Environment) GetS3AccessKey() string {
+	return e.s3AccessKey
+}
+
+// GetS3SecretKey returns the s3 secret key.
+func (e *Environment) GetS3SecretKey() string {
+	return e.s3SecretKey
+}
+
 // NewEnvironment creates a new instance of the environment.
 func NewEnvironment(envConfig map[string]string) *Environment {
 	env := DefaultEnvironment()
 	if val, ok := envConfig[ServerWriteTimeoutEnvName]; ok {
-		env.serverWriteTimeout = env.toInt64(val)
+		env.serverWriteTimeout = env.toInt64(ServerWriteTimeoutEnvName, val)
 	}
 	if val, ok := envConfig[ServerReadTimeoutEnvName]; ok {
-		env.serverReadTimeout = env.toInt64(val)
+		env.serverReadTimeout = env.toInt64(ServerReadTimeoutEnvName, val)
 	}
 	if val, ok := envConfig[ServerIdleTimeoutEnvName]; ok {
-		env.serverIdleTimeout = env.toInt64(val)
+		env.serverIdleTimeout = env.toInt64(ServerIdleTimeoutEnvName, val)
 	}
 	if val, ok := envConfig[ServerAddrEnvName]; ok {
 		env.serverAddr = val
@@ -187,10 +449,10 @@ func NewEnvironment(envConfig map[string]string) *Environment {
 		env.databaseName = val
 	}
 	if val, ok := envConfig[SessionNameLengthEnvName]; ok {
-		env.sessionNameLength = int(env.toInt64(val))
+		env.sessionNameLength = int(env.toInt64(SessionNameLengthEnvName, val))
 	}
 	if val, ok := envConfig[SessionLengthEnvName]; ok {
-		env.sessionLength = env.toInt64(val)
+		env.sessionLength = env.toInt64(SessionLengthEnvName, val)
 	}
 	if val, ok := envConfig[SessionNameAlphabetEnvName]; ok {
 		env.sessionNameAlphabet = val
@@ -207,14 +469,121 @@ func NewEnvironment(envConfig map[string]string) *Environment {
 	if val, ok := envConfig[UploadDirectoryPathEnvName]; ok {
 		env.uploadDirectoryPath = val
 	}
+	if val, ok := envConfig[UploadMaxSizeEnvName]; ok {
+		env.uploadMaxSize = env.toInt64(UploadMaxSizeEnvName, val)
+	}
+	if val, ok := envConfig[UploadRetentionEnvName]; ok {
+		env.uploadRetention = env.toInt64(UploadRetentionEnvName, val)
+	}
+	if val, ok := envConfig[RDAPBaseURLEnvName]; ok {
+		env.rdapBaseURL = val
+	}
+	if val, ok := envConfig[CalendarFeedTokenEnvName]; ok {
+		env.calendarFeedToken = val
+	}
+	if val, ok := envConfig[SMTPHostEnvName]; ok {
+		env.smtpHost = val
+	}
+	if val, ok := envConfig[SMTPPortEnvName]; ok {
+		env.smtpPort = val
+	}
+	if val, ok := envConfig[SMTPUsernameEnvName]; ok {
+		env.smtpUsername = val
+	}
+	if val, ok := envConfig[SMTPPasswordEnvName]; ok {
+		env.smtpPassword = val
+	}
+	if val, ok := envConfig[SMTPFromEnvName]; ok {
+		env.smtpFrom = val
+	}
+	if val, ok := envConfig[PublicURLEnvName]; ok {
+		env.publicURL = val
+	}
+	if val, ok := envConfig[NotificationCheckIntervalEnvName]; ok {
+		env.notificationCheckInterval = env.toInt64(NotificationCheckIntervalEnvName, val)
+	}
+	if val, ok := envConfig[NotificationDigestHourEnvName]; ok {
+		env.notificationDigestHour = int(env.toInt64(NotificationDigestHourEnvName, val))
+	}
+	if val, ok := envConfig[MetricsTokenEnvName]; ok {
+		env.metricsToken = val
+	}
+	if val, ok := envConfig[MetricsScoreThresholdEnvName]; ok {
+		env.metricsScoreThreshold = int(env.toInt64(MetricsScoreThresholdEnvName, val))
+	}
+	if val, ok := envConfig[LogFormatEnvName]; ok {
+		env.logFormat = val
+	}
+	if val, ok := envConfig[LogLevelEnvName]; ok {
+		env.logLevel = val
+	}
+	if val, ok := envConfig[AppEnvEnvName]; ok {
+		env.appEnv = val
+	}
+	if val, ok := envConfig[HealthCheckTimeoutEnvName]; ok {
+		env.healthCheckTimeout = env.toInt64(HealthCheckTimeoutEnvName, val)
+	}
+	if val, ok := envConfig[AutoMigrateEnvName]; ok {
+		env.autoMigrate = env.toBool(AutoMigrateEnvName, val)
+	}
+	if val, ok := envConfig[AssetsFromDiskEnvName]; ok {
+		env.assetsFromDisk = env.toBool(AssetsFromDiskEnvName, val)
+	}
+	if val, ok := envConfig[ShutdownTimeoutEnvName]; ok {
+		env.shutdownTimeout = env.toInt64(ShutdownTimeoutEnvName, val)
+	}
+	if val, ok := envConfig[JobWorkersEnvName]; ok {
+		env.jobWorkers = env.toInt64(JobWorkersEnvName, val)
+	}
+	if val, ok := envConfig[JobPollIntervalEnvName]; ok {
+		env.jobPollInterval = env.toInt64(JobPollIntervalEnvName, val)
+	}
+	if val, ok := envConfig[TLSCertFileEnvName]; ok {
+		env.tlsCertFile = val
+	}
+	if val, ok := envConfig[TLSKeyFileEnvName]; ok {
+		env.tlsKeyFile = val
+	}
+	if val, ok := envConfig[TLSRedirectPortEnvName]; ok {
+		env.tlsRedirectPort = val
+	}
+	if val, ok := envConfig[StorageBackendEnvName]; ok {
+		env.storageBackend = val
+	}
+	if val, ok := envConfig[S3EndpointEnvName]; ok {
+		env.s3Endpoint = val
+	}
+	if val, ok := envConfig[S3RegionEnvName]; ok {
+		env.s3Region = val
+	}
+	if val, ok := envConfig[S3BucketEnvName]; ok {
+		env.s3Bucket = val
+	}
+	if val, ok := envConfig[S3AccessKeyEnvName]; ok {
+		env.s3AccessKey = val
+	}
+	if val, ok := envConfig[S3SecretKeyEnvName]; ok {
+		env.s3SecretKey = val
+	}
 
 	return env
 }
 
-// toInt converts a string to an integer.
-func (e *Environment) toInt64(s string) int64 {
+// toBool converts a string to a boolean. The invalid values are false, and the error is stored for the validation.
+func (e *Environment) toBool(name, s string) bool {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		e.parseErrors = append(e.parseErrors, fmt.Errorf("%s: invalid boolean %q", name, s))
+		return false
+	}
+	return b
+}
+
+// toInt converts a string to an integer. The invalid values are 0, and the error is stored for the validation.
+func (e *Environment) toInt64(name, s string) int64 {
 	i, err := strconv.ParseInt(s, 10, 64)
 	if err != nil {
+		e.parseErrors = append(e.parseErrors, fmt.Errorf("%s: invalid number %q", name, s))
 		return 0
 	}
 	return i
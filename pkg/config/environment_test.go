@@ -25,6 +25,12 @@ func TestDefaultEnvironment(t *testing.T) {
 	if env.GetMigrationDirectoryPath() != DefaultMigrationDirectoryPath {
 		t.Errorf("Expected %s, got %s", DefaultMigrationDirectoryPath, env.GetMigrationDirectoryPath())
 	}
+	if env.GetAutoMigrate() != DefaultAutoMigrate {
+		t.Errorf("Expected %t, got %t", DefaultAutoMigrate, env.GetAutoMigrate())
+	}
+	if env.GetAssetsFromDisk() != DefaultAssetsFromDisk {
+		t.Errorf("Expected %t, got %t", DefaultAssetsFromDisk, env.GetAssetsFromDisk())
+	}
 	if env.GetDatabaseHost() != DefaultDatabaseHost {
 		t.Errorf("Expected %s, got %s", DefaultDatabaseHost, env.GetDatabaseHost())
 	}
@@ -61,6 +67,63 @@ func TestDefaultEnvironment(t *testing.T) {
 	if env.GetUploadDirectoryPath() != DefaultUploadDirectoryPath {
 		t.Errorf("Expected %s, got %s", DefaultUploadDirectoryPath, env.GetUploadDirectoryPath())
 	}
+	if env.GetUploadMaxSize() != DefaultUploadMaxSize || env.GetUploadRetention() != DefaultUploadRetention {
+		t.Errorf("Expected %d and %d, got %d and %d", DefaultUploadMaxSize, DefaultUploadRetention, env.GetUploadMaxSize(), env.GetUploadRetention())
+	}
+	if env.GetRDAPBaseURL() != DefaultRDAPBaseURL {
+		t.Errorf("Expected %s, got %s", DefaultRDAPBaseURL, env.GetRDAPBaseURL())
+	}
+	if env.GetCalendarFeedToken() != DefaultCalendarFeedToken {
+		t.Errorf("Expected %s, got %s", DefaultCalendarFeedToken, env.GetCalendarFeedToken())
+	}
+	if env.GetSMTPHost() != DefaultSMTPHost {
+		t.Errorf("Expected %s, got %s", DefaultSMTPHost, env.GetSMTPHost())
+	}
+	if env.GetSMTPPort() != DefaultSMTPPort {
+		t.Errorf("Expected %s, got %s", DefaultSMTPPort, env.GetSMTPPort())
+	}
+	if env.GetSMTPFrom() != DefaultSMTPFrom {
+		t.Errorf("Expected %s, got %s", DefaultSMTPFrom, env.GetSMTPFrom())
+	}
+	if env.GetPublicURL() != DefaultPublicURL {
+		t.Errorf("Expected %s, got %s", DefaultPublicURL, env.GetPublicURL())
+	}
+	if env.GetNotificationCheckInterval() != DefaultNotificationCheckInterval {
+		t.Errorf("Expected %d, got %d", DefaultNotificationCheckInterval, env.GetNotificationCheckInterval())
+	}
+	if env.GetNotificationDigestHour() != DefaultNotificationDigestHour {
+		t.Errorf("Expected %d, got %d", DefaultNotificationDigestHour, env.GetNotificationDigestHour())
+	}
+	if env.GetMetricsToken() != DefaultMetricsToken {
+		t.Errorf("Expected %s, got %s", DefaultMetricsToken, env.GetMetricsToken())
+	}
+	if env.GetMetricsScoreThreshold() != DefaultMetricsScoreThreshold {
+		t.Errorf("Expected %d, got %d", DefaultMetricsScoreThreshold, env.GetMetricsScoreThreshold())
+	}
+	if env.GetLogFormat() != DefaultLogFormat {
+		t.Errorf("Expected %s, got %s", DefaultLogFormat, env.GetLogFormat())
+	}
+	if env.GetLogLevel() != DefaultLogLevel {
+		t.Errorf("Expected %s, got %s", DefaultLogLevel, env.GetLogLevel())
+	}
+	if env.GetAppEnv() != DefaultAppEnv {
+		t.Errorf("Expected %s, got %s", DefaultAppEnv, env.GetAppEnv())
+	}
+	if env.GetHealthCheckTimeout() != DefaultHealthCheckTimeout {
+		t.Errorf("Expected %d, got %d", DefaultHealthCheckTimeout, env.GetHealthCheckTimeout())
+	}
+	if env.GetShutdownTimeout() != DefaultShutdownTimeout {
+		t.Errorf("Expected %d, got %d", DefaultShutdownTimeout, env.GetShutdownTimeout())
+	}
+	if env.GetJobWorkers() != DefaultJobWorkers || env.GetJobPollInterval() != DefaultJobPollInterval {
+		t.Errorf("Expected %d and %d, got %d and %d", DefaultJobWorkers, DefaultJobPollInterval, env.GetJobWorkers(), env.GetJobPollInterval())
+	}
+	if env.GetTLSEnabled() || env.GetTLSRedirectPort() != DefaultTLSRedirectPort {
+		t.Errorf("Expected disabled tls, got %t, %s", env.GetTLSEnabled(), env.GetTLSRedirectPort())
+	}
+	if env.GetStorageBackend() != DefaultStorageBackend || env.GetS3Region() != DefaultS3Region || env.GetS3Bucket() != DefaultS3Bucket {
+		t.Errorf("Expected %s storage, got %s, %s, %s", DefaultStorageBackend, env.GetStorageBackend(), env.GetS3Region(), env.GetS3Bucket())
+	}
 }
 
 // TestNewEnvironmentWithEmptyValues tests the NewEnvironment function with empty values.
@@ -88,6 +151,12 @@ func TestNewEnvironmentWithEmptyValues(t *testing.T) {
 	if env.GetMigrationDirectoryPath() != DefaultMigrationDirectoryPath {
 		t.Errorf("Expected %s, got %s", DefaultMigrationDirectoryPath, env.GetMigrationDirectoryPath())
 	}
+	if env.GetAutoMigrate() != DefaultAutoMigrate {
+		t.Errorf("Expected %t, got %t", DefaultAutoMigrate, env.GetAutoMigrate())
+	}
+	if env.GetAssetsFromDisk() != DefaultAssetsFromDisk {
+		t.Errorf("Expected %t, got %t", DefaultAssetsFromDisk, env.GetAssetsFromDisk())
+	}
 	if env.GetDatabaseHost() != DefaultDatabaseHost {
 		t.Errorf("Expected %s, got %s", DefaultDatabaseHost, env.GetDatabaseHost())
 	}
@@ -124,6 +193,63 @@ func TestNewEnvironmentWithEmptyValues(t *testing.T) {
 	if env.GetUploadDirectoryPath() != DefaultUploadDirectoryPath {
 		t.Errorf("Expected %s, got %s", DefaultUploadDirectoryPath, env.GetUploadDirectoryPath())
 	}
+	if env.GetUploadMaxSize() != DefaultUploadMaxSize || env.GetUploadRetention() != DefaultUploadRetention {
+		t.Errorf("Expected %d and %d, got %d and %d", DefaultUploadMaxSize, DefaultUploadRetention, env.GetUploadMaxSize(), env.GetUploadRetention())
+	}
+	if env.GetRDAPBaseURL() != DefaultRDAPBaseURL {
+		t.Errorf("Expected %s, got %s", DefaultRDAPBaseURL, env.GetRDAPBaseURL())
+	}
+	if env.GetCalendarFeedToken() != DefaultCalendarFeedToken {
+		t.Errorf("Expected %s, got %s", DefaultCalendarFeedToken, env.GetCalendarFeedToken())
+	}
+	if env.GetSMTPHost() != DefaultSMTPHost {
+		t.Errorf("Expected %s, got %s", DefaultSMTPHost, env.GetSMTPHost())
+	}
+	if env.GetSMTPPort() != DefaultSMTPPort {
+		t.Errorf("Expected %s, got %s", DefaultSMTPPort, env.GetSMTPPort())
+	}
+	if env.GetSMTPFrom() != DefaultSMTPFrom {
+		t.Errorf("Expected %s, got %s", DefaultSMTPFrom, env.GetSMTPFrom())
+	}
+	if env.GetPublicURL() != DefaultPublicURL {
+		t.Errorf("Expected %s, got %s", DefaultPublicURL, env.GetPublicURL())
+	}
+	if env.GetNotificationCheckInterval() != DefaultNotificationCheckInterval {
+		t.Errorf("Expected %d, got %d", DefaultNotificationCheckInterval, env.GetNotificationCheckInterval())
+	}
+	if env.GetNotificationDigestHour() != DefaultNotificationDigestHour {
+		t.Errorf("Expected %d, got %d", DefaultNotificationDigestHour, env.GetNotificationDigestHour())
+	}
+	if env.GetMetricsToken() != DefaultMetricsToken {
+		t.Errorf("Expected %s, got %s", DefaultMetricsToken, env.GetMetricsToken())
+	}
+	if env.GetMetricsScoreThreshold() != DefaultMetricsScoreThreshold {
+		t.Errorf("Expected %d, got %d", DefaultMetricsScoreThreshold, env.GetMetricsScoreThreshold())
+	}
+	if env.GetLogFormat() != DefaultLogFormat {
+		t.Errorf("Expected %s, got %s", DefaultLogFormat, env.GetLogFormat())
+	}
+	if env.GetLogLevel() != DefaultLogLevel {
+		t.Errorf("Expected %s, got %s", DefaultLogLevel, env.GetLogLevel())
+	}
+	if env.GetAppEnv() != DefaultAppEnv {
+		t.Errorf("Expected %s, got %s", DefaultAppEnv, env.GetAppEnv())
+	}
+	if env.GetHealthCheckTimeout() != DefaultHealthCheckTimeout {
+		t.Errorf("Expected %d, got %d", DefaultHealthCheckTimeout, env.GetHealthCheckTimeout())
+	}
+	if env.GetShutdownTimeout() != DefaultShutdownTimeout {
+		t.Errorf("Expected %d, got %d", DefaultShutdownTimeout, env.GetShutdownTimeout())
+	}
+	if env.GetJobWorkers() != DefaultJobWorkers || env.GetJobPollInterval() != DefaultJobPollInterval {
+		t.Errorf("Expected %d and %d, got %d and %d", DefaultJobWorkers, DefaultJobPollInterval, env.GetJobWorkers(), env.GetJobPollInterval())
+	}
+	if env.GetTLSEnabled() || env.GetTLSRedirectPort() != DefaultTLSRedirectPort {
+		t.Errorf("Expected disabled tls, got %t, %s", env.GetTLSEnabled(), env.GetTLSRedirectPort())
+	}
+	if env.GetStorageBackend() != DefaultStorageBackend || env.GetS3Region() != DefaultS3Region || env.GetS3Bucket() != DefaultS3Bucket {
+		t.Errorf("Expected %s storage, got %s, %s, %s", DefaultStorageBackend, env.GetStorageBackend(), env.GetS3Region(), env.GetS3Bucket())
+	}
 }
 
 // TestNewEnvironmentServerWriteTimeout tests the NewEnvironment function with a server write timeout value.
@@ -321,6 +447,14 @@ func TestNewEnvironmentUploadDirectoryPath(t *testing.T) {
 	}
 }
 
+// TestNewEnvironmentUploadLimits tests the NewEnvironment function with upload max size and retention values.
+func TestNewEnvironmentUploadLimits(t *testing.T) {
+	env := NewEnvironment(map[string]string{UploadMaxSizeEnvName: "2", UploadRetentionEnvName: "6"})
+	if env.GetUploadMaxSize() != 2 || env.GetUploadRetention() != 6 {
+		t.Errorf("Expected 2 and 6, got %d and %d", env.GetUploadMaxSize(), env.GetUploadRetention())
+	}
+}
+
 // TestWrongServerWriteTimeout tests the NewEnvironment function with a wrong server write timeout value.
 func TestWrongServerWriteTimeout(t *testing.T) {
 	envList := make(map[string]string)
@@ -331,3 +465,197 @@ func TestWrongServerWriteTimeout(t *testing.T) {
 		t.Errorf("Expected %d, got %d", fallbackTimeout, env.GetServerWriteTimeout())
 	}
 }
+
+// TestNewEnvironmentRDAPBaseURL tests the NewEnvironment function with an RDAP base url value.
+func TestNewEnvironmentRDAPBaseURL(t *testing.T) {
+	envList := make(map[string]string)
+	rdapBaseURL := "http://localhost:8081"
+	envList[RDAPBaseURLEnvName] = rdapBaseURL
+	env := NewEnvironment(envList)
+	if env.GetRDAPBaseURL() != rdapBaseURL {
+		t.Errorf("Expected %s, got %s", rdapBaseURL, env.GetRDAPBaseURL())
+	}
+}
+
+// TestNewEnvironmentCalendarFeedToken tests the NewEnvironment function with a calendar feed token value.
+func TestNewEnvironmentCalendarFeedToken(t *testing.T) {
+	envList := make(map[string]string)
+	calendarFeedToken := "secret-token"
+	envList[CalendarFeedTokenEnvName] = calendarFeedToken
+	env := NewEnvironment(envList)
+	if env.GetCalendarFeedToken() != calendarFeedToken {
+		t.Errorf("Expected %s, got %s", calendarFeedToken, env.GetCalendarFeedToken())
+	}
+}
+
+// TestNewEnvironmentSMTP tests the NewEnvironment function with smtp values.
+func TestNewEnvironmentSMTP(t *testing.T) {
+	envList := make(map[string]string)
+	envList[SMTPHostEnvName] = "mail.example.com"
+	envList[SMTPPortEnvName] = "587"
+	envList[SMTPUsernameEnvName] = "user"
+	envList[SMTPPasswordEnvName] = "password"
+	envList[SMTPFromEnvName] = "noreply@example.com"
+	env := NewEnvironment(envList)
+	if env.GetSMTPHost() != "mail.example.com" {
+		t.Errorf("Expected mail.example.com, got %s", env.GetSMTPHost())
+	}
+	if env.GetSMTPPort() != "587" {
+		t.Errorf("Expected 587, got %s", env.GetSMTPPort())
+	}
+	if env.GetSMTPUsername() != "user" {
+		t.Errorf("Expected user, got %s", env.GetSMTPUsername())
+	}
+	if env.GetSMTPPassword() != "password" {
+		t.Errorf("Expected password, got %s", env.GetSMTPPassword())
+	}
+	if env.GetSMTPFrom() != "noreply@example.com" {
+		t.Errorf("Expected noreply@example.com, got %s", env.GetSMTPFrom())
+	}
+}
+
+// TestNewEnvironmentPublicURL tests the NewEnvironment function with a public url value.
+func TestNewEnvironmentPublicURL(t *testing.T) {
+	envList := make(map[string]string)
+	publicURL := "https://projectregister.example.com"
+	envList[PublicURLEnvName] = publicURL
+	env := NewEnvironment(envList)
+	if env.GetPublicURL() != publicURL {
+		t.Errorf("Expected %s, got %s", publicURL, env.GetPublicURL())
+	}
+}
+
+// TestNewEnvironmentNotification tests the NewEnvironment function with notification values.
+func TestNewEnvironmentNotification(t *testing.T) {
+	envList := make(map[string]string)
+	envList[NotificationCheckIntervalEnvName] = "15"
+	envList[NotificationDigestHourEnvName] = "6"
+	env := NewEnvironment(envList)
+	if env.GetNotificationCheckInterval() != 15 {
+		t.Errorf("Expected 15, got %d", env.GetNotificationCheckInterval())
+	}
+	if env.GetNotificationCheckSchedule() != "*/15 * * * *" {
+		t.Errorf("Expected */15 * * * *, got %s", env.GetNotificationCheckSchedule())
+	}
+	if env.GetNotificationDigestHour() != 6 {
+		t.Errorf("Expected 6, got %d", env.GetNotificationDigestHour())
+	}
+}
+
+// TestNewEnvironmentMetrics tests the NewEnvironment function with metrics values.
+func TestNewEnvironmentMetrics(t *testing.T) {
+	envList := make(map[string]string)
+	envList[MetricsTokenEnvName] = "secret"
+	envList[MetricsScoreThresholdEnvName] = "30"
+	env := NewEnvironment(envList)
+	if env.GetMetricsToken() != "secret" {
+		t.Errorf("Expected secret, got %s", env.GetMetricsToken())
+	}
+	if env.GetMetricsScoreThreshold() != 30 {
+		t.Errorf("Expected 30, got %d", env.GetMetricsScoreThreshold())
+	}
+}
+
+// TestNewEnvironmentLog tests the NewEnvironment function with log and application environment values.
+func TestNewEnvironmentLog(t *testing.T) {
+	envList := make(map[string]string)
+	envList[LogFormatEnvName] = "json"
+	envList[LogLevelEnvName] = "debug"
+	envList[AppEnvEnvName] = AppEnvDevelopment
+	env := NewEnvironment(envList)
+	if env.GetLogFormat() != "json" {
+		t.Errorf("Expected json, got %s", env.GetLogFormat())
+	}
+	if env.GetLogLevel() != "debug" {
+		t.Errorf("Expected debug, got %s", env.GetLogLevel())
+	}
+	if env.GetAppEnv() != AppEnvDevelopment {
+		t.Errorf("Expected %s, got %s", AppEnvDevelopment, env.GetAppEnv())
+	}
+}
+
+// TestNewEnvironmentHealthCheck tests the NewEnvironment function with health check values.
+func TestNewEnvironmentHealthCheck(t *testing.T) {
+	envList := make(map[string]string)
+	envList[HealthCheckTimeoutEnvName] = "5"
+	env := NewEnvironment(envList)
+	if env.GetHealthCheckTimeout() != 5 {
+		t.Errorf("Expected 5, got %d", env.GetHealthCheckTimeout())
+	}
+}
+
+// TestNewEnvironmentShutdownTimeout tests the NewEnvironment function with a shutdown timeout value.
+func TestNewEnvironmentShutdownTimeout(t *testing.T) {
+	env := NewEnvironment(map[string]string{ShutdownTimeoutEnvName: "30"})
+	if env.GetShutdownTimeout() != 30 {
+		t.Errorf("Expected 30, got %d", env.GetShutdownTimeout())
+	}
+}
+
+// TestNewEnvironmentJobs tests the NewEnvironment function with the job worker values.
+func TestNewEnvironmentJobs(t *testing.T) {
+	env := NewEnvironment(map[string]string{JobWorkersEnvName: "4", JobPollIntervalEnvName: "1"})
+	if env.GetJobWorkers() != 4 || env.GetJobPollInterval() != 1 {
+		t.Errorf("Expected 4 and 1, got %d and %d", env.GetJobWorkers(), env.GetJobPollInterval())
+	}
+}
+
+// TestNewEnvironmentAutoMigrate tests the NewEnvironment function with the automatic migration values.
+func TestNewEnvironmentAutoMigrate(t *testing.T) {
+	testData := []struct {
+		value    string
+		expected bool
+	}{
+		{"false", false},
+		{"0", false},
+		{"true", true},
+		{"invalid", false},
+	}
+	for _, tt := range testData {
+		env := NewEnvironment(map[string]string{AutoMigrateEnvName: tt.value})
+		if env.GetAutoMigrate() != tt.expected {
+			t.Errorf("Expected %t for '%s', got %t", tt.expected, tt.value, env.GetAutoMigrate())
+		}
+	}
+}
+
+// TestNewEnvironmentAssetsFromDisk tests the NewEnvironment function with the disk based assets value.
+func TestNewEnvironmentAssetsFromDisk(t *testing.T) {
+	env := NewEnvironment(map[string]string{AssetsFromDiskEnvName: "true"})
+	if !env.GetAssetsFromDisk() {
+		t.Errorf("Expected true, got %t", env.GetAssetsFromDisk())
+	}
+}
+
+// TestNewEnvironmentStorage tests the NewEnvironment function with the s3 storage values.
+func TestNewEnvironmentStorage(t *testing.T) {
+	env := NewEnvironment(map[string]string{
+		StorageBackendEnvName: StorageBackendS3,
+		S3EndpointEnvName:     "http://minio:9000",
+		S3RegionEnvName:       "eu-central-1",
+		S3BucketEnvName:       "uploads",
+		S3AccessKeyEnvName:    "access",
+		S3SecretKeyEnvName:    "secret",
+	})
+	if env.GetStorageBackend() != StorageBackendS3 || env.GetS3Endpoint() != "http://minio:9000" || env.GetS3Region() != "eu-central-1" {
+		t.Errorf("Expected s3 storage, got %s, %s, %s", env.GetStorageBackend(), env.GetS3Endpoint(), env.GetS3Region())
+	}
+	if env.GetS3Bucket() != "uploads" || env.GetS3AccessKey() != "access" || env.GetS3SecretKey() != "secret" {
+		t.Errorf("Expected uploads, access and secret, got %s, %s, %s", env.GetS3Bucket(), env.GetS3AccessKey(), env.GetS3SecretKey())
+	}
+}
+
+// TestNewEnvironmentTLS tests the NewEnvironment function with the tls values.
+func TestNewEnvironmentTLS(t *testing.T) {
+	env := NewEnvironment(map[string]string{TLSCertFileEnvName: "cert.pem", TLSRedirectPortEnvName: "8080"})
+	if env.GetTLSCertFile() != "cert.pem" || env.GetTLSRedirectPort() != "8080" {
+		t.Errorf("Expected cert.pem and 8080, got %s and %s", env.GetTLSCertFile(), env.GetTLSRedirectPort())
+	}
+	if env.GetTLSEnabled() {
+		t.Error("Expected disabled tls without the key file.")
+	}
+	env = NewEnvironment(map[string]string{TLSCertFileEnvName: "cert.pem", TLSKeyFileEnvName: "key.pem"})
+	if !env.GetTLSEnabled() || env.GetTLSKeyFile() != "key.pem" {
+		t.Errorf("Expected enabled tls with key.pem, got %t and %s", env.GetTLSEnabled(), env.GetTLSKeyFile())
+	}
+}
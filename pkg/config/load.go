@@ -0,0 +1,111 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load merges the configuration sources into a variable map, that could be passed to the NewEnvironment.
+// The process environment overrides the .env file, that overrides the optional yaml config file.
+// The config file is set with the CONFIG_FILE variable of the .env file or the process environment.
+// Its keys are the variable names or nested sections, eg. the password of the database section is DATABASE_PASSWORD.
+// The VARIABLE_FILE variables set the VARIABLE to the content of the file, so that the secrets could be mounted as files.
+// Every problem is reported in the returned error.
+func Load(dotenvConfig map[string]string, environ []string) (map[string]string, error) {
+	overrides := make(map[string]string)
+	for name, value := range dotenvConfig {
+		overrides[name] = value
+	}
+	for _, entry := range environ {
+		name, value, ok := strings.Cut(entry, "=")
+		if ok && isLoadable(name) {
+			overrides[name] = value
+		}
+	}
+	var errs []error
+	result := make(map[string]string)
+	if path := overrides[ConfigFileEnvName]; path != "" {
+		fileConfig, err := readConfigFile(path)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for name, value := range fileConfig {
+			result[name] = value
+		}
+	}
+	for name, value := range overrides {
+		if isLoadable(name) {
+			result[name] = value
+		}
+	}
+	// The file variables are resolved after the merge, they take precedence over the plain variables.
+	for _, v := range variables {
+		path, ok := result[v.name+FileEnvNameSuffix]
+		if !ok {
+			continue
+		}
+		delete(result, v.name+FileEnvNameSuffix)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", v.name+FileEnvNameSuffix, err))
+			continue
+		}
+		result[v.name] = strings.TrimRight(string(content), "\r\n")
+	}
+	delete(result, ConfigFileEnvName)
+	return result, errors.Join(errs...)
+}
+
+// isLoadable returns true if the name is a configuration variable, a file variable or the config file variable.
+func isLoadable(name string) bool {
+	if name == ConfigFileEnvName {
+		return true
+	}
+	return isVariable(strings.TrimSuffix(name, FileEnvNameSuffix))
+}
+
+// readConfigFile reads the yaml config file. The nested sections are flattened, and the unknown keys are reported.
+func readConfigFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ConfigFileEnvName, err)
+	}
+	var document map[string]interface{}
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("%s: %s: %w", ConfigFileEnvName, path, err)
+	}
+	result := make(map[string]string)
+	var errs []error
+	flatten("", document, result, &errs)
+	for name := range result {
+		if name == ConfigFileEnvName || !isLoadable(name) {
+			errs = append(errs, fmt.Errorf("%s: %s: unknown key %s", ConfigFileEnvName, path, name))
+			delete(result, name)
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// flatten stores the scalar values of the section with the upper case, underscore separated key path.
+func flatten(prefix string, section map[string]interface{}, result map[string]string, errs *[]error) {
+	for key, value := range section {
+		name := strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flatten(name, v, result, errs)
+		case []interface{}:
+			*errs = append(*errs, fmt.Errorf("%s: %s: lists are not supported", ConfigFileEnvName, name))
+		case nil:
+			result[name] = ""
+		default:
+			result[name] = fmt.Sprint(v)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile writes the content to a file of the temporary directory, and returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestLoadLayers tests that the process environment overrides the .env file, that overrides the config file.
+func TestLoadLayers(t *testing.T) {
+	configFile := writeFile(t, "config.yaml", `
+server:
+  port: 8080
+  addr: 127.0.0.1
+database:
+  host: config-host
+  name: config-name
+LOG_LEVEL: debug
+notification:
+  check_interval: 30
+`)
+	dotenvConfig := map[string]string{
+		ConfigFileEnvName:   configFile,
+		DatabaseHostEnvName: "dotenv-host",
+		DatabaseNameEnvName: "dotenv-name",
+	}
+	environ := []string{"DATABASE_NAME=environ-name", "PATH=/usr/bin"}
+	result, err := Load(dotenvConfig, environ)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		ServerPortEnvName:                "8080",
+		ServerAddrEnvName:                "127.0.0.1",
+		DatabaseHostEnvName:              "dotenv-host",
+		DatabaseNameEnvName:              "environ-name",
+		LogLevelEnvName:                  "debug",
+		NotificationCheckIntervalEnvName: "30",
+	}
+	if len(result) != len(expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	for name, value := range expected {
+		if result[name] != value {
+			t.Errorf("Expected %s for %s, got %s", value, name, result[name])
+		}
+	}
+}
+
+// TestLoadFileVariables tests that the secrets are read from the files.
+func TestLoadFileVariables(t *testing.T) {
+	secretFile := writeFile(t, "password", "secret\n")
+	result, err := Load(map[string]string{DatabasePasswordEnvName: "password"}, []string{"DATABASE_PASSWORD_FILE=" + secretFile})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result[DatabasePasswordEnvName] != "secret" {
+		t.Errorf("Expected secret, got %s", result[DatabasePasswordEnvName])
+	}
+	if _, ok := result[DatabasePasswordEnvName+FileEnvNameSuffix]; ok {
+		t.Error("The file variable is not removed.")
+	}
+	if _, err := Load(nil, []string{"SMTP_PASSWORD_FILE=/missing/password"}); err == nil || !strings.Contains(err.Error(), "SMTP_PASSWORD_FILE") {
+		t.Errorf("Expected missing file error, got %v", err)
+	}
+}
+
+// TestLoadInvalidConfigFile tests the unknown keys, the lists and the missing config file.
+func TestLoadInvalidConfigFile(t *testing.T) {
+	configFile := writeFile(t, "config.yaml", `
+server:
+  port: 8080
+  colour: blue
+database:
+  hosts: [a, b]
+`)
+	result, err := Load(map[string]string{ConfigFileEnvName: configFile}, nil)
+	if err == nil || !strings.Contains(err.Error(), "unknown key SERVER_COLOUR") || !strings.Contains(err.Error(), "DATABASE_HOSTS: lists are not supported") {
+		t.Errorf("Expected unknown key and list errors, got %v", err)
+	}
+	if result[ServerPortEnvName] != "8080" {
+		t.Errorf("Expected the valid keys to be loaded, got %v", result)
+	}
+	if _, err := Load(nil, []string{"CONFIG_FILE=/missing/config.yaml"}); err == nil {
+		t.Error("Expected missing config file error.")
+	}
+}
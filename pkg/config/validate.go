@@ -0,0 +1,171 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Validate checks the configuration. Every problem is reported in the returned error,
+// including the invalid numbers and booleans that are replaced with the zero value by the NewEnvironment.
+func (e *Environment) Validate() error {
+	errs := append([]error{}, e.parseErrors...)
+	check := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	check(validatePort(ServerPortEnvName, e.serverPort))
+	check(validatePort(DatabasePortEnvName, e.databasePort))
+	check(validatePort(SMTPPortEnvName, e.smtpPort))
+	check(validatePositive(ServerWriteTimeoutEnvName, e.serverWriteTimeout))
+	check(validatePositive(ServerReadTimeoutEnvName, e.serverReadTimeout))
+	check(validatePositive(ServerIdleTimeoutEnvName, e.serverIdleTimeout))
+	check(validatePositive(SessionLengthEnvName, e.sessionLength))
+	check(validatePositive(HealthCheckTimeoutEnvName, e.healthCheckTimeout))
+	check(validatePositive(ShutdownTimeoutEnvName, e.shutdownTimeout))
+	check(validatePositive(JobWorkersEnvName, e.jobWorkers))
+	check(validatePositive(JobPollIntervalEnvName, e.jobPollInterval))
+	check(validatePositive(UploadMaxSizeEnvName, e.uploadMaxSize))
+	check(validatePositive(UploadRetentionEnvName, e.uploadRetention))
+	if e.sessionNameLength < MinSessionNameLength {
+		errs = append(errs, fmt.Errorf("%s: %d is shorter than %d", SessionNameLengthEnvName, e.sessionNameLength, MinSessionNameLength))
+	}
+	if distinct := distinctCharacters(e.sessionNameAlphabet); distinct < MinSessionNameAlphabetLength {
+		errs = append(errs, fmt.Errorf("%s: %d distinct characters, at least %d is required", SessionNameAlphabetEnvName, distinct, MinSessionNameAlphabetLength))
+	}
+	if e.notificationDigestHour < 0 || e.notificationDigestHour > 23 {
+		errs = append(errs, fmt.Errorf("%s: %d is not an hour of the day", NotificationDigestHourEnvName, e.notificationDigestHour))
+	}
+	if e.notificationCheckInterval != 0 {
+		if _, err := intervalSchedule(e.notificationCheckInterval); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", NotificationCheckIntervalEnvName, err))
+		}
+	}
+	check(validateOneOf(LogFormatEnvName, e.logFormat, "text", "json"))
+	check(validateOneOf(LogLevelEnvName, e.logLevel, "debug", "info", "warn", "warning", "error"))
+	check(validateOneOf(AppEnvEnvName, e.appEnv, AppEnvProduction, AppEnvDevelopment))
+	check(validateURL(PublicURLEnvName, e.publicURL))
+	if e.rdapBaseURL != "" {
+		check(validateURL(RDAPBaseURLEnvName, e.rdapBaseURL))
+	}
+	if e.renderBaseTemplate == "" {
+		errs = append(errs, fmt.Errorf("%s: the base template is required", RenderBaseTemplateEnvName))
+	}
+	check(validateOneOf(StorageBackendEnvName, e.storageBackend, StorageBackendLocal, StorageBackendS3))
+	// The upload directory is used only by the local storage, the s3 storage needs the bucket.
+	if strings.EqualFold(e.storageBackend, StorageBackendS3) {
+		check(validateURL(S3EndpointEnvName, e.s3Endpoint))
+		if e.s3Bucket == "" {
+			errs = append(errs, fmt.Errorf("%s: the bucket is required", S3BucketEnvName))
+		}
+		if e.s3AccessKey == "" || e.s3SecretKey == "" {
+			errs = append(errs, fmt.Errorf("%s, %s: the credentials are required", S3AccessKeyEnvName, S3SecretKeyEnvName))
+		}
+	} else {
+		check(validateDirectory(UploadDirectoryPathEnvName, e.uploadDirectoryPath))
+	}
+	// The asset directories are used only if the embedded files are overridden.
+	if e.assetsFromDisk {
+		check(validateDirectory(RenderTemplateDirectoryPathEnvName, e.renderTemplateDirectoryPath))
+		check(validateDirectory(StaticDirectoryPathEnvName, e.staticDirectoryPath))
+		check(validateDirectory(MigrationDirectoryPathEnvName, e.migrationDirectoryPath))
+	}
+	// The https needs both files, and the redirect listener is used only with https.
+	if (e.tlsCertFile == "") != (e.tlsKeyFile == "") {
+		errs = append(errs, fmt.Errorf("%s, %s: both or none of them has to be set", TLSCertFileEnvName, TLSKeyFileEnvName))
+	}
+	if e.GetTLSEnabled() {
+		check(validateFile(TLSCertFileEnvName, e.tlsCertFile))
+		check(validateFile(TLSKeyFileEnvName, e.tlsKeyFile))
+	}
+	if e.tlsRedirectPort != "" {
+		check(validatePort(TLSRedirectPortEnvName, e.tlsRedirectPort))
+		if !e.GetTLSEnabled() {
+			errs = append(errs, fmt.Errorf("%s: the redirect listener needs the https", TLSRedirectPortEnvName))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validatePort checks that the value is a tcp port number.
+func validatePort(name, value string) error {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("%s: invalid port %q", name, value)
+	}
+	return nil
+}
+
+// validatePositive checks that the value is greater than zero.
+func validatePositive(name string, value int64) error {
+	if value <= 0 {
+		return fmt.Errorf("%s: %d is not positive", name, value)
+	}
+	return nil
+}
+
+// validateOneOf checks that the value is one of the allowed values. The comparison is case insensitive.
+func validateOneOf(name, value string, allowed ...string) error {
+	for _, a := range allowed {
+		if strings.EqualFold(value, a) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: %q is not one of %s", name, value, strings.Join(allowed, ", "))
+}
+
+// validateURL checks that the value is an absolute http or https url.
+func validateURL(name, value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s: invalid url %q", name, value)
+	}
+	return nil
+}
+
+// validateDirectory checks that the path is a readable directory.
+func validateDirectory(name, path string) error {
+	if _, err := os.ReadDir(path); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// validateFile checks that the path is a readable file.
+func validateFile(name, path string) error {
+	if _, err := os.ReadFile(path); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// distinctCharacters returns the number of the distinct characters of the string.
+func distinctCharacters(s string) int {
+	characters := make(map[rune]struct{})
+	for _, c := range s {
+		characters[c] = struct{}{}
+	}
+	return len(characters)
+}
+
+// intervalSchedule returns the cron expression that runs in every given minutes. The interval has to divide
+// an hour or a day, eg. 15 is */15 * * * *, 120 is 0 */2 * * *. The 0 interval is the empty expression.
+func intervalSchedule(minutes int64) (string, error) {
+	switch {
+	case minutes == 0:
+		return "", nil
+	case minutes < 0:
+		return "", fmt.Errorf("%d is not positive", minutes)
+	case minutes < 60 && 60%minutes == 0:
+		return fmt.Sprintf("*/%d * * * *", minutes), nil
+	case minutes == 24*60:
+		return "0 0 * * *", nil
+	case minutes%60 == 0 && 24%(minutes/60) == 0:
+		return fmt.Sprintf("0 */%d * * *", minutes/60), nil
+	}
+	return "", fmt.Errorf("%d minutes does not divide an hour or a day, set the schedule of the expiry notifications task instead", minutes)
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestValidateDefaults tests that the default configuration is valid with an existing upload directory.
+func TestValidateDefaults(t *testing.T) {
+	env := NewEnvironment(map[string]string{UploadDirectoryPathEnvName: t.TempDir()})
+	if err := env.Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+// TestValidateAggregated tests that every problem is reported in the validation error.
+func TestValidateAggregated(t *testing.T) {
+	env := NewEnvironment(map[string]string{
+		ServerPortEnvName:                "99999",
+		DatabasePortEnvName:              "postgres",
+		ServerWriteTimeoutEnvName:        "wrong",
+		AutoMigrateEnvName:               "maybe",
+		SessionNameAlphabetEnvName:       "abc",
+		SessionNameLengthEnvName:         "8",
+		NotificationDigestHourEnvName:    "24",
+		NotificationCheckIntervalEnvName: "45",
+		LogFormatEnvName:                 "xml",
+		PublicURLEnvName:                 "localhost",
+		UploadDirectoryPathEnvName:       "/missing/uploads",
+	})
+	err := env.Validate()
+	if err == nil {
+		t.Fatal("Expected validation error.")
+	}
+	for _, name := range []string{
+		ServerPortEnvName,
+		DatabasePortEnvName,
+		ServerWriteTimeoutEnvName + ": invalid number",
+		ServerWriteTimeoutEnvName + ": 0 is not positive",
+		AutoMigrateEnvName,
+		SessionNameAlphabetEnvName,
+		SessionNameLengthEnvName,
+		NotificationDigestHourEnvName,
+		NotificationCheckIntervalEnvName,
+		LogFormatEnvName,
+		PublicURLEnvName,
+		UploadDirectoryPathEnvName,
+	} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("Expected %s in the error, got %v", name, err)
+		}
+	}
+}
+
+// TestValidateAssetsFromDisk tests that the asset directories are checked only if they are used.
+func TestValidateAssetsFromDisk(t *testing.T) {
+	envConfig := map[string]string{
+		UploadDirectoryPathEnvName:         t.TempDir(),
+		RenderTemplateDirectoryPathEnvName: "/missing/template",
+	}
+	if err := NewEnvironment(envConfig).Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	envConfig[AssetsFromDiskEnvName] = "true"
+	if err := NewEnvironment(envConfig).Validate(); err == nil || !strings.Contains(err.Error(), RenderTemplateDirectoryPathEnvName) {
+		t.Errorf("Expected template directory error, got %v", err)
+	}
+}
+
+// TestValidateTLS tests the tls file pair and the redirect port.
+func TestValidateTLS(t *testing.T) {
+	uploadDirectory := t.TempDir()
+	certFile := writeFile(t, "cert.pem", "certificate")
+	testData := []struct {
+		envConfig     map[string]string
+		expectedError string
+	}{
+		{map[string]string{TLSCertFileEnvName: certFile, TLSKeyFileEnvName: certFile, TLSRedirectPortEnvName: "8080"}, ""},
+		{map[string]string{TLSCertFileEnvName: certFile}, "both or none"},
+		{map[string]string{TLSCertFileEnvName: certFile, TLSKeyFileEnvName: "/missing/key.pem"}, TLSKeyFileEnvName},
+		{map[string]string{TLSRedirectPortEnvName: "8080"}, "needs the https"},
+	}
+	for _, tt := range testData {
+		tt.envConfig[UploadDirectoryPathEnvName] = uploadDirectory
+		err := NewEnvironment(tt.envConfig).Validate()
+		if tt.expectedError == "" && err != nil {
+			t.Errorf("Unexpected error for %v: %v", tt.envConfig, err)
+		}
+		if tt.expectedError != "" && (err == nil || !strings.Contains(err.Error(), tt.expectedError)) {
+			t.Errorf("Expected %s error for %v, got %v", tt.expectedError, tt.envConfig, err)
+		}
+	}
+}
+
+// TestValidateStorage tests that the upload directory is checked only for the local storage, and the s3 settings for the s3 storage.
+func TestValidateStorage(t *testing.T) {
+	s3Config := map[string]string{
+		StorageBackendEnvName:      StorageBackendS3,
+		UploadDirectoryPathEnvName: "/missing/uploads",
+		S3EndpointEnvName:          "http://minio:9000",
+		S3BucketEnvName:            "uploads",
+		S3AccessKeyEnvName:         "access",
+		S3SecretKeyEnvName:         "secret",
+	}
+	testData := []struct {
+		envConfig     map[string]string
+		expectedError string
+	}{
+		{s3Config, ""},
+		{map[string]string{StorageBackendEnvName: "ftp", UploadDirectoryPathEnvName: t.TempDir()}, StorageBackendEnvName},
+		{map[string]string{StorageBackendEnvName: StorageBackendS3, S3EndpointEnvName: "minio:9000"}, S3EndpointEnvName},
+		{map[string]string{StorageBackendEnvName: StorageBackendS3, S3EndpointEnvName: "http://minio:9000"}, S3BucketEnvName},
+		{map[string]string{StorageBackendEnvName: StorageBackendS3, S3EndpointEnvName: "http://minio:9000", S3BucketEnvName: "uploads"}, "the credentials are required"},
+	}
+	for _, tt := range testData {
+		err := NewEnvironment(tt.envConfig).Validate()
+		if tt.expectedError == "" && err != nil {
+			t.Errorf("Unexpected error for %v: %v", tt.envConfig, err)
+		}
+		if tt.expectedError != "" && (err == nil || !strings.Contains(err.Error(), tt.expectedError)) {
+			t.Errorf("Expected %s error for %v, got %v", tt.expectedError, tt.envConfig, err)
+		}
+	}
+}
+
+// TestRedacted tests that the secrets are redacted in the printed configuration.
+func TestRedacted(t *testing.T) {
+	env := NewEnvironment(map[string]string{DatabasePasswordEnvName: "secret", MetricsTokenEnvName: ""})
+	values := make(map[string]string)
+	for _, v := range env.Redacted() {
+		values[v.Name] = v.Value
+	}
+	if len(values) != len(variables) {
+		t.Errorf("Expected %d variables, got %d", len(variables), len(values))
+	}
+	if values[DatabasePasswordEnvName] != RedactedValue {
+		t.Errorf("Expected redacted password, got %s", values[DatabasePasswordEnvName])
+	}
+	if values[MetricsTokenEnvName] != "" {
+		t.Errorf("Expected empty token, got %s", values[MetricsTokenEnvName])
+	}
+	if values[DatabaseUserEnvName] != DefaultDatabaseUser {
+		t.Errorf("Expected %s, got %s", DefaultDatabaseUser, values[DatabaseUserEnvName])
+	}
+}
+
+// TestIntervalSchedule tests the cron expressions of the deprecated notification check interval.
+func TestIntervalSchedule(t *testing.T) {
+	testData := []struct {
+		minutes  int64
+		expected string
+		valid    bool
+	}{
+		{0, "", true},
+		{1, "*/1 * * * *", true},
+		{15, "*/15 * * * *", true},
+		{60, "0 */1 * * *", true},
+		{120, "0 */2 * * *", true},
+		{1440, "0 0 * * *", true},
+		{-5, "", false},
+		{45, "", false},
+		{300, "", false},
+		{2880, "", false},
+	}
+	for _, tt := range testData {
+		schedule, err := intervalSchedule(tt.minutes)
+		if (err == nil) != tt.valid {
+			t.Errorf("Unexpected error for %d: %v", tt.minutes, err)
+		}
+		if schedule != tt.expected {
+			t.Errorf("Expected %q for %d, got %q", tt.expected, tt.minutes, schedule)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"strconv"
+)
+
+// Variable is a configuration variable with its value.
+type Variable struct {
+	Name  string
+	Value string
+}
+
+// variable describes a configuration variable. The secrets are redacted in the printed configuration.
+type variable struct {
+	name   string
+	secret bool
+	value  func(e *Environment) string
+}
+
+// variables is the list of the configuration variables in the order of the .env.example file.
+var variables = []variable{
+	{ServerWriteTimeoutEnvName, false, func(e *Environment) string { return strconv.FormatInt(e.serverWriteTimeout, 10) }},
+	{ServerReadTimeoutEnvName, false, func(e *Environment) string { return strconv.FormatInt(e.serverReadTimeout, 10) }},
+	{ServerIdleTimeoutEnvName, false, func(e *Environment) string { return strconv.FormatInt(e.serverIdleTimeout, 10) }},
+	{ServerAddrEnvName, false, func(e *Environment) string { return e.serverAddr }},
+	{ServerPortEnvName, false, func(e *Environment) string { return e.serverPort }},
+	{MigrationDirectoryPathEnvName, false, func(e *Environment) string { return e.migrationDirectoryPath }},
+	{AutoMigrateEnvName, false, func(e *Environment) string { return strconv.FormatBool(e.autoMigrate) }},
+	{DatabaseUserEnvName, false, func(e *Environment) string { return e.databaseUser }},
+	{DatabasePasswordEnvName, true, func(e *Environment) string { return e.databasePassword }},
+	{DatabaseHostEnvName, false, func(e *Environment) string { return e.databaseHost }},
+	{DatabasePortEnvName, false, func(e *Environment) string { return e.databasePort }},
+	{DatabaseNameEnvName, false, func(e *Environment) string { return e.databaseName }},
+	{SessionNameLengthEnvName, false, func(e *Environment) string { return strconv.Itoa(e.sessionNameLength) }},
+	{SessionLengthEnvName, false, func(e *Environment) string { return strconv.FormatInt(e.sessionLength, 10) }},
+	{SessionNameAlphabetEnvName, false, func(e *Environment) string { return e.sessionNameAlphabet }},
+	{RenderTemplateDirectoryPathEnvName, false, func(e *Environment) string { return e.renderTemplateDirectoryPath }},
+	{RenderBaseTemplateEnvName, false, func(e *Environment) string { return e.renderBaseTemplate }},
+	{StaticDirectoryPathEnvName, false, func(e *Environment) string { return e.staticDirectoryPath }},
+	{UploadDirectoryPathEnvName, false, func(e *Environment) string { return e.uploadDirectoryPath }},
+	{UploadMaxSizeEnvName, false, func(e *Environment) string { return strconv.FormatInt(e.uploadMaxSize, 10) }},
+	{UploadRetentionEnvName, false, func(e *Environment) string { return strconv.FormatInt(e.uploadRetention, 10) }},
+	{AssetsFromDiskEnvName, false, func(e *Environment) string { return strconv.FormatBool(e.assetsFromDisk) }},
+	{RDAPBaseURLEnvName, false, func(e *Environment) string { return e.rdapBaseURL }},
+	{CalendarFeedTokenEnvName, true, func(e *Environment) string { return e.calendarFeedToken }},
+	{SMTPHostEnvName, false, func(e *Environment) string { return e.smtpHost }},
+	{SMTPPortEnvName, false, func(e *Environment) string { return e.smtpPort }},
+	{SMTPUsernameEnvName, false, func(e *Environment) string { return e.smtpUsername }},
+	{SMTPPasswordEnvName, true, func(e *Environment) string { return e.smtpPassword }},
+	{SMTPFromEnvName, false, func(e *Environment) string { return e.smtpFrom }},
+	{PublicURLEnvName, false, func(e *Environment) string { return e.publicURL }},
+	{NotificationCheckIntervalEnvName, false, func(e *Environment) string { return strconv.FormatInt(e.notificationCheckInterval, 10) }},
+	{NotificationDigestHourEnvName, false, func(e *Environment) string { return strconv.Itoa(e.notificationDigestHour) }},
+	{MetricsTokenEnvName, true, func(e *Environment) string { return e.metricsToken }},
+	{MetricsScoreThresholdEnvName, false, func(e *Environment) string { return strconv.Itoa(e.metricsScoreThreshold) }},
+	{LogFormatEnvName, false, func(e *Environment) string { return e.logFormat }},
+	{LogLevelEnvName, false, func(e *Environment) string { return e.logLevel }},
+	{AppEnvEnvName, false, func(e *Environment) string { return e.appEnv }},
+	{HealthCheckTimeoutEnvName, false, func(e *Environment) string { return strconv.FormatInt(e.healthCheckTimeout, 10) }},
+	{ShutdownTimeoutEnvName, false, func(e *Environment) string { return strconv.FormatInt(e.shutdownTimeout, 10) }},
+	{JobWorkersEnvName, false, func(e *Environment) string { return strconv.FormatInt(e.jobWorkers, 10) }},
+	{JobPollIntervalEnvName, false, func(e *Environment) string { return strconv.FormatInt(e.jobPollInterval, 10) }},
+	{TLSCertFileEnvName, false, func(e *Environment) string { return e.tlsCertFile }},
+	{TLSKeyFileEnvName, false, func(e *Environment) string { return e.tlsKeyFile }},
+	{TLSRedirectPortEnvName, false, func(e *Environment) string { return e.tlsRedirectPort }},
+	{StorageBackendEnvName, false, func(e *Environment) string { return e.storageBackend }},
+	{S3EndpointEnvName, false, func(e *Environment) string { return e.s3Endpoint }},
+	{S3RegionEnvName, false, func(e *Environment) string { return e.s3Region }},
+	{S3BucketEnvName, false, func(e *Environment) string { return e.s3Bucket }},
+	{S3AccessKeyEnvName, false, func(e *Environment) string { return e.s3AccessKey }},
+	{S3SecretKeyEnvName, true, func(e *Environment) string { return e.s3SecretKey }},
+}
+
+// isVariable returns true if the name is a configuration variable.
+func isVariable(name string) bool {
+	for _, v := range variables {
+		if v.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Redacted returns the configuration variables with their values. The values of the secrets are replaced
+// with the RedactedValue, unless they are empty, so that the missing secrets are still visible.
+func (e *Environment) Redacted() []Variable {
+	result := make([]Variable, 0, len(variables))
+	for _, v := range variables {
+		value := v.value(e)
+		if v.secret && value != "" {
+			value = RedactedValue
+		}
+		result = append(result, Variable{Name: v.name, Value: value})
+	}
+	return result
+}
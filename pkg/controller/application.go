@@ -2,6 +2,7 @@ package controller
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -19,7 +20,11 @@ import (
 // GET /admin/application/view/{applicationId}
 // It renders the application view page.
 func (c *Controller) ApplicationViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("applications.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -32,7 +37,7 @@ func (c *Controller) ApplicationViewController(w http.ResponseWriter, r *http.Re
 	content := response.NewApplicationDetailResponse(currentUser, application)
 	err = c.renderer.Template.RenderTemplate(w, "detail-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
 
@@ -47,7 +52,7 @@ func (c *Controller) applicationViewData(r *http.Request) (*model.Application, i
 	}
 	application, err := c.repositoryContainer.GetApplicationRepository().GetApplicationByID(applicationID)
 	if err != nil {
-		return nil, http.StatusInternalServerError, err
+		return nil, repositoryErrorStatus(err), err
 	}
 	return application, http.StatusOK, nil
 }
@@ -56,7 +61,11 @@ func (c *Controller) applicationViewData(r *http.Request) (*model.Application, i
 // On case of get request, it returns the application create page.
 // On case of post request, it creates the application and redirects to the list page.
 func (c *Controller) ApplicationCreateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("applications.create") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -64,24 +73,24 @@ func (c *Controller) ApplicationCreateViewController(w http.ResponseWriter, r *h
 	if r.Method == http.MethodGet {
 		content, errorMessage, err := c.createApplicationFormResponse(currentUser, nil)
 		if errorMessage != "" {
-			c.renderer.Error(w, http.StatusInternalServerError, errorMessage, err)
+			c.renderRepositoryError(w, errorMessage, err)
 			return
 		}
 		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
 	if r.Method == http.MethodPost {
-		app, domainIDS, errorMessage, err := c.validateApplicationForm(r)
+		app, errorMessage, err := c.validateApplicationForm(r, nil)
 		if errorMessage != "" {
 			c.renderer.Error(w, http.StatusBadRequest, errorMessage, err)
 			return
 		}
-		_, err = c.repositoryContainer.GetApplicationRepository().CreateApplication(app.Client.ID, app.Project.ID, app.Environment.ID, app.Database.ID, app.Runtime.ID, app.Pool.ID, app.Framework.ID, app.Repository, app.Branch, app.DBName, app.DBUser, app.DocumentRoot, domainIDS)
+		_, err = c.repositoryContainer.GetApplicationRepository().CreateApplication(app.Client.ID, app.Project.ID, app.Environment.ID, app.Database.ID, app.Runtime.ID, app.Pool.ID, app.Framework.ID, app.Repository, app.Branch, app.DBName, app.DBUser, app.DocumentRoot, app.DomainRoles)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, ApplicationCreateCreateApplicationErrorMessage, err)
+			c.renderRepositoryError(w, ApplicationCreateCreateApplicationErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/application/list", http.StatusSeeOther)
@@ -93,7 +102,11 @@ func (c *Controller) ApplicationCreateViewController(w http.ResponseWriter, r *h
 // On case of get request, it returns the application update page.
 // On case of post request, it updates the application and redirects to the list page.
 func (c *Controller) ApplicationUpdateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("applications.update") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -110,24 +123,24 @@ func (c *Controller) ApplicationUpdateViewController(w http.ResponseWriter, r *h
 	// get the application
 	application, err := c.repositoryContainer.GetApplicationRepository().GetApplicationByID(applicationID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ApplicationFailedToGetApplicationErrorMessage, err)
+		c.renderRepositoryError(w, ApplicationFailedToGetApplicationErrorMessage, err)
 		return
 	}
 
 	if r.Method == http.MethodGet {
 		content, errorMessage, err := c.createApplicationFormResponse(currentUser, application)
 		if errorMessage != "" {
-			c.renderer.Error(w, http.StatusInternalServerError, errorMessage, err)
+			c.renderRepositoryError(w, errorMessage, err)
 			return
 		}
 		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
 	if r.Method == http.MethodPost {
-		app, _, errorMessage, err := c.validateApplicationForm(r)
+		app, errorMessage, err := c.validateApplicationForm(r, application)
 		if errorMessage != "" {
 			c.renderer.Error(w, http.StatusBadRequest, errorMessage, err)
 			return
@@ -136,7 +149,7 @@ func (c *Controller) ApplicationUpdateViewController(w http.ResponseWriter, r *h
 
 		err = c.repositoryContainer.GetApplicationRepository().UpdateApplication(app)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, ApplicationUpdateUpdateApplicationErrorMessage, err)
+			c.renderRepositoryError(w, ApplicationUpdateUpdateApplicationErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/application/list", http.StatusSeeOther)
@@ -148,7 +161,11 @@ func (c *Controller) ApplicationUpdateViewController(w http.ResponseWriter, r *h
 // It is responsible for deleting a application.
 // It redirects to the application list page.
 func (c *Controller) ApplicationDeleteViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("applications.delete") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -164,16 +181,51 @@ func (c *Controller) ApplicationDeleteViewController(w http.ResponseWriter, r *h
 	// delete the application
 	err = c.repositoryContainer.GetApplicationRepository().DeleteApplication(applicationID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ApplicationDeleteFailedToDeleteErrorMessage, err)
+		c.renderRepositoryError(w, ApplicationDeleteFailedToDeleteErrorMessage, err)
 		return
 	}
 	// redirect to the application list
 	http.Redirect(w, r, "/admin/application/list", http.StatusSeeOther)
 }
 
+// applicationFilterFromForm sets the application filter fields from the submitted form or the query parameters.
+// The relation filters are the lists of the ids, it returns error if an id is not a number.
+func applicationFilterFromForm(r *http.Request, filter *model.ApplicationFilter) error {
+	r.ParseForm()
+	filter.Domain = r.FormValue("domain")
+	filter.Branch = r.FormValue("branch")
+	filter.DBName = r.FormValue("db_name")
+	filter.DBUser = r.FormValue("db_user")
+	filter.DocRoot = r.FormValue("doc_root")
+	filter.Repository = r.FormValue("repository")
+
+	idFilters := map[string]*[]string{
+		"client":      &filter.ClientIDs,
+		"project":     &filter.ProjectIDs,
+		"environment": &filter.EnvironmentIDs,
+		"database":    &filter.DatabaseIDs,
+		"runtime":     &filter.RuntimeIDs,
+		"pool":        &filter.PoolIDs,
+		"framework":   &filter.FrameworkIDs,
+	}
+	for name, ids := range idFilters {
+		for _, v := range r.Form[name] {
+			if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+				return err
+			}
+			*ids = append(*ids, v)
+		}
+	}
+	return nil
+}
+
 // ApplicationListViewController is the controller for the application list view.
 func (c *Controller) ApplicationListViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("applications.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -181,68 +233,9 @@ func (c *Controller) ApplicationListViewController(w http.ResponseWriter, r *htt
 	filter := model.NewApplicationFilter()
 	csvOutput := false
 	if r.Method == http.MethodPost {
-		filter.Domain = r.FormValue("domain")
-		filter.Branch = r.FormValue("branch")
-		filter.DBName = r.FormValue("db_name")
-		filter.DBUser = r.FormValue("db_user")
-		filter.DocRoot = r.FormValue("doc_root")
-		filter.Repository = r.FormValue("repository")
-
-		for _, v := range r.Form["client"] {
-			_, err := strconv.ParseInt(v, 10, 64)
-			if err != nil {
-				c.renderer.Error(w, http.StatusBadRequest, EnvironmentListServerIDInvalidErrorMessage, err)
-				return
-			}
-			filter.ClientIDs = append(filter.ClientIDs, v)
-		}
-		for _, v := range r.Form["project"] {
-			_, err := strconv.ParseInt(v, 10, 64)
-			if err != nil {
-				c.renderer.Error(w, http.StatusBadRequest, EnvironmentListServerIDInvalidErrorMessage, err)
-				return
-			}
-			filter.ProjectIDs = append(filter.ProjectIDs, v)
-		}
-		for _, v := range r.Form["environment"] {
-			_, err := strconv.ParseInt(v, 10, 64)
-			if err != nil {
-				c.renderer.Error(w, http.StatusBadRequest, EnvironmentListServerIDInvalidErrorMessage, err)
-				return
-			}
-			filter.EnvironmentIDs = append(filter.EnvironmentIDs, v)
-		}
-		for _, v := range r.Form["database"] {
-			_, err := strconv.ParseInt(v, 10, 64)
-			if err != nil {
-				c.renderer.Error(w, http.StatusBadRequest, EnvironmentListServerIDInvalidErrorMessage, err)
-				return
-			}
-			filter.DatabaseIDs = append(filter.DatabaseIDs, v)
-		}
-		for _, v := range r.Form["runtime"] {
-			_, err := strconv.ParseInt(v, 10, 64)
-			if err != nil {
-				c.renderer.Error(w, http.StatusBadRequest, EnvironmentListServerIDInvalidErrorMessage, err)
-				return
-			}
-			filter.RuntimeIDs = append(filter.RuntimeIDs, v)
-		}
-		for _, v := range r.Form["pool"] {
-			_, err := strconv.ParseInt(v, 10, 64)
-			if err != nil {
-				c.renderer.Error(w, http.StatusBadRequest, EnvironmentListServerIDInvalidErrorMessage, err)
-				return
-			}
-			filter.PoolIDs = append(filter.PoolIDs, v)
-		}
-		for _, v := range r.Form["framework"] {
-			_, err := strconv.ParseInt(v, 10, 64)
-			if err != nil {
-				c.renderer.Error(w, http.StatusBadRequest, EnvironmentListServerIDInvalidErrorMessage, err)
-				return
-			}
-			filter.FrameworkIDs = append(filter.FrameworkIDs, v)
+		if err := applicationFilterFromForm(r, filter); err != nil {
+			c.renderer.Error(w, http.StatusBadRequest, ApplicationListFilterInvalidErrorMessage, err)
+			return
 		}
 		filter.VisibleColumns = transformers.StringSliceToInt64Slice(r.Form["visible_columns"])
 		// if the csv output is requested, set the flag
@@ -254,7 +247,7 @@ func (c *Controller) ApplicationListViewController(w http.ResponseWriter, r *htt
 	// get all applications
 	applications, err := c.repositoryContainer.GetApplicationRepository().GetApplications(filter)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ApplicationListFailedToGetApplicationsErrorMessage, err)
+		c.renderRepositoryError(w, ApplicationListFailedToGetApplicationsErrorMessage, err)
 		return
 	}
 	if csvOutput {
@@ -263,50 +256,54 @@ func (c *Controller) ApplicationListViewController(w http.ResponseWriter, r *htt
 	}
 	runtimes, err := c.repositoryContainer.GetRuntimeRepository().GetRuntimes(model.NewRuntimeFilter())
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ApplicationCreateFailedToGetRuntimesErrorMessage, err)
+		c.renderRepositoryError(w, ApplicationCreateFailedToGetRuntimesErrorMessage, err)
 		return
 	}
 	pools, err := c.repositoryContainer.GetPoolRepository().GetPools(model.NewPoolFilter())
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ApplicationCreateFailedToGetPoolsErrorMessage, err)
+		c.renderRepositoryError(w, ApplicationCreateFailedToGetPoolsErrorMessage, err)
 		return
 	}
 	clients, err := c.repositoryContainer.GetClientRepository().GetClients(model.NewClientFilter())
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ApplicationCreateFailedToGetClientsErrorMessage, err)
+		c.renderRepositoryError(w, ApplicationCreateFailedToGetClientsErrorMessage, err)
 		return
 	}
 	projects, err := c.repositoryContainer.GetProjectRepository().GetProjects(model.NewProjectFilter())
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ApplicationCreateFailedToGetProjectsErrorMessage, err)
+		c.renderRepositoryError(w, ApplicationCreateFailedToGetProjectsErrorMessage, err)
 		return
 	}
 	environments, err := c.repositoryContainer.GetEnvironmentRepository().GetEnvironments(model.NewEnvironmentFilter())
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ApplicationCreateFailedToGetEnvironmentsErrorMessage, err)
+		c.renderRepositoryError(w, ApplicationCreateFailedToGetEnvironmentsErrorMessage, err)
 		return
 	}
 	databases, err := c.repositoryContainer.GetDatabaseRepository().GetDatabases(model.NewDatabaseFilter())
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ApplicationCreateFailedToGetDatabasesErrorMessage, err)
+		c.renderRepositoryError(w, ApplicationCreateFailedToGetDatabasesErrorMessage, err)
 		return
 	}
 	frameworks, err := c.repositoryContainer.GetFrameworkRepository().GetFrameworks(model.NewFrameworkFilter())
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ApplicationCreateFailedToGetFrameworksErrorMessage, err)
+		c.renderRepositoryError(w, ApplicationCreateFailedToGetFrameworksErrorMessage, err)
 		return
 	}
 	content := response.NewApplicationListResponse(currentUser, applications, clients, projects, environments, databases, runtimes, pools, frameworks, filter)
 	err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
 
 // ApplicationImportToEnvironmentFormController is the controller for the application import to environment form.
 // It is responsible for handling the forms that guides you throught the import process.
 func (c *Controller) ApplicationImportToEnvironmentFormController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("applications.create") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -323,7 +320,7 @@ func (c *Controller) ApplicationImportToEnvironmentFormController(w http.Respons
 	// load the environment
 	environment, err := c.repositoryContainer.GetEnvironmentRepository().GetEnvironmentByID(environmentID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ApplicationImportFailedToGetEnvironmentErrorMessage, err)
+		c.renderRepositoryError(w, ApplicationImportFailedToGetEnvironmentErrorMessage, err)
 		return
 	}
 	// On case of get method load the form template
@@ -331,21 +328,16 @@ func (c *Controller) ApplicationImportToEnvironmentFormController(w http.Respons
 		content := response.NewApplicationImportToEnvironmentFormResponse(currentUser, environment)
 		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
-	// On case of post method, store the csv file content in file and redirect to the mapping page.
+	// On case of post method, store the uploaded file and redirect to the mapping page.
 	if r.Method == http.MethodPost {
-		filename, err := c.storeImportCSVFile(r)
-		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, ApplicationImportFailedToSaveFileErrorMessage, err)
+		fileID, query, ok := c.uploadImportFile(w, r, currentUser)
+		if !ok {
 			return
 		}
-		hasHeader := r.FormValue("has_header")
-		redirectURL := "/admin/application/mapping-to-environment/" + environmentIDVariable + "/" + filename
-		if hasHeader == "1" {
-			redirectURL += "?has_header=true"
-		}
+		redirectURL := "/admin/application/mapping-to-environment/" + environmentIDVariable + "/" + fileID + "?" + query.Encode()
 		http.Redirect(w, r, redirectURL, http.StatusSeeOther)
 	}
 }
@@ -353,7 +345,11 @@ func (c *Controller) ApplicationImportToEnvironmentFormController(w http.Respons
 // ApplicationMappingToEnvironmentFormController is the controller for the application mapping to environment form.
 // It is responsible for handling the forms that guides you throught the mapping process.
 func (c *Controller) ApplicationMappingToEnvironmentFormController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("applications.create") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -367,37 +363,31 @@ func (c *Controller) ApplicationMappingToEnvironmentFormController(w http.Respon
 		c.renderer.Error(w, http.StatusBadRequest, ApplicationImportInvalidEnvironmentIDErrorMessage, err)
 		return
 	}
-	fileID := vars["fileId"]
+	fileID, ok := c.importFileID(w, r)
+	if !ok {
+		return
+	}
 	// load the environment
 	environment, err := c.repositoryContainer.GetEnvironmentRepository().GetEnvironmentByID(environmentID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ApplicationImportFailedToGetEnvironmentErrorMessage, err)
+		c.renderRepositoryError(w, ApplicationImportFailedToGetEnvironmentErrorMessage, err)
 		return
 	}
 	// On case of get method load the form template
 	if r.Method == http.MethodGet {
-		// get the file content
-		csvData, err := c.csvStorage.Read(fileID + ".csv")
-		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, "Failed to read the CSV.", err)
+		// the file of an other user is not found.
+		file, ok := c.readImportFile(w, r, currentUser, fileID, r.URL.Query())
+		if !ok {
 			return
 		}
-		hasHeader := r.URL.Query().Get("has_header")
-		header := []string{}
-		if hasHeader == "true" {
-			// if the first line is the header, remove it
-			header = csvData[0]
-			csvData = csvData[1:]
-		}
-		content := response.NewApplicationMappingToEnvironmentFormResponse(currentUser, environment, fileID, csvData, header)
-		err = c.renderer.Template.RenderTemplate(w, "application-import-mapping.html", content)
+		content := response.NewApplicationMappingToEnvironmentFormResponse(currentUser, environment, fileID, file.rows, file.header, file.hasHeader, file.options)
+		err = c.renderer.Template.RenderTemplate(w, "import-mapping.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 	// On case of post process the mapping form and execute the import process.
 	if r.Method == http.MethodPost {
-		fileName := r.FormValue("file_id")
 		environmentIDRaw := r.FormValue("environment_id")
 		// it has to be converted to int64
 		environmentID, err := strconv.ParseInt(environmentIDRaw, 10, 64)
@@ -405,42 +395,21 @@ func (c *Controller) ApplicationMappingToEnvironmentFormController(w http.Respon
 			c.renderer.Error(w, http.StatusBadRequest, ApplicationImportInvalidEnvironmentIDErrorMessage, err)
 			return
 		}
-		mappingRules, err := c.getImportApplicationToEnvironmentMapping(r)
-		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, "Failed to get the mapping rules", err)
-			return
-		}
-		results, err := c.importApplicationToEnvironment(environmentID, fileName, mappingRules)
-		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, "Failed to import", err)
+		mapping, options, ok := c.importMappingFromForm(w, r, fileID, parser.ApplicationImportFields)
+		if !ok {
 			return
 		}
-		content := response.NewApplicationImportToEnvironmentListResponse(currentUser, environment, fileName, results)
-		err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
-		if err != nil {
-			panic(err)
-		}
-	}
-}
-
-// getImportApplicationToEnvironmentMapping returns the mapping rules for the import process.
-func (c *Controller) getImportApplicationToEnvironmentMapping(r *http.Request) (parser.ApplicationImportMapping, error) {
-	mappingRules := parser.NewApplicationImportMapping()
-	parameterNames := []string{"client", "project", "domains", "runtime", "pool", "framework", "database", "database_name", "database_user", "doc_root", "repository", "branch"}
-	for _, parameterName := range parameterNames {
-		parameterIndexRaw := r.FormValue(parameterName)
-		parameterCustomValue := r.FormValue(parameterName + "_custom")
-		if parameterIndexRaw != "" {
-			parameterInt, err := strconv.Atoi(parameterIndexRaw)
-			if err != nil {
-				return parser.ApplicationImportMapping{}, err
-			}
-			mappingRules[parameterName].ColumnIndex = parameterInt
-		} else {
-			mappingRules[parameterName].CustomValue = parameterCustomValue
+		// the import runs in the background, its progress is displayed on the job page.
+		payload := &applicationImportPayload{
+			EnvironmentID:   environmentID,
+			FileID:          fileID,
+			Mapping:         parser.ApplicationImportMapping(mapping),
+			HasHeader:       r.FormValue("has_header") == "true",
+			Options:         options,
+			DomainSeparator: r.FormValue("domains_separator"),
 		}
+		c.enqueueJob(w, r, model.JobTypeApplicationImport, payload, ImportFailedToStartErrorMessage)
 	}
-	return mappingRules, nil
 }
 
 // It creates the content for the application forms.
@@ -485,8 +454,10 @@ func (c *Controller) createApplicationFormResponse(currentUser *model.User, appl
 	return response.NewUpdateApplicationResponse(currentUser, application, clients, projects, environments, databases, runtimes, pools, frameworks, domains), "", nil
 }
 
-// It validates the application form data. Returns the Application with the validated data, the domain ids, and an error message and error.
-func (c *Controller) validateApplicationForm(r *http.Request) (*model.Application, []int64, string, error) {
+// It validates the application form data. Returns the Application with the validated data, and an error message and error.
+// The current application is used for keeping the roles of the domains on update, it is nil on create.
+// The selected primary domain is the primary one, the other domains keep their alias or redirect role.
+func (c *Controller) validateApplicationForm(r *http.Request, current *model.Application) (*model.Application, string, error) {
 	clientIDRaw := r.FormValue("client")
 	projectIDRaw := r.FormValue("project")
 	envIDRaw := r.FormValue("environment")
@@ -499,41 +470,41 @@ func (c *Controller) validateApplicationForm(r *http.Request) (*model.Applicatio
 	branch := r.FormValue("branch")
 	frameworkIDRaw := r.FormValue("framework")
 	documentRoot := r.FormValue("document_root")
-	domainIDsRaw := r.Form["domain"]
-	var domainIDS []int64
+	domainIDsRaw := r.Form["domains"]
+	primaryDomainIDRaw := r.FormValue("primary_domain")
 
 	// if the clientID, projectID, envID, dbID, runtimeID, poolID is empty, return an error
 	if clientIDRaw == "" || projectIDRaw == "" || envIDRaw == "" {
-		return nil, domainIDS, ApplicationCreateRequiredFieldMissing, nil
+		return nil, ApplicationCreateRequiredFieldMissing, nil
 	}
 	// convert the clientID, projectID, envID, dbID, runtimeID, poolID to int64
 	clientID, err := strconv.ParseInt(clientIDRaw, 10, 64)
 	if err != nil {
-		return nil, domainIDS, ApplicationCreateClientIDInvalidErrorMessage, err
+		return nil, ApplicationCreateClientIDInvalidErrorMessage, err
 	}
 	projectID, err := strconv.ParseInt(projectIDRaw, 10, 64)
 	if err != nil {
-		return nil, domainIDS, ApplicationCreateProjectIDInvalidErrorMessage, err
+		return nil, ApplicationCreateProjectIDInvalidErrorMessage, err
 	}
 	envID, err := strconv.ParseInt(envIDRaw, 10, 64)
 	if err != nil {
-		return nil, domainIDS, ApplicationCreateEnvironmentIDInvalidErrorMessage, err
+		return nil, ApplicationCreateEnvironmentIDInvalidErrorMessage, err
 	}
 	dbID, err := strconv.ParseInt(dbIDRaw, 10, 64)
 	if err != nil {
-		return nil, domainIDS, ApplicationCreateDatabaseIDInvalidErrorMessage, err
+		return nil, ApplicationCreateDatabaseIDInvalidErrorMessage, err
 	}
 	runtimeID, err := strconv.ParseInt(runtimeIDRaw, 10, 64)
 	if err != nil {
-		return nil, domainIDS, ApplicationCreateRuntimeIDInvalidErrorMessage, err
+		return nil, ApplicationCreateRuntimeIDInvalidErrorMessage, err
 	}
 	poolID, err := strconv.ParseInt(poolIDRaw, 10, 64)
 	if err != nil {
-		return nil, domainIDS, ApplicationCreatePoolIDInvalidErrorMessage, err
+		return nil, ApplicationCreatePoolIDInvalidErrorMessage, err
 	}
 	frameworkID, err := strconv.ParseInt(frameworkIDRaw, 10, 64)
 	if err != nil {
-		return nil, domainIDS, ApplicationCreateFrameworkIDInvalidErrorMessage, err
+		return nil, ApplicationCreateFrameworkIDInvalidErrorMessage, err
 	}
 	app := &model.Application{
 		Client:       &model.Client{ID: clientID},
@@ -549,165 +520,130 @@ func (c *Controller) validateApplicationForm(r *http.Request) (*model.Applicatio
 		Framework:    &model.Framework{ID: frameworkID},
 		DocumentRoot: documentRoot,
 	}
+	var primaryDomainID int64
+	if primaryDomainIDRaw != "" {
+		primaryDomainID, err = strconv.ParseInt(primaryDomainIDRaw, 10, 64)
+		if err != nil {
+			return nil, ApplicationCreatePrimaryDomainInvalidErrorMessage, err
+		}
+	}
 	for _, domainIDRaw := range domainIDsRaw {
 		domainID, err := strconv.ParseInt(domainIDRaw, 10, 64)
 		if err != nil {
-			return nil, domainIDS, ApplicationCreateDomainIDInvalidErrorMessage, err
+			return nil, ApplicationCreateDomainIDInvalidErrorMessage, err
+		}
+		role := &model.ApplicationDomain{DomainID: domainID, Role: model.ApplicationDomainRoleAlias}
+		if current != nil && current.HasDomainID(domainID) {
+			currentRole := *current.DomainRole(domainID)
+			if currentRole.Role != model.ApplicationDomainRolePrimary {
+				role = &currentRole
+			}
+		}
+		if domainID == primaryDomainID {
+			role = &model.ApplicationDomain{DomainID: domainID, Role: model.ApplicationDomainRolePrimary}
 		}
-		domainIDS = append(domainIDS, domainID)
 		app.Domains = append(app.Domains, &model.Domain{ID: domainID})
+		app.DomainRoles = append(app.DomainRoles, role)
 	}
-	// add the domain ids
-	return app, domainIDS, "", nil
+	if primaryDomainID != 0 && !app.HasDomainID(primaryDomainID) {
+		return nil, ApplicationCreatePrimaryDomainInvalidErrorMessage, nil
+	}
+	if err := app.ValidateDomainRoles(); err != nil {
+		return nil, ApplicationDomainRolesInvalidErrorMessage, err
+	}
+	return app, "", nil
 
 }
 
-// storeImportCSVFile stores the csv file in the file system.
-// It returns the filename and an error.
-func (c *Controller) storeImportCSVFile(r *http.Request) (string, error) {
-	r.ParseMultipartForm(32 << 20)
-	file, _, err := r.FormFile("csvfile")
-	if err != nil {
-		return "", err
-	}
-	defer file.Close()
-	// store the file
-	return c.csvStorage.Save(file)
-}
+// importApplicationRow imports the mapped row as an application of the environment.
+// The missing client, project, runtime, pool, database, framework and domains are created.
+// The domains are separated with the domain separator, the empty separator is any whitespace.
+// It returns the created application and an error.
+func (c *Controller) importApplicationRow(environmentID int64, rowData map[string]string, domainSeparator string) (*model.Application, error) {
+	clientName := rowData["client"]
+	projectName := rowData["project"]
+	runtimeName := rowData["runtime"]
+	poolName := rowData["pool"]
+	databaseTypeName := rowData["database"]
 
-// importApplicationToEnvironment imports the applications to the environment.
-// It returns the results and an error.
-func (c *Controller) importApplicationToEnvironment(environmentID int64, fileName string, mappingRules parser.ApplicationImportMapping) (*parser.ApplicationImportResult, error) {
-	results := parser.NewApplicationImportResult()
-	// get the file content
-	csvData, err := c.csvStorage.Read(fileName + ".csv")
-	if err != nil {
-		return &results, err
-	}
-
-	// parse the file content every line represents an application
-	for rowIndex, line := range csvData {
-		importRow := mappingRules.MapRow(line)
-		// set the response to empty string. it means process went well
-		results[rowIndex] = importRow
-		clientName := importRow.RowData["client"]
-		projectName := importRow.RowData["project"]
-		runtimeName := importRow.RowData["runtime"]
-		poolName := importRow.RowData["pool"]
-		databaseTypeName := importRow.RowData["database"]
-
-		domainsRaw := importRow.RowData["domains"]
-		frameworkName := importRow.RowData["framework"]
-		databaseName := importRow.RowData["database_name"]
-		if databaseName == "-" {
-			databaseName = ""
-		}
-		databaseUser := importRow.RowData["database_user"]
-		if databaseUser == "-" {
-			databaseUser = ""
-		}
-		docRoot := importRow.RowData["doc_root"]
-		repository := importRow.RowData["repository"]
-		branch := importRow.RowData["branch"]
-
-		// if the client name does not exist, create it
-		client, err := c.repositoryContainer.GetClientRepository().GetClientByName(clientName)
-		if err != nil {
-			client, err = c.repositoryContainer.GetClientRepository().CreateClient(clientName)
-			if err != nil {
-				currentData := results[rowIndex]
-				currentData.ErrorMessage = err.Error()
-				results[rowIndex] = currentData
-				continue
-			}
-		}
-
-		// if the project name does not exist, create it
-		project, err := c.repositoryContainer.GetProjectRepository().GetProjectByName(projectName)
-		if err != nil {
-			project, err = c.repositoryContainer.GetProjectRepository().CreateProject(projectName)
-			if err != nil {
-				currentData := results[rowIndex]
-				currentData.ErrorMessage = err.Error()
-				results[rowIndex] = currentData
-				continue
-			}
-		}
-		// if the runtime name does not exist, create it
-		runtime, err := c.repositoryContainer.GetRuntimeRepository().GetRuntimeByName(runtimeName)
-		if err != nil {
-			runtime, err = c.repositoryContainer.GetRuntimeRepository().CreateRuntime(runtimeName, 0)
-			if err != nil {
-				currentData := results[rowIndex]
-				currentData.ErrorMessage = err.Error()
-				results[rowIndex] = currentData
-				continue
-			}
-		}
-		// if the pool name does not exist, create it
-		pool, err := c.repositoryContainer.GetPoolRepository().GetPoolByName(poolName)
-		if err != nil {
-			pool, err = c.repositoryContainer.GetPoolRepository().CreatePool(poolName)
-			if err != nil {
-				currentData := results[rowIndex]
-				currentData.ErrorMessage = err.Error()
-				results[rowIndex] = currentData
-				continue
-			}
-		}
-		// if the database name does not exist, create it
-		database, err := c.repositoryContainer.GetDatabaseRepository().GetDatabaseByName(databaseTypeName)
-		if err != nil {
-			database, err = c.repositoryContainer.GetDatabaseRepository().CreateDatabase(databaseTypeName)
-			if err != nil {
-				currentData := results[rowIndex]
-				currentData.ErrorMessage = err.Error()
-				results[rowIndex] = currentData
-				continue
-			}
-		}
-		// if the framework name does not exist, create it
-		framework, err := c.repositoryContainer.GetFrameworkRepository().GetFrameworkByName(frameworkName)
-		if err != nil {
-			framework, err = c.repositoryContainer.GetFrameworkRepository().CreateFramework(frameworkName, 0)
-			if err != nil {
-				currentData := results[rowIndex]
-				currentData.ErrorMessage = err.Error()
-				results[rowIndex] = currentData
-				continue
-			}
-		}
+	domainsRaw := rowData["domains"]
+	frameworkName := rowData["framework"]
+	databaseName := rowData["database_name"]
+	if databaseName == "-" {
+		databaseName = ""
+	}
+	databaseUser := rowData["database_user"]
+	if databaseUser == "-" {
+		databaseUser = ""
+	}
+	docRoot := rowData["doc_root"]
+	repository := rowData["repository"]
+	branch := rowData["branch"]
 
-		// handle the domains. The domains are separated by space.
-		// If the domain does not exists, create it.
-		domainNames := strings.Split(domainsRaw, " ")
-		domainIDs := []int64{}
-		for _, domainName := range domainNames {
-			domain, err := c.repositoryContainer.GetDomainRepository().GetDomainByName(domainName)
-			if err != nil {
-				domain, err = c.repositoryContainer.GetDomainRepository().CreateDomain(domainName)
-				if err != nil {
-					currentData := results[rowIndex]
-					currentData.ErrorMessage = err.Error()
-					results[rowIndex] = currentData
-					continue
-				}
-			}
-			domainIDs = append(domainIDs, domain.ID)
-		}
+	// if the client name does not exist, create it
+	client, err := c.repositoryContainer.GetClientRepository().GetClientByName(clientName)
+	if errors.Is(err, model.ErrNotFound) {
+		client, err = c.repositoryContainer.GetClientRepository().CreateClient(clientName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// if the project name does not exist, create it
+	project, err := c.repositoryContainer.GetProjectRepository().GetProjectByName(projectName)
+	if errors.Is(err, model.ErrNotFound) {
+		project, err = c.repositoryContainer.GetProjectRepository().CreateProject(projectName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// if the runtime name does not exist, create it
+	runtime, err := c.repositoryContainer.GetRuntimeRepository().GetRuntimeByName(runtimeName)
+	if errors.Is(err, model.ErrNotFound) {
+		runtime, err = c.repositoryContainer.GetRuntimeRepository().CreateRuntime(runtimeName, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// if the pool name does not exist, create it
+	pool, err := c.repositoryContainer.GetPoolRepository().GetPoolByName(poolName)
+	if errors.Is(err, model.ErrNotFound) {
+		pool, err = c.repositoryContainer.GetPoolRepository().CreatePool(poolName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// if the database name does not exist, create it
+	database, err := c.repositoryContainer.GetDatabaseRepository().GetDatabaseByName(databaseTypeName)
+	if errors.Is(err, model.ErrNotFound) {
+		database, err = c.repositoryContainer.GetDatabaseRepository().CreateDatabase(databaseTypeName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// if the framework name does not exist, create it
+	framework, err := c.repositoryContainer.GetFrameworkRepository().GetFrameworkByName(frameworkName)
+	if errors.Is(err, model.ErrNotFound) {
+		framework, err = c.repositoryContainer.GetFrameworkRepository().CreateFramework(frameworkName, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-		// create the application
-		app, err := c.repositoryContainer.GetApplicationRepository().CreateApplication(client.ID, project.ID, environmentID, database.ID, runtime.ID, pool.ID, framework.ID, repository, branch, databaseName, databaseUser, docRoot, domainIDs)
-		currentData := results[rowIndex]
+	// handle the domains. The domains are separated by the domain separator.
+	// If the domain does not exists, create it.
+	domainNames := parser.SplitList(domainsRaw, domainSeparator)
+	domainIDs := []int64{}
+	for _, domainName := range domainNames {
+		domain, err := c.getOrCreateDomain(domainName)
 		if err != nil {
-			currentData.ErrorMessage = err.Error()
-			results[rowIndex] = currentData
-			continue
+			return nil, err
 		}
-		currentData.Application = app
-		results[rowIndex] = currentData
+		domainIDs = append(domainIDs, domain.ID)
 	}
-	return &results, nil
+
+	// create the application
+	// the first domain of the row is the primary domain
+	return c.repositoryContainer.GetApplicationRepository().CreateApplication(client.ID, project.ID, environmentID, database.ID, runtime.ID, pool.ID, framework.ID, repository, branch, databaseName, databaseUser, docRoot, model.NewApplicationDomains(domainIDs))
 }
 
 // exportApplicationsToCSV exports the applications to a csv file.
@@ -757,11 +693,11 @@ func (c *Controller) exportApplicationsToCSV(w http.ResponseWriter, applications
 			currentRow = append(currentRow, app.DocumentRoot)
 		}
 		if filter.IsVisibleColumn("Domains") {
-			domainNames := []string{}
-			for _, domain := range app.Domains {
-				domainNames = append(domainNames, domain.Name)
+			primaryDomainName := ""
+			if primaryDomain := app.PrimaryDomain(); primaryDomain != nil {
+				primaryDomainName = primaryDomain.Name
 			}
-			currentRow = append(currentRow, strings.Join(domainNames, ", "))
+			currentRow = append(currentRow, primaryDomainName)
 		}
 		if filter.IsVisibleColumn("Created At") {
 			currentRow = append(currentRow, app.CreatedAt)
@@ -782,3 +718,107 @@ func (c *Controller) exportApplicationsToCSV(w http.ResponseWriter, applications
 		return
 	}
 }
+
+// ApplicationDomainRolesViewController is the controller for the application domain roles form.
+// On case of get request, it returns the domain roles form.
+// On case of post request, it updates the domain roles and redirects to the application view page.
+// Exactly one primary domain is required, the redirects need a target url and a redirect status code.
+func (c *Controller) ApplicationDomainRolesViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("applications.update") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	application, statusCode, err := c.applicationViewData(r)
+	if err != nil {
+		c.renderer.Error(w, statusCode, ApplicationFailedToGetApplicationErrorMessage, err)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		content := response.NewApplicationDomainRolesFormResponse(currentUser, application)
+		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
+		if err != nil {
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+		}
+	}
+
+	if r.Method == http.MethodPost {
+		domainRoles := []*model.ApplicationDomain{}
+		for _, domain := range application.Domains {
+			role, err := applicationDomainRoleFromForm(r, domain.ID)
+			if err != nil {
+				c.renderer.Error(w, http.StatusBadRequest, ApplicationDomainRolesInvalidErrorMessage, err)
+				return
+			}
+			domainRoles = append(domainRoles, role)
+		}
+		application.DomainRoles = domainRoles
+		if err := application.ValidateDomainRoles(); err != nil {
+			c.renderer.Error(w, http.StatusBadRequest, ApplicationDomainRolesInvalidErrorMessage, err)
+			return
+		}
+		err = c.repositoryContainer.GetApplicationRepository().UpdateApplication(application)
+		if err != nil {
+			c.renderRepositoryError(w, ApplicationUpdateDomainRolesErrorMessage, err)
+			return
+		}
+		http.Redirect(w, r, fmt.Sprintf("/admin/application/view/%d", application.ID), http.StatusSeeOther)
+		return
+	}
+}
+
+// applicationDomainRoleFromForm returns the role of the domain from the submitted domain roles form.
+// The role select value is the 1 based index of the model.ApplicationDomainRoles.
+// The redirect target and status are only kept for the redirect role.
+func applicationDomainRoleFromForm(r *http.Request, domainID int64) (*model.ApplicationDomain, error) {
+	roleIndex, err := strconv.Atoi(r.FormValue(fmt.Sprintf("role_%d", domainID)))
+	if err != nil {
+		return nil, err
+	}
+	if roleIndex < 1 || roleIndex > len(model.ApplicationDomainRoles) {
+		return nil, fmt.Errorf("invalid role index: %d", roleIndex)
+	}
+	role := &model.ApplicationDomain{DomainID: domainID, Role: model.ApplicationDomainRoles[roleIndex-1]}
+	if role.Role != model.ApplicationDomainRoleRedirect {
+		return role, nil
+	}
+	role.RedirectTarget = strings.TrimSpace(r.FormValue(fmt.Sprintf("redirect_target_%d", domainID)))
+	role.RedirectStatus, err = strconv.Atoi(r.FormValue(fmt.Sprintf("redirect_status_%d", domainID)))
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// ApplicationCheckViewController is the controller for the application check.
+// It starts the ssl check and the security audit of the primary domain of the application,
+// as the aliases and the redirects are not the canonical address of the application.
+// It redirects to the job view page.
+func (c *Controller) ApplicationCheckViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("domains.update") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	application, statusCode, err := c.applicationViewData(r)
+	if err != nil {
+		c.renderer.Error(w, statusCode, ApplicationFailedToGetApplicationErrorMessage, err)
+		return
+	}
+	domain := application.PrimaryDomain()
+	if domain == nil {
+		c.renderer.Error(w, http.StatusBadRequest, ApplicationCheckPrimaryDomainMissingErrorMessage, nil)
+		return
+	}
+	payload := &model.DomainCheckPayload{DomainIDs: []int64{domain.ID}, SSL: true, Security: true}
+	c.enqueueJob(w, r, model.JobTypeDomainCheck, payload, DomainCheckFailedToStartErrorMessage)
+}
@@ -1,12 +1,16 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/akosgarai/projectregister/pkg/controller/response"
 	"github.com/akosgarai/projectregister/pkg/controller/response/components"
+	"github.com/akosgarai/projectregister/pkg/logging"
 	"github.com/akosgarai/projectregister/pkg/model"
 	"github.com/akosgarai/projectregister/pkg/passwd"
+	"github.com/akosgarai/projectregister/pkg/render"
 	"github.com/akosgarai/projectregister/pkg/session"
 )
 
@@ -29,7 +33,7 @@ func (c *Controller) LoginPageController(w http.ResponseWriter, r *http.Request)
 	content := response.NewResponse(headerText, &model.User{Role: &model.Role{}}, headerContent)
 	err = c.renderer.Template.RenderTemplate(w, "login.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
 
@@ -45,6 +49,11 @@ func (c *Controller) LoginActionController(w http.ResponseWriter, r *http.Reques
 		return
 	}
 	user, err := c.repositoryContainer.GetUserRepository().GetUserByEmail(username)
+	// The unknown user is handled as the invalid password, so that the existing emails are not revealed.
+	if errors.Is(err, model.ErrNotFound) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
 	if err != nil {
 		c.renderer.Error(w, http.StatusInternalServerError, UserFailedToGetUserErrorMessage, err)
 		return
@@ -81,24 +90,39 @@ func (c *Controller) AuthMiddleware(next http.Handler) http.Handler {
 			http.Redirect(w, r, "/login", http.StatusSeeOther)
 			return
 		}
-		_, err = c.sessionStore.Get(sessionKey.Value)
+		userSession, err := c.sessionStore.Get(sessionKey.Value)
 		if err != nil {
 			http.Redirect(w, r, "/login", http.StatusSeeOther)
 			return
 		}
+		// the user id is added to the log lines of the request.
+		logging.SetUserID(r.Context(), userSession.GetUser().ID)
 		next.ServeHTTP(w, r)
 	})
 }
 
+// ErrNotAuthenticated is returned by the CurrentUser if the session cookie or the session is missing, eg. it is expired.
+var ErrNotAuthenticated = errors.New("not authenticated")
+
 // CurrentUser returns the current user.
-func (c *Controller) CurrentUser(r *http.Request) *model.User {
+// It returns ErrNotAuthenticated if the session cookie or the session is missing.
+func (c *Controller) CurrentUser(r *http.Request) (*model.User, error) {
 	sessionKey, err := r.Cookie("session")
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("%w: %w", ErrNotAuthenticated, err)
 	}
 	session, err := c.sessionStore.Get(sessionKey.Value)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("%w: %w", ErrNotAuthenticated, err)
+	}
+	return session.GetUser(), nil
+}
+
+// renderNotAuthenticated redirects to the login page, or responds with unauthorized status on the json routes.
+func (c *Controller) renderNotAuthenticated(w http.ResponseWriter, r *http.Request, err error) {
+	if jsonWriter, ok := w.(render.JSONErrorWriter); ok && jsonWriter.JSONErrors() {
+		c.renderer.Error(w, http.StatusUnauthorized, NotAuthenticatedErrorMessage, err)
+		return
 	}
-	return session.GetUser()
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
 }
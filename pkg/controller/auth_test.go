@@ -2,11 +2,15 @@ package controller
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/akosgarai/projectregister/pkg/config"
+	"github.com/akosgarai/projectregister/pkg/health"
+	"github.com/akosgarai/projectregister/pkg/metrics"
 	"github.com/akosgarai/projectregister/pkg/model"
 	"github.com/akosgarai/projectregister/pkg/passwd"
 	"github.com/akosgarai/projectregister/pkg/render"
@@ -20,7 +24,13 @@ func getNewAuthController() *Controller {
 		testhelper.NewRepositoryContainerMock(),
 		session.NewStore(testConfig),
 		testhelper.CSVStorageMock{},
-		render.NewRenderer(testConfig, render.NewTemplates()))
+		render.NewRenderer(testConfig, render.NewTemplates()),
+		nil,
+		"",
+		metrics.NewRegistry(),
+		"",
+		health.NewChecker(time.Second),
+		nil)
 	// call the cacheTemplate function
 	c.CacheTemplates()
 	return c
@@ -122,6 +132,8 @@ func TestLoginActionControllerNoUser(t *testing.T) {
 		session.NewStore(testConfig),
 		testhelper.CSVStorageMock{},
 		render.NewRenderer(testConfig, render.NewTemplates()),
+		nil,
+		"",
 	)
 
 	// Send request with the username and password.
@@ -160,7 +172,13 @@ func TestLoginActionControllerWrongPassword(t *testing.T) {
 		repositoryContainer,
 		session.NewStore(testConfig),
 		testhelper.CSVStorageMock{},
-		render.NewRenderer(testConfig, render.NewTemplates()))
+		render.NewRenderer(testConfig, render.NewTemplates()),
+		nil,
+		"",
+		metrics.NewRegistry(),
+		"",
+		health.NewChecker(time.Second),
+		nil)
 
 	// Send request with the username and password.
 	// The user db is not empty, but the password is wrong.
@@ -201,7 +219,13 @@ func TestLoginActionController(t *testing.T) {
 		repositoryContainer,
 		session.NewStore(testConfig),
 		testhelper.CSVStorageMock{},
-		render.NewRenderer(testConfig, render.NewTemplates()))
+		render.NewRenderer(testConfig, render.NewTemplates()),
+		nil,
+		"",
+		metrics.NewRegistry(),
+		"",
+		health.NewChecker(time.Second),
+		nil)
 
 	// Send request with the username and password.
 	// The user db is not empty, and the password is correct.
@@ -223,3 +247,24 @@ func TestLoginActionController(t *testing.T) {
 	// On this case the status code is 303.
 	testhelper.CheckResponseCode(t, rr, http.StatusSeeOther)
 }
+
+// TestCurrentUserWithoutSession tests that the missing session is an error, and the pages redirect to the login page.
+func TestCurrentUserWithoutSession(t *testing.T) {
+	c := getNewAuthController()
+	req, err := http.NewRequest("GET", "/admin/dashboard", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CurrentUser(req); !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("Expected not authenticated error, got %v", err)
+	}
+	req.AddCookie(&http.Cookie{Name: "session", Value: "expired"})
+	if _, err := c.CurrentUser(req); !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("Expected not authenticated error with expired session, got %v", err)
+	}
+	rr := httptest.NewRecorder()
+	c.DashboardController(rr, req)
+	if rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/login" {
+		t.Errorf("Expected redirect to the login page, got %d %s", rr.Code, rr.Header().Get("Location"))
+	}
+}
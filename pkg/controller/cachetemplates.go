@@ -1,25 +1,32 @@
 package controller
 
+import (
+	"github.com/akosgarai/projectregister/pkg/render"
+)
+
 // CacheTemplates builds the templates and stores them in templates.
+// The paths are relative to the root of the template file system.
 func (c *Controller) CacheTemplates() {
 
-	headerTemplate := c.renderer.GetTemplateDirectoryPath() + "/frontend-components/header.html.tmpl"
-	formItemsTemplate := c.renderer.GetTemplateDirectoryPath() + "/frontend-components/form-items.html.tmpl"
-	detailItemsTemplate := c.renderer.GetTemplateDirectoryPath() + "/frontend-components/detail-items.html.tmpl"
-	listingItemsTemplate := c.renderer.GetTemplateDirectoryPath() + "/frontend-components/listing.html.tmpl"
+	headerTemplate := "frontend-components/header.html.tmpl"
+	formItemsTemplate := "frontend-components/form-items.html.tmpl"
+	detailItemsTemplate := "frontend-components/detail-items.html.tmpl"
+	listingItemsTemplate := "frontend-components/listing.html.tmpl"
 
 	// Template for the login page.
-	c.renderer.Template.AddTemplate("login.html", []string{headerTemplate, c.renderer.GetTemplateDirectoryPath() + "/auth/login.html.tmpl"})
+	c.renderer.Template.AddTemplate("login.html", []string{headerTemplate, "auth/login.html.tmpl"})
 
 	// Template for the dashboard.
-	c.renderer.Template.AddTemplate("dashboard.html", []string{headerTemplate, c.renderer.GetTemplateDirectoryPath() + "/dashboard/index.html.tmpl"})
-	// Template for the application import mapping.
-	c.renderer.Template.AddTemplate("application-import-mapping.html", []string{headerTemplate, formItemsTemplate, listingItemsTemplate, c.renderer.GetTemplateDirectoryPath() + "/pages/application-import-mapping.html.tmpl"})
+	c.renderer.Template.AddTemplate("dashboard.html", []string{headerTemplate, "dashboard/index.html.tmpl"})
+	// Template for the import mapping and preview pages.
+	c.renderer.Template.AddTemplate("import-mapping.html", []string{headerTemplate, formItemsTemplate, listingItemsTemplate, "pages/import-mapping.html.tmpl"})
 
 	// Template for the view.
-	c.renderer.Template.AddTemplate("detail-page.html", []string{headerTemplate, detailItemsTemplate, c.renderer.GetTemplateDirectoryPath() + "/pages/detail.html.tmpl"})
+	c.renderer.Template.AddTemplate("detail-page.html", []string{headerTemplate, detailItemsTemplate, "pages/detail.html.tmpl"})
 	// Template for the list.
-	c.renderer.Template.AddTemplate("listing-page.html", []string{headerTemplate, listingItemsTemplate, formItemsTemplate, c.renderer.GetTemplateDirectoryPath() + "/pages/listing.html.tmpl"})
+	c.renderer.Template.AddTemplate("listing-page.html", []string{headerTemplate, listingItemsTemplate, formItemsTemplate, "pages/listing.html.tmpl"})
 	// Template for the update.
-	c.renderer.Template.AddTemplate("form-page.html", []string{headerTemplate, formItemsTemplate, c.renderer.GetTemplateDirectoryPath() + "/pages/form.html.tmpl"})
+	c.renderer.Template.AddTemplate("form-page.html", []string{headerTemplate, formItemsTemplate, "pages/form.html.tmpl"})
+	// Template for the errors.
+	c.renderer.Template.AddTemplate(render.ErrorPageTemplate, []string{headerTemplate, "pages/error.html.tmpl"})
 }
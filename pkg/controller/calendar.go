@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/calendar"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+const (
+	// calendarFeedName is the name of the expiry calendar.
+	calendarFeedName = "Project Register Expiry"
+	// calendarFeedReminder is the time before the expiry when the calendar alarm is triggered.
+	calendarFeedReminder = 14 * 24 * time.Hour
+)
+
+// CalendarFeedController is the controller of the expiry calendar feed.
+// GET /calendar/expiry.ics?token={token}
+// It returns the domain and the certificate expiry dates in iCalendar format.
+// The feed is public, as the calendar clients can not log in, but it is protected with the token.
+// If the token is not configured, the feed is disabled.
+func (c *Controller) CalendarFeedController(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if c.calendarFeedToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(c.calendarFeedToken)) != 1 {
+		c.renderer.Error(w, http.StatusNotFound, CalendarFeedNotFoundErrorMessage, nil)
+		return
+	}
+	domains, err := c.repositoryContainer.GetDomainRepository().GetDomains(model.NewDomainFilter())
+	if err != nil {
+		c.renderRepositoryError(w, CalendarFeedFailedToGetDomainsErrorMessage, err)
+		return
+	}
+	certificates, err := c.repositoryContainer.GetCertificateRepository().GetCertificates(model.NewCertificateFilter())
+	if err != nil {
+		c.renderRepositoryError(w, CalendarFeedFailedToGetCertificatesErrorMessage, err)
+		return
+	}
+	baseURL := "http://" + r.Host
+	if r.TLS != nil {
+		baseURL = "https://" + r.Host
+	}
+	events := []*calendar.Event{}
+	for _, domain := range *domains {
+		expiresAt, ok := domain.ExpiryDate()
+		if !ok {
+			continue
+		}
+		description := fmt.Sprintf("Registrar: %s\nAuto renew: %t", domain.Registrar, domain.AutoRenew)
+		if domain.BillingClient != nil {
+			description += "\nBilling client: " + domain.BillingClient.Name
+		}
+		events = append(events, &calendar.Event{
+			UID:         fmt.Sprintf("domain-%d@projectregister", domain.ID),
+			Summary:     "Domain expires: " + domain.Name,
+			Description: description,
+			URL:         fmt.Sprintf("%s/admin/domain/view/%d", baseURL, domain.ID),
+			Date:        expiresAt,
+			Reminder:    calendarFeedReminder,
+		})
+	}
+	for _, certificate := range *certificates {
+		expiresAt := certificate.ExpiresAt()
+		if expiresAt.IsZero() {
+			continue
+		}
+		events = append(events, &calendar.Event{
+			UID:         fmt.Sprintf("certificate-%d@projectregister", certificate.ID),
+			Summary:     "Certificate expires: " + certificate.Name,
+			Description: fmt.Sprintf("Common name: %s\nIssuer: %s", certificate.CommonName, certificate.Issuer),
+			URL:         fmt.Sprintf("%s/admin/certificate/view/%d", baseURL, certificate.ID),
+			Date:        expiresAt.UTC(),
+			Reminder:    calendarFeedReminder,
+		})
+	}
+	// the calendar is generated into a buffer, so that the error page could be sent on failure.
+	var buffer bytes.Buffer
+	if err := calendar.Write(&buffer, calendarFeedName, events, time.Now()); err != nil {
+		c.renderer.Error(w, http.StatusInternalServerError, CalendarFeedFailedToWriteErrorMessage, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", "inline; filename=\"expiry.ics\"")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buffer.Bytes())
+}
@@ -0,0 +1,273 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/akosgarai/projectregister/pkg/certificate"
+	"github.com/akosgarai/projectregister/pkg/controller/response"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+const (
+	// certificateMaxPEMSize is the maximum size of the uploaded PEM file.
+	certificateMaxPEMSize = 1 << 20
+)
+
+// CertificateViewController is the controller for the certificate view page.
+// GET /admin/certificate/view/{certificateId}
+// It renders the certificate view page.
+func (c *Controller) CertificateViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("certificates.view") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	certificate, statusCode, err := c.certificateViewData(r)
+	if err != nil {
+		c.renderer.Error(w, statusCode, CertificateFailedToGetCertificateErrorMessage, err)
+		return
+	}
+	content := response.NewCertificateDetailResponse(currentUser, certificate)
+	err = c.renderer.Template.RenderTemplate(w, "detail-page.html", content)
+	if err != nil {
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+	}
+}
+
+// certificateViewData gets the request as input, and returns the certificate data, status code and error.
+func (c *Controller) certificateViewData(r *http.Request) (*model.Certificate, int, error) {
+	vars := mux.Vars(r)
+	certificateIDVariable := vars["certificateId"]
+	// it has to be converted to int64
+	certificateID, err := strconv.ParseInt(certificateIDVariable, 10, 64)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	certificate, err := c.repositoryContainer.GetCertificateRepository().GetCertificateByID(certificateID)
+	if err != nil {
+		return nil, repositoryErrorStatus(err), err
+	}
+	return certificate, http.StatusOK, nil
+}
+
+// CertificateCreateViewController is the controller for the certificate create view.
+// On case of get request, it returns the certificate upload page.
+// On case of post request, it parses the uploaded PEM file, links the certificate
+// to the domains that are covered by its SAN entries and redirects to the view page.
+func (c *Controller) CertificateCreateViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("certificates.create") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	if r.Method == http.MethodGet {
+		content := response.NewCreateCertificateResponse(currentUser)
+		err := c.renderer.Template.RenderTemplate(w, "form-page.html", content)
+		if err != nil {
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+		}
+	}
+
+	if r.Method == http.MethodPost {
+		r.ParseMultipartForm(certificateMaxPEMSize)
+		file, _, err := r.FormFile("pemfile")
+		if err != nil {
+			c.renderer.Error(w, http.StatusBadRequest, CertificateCreateRequiredFieldMissing, err)
+			return
+		}
+		defer file.Close()
+		pemData, err := io.ReadAll(io.LimitReader(file, certificateMaxPEMSize))
+		if err != nil {
+			c.renderer.Error(w, http.StatusBadRequest, CertificateCreateFailedToReadFileErrorMessage, err)
+			return
+		}
+		parsed, err := certificate.ParsePEM(pemData)
+		if err != nil {
+			c.renderer.Error(w, http.StatusBadRequest, CertificateCreateInvalidPEMErrorMessage, err)
+			return
+		}
+		// only the missing certificate could be registered, the other lookup errors are not hidden.
+		_, err = c.repositoryContainer.GetCertificateRepository().GetCertificateByFingerprint(parsed.Fingerprint)
+		if err == nil {
+			c.renderer.Error(w, http.StatusBadRequest, CertificateCreateAlreadyRegisteredErrorMessage, nil)
+			return
+		}
+		if !errors.Is(err, model.ErrNotFound) {
+			c.renderer.Error(w, http.StatusInternalServerError, CertificateFailedToGetCertificateErrorMessage, err)
+			return
+		}
+		// the name is optional, the common name is used as default.
+		name := r.FormValue("name")
+		if name == "" {
+			name = parsed.CommonName
+		}
+		domains, err := c.repositoryContainer.GetDomainRepository().GetDomains(model.NewDomainFilter())
+		if err != nil {
+			c.renderRepositoryError(w, CertificateCreateFailedToGetDomainsErrorMessage, err)
+			return
+		}
+		domainIDs := []int64{}
+		for _, domain := range certificate.CoveredDomains(parsed, *domains) {
+			domainIDs = append(domainIDs, domain.ID)
+		}
+
+		created, err := c.repositoryContainer.GetCertificateRepository().CreateCertificate(name, parsed.CommonName, parsed.Issuer, parsed.SerialNumber, parsed.Fingerprint, parsed.DNSNames, parsed.NotBefore, parsed.NotAfter, parsed.PEM, domainIDs)
+		if err != nil {
+			c.renderRepositoryError(w, CertificateCreateCreateCertificateErrorMessage, err)
+			return
+		}
+		http.Redirect(w, r, "/admin/certificate/view/"+strconv.FormatInt(created.ID, 10), http.StatusSeeOther)
+		return
+	}
+}
+
+// CertificateUpdateViewController is the controller for the certificate update view.
+// On case of get request, it returns the certificate update page.
+// On case of post request, it updates the name and the domains of the certificate and redirects to the list page.
+func (c *Controller) CertificateUpdateViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("certificates.update") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	vars := mux.Vars(r)
+	certificateIDVariable := vars["certificateId"]
+	// it has to be converted to int64
+	certificateID, err := strconv.ParseInt(certificateIDVariable, 10, 64)
+	if err != nil {
+		c.renderer.Error(w, http.StatusBadRequest, CertificateCertificateIDInvalidErrorMessage, err)
+		return
+	}
+
+	// get the certificate
+	certificate, err := c.repositoryContainer.GetCertificateRepository().GetCertificateByID(certificateID)
+	if err != nil {
+		c.renderRepositoryError(w, CertificateFailedToGetCertificateErrorMessage, err)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		domains, err := c.repositoryContainer.GetDomainRepository().GetDomains(model.NewDomainFilter())
+		if err != nil {
+			c.renderRepositoryError(w, CertificateUpdateFailedToGetDomainsErrorMessage, err)
+			return
+		}
+		content := response.NewUpdateCertificateResponse(currentUser, certificate, domains)
+		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
+		if err != nil {
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+		}
+	}
+
+	if r.Method == http.MethodPost {
+		r.ParseForm()
+		name := r.FormValue("name")
+
+		// if the name is empty, return an error
+		if name == "" {
+			c.renderer.Error(w, http.StatusBadRequest, CertificateUpdateRequiredFieldMissing, nil)
+			return
+		}
+		certificate.Name = name
+		certificate.Domains = []*model.Domain{}
+		for _, domainIDRaw := range r.Form["domains"] {
+			domainID, err := strconv.ParseInt(domainIDRaw, 10, 64)
+			if err != nil {
+				c.renderer.Error(w, http.StatusBadRequest, CertificateUpdateDomainIDInvalidErrorMessage, err)
+				return
+			}
+			certificate.Domains = append(certificate.Domains, &model.Domain{ID: domainID})
+		}
+
+		err = c.repositoryContainer.GetCertificateRepository().UpdateCertificate(certificate)
+		if err != nil {
+			c.renderRepositoryError(w, CertificateUpdateUpdateCertificateErrorMessage, err)
+			return
+		}
+		http.Redirect(w, r, "/admin/certificate/list", http.StatusSeeOther)
+		return
+	}
+}
+
+// CertificateDeleteViewController is the controller for the certificate delete form.
+// It is responsible for deleting a certificate.
+// It redirects to the certificate list page.
+func (c *Controller) CertificateDeleteViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("certificates.delete") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	vars := mux.Vars(r)
+	certificateIDVariable := vars["certificateId"]
+	// it has to be converted to int64
+	certificateID, err := strconv.ParseInt(certificateIDVariable, 10, 64)
+	if err != nil {
+		c.renderer.Error(w, http.StatusBadRequest, CertificateCertificateIDInvalidErrorMessage, err)
+		return
+	}
+	// delete the certificate
+	err = c.repositoryContainer.GetCertificateRepository().DeleteCertificate(certificateID)
+	if err != nil {
+		c.renderRepositoryError(w, CertificateDeleteFailedToDeleteErrorMessage, err)
+		return
+	}
+	// redirect to the certificate list
+	http.Redirect(w, r, "/admin/certificate/list", http.StatusSeeOther)
+}
+
+// CertificateListViewController is the controller for the certificate list view.
+// The certificates are ordered by the expiration date.
+func (c *Controller) CertificateListViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("certificates.view") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	filter := model.NewCertificateFilter()
+	if r.Method == http.MethodPost {
+		r.ParseForm()
+		filter.Name = r.FormValue("name")
+		filter.DomainIDs = r.Form["domains"]
+	}
+	certificates, err := c.repositoryContainer.GetCertificateRepository().GetCertificates(filter)
+	if err != nil {
+		c.renderRepositoryError(w, CertificateListFailedToGetCertificatesErrorMessage, err)
+		return
+	}
+	domains, err := c.repositoryContainer.GetDomainRepository().GetDomains(model.NewDomainFilter())
+	if err != nil {
+		c.renderRepositoryError(w, CertificateListFailedToGetDomainsErrorMessage, err)
+		return
+	}
+	content := response.NewCertificateListResponse(currentUser, certificates, domains, filter)
+	err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
+	if err != nil {
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+	}
+}
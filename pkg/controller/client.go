@@ -14,7 +14,11 @@ import (
 // GET /admin/client/view/{clientId}
 // It renders the client view page.
 func (c *Controller) ClientViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("clients.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -27,7 +31,7 @@ func (c *Controller) ClientViewController(w http.ResponseWriter, r *http.Request
 	content := response.NewClientDetailResponse(currentUser, client)
 	err = c.renderer.Template.RenderTemplate(w, "detail-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
 
@@ -42,7 +46,7 @@ func (c *Controller) clientViewData(r *http.Request) (*model.Client, int, error)
 	}
 	client, err := c.repositoryContainer.GetClientRepository().GetClientByID(clientID)
 	if err != nil {
-		return nil, http.StatusInternalServerError, err
+		return nil, repositoryErrorStatus(err), err
 	}
 	return client, http.StatusOK, nil
 }
@@ -51,7 +55,11 @@ func (c *Controller) clientViewData(r *http.Request) (*model.Client, int, error)
 // On case of get request, it returns the client create page.
 // On case of post request, it creates the client and redirects to the list page.
 func (c *Controller) ClientCreateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("clients.create") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -60,7 +68,7 @@ func (c *Controller) ClientCreateViewController(w http.ResponseWriter, r *http.R
 		content := response.NewCreateClientResponse(currentUser)
 		err := c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -76,7 +84,7 @@ func (c *Controller) ClientCreateViewController(w http.ResponseWriter, r *http.R
 
 		_, err := c.repositoryContainer.GetClientRepository().CreateClient(name)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, ClientCreateCreateClientErrorMessage, err)
+			c.renderRepositoryError(w, ClientCreateCreateClientErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/client/list", http.StatusSeeOther)
@@ -88,7 +96,11 @@ func (c *Controller) ClientCreateViewController(w http.ResponseWriter, r *http.R
 // On case of get request, it returns the client update page.
 // On case of post request, it updates the client and redirects to the list page.
 func (c *Controller) ClientUpdateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("clients.update") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -105,7 +117,7 @@ func (c *Controller) ClientUpdateViewController(w http.ResponseWriter, r *http.R
 	// get the client
 	client, err := c.repositoryContainer.GetClientRepository().GetClientByID(clientID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ClientFailedToGetClientErrorMessage, err)
+		c.renderRepositoryError(w, ClientFailedToGetClientErrorMessage, err)
 		return
 	}
 
@@ -113,7 +125,7 @@ func (c *Controller) ClientUpdateViewController(w http.ResponseWriter, r *http.R
 		content := response.NewUpdateClientResponse(currentUser, client)
 		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -131,7 +143,7 @@ func (c *Controller) ClientUpdateViewController(w http.ResponseWriter, r *http.R
 		client.Name = name
 		err = c.repositoryContainer.GetClientRepository().UpdateClient(client)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, ClientUpdateUpdateClientErrorMessage, err)
+			c.renderRepositoryError(w, ClientUpdateUpdateClientErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/client/list", http.StatusSeeOther)
@@ -143,7 +155,11 @@ func (c *Controller) ClientUpdateViewController(w http.ResponseWriter, r *http.R
 // It is responsible for deleting a client.
 // It redirects to the client list page.
 func (c *Controller) ClientDeleteViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("clients.delete") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -159,7 +175,7 @@ func (c *Controller) ClientDeleteViewController(w http.ResponseWriter, r *http.R
 	// delete the client
 	err = c.repositoryContainer.GetClientRepository().DeleteClient(clientID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ClientDeleteFailedToDeleteErrorMessage, err)
+		c.renderRepositoryError(w, ClientDeleteFailedToDeleteErrorMessage, err)
 		return
 	}
 	// redirect to the client list
@@ -168,7 +184,11 @@ func (c *Controller) ClientDeleteViewController(w http.ResponseWriter, r *http.R
 
 // ClientListViewController is the controller for the client list view.
 func (c *Controller) ClientListViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("clients.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -184,12 +204,12 @@ func (c *Controller) ClientListViewController(w http.ResponseWriter, r *http.Req
 	// get all clients
 	clients, err := c.repositoryContainer.GetClientRepository().GetClients(filter)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ClientListFailedToGetClientsErrorMessage, err)
+		c.renderRepositoryError(w, ClientListFailedToGetClientsErrorMessage, err)
 		return
 	}
 	content := response.NewClientListResponse(currentUser, clients, filter)
 	err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
@@ -1,7 +1,14 @@
 package controller
 
 import (
+	"errors"
+	"net/http"
+
+	"github.com/akosgarai/projectregister/pkg/health"
+	"github.com/akosgarai/projectregister/pkg/jobqueue"
+	"github.com/akosgarai/projectregister/pkg/metrics"
 	"github.com/akosgarai/projectregister/pkg/model"
+	"github.com/akosgarai/projectregister/pkg/rdap"
 	"github.com/akosgarai/projectregister/pkg/render"
 	"github.com/akosgarai/projectregister/pkg/session"
 	"github.com/akosgarai/projectregister/pkg/storage"
@@ -16,6 +23,22 @@ type Controller struct {
 	csvStorage   storage.CSVStorage
 
 	renderer *render.Renderer
+
+	// registrationLookup is the domain registration data source. Nil means that the lookup is disabled.
+	registrationLookup rdap.Lookup
+	// calendarFeedToken is the access token of the calendar feed. Empty means that the feed is disabled.
+	calendarFeedToken string
+
+	// metricsRegistry holds the collectors of the metrics endpoint.
+	metricsRegistry *metrics.Registry
+	// metricsToken is the bearer token of the metrics endpoint. Empty means that the endpoint is public.
+	metricsToken string
+
+	// healthChecker runs the dependency checks of the readiness endpoint.
+	healthChecker *health.Checker
+
+	// jobQueue runs the long operations, eg. the imports and the domain checks in the background.
+	jobQueue *jobqueue.Queue
 }
 
 // New creates a new controller
@@ -24,6 +47,12 @@ func New(
 	sessionStore *session.Store,
 	csvStorage storage.CSVStorage,
 	renderer *render.Renderer,
+	registrationLookup rdap.Lookup,
+	calendarFeedToken string,
+	metricsRegistry *metrics.Registry,
+	metricsToken string,
+	healthChecker *health.Checker,
+	jobQueue *jobqueue.Queue,
 ) *Controller {
 	return &Controller{
 		repositoryContainer: repositoryContainer,
@@ -32,5 +61,48 @@ func New(
 		csvStorage:   csvStorage,
 
 		renderer: renderer,
+
+		registrationLookup: registrationLookup,
+		calendarFeedToken:  calendarFeedToken,
+
+		metricsRegistry: metricsRegistry,
+		metricsToken:    metricsToken,
+
+		healthChecker: healthChecker,
+
+		jobQueue: jobQueue,
 	}
 }
+
+// repositoryErrorStatus maps the typed repository errors to http status codes.
+// The missing items are 404, the unique constraint violations are 409,
+// the foreign key violations are 422 and every other error is 500.
+func repositoryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, model.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, model.ErrForeignKey):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+// repositoryErrorMessage extends the message with the friendly description of the typed repository errors.
+func repositoryErrorMessage(message string, err error) string {
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		return message + ". " + RepositoryNotFoundErrorMessage
+	case errors.Is(err, model.ErrConflict):
+		return message + ". " + RepositoryConflictErrorMessage
+	case errors.Is(err, model.ErrForeignKey):
+		return message + ". " + RepositoryForeignKeyErrorMessage
+	}
+	return message
+}
+
+// renderRepositoryError renders the error of a repository call with the mapped status code and message.
+func (c *Controller) renderRepositoryError(w http.ResponseWriter, message string, err error) {
+	c.renderer.Error(w, repositoryErrorStatus(err), repositoryErrorMessage(message, err), err)
+}
@@ -1,9 +1,16 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/akosgarai/projectregister/pkg/config"
+	"github.com/akosgarai/projectregister/pkg/health"
+	"github.com/akosgarai/projectregister/pkg/metrics"
+	"github.com/akosgarai/projectregister/pkg/model"
 	"github.com/akosgarai/projectregister/pkg/render"
 	"github.com/akosgarai/projectregister/pkg/session"
 	"github.com/akosgarai/projectregister/pkg/testhelper"
@@ -21,6 +28,12 @@ func TestNew(t *testing.T) {
 		sessionStore,
 		csvStorage,
 		renderer,
+		nil,
+		"",
+		metrics.NewRegistry(),
+		"",
+		health.NewChecker(time.Second),
+		nil,
 	)
 	if c.repositoryContainer.GetUserRepository() != repositoryContainer.Users {
 		t.Errorf("UserRepository field is not the same as the input.")
@@ -65,3 +78,25 @@ func TestNew(t *testing.T) {
 		t.Errorf("Renderer field is not the same as the input.")
 	}
 }
+
+// TestRepositoryErrorStatus tests the status code and message mapping of the repository errors.
+func TestRepositoryErrorStatus(t *testing.T) {
+	testData := []struct {
+		err             error
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{fmt.Errorf("%w: sql: no rows in result set", model.ErrNotFound), http.StatusNotFound, "Failed. " + RepositoryNotFoundErrorMessage},
+		{fmt.Errorf("%w: duplicate key", model.ErrConflict), http.StatusConflict, "Failed. " + RepositoryConflictErrorMessage},
+		{fmt.Errorf("%w: violates foreign key", model.ErrForeignKey), http.StatusUnprocessableEntity, "Failed. " + RepositoryForeignKeyErrorMessage},
+		{errors.New("connection refused"), http.StatusInternalServerError, "Failed"},
+	}
+	for _, tt := range testData {
+		if status := repositoryErrorStatus(tt.err); status != tt.expectedStatus {
+			t.Errorf("Invalid status for '%s'. Expected: %d, got: %d", tt.err, tt.expectedStatus, status)
+		}
+		if message := repositoryErrorMessage("Failed", tt.err); message != tt.expectedMessage {
+			t.Errorf("Invalid message for '%s'. Expected: '%s', got: '%s'", tt.err, tt.expectedMessage, message)
+		}
+	}
+}
@@ -9,12 +9,16 @@ import (
 
 // DashboardController is the dashboard controller.
 func (c *Controller) DashboardController(w http.ResponseWriter, r *http.Request) {
-	user := c.CurrentUser(r)
+	user, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	headerText := "Dashboard"
 	headerContent := components.NewContentHeader(headerText, []*components.Link{})
 	content := response.NewResponse(headerText, user, headerContent)
-	err := c.renderer.Template.RenderTemplate(w, "dashboard.html", content)
+	err = c.renderer.Template.RenderTemplate(w, "dashboard.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
@@ -4,8 +4,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/akosgarai/projectregister/pkg/config"
+	"github.com/akosgarai/projectregister/pkg/health"
+	"github.com/akosgarai/projectregister/pkg/metrics"
 	"github.com/akosgarai/projectregister/pkg/render"
 	"github.com/akosgarai/projectregister/pkg/session"
 	"github.com/akosgarai/projectregister/pkg/testhelper"
@@ -25,7 +28,13 @@ func TestDashboardController(t *testing.T) {
 		testhelper.NewRepositoryContainerMock(),
 		sessionStore,
 		testhelper.CSVStorageMock{},
-		render.NewRenderer(testConfig, render.NewTemplates()))
+		render.NewRenderer(testConfig, render.NewTemplates()),
+		nil,
+		"",
+		metrics.NewRegistry(),
+		"",
+		health.NewChecker(time.Second),
+		nil)
 	c.CacheTemplates()
 
 	req, err := testhelper.NewRequestWithSessionCookie("GET", "/dashboard")
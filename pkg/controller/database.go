@@ -14,7 +14,11 @@ import (
 // GET /admin/database/view/{databaseId}
 // It renders the database view page.
 func (c *Controller) DatabaseViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("databases.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -27,7 +31,7 @@ func (c *Controller) DatabaseViewController(w http.ResponseWriter, r *http.Reque
 	content := response.NewDatabaseDetailResponse(currentUser, database)
 	err = c.renderer.Template.RenderTemplate(w, "detail-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
 
@@ -42,7 +46,7 @@ func (c *Controller) databaseViewData(r *http.Request) (*model.Database, int, er
 	}
 	database, err := c.repositoryContainer.GetDatabaseRepository().GetDatabaseByID(databaseID)
 	if err != nil {
-		return nil, http.StatusInternalServerError, err
+		return nil, repositoryErrorStatus(err), err
 	}
 	return database, http.StatusOK, nil
 }
@@ -51,7 +55,11 @@ func (c *Controller) databaseViewData(r *http.Request) (*model.Database, int, er
 // On case of get request, it returns the database create page.
 // On case of post request, it creates the database and redirects to the list page.
 func (c *Controller) DatabaseCreateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("databases.create") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -60,7 +68,7 @@ func (c *Controller) DatabaseCreateViewController(w http.ResponseWriter, r *http
 		content := response.NewCreateDatabaseResponse(currentUser)
 		err := c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -76,7 +84,7 @@ func (c *Controller) DatabaseCreateViewController(w http.ResponseWriter, r *http
 
 		_, err := c.repositoryContainer.GetDatabaseRepository().CreateDatabase(name)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, DatabaseCreateCreateDatabaseErrorMessage, err)
+			c.renderRepositoryError(w, DatabaseCreateCreateDatabaseErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/database/list", http.StatusSeeOther)
@@ -88,7 +96,11 @@ func (c *Controller) DatabaseCreateViewController(w http.ResponseWriter, r *http
 // On case of get request, it returns the database update page.
 // On case of post request, it updates the database and redirects to the list page.
 func (c *Controller) DatabaseUpdateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("databases.update") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -105,7 +117,7 @@ func (c *Controller) DatabaseUpdateViewController(w http.ResponseWriter, r *http
 	// get the database
 	database, err := c.repositoryContainer.GetDatabaseRepository().GetDatabaseByID(databaseID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, DatabaseFailedToGetDatabaseErrorMessage, err)
+		c.renderRepositoryError(w, DatabaseFailedToGetDatabaseErrorMessage, err)
 		return
 	}
 
@@ -113,7 +125,7 @@ func (c *Controller) DatabaseUpdateViewController(w http.ResponseWriter, r *http
 		content := response.NewUpdateDatabaseResponse(currentUser, database)
 		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -131,7 +143,7 @@ func (c *Controller) DatabaseUpdateViewController(w http.ResponseWriter, r *http
 		database.Name = name
 		err = c.repositoryContainer.GetDatabaseRepository().UpdateDatabase(database)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, DatabaseUpdateUpdateDatabaseErrorMessage, err)
+			c.renderRepositoryError(w, DatabaseUpdateUpdateDatabaseErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/database/list", http.StatusSeeOther)
@@ -143,7 +155,11 @@ func (c *Controller) DatabaseUpdateViewController(w http.ResponseWriter, r *http
 // It is responsible for deleting a database.
 // It redirects to the database list page.
 func (c *Controller) DatabaseDeleteViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("databases.delete") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -159,7 +175,7 @@ func (c *Controller) DatabaseDeleteViewController(w http.ResponseWriter, r *http
 	// delete the database
 	err = c.repositoryContainer.GetDatabaseRepository().DeleteDatabase(databaseID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, DatabaseDeleteFailedToDeleteErrorMessage, err)
+		c.renderRepositoryError(w, DatabaseDeleteFailedToDeleteErrorMessage, err)
 		return
 	}
 	// redirect to the database list
@@ -168,7 +184,11 @@ func (c *Controller) DatabaseDeleteViewController(w http.ResponseWriter, r *http
 
 // DatabaseListViewController is the controller for the database list view.
 func (c *Controller) DatabaseListViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("databases.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -180,12 +200,12 @@ func (c *Controller) DatabaseListViewController(w http.ResponseWriter, r *http.R
 	// get all databases
 	databases, err := c.repositoryContainer.GetDatabaseRepository().GetDatabases(filter)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, DatabaseListFailedToGetDatabasesErrorMessage, err)
+		c.renderRepositoryError(w, DatabaseListFailedToGetDatabasesErrorMessage, err)
 		return
 	}
 	content := response.NewDatabaseListResponse(currentUser, databases, filter)
 	err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
@@ -0,0 +1,372 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/akosgarai/projectregister/pkg/controller/response"
+	"github.com/akosgarai/projectregister/pkg/dnszone"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+const (
+	// zoneFileMaxSize is the maximum size of the uploaded zone file.
+	zoneFileMaxSize = 1 << 20
+)
+
+// DNSRecordCreateViewController is the controller for the dns record create view.
+// On case of get request, it returns the dns record create page.
+// On case of post request, it creates the record in the zone of the domain and redirects to the dns record list page.
+func (c *Controller) DNSRecordCreateViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("domains.update") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	vars := mux.Vars(r)
+	domainIDVariable := vars["domainId"]
+	// it has to be converted to int64
+	domainID, err := strconv.ParseInt(domainIDVariable, 10, 64)
+	if err != nil {
+		c.renderer.Error(w, http.StatusBadRequest, DomainDomainIDInvalidErrorMessage, err)
+		return
+	}
+	domain, err := c.repositoryContainer.GetDomainRepository().GetDomainByID(domainID)
+	if err != nil {
+		c.renderRepositoryError(w, DomainFailedToGetDomainErrorMessage, err)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		content := response.NewCreateDNSRecordResponse(currentUser, domain)
+		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
+		if err != nil {
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+		}
+	}
+
+	if r.Method == http.MethodPost {
+		record := &model.DNSRecord{DomainID: domain.ID}
+		errorMessage, err := c.validateDNSRecordForm(r, domain, record)
+		if errorMessage != "" {
+			c.renderer.Error(w, http.StatusBadRequest, errorMessage, err)
+			return
+		}
+		_, err = c.repositoryContainer.GetDNSRecordRepository().CreateDNSRecord(record.DomainID, record.Name, record.Type, record.TTL, record.Priority, record.Value)
+		if err != nil {
+			c.renderRepositoryError(w, DNSRecordCreateCreateDNSRecordErrorMessage, err)
+			return
+		}
+		http.Redirect(w, r, "/admin/dns-record/list/"+strconv.FormatInt(domain.ID, 10), http.StatusSeeOther)
+		return
+	}
+}
+
+// DNSRecordUpdateViewController is the controller for the dns record update view.
+// On case of get request, it returns the dns record update page.
+// On case of post request, it updates the record and redirects to the dns record list page.
+func (c *Controller) DNSRecordUpdateViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("domains.update") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	vars := mux.Vars(r)
+	recordIDVariable := vars["recordId"]
+	// it has to be converted to int64
+	recordID, err := strconv.ParseInt(recordIDVariable, 10, 64)
+	if err != nil {
+		c.renderer.Error(w, http.StatusBadRequest, DNSRecordRecordIDInvalidErrorMessage, err)
+		return
+	}
+	record, err := c.repositoryContainer.GetDNSRecordRepository().GetDNSRecordByID(recordID)
+	if err != nil {
+		c.renderRepositoryError(w, DNSRecordFailedToGetDNSRecordErrorMessage, err)
+		return
+	}
+	domain, err := c.repositoryContainer.GetDomainRepository().GetDomainByID(record.DomainID)
+	if err != nil {
+		c.renderRepositoryError(w, DomainFailedToGetDomainErrorMessage, err)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		content := response.NewUpdateDNSRecordResponse(currentUser, domain, record)
+		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
+		if err != nil {
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+		}
+	}
+
+	if r.Method == http.MethodPost {
+		errorMessage, err := c.validateDNSRecordForm(r, domain, record)
+		if errorMessage != "" {
+			c.renderer.Error(w, http.StatusBadRequest, errorMessage, err)
+			return
+		}
+		err = c.repositoryContainer.GetDNSRecordRepository().UpdateDNSRecord(record)
+		if err != nil {
+			c.renderRepositoryError(w, DNSRecordUpdateUpdateDNSRecordErrorMessage, err)
+			return
+		}
+		http.Redirect(w, r, "/admin/dns-record/list/"+strconv.FormatInt(domain.ID, 10), http.StatusSeeOther)
+		return
+	}
+}
+
+// validateDNSRecordForm sets the record fields from the form values.
+// It returns the error message and the error, the message is empty if the form is valid.
+func (c *Controller) validateDNSRecordForm(r *http.Request, domain *model.Domain, record *model.DNSRecord) (string, error) {
+	record.Name = r.FormValue("name")
+	record.Value = r.FormValue("value")
+	if record.Value == "" {
+		return DNSRecordRequiredFieldMissing, nil
+	}
+	recordType, err := dnsRecordTypeFromForm(r.FormValue("type"))
+	if err != nil {
+		return DNSRecordTypeInvalidErrorMessage, err
+	}
+	record.Type = recordType
+	record.TTL = model.DNSRecordDefaultTTL
+	if ttlRaw := r.FormValue("ttl"); ttlRaw != "" {
+		record.TTL, err = strconv.Atoi(ttlRaw)
+		if err != nil {
+			return DNSRecordTTLInvalidErrorMessage, err
+		}
+	}
+	record.Priority = 0
+	if record.Type == model.DNSRecordTypeMX {
+		record.Priority, err = strconv.Atoi(r.FormValue("priority"))
+		if err != nil {
+			return DNSRecordPriorityInvalidErrorMessage, err
+		}
+	}
+	if err := dnszone.NormalizeRecord(record, domain.Name); err != nil {
+		return DNSRecordInvalidErrorMessage, err
+	}
+	if err := dnszone.ValidateRecord(record); err != nil {
+		return DNSRecordInvalidErrorMessage, err
+	}
+	return "", nil
+}
+
+// dnsRecordTypeFromForm returns the record type of the type select option.
+// The options are the indexes of the supported types starting from 1.
+func dnsRecordTypeFromForm(raw string) (string, error) {
+	typeIndex, err := strconv.Atoi(raw)
+	if err != nil {
+		return "", err
+	}
+	if typeIndex < 1 || typeIndex > len(model.DNSRecordTypes) {
+		return "", fmt.Errorf("invalid type option: %d", typeIndex)
+	}
+	return model.DNSRecordTypes[typeIndex-1], nil
+}
+
+// DNSRecordListViewController is the controller for the dns record list view of a domain.
+// GET /admin/dns-record/list/{domainId}
+// It renders the records of the domain zone.
+func (c *Controller) DNSRecordListViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("domains.view") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	domain, statusCode, err := c.domainViewData(r)
+	if err != nil {
+		c.renderer.Error(w, statusCode, DomainFailedToGetDomainErrorMessage, err)
+		return
+	}
+	filter := model.NewDNSRecordFilter()
+	filter.DomainIDs = []string{strconv.FormatInt(domain.ID, 10)}
+	if r.Method == http.MethodPost && r.FormValue("type") != "" {
+		filter.Type, err = dnsRecordTypeFromForm(r.FormValue("type"))
+		if err != nil {
+			c.renderer.Error(w, http.StatusBadRequest, DNSRecordTypeInvalidErrorMessage, err)
+			return
+		}
+	}
+	records, err := c.repositoryContainer.GetDNSRecordRepository().GetDNSRecords(filter)
+	if err != nil {
+		c.renderRepositoryError(w, DNSRecordListFailedToGetDNSRecordsErrorMessage, err)
+		return
+	}
+	content := response.NewDNSRecordListResponse(currentUser, domain, records, filter)
+	err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
+	if err != nil {
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+	}
+}
+
+// DNSRecordDeleteViewController is the controller for the dns record delete form.
+// It is responsible for deleting a dns record.
+// It redirects to the dns record list page of the domain.
+func (c *Controller) DNSRecordDeleteViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("domains.update") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	vars := mux.Vars(r)
+	recordIDVariable := vars["recordId"]
+	// it has to be converted to int64
+	recordID, err := strconv.ParseInt(recordIDVariable, 10, 64)
+	if err != nil {
+		c.renderer.Error(w, http.StatusBadRequest, DNSRecordRecordIDInvalidErrorMessage, err)
+		return
+	}
+	record, err := c.repositoryContainer.GetDNSRecordRepository().GetDNSRecordByID(recordID)
+	if err != nil {
+		c.renderRepositoryError(w, DNSRecordFailedToGetDNSRecordErrorMessage, err)
+		return
+	}
+	err = c.repositoryContainer.GetDNSRecordRepository().DeleteDNSRecord(recordID)
+	if err != nil {
+		c.renderRepositoryError(w, DNSRecordDeleteFailedToDeleteErrorMessage, err)
+		return
+	}
+	http.Redirect(w, r, "/admin/dns-record/list/"+strconv.FormatInt(record.DomainID, 10), http.StatusSeeOther)
+}
+
+// DomainZoneImportViewController is the controller for the zone file import.
+// On case of get request, it returns the zone file upload page.
+// On case of post request, it creates the apex domain, the domains of the address and alias records
+// and the records of the zone. The records that already exist are skipped, so the import could be repeated.
+// It redirects to the view page of the apex domain.
+func (c *Controller) DomainZoneImportViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("domains.create") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	if r.Method == http.MethodGet {
+		content := response.NewDomainZoneImportResponse(currentUser)
+		err := c.renderer.Template.RenderTemplate(w, "form-page.html", content)
+		if err != nil {
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+		}
+	}
+
+	if r.Method == http.MethodPost {
+		r.ParseMultipartForm(zoneFileMaxSize)
+		file, _, err := r.FormFile("zonefile")
+		if err != nil {
+			c.renderer.Error(w, http.StatusBadRequest, DomainZoneImportRequiredFieldMissing, err)
+			return
+		}
+		defer file.Close()
+		zone, err := dnszone.Parse(io.LimitReader(file, zoneFileMaxSize), r.FormValue("origin"))
+		if err != nil {
+			c.renderer.Error(w, http.StatusBadRequest, DomainZoneImportInvalidZoneFileErrorMessage, err)
+			return
+		}
+		apex, err := c.getOrCreateDomain(zone.Origin)
+		if err != nil {
+			c.renderRepositoryError(w, DomainZoneImportFailedToCreateDomainErrorMessage, err)
+			return
+		}
+		// the default ttl of the zone is kept, so that it is written by the export.
+		if apex.ZoneTTL != zone.TTL {
+			apex.ZoneTTL = zone.TTL
+			if err := c.repositoryContainer.GetDomainRepository().UpdateDomain(apex); err != nil {
+				c.renderRepositoryError(w, DomainZoneImportFailedToUpdateDomainErrorMessage, err)
+				return
+			}
+		}
+		recordFilter := model.NewDNSRecordFilter()
+		recordFilter.DomainIDs = []string{strconv.FormatInt(apex.ID, 10)}
+		existingRecords, err := c.repositoryContainer.GetDNSRecordRepository().GetDNSRecords(recordFilter)
+		if err != nil {
+			c.renderRepositoryError(w, DomainZoneImportFailedToGetDNSRecordsErrorMessage, err)
+			return
+		}
+		for _, record := range zone.Records {
+			// the host names of the zone are registered as domains
+			isHostRecord := record.Type == model.DNSRecordTypeA || record.Type == model.DNSRecordTypeAAAA || record.Type == model.DNSRecordTypeCNAME
+			if isHostRecord && record.Name != model.DNSRecordApexName && record.Name[0] != '*' {
+				if _, err := c.getOrCreateDomain(record.FQDN(zone.Origin)); err != nil {
+					c.renderRepositoryError(w, DomainZoneImportFailedToCreateDomainErrorMessage, err)
+					return
+				}
+			}
+			if existingRecords.Contains(record) {
+				continue
+			}
+			_, err = c.repositoryContainer.GetDNSRecordRepository().CreateDNSRecord(apex.ID, record.Name, record.Type, record.TTL, record.Priority, record.Value)
+			if err != nil {
+				c.renderRepositoryError(w, DomainZoneImportFailedToCreateDNSRecordErrorMessage, err)
+				return
+			}
+		}
+		http.Redirect(w, r, "/admin/domain/view/"+strconv.FormatInt(apex.ID, 10), http.StatusSeeOther)
+		return
+	}
+}
+
+// getOrCreateDomain returns the domain with the given name. The domain is created if it does not exist.
+func (c *Controller) getOrCreateDomain(name string) (*model.Domain, error) {
+	domain, err := c.repositoryContainer.GetDomainRepository().GetDomainByName(name)
+	if errors.Is(err, model.ErrNotFound) {
+		return c.repositoryContainer.GetDomainRepository().CreateDomain(name)
+	}
+	return domain, err
+}
+
+// DomainZoneExportController is the controller for the zone file export.
+// GET /admin/domain/zone-export/{domainId}
+// It returns the zone file of the domain as attachment.
+func (c *Controller) DomainZoneExportController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("domains.view") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	domain, statusCode, err := c.domainViewData(r)
+	if err != nil {
+		c.renderer.Error(w, statusCode, DomainFailedToGetDomainErrorMessage, err)
+		return
+	}
+	recordFilter := model.NewDNSRecordFilter()
+	recordFilter.DomainIDs = []string{strconv.FormatInt(domain.ID, 10)}
+	records, err := c.repositoryContainer.GetDNSRecordRepository().GetDNSRecords(recordFilter)
+	if err != nil {
+		c.renderRepositoryError(w, DomainFailedToGetDNSRecordsErrorMessage, err)
+		return
+	}
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": domain.Name + ".zone"}))
+	w.Header().Set("Content-Type", "text/dns")
+	if err := dnszone.Export(w, domain.Name, domain.ZoneTTL, *records); err != nil {
+		c.renderer.Error(w, http.StatusInternalServerError, DomainZoneExportFailedToExportErrorMessage, err)
+		return
+	}
+}
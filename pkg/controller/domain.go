@@ -1,21 +1,34 @@
 package controller
 
 import (
+	"context"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 
 	"github.com/akosgarai/projectregister/pkg/controller/response"
-	"github.com/akosgarai/projectregister/pkg/domaincheck"
 	"github.com/akosgarai/projectregister/pkg/model"
+	"github.com/akosgarai/projectregister/pkg/rdap"
+)
+
+const (
+	// domainExpiringDefaultDays is the default time window of the expiring domains view in days.
+	domainExpiringDefaultDays = 60
+	// domainRDAPLookupTimeout is the time limit of the registration lookup.
+	domainRDAPLookupTimeout = 20 * time.Second
 )
 
 // DomainViewController is the controller for the domain view page.
 // GET /admin/domain/view/{domainId}
 // It renders the domain view page.
 func (c *Controller) DomainViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("domains.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -25,10 +38,24 @@ func (c *Controller) DomainViewController(w http.ResponseWriter, r *http.Request
 		c.renderer.Error(w, statusCode, DomainFailedToGetDomainErrorMessage, err)
 		return
 	}
-	content := response.NewDomainDetailResponse(currentUser, domain)
+	certificateFilter := model.NewCertificateFilter()
+	certificateFilter.DomainIDs = []string{strconv.FormatInt(domain.ID, 10)}
+	certificates, err := c.repositoryContainer.GetCertificateRepository().GetCertificates(certificateFilter)
+	if err != nil {
+		c.renderRepositoryError(w, DomainFailedToGetCertificatesErrorMessage, err)
+		return
+	}
+	recordFilter := model.NewDNSRecordFilter()
+	recordFilter.DomainIDs = []string{strconv.FormatInt(domain.ID, 10)}
+	records, err := c.repositoryContainer.GetDNSRecordRepository().GetDNSRecords(recordFilter)
+	if err != nil {
+		c.renderRepositoryError(w, DomainFailedToGetDNSRecordsErrorMessage, err)
+		return
+	}
+	content := response.NewDomainDetailResponse(currentUser, domain, certificates, records)
 	err = c.renderer.Template.RenderTemplate(w, "detail-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
 
@@ -43,7 +70,7 @@ func (c *Controller) domainViewData(r *http.Request) (*model.Domain, int, error)
 	}
 	domain, err := c.repositoryContainer.GetDomainRepository().GetDomainByID(domainID)
 	if err != nil {
-		return nil, http.StatusInternalServerError, err
+		return nil, repositoryErrorStatus(err), err
 	}
 	return domain, http.StatusOK, nil
 }
@@ -52,16 +79,25 @@ func (c *Controller) domainViewData(r *http.Request) (*model.Domain, int, error)
 // On case of get request, it returns the domain create page.
 // On case of post request, it creates the domain and redirects to the list page.
 func (c *Controller) DomainCreateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("domains.create") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
 	}
 	if r.Method == http.MethodGet {
-		content := response.NewCreateDomainResponse(currentUser)
-		err := c.renderer.Template.RenderTemplate(w, "form-page.html", content)
+		clients, err := c.repositoryContainer.GetClientRepository().GetClients(model.NewClientFilter())
+		if err != nil {
+			c.renderRepositoryError(w, DomainCreateFailedToGetClientsErrorMessage, err)
+			return
+		}
+		content := response.NewCreateDomainResponse(currentUser, clients)
+		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -75,9 +111,17 @@ func (c *Controller) DomainCreateViewController(w http.ResponseWriter, r *http.R
 			return
 		}
 
-		_, err := c.repositoryContainer.GetDomainRepository().CreateDomain(name)
+		registration := &model.Domain{}
+		if err := domainRegistrationFromForm(r, registration); err != nil {
+			c.renderer.Error(w, http.StatusBadRequest, DomainCreateInvalidRegistrationErrorMessage, err)
+			return
+		}
+
+		// the domain is created with its registration data in one insert.
+		registration.Name = name
+		_, err := c.repositoryContainer.GetDomainRepository().CreateDomainWithRegistration(registration)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, DomainCreateCreateDomainErrorMessage, err)
+			c.renderRepositoryError(w, DomainCreateCreateDomainErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/domain/list", http.StatusSeeOther)
@@ -89,7 +133,11 @@ func (c *Controller) DomainCreateViewController(w http.ResponseWriter, r *http.R
 // On case of get request, it returns the domain update page.
 // On case of post request, it updates the domain and redirects to the list page.
 func (c *Controller) DomainUpdateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("domains.update") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -106,15 +154,20 @@ func (c *Controller) DomainUpdateViewController(w http.ResponseWriter, r *http.R
 	// get the domain
 	domain, err := c.repositoryContainer.GetDomainRepository().GetDomainByID(domainID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, DomainFailedToGetDomainErrorMessage, err)
+		c.renderRepositoryError(w, DomainFailedToGetDomainErrorMessage, err)
 		return
 	}
 
 	if r.Method == http.MethodGet {
-		content := response.NewUpdateDomainResponse(currentUser, domain)
+		clients, err := c.repositoryContainer.GetClientRepository().GetClients(model.NewClientFilter())
+		if err != nil {
+			c.renderRepositoryError(w, DomainUpdateFailedToGetClientsErrorMessage, err)
+			return
+		}
+		content := response.NewUpdateDomainResponse(currentUser, domain, clients)
 		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -128,11 +181,16 @@ func (c *Controller) DomainUpdateViewController(w http.ResponseWriter, r *http.R
 			return
 		}
 
+		if err := domainRegistrationFromForm(r, domain); err != nil {
+			c.renderer.Error(w, http.StatusBadRequest, DomainUpdateInvalidRegistrationErrorMessage, err)
+			return
+		}
+
 		// update the domain
 		domain.Name = name
 		err = c.repositoryContainer.GetDomainRepository().UpdateDomain(domain)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, DomainUpdateUpdateDomainErrorMessage, err)
+			c.renderRepositoryError(w, DomainUpdateUpdateDomainErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/domain/list", http.StatusSeeOther)
@@ -140,11 +198,44 @@ func (c *Controller) DomainUpdateViewController(w http.ResponseWriter, r *http.R
 	}
 }
 
+// domainRegistrationFromForm sets the registration data of the domain from the submitted form.
+// The dates have to be in the model.DomainDateFormat format, the empty values clear the data.
+func domainRegistrationFromForm(r *http.Request, domain *model.Domain) error {
+	registeredAt := r.FormValue("registered_at")
+	expiresAt := r.FormValue("expires_at")
+	for _, date := range []string{registeredAt, expiresAt} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse(model.DomainDateFormat, date); err != nil {
+			return err
+		}
+	}
+	var billingClient *model.Client
+	if billingClientIDRaw := r.FormValue("billing_client"); billingClientIDRaw != "" {
+		billingClientID, err := strconv.ParseInt(billingClientIDRaw, 10, 64)
+		if err != nil {
+			return err
+		}
+		billingClient = &model.Client{ID: billingClientID}
+	}
+	domain.Registrar = r.FormValue("registrar")
+	domain.RegisteredAt = registeredAt
+	domain.ExpiresAt = expiresAt
+	domain.AutoRenew = r.FormValue("auto_renew") == "1"
+	domain.BillingClient = billingClient
+	return nil
+}
+
 // DomainDeleteViewController is the controller for the domain delete form.
 // It is responsible for deleting a domain.
 // It redirects to the domain list page.
 func (c *Controller) DomainDeleteViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("domains.delete") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -160,7 +251,7 @@ func (c *Controller) DomainDeleteViewController(w http.ResponseWriter, r *http.R
 	// delete the domain
 	err = c.repositoryContainer.GetDomainRepository().DeleteDomain(domainID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, DomainDeleteFailedToDeleteErrorMessage, err)
+		c.renderRepositoryError(w, DomainDeleteFailedToDeleteErrorMessage, err)
 		return
 	}
 	// redirect to the domain list
@@ -169,7 +260,11 @@ func (c *Controller) DomainDeleteViewController(w http.ResponseWriter, r *http.R
 
 // DomainListViewController is the controller for the domain list view.
 func (c *Controller) DomainListViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("domains.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -181,21 +276,31 @@ func (c *Controller) DomainListViewController(w http.ResponseWriter, r *http.Req
 	// get all domains
 	domains, err := c.repositoryContainer.GetDomainRepository().GetDomains(filter)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, DomainListFailedToGetDomainsErrorMessage, err)
+		c.renderRepositoryError(w, DomainListFailedToGetDomainsErrorMessage, err)
 		return
 	}
-	content := response.NewDomainListResponse(currentUser, domains, filter)
+	// the certificates are necessary for the certificate mismatch flag
+	certificates, err := c.repositoryContainer.GetCertificateRepository().GetCertificates(model.NewCertificateFilter())
+	if err != nil {
+		c.renderRepositoryError(w, DomainListFailedToGetCertificatesErrorMessage, err)
+		return
+	}
+	content := response.NewDomainListResponse(currentUser, domains, certificates, filter)
 	err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
 
 // DomainCheckSSLViewController is the controller for the domain check ssl view.
-// It is responsible for checking the ssl status of a domain.
-// It redirects to the domain view page.
+// It is responsible for starting the ssl status check of a domain.
+// It redirects to the job view page.
 func (c *Controller) DomainCheckSSLViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("domains.update") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -211,17 +316,136 @@ func (c *Controller) DomainCheckSSLViewController(w http.ResponseWriter, r *http
 	// get the domain
 	domain, err := c.repositoryContainer.GetDomainRepository().GetDomainByID(domainID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, DomainFailedToGetDomainErrorMessage, err)
+		c.renderRepositoryError(w, DomainFailedToGetDomainErrorMessage, err)
+		return
+	}
+	// the ssl status is checked in the background
+	payload := &model.DomainCheckPayload{DomainIDs: []int64{domain.ID}, SSL: true}
+	c.enqueueJob(w, r, model.JobTypeDomainCheck, payload, DomainCheckFailedToStartErrorMessage)
+}
+
+// DomainCheckSecurityViewController is the controller for the domain security audit.
+// It starts the audit of the security headers and the TLS configuration of the domain, the grade is stored by the job.
+// It redirects to the job view page.
+func (c *Controller) DomainCheckSecurityViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
 		return
 	}
-	// check the ssl status
-	hasSSL := domaincheck.HasSSL(domain)
-	// update the domain
-	domain.HasSSL = hasSSL
+	if !currentUser.HasPrivilege("domains.update") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	vars := mux.Vars(r)
+	domainIDVariable := vars["domainId"]
+	// it has to be converted to int64
+	domainID, err := strconv.ParseInt(domainIDVariable, 10, 64)
+	if err != nil {
+		c.renderer.Error(w, http.StatusBadRequest, DomainDomainIDInvalidErrorMessage, err)
+		return
+	}
+	// get the domain
+	domain, err := c.repositoryContainer.GetDomainRepository().GetDomainByID(domainID)
+	if err != nil {
+		c.renderRepositoryError(w, DomainFailedToGetDomainErrorMessage, err)
+		return
+	}
+	// the domain is audited in the background
+	payload := &model.DomainCheckPayload{DomainIDs: []int64{domain.ID}, Security: true}
+	c.enqueueJob(w, r, model.JobTypeDomainCheck, payload, DomainCheckFailedToStartErrorMessage)
+}
+
+// DomainRDAPLookupViewController is the controller for the domain registration lookup.
+// It gets the registrar and the registration dates of the domain from the RDAP service and stores them.
+// The subdomains are resolved to their registered domain.
+// It redirects to the domain view page.
+func (c *Controller) DomainRDAPLookupViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("domains.update") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	if c.registrationLookup == nil {
+		c.renderer.Error(w, http.StatusServiceUnavailable, DomainRDAPLookupDisabledErrorMessage, nil)
+		return
+	}
+	vars := mux.Vars(r)
+	domainIDVariable := vars["domainId"]
+	// it has to be converted to int64
+	domainID, err := strconv.ParseInt(domainIDVariable, 10, 64)
+	if err != nil {
+		c.renderer.Error(w, http.StatusBadRequest, DomainDomainIDInvalidErrorMessage, err)
+		return
+	}
+	// get the domain
+	domain, err := c.repositoryContainer.GetDomainRepository().GetDomainByID(domainID)
+	if err != nil {
+		c.renderRepositoryError(w, DomainFailedToGetDomainErrorMessage, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), domainRDAPLookupTimeout)
+	defer cancel()
+	registration, err := rdap.LookupRegistrable(ctx, c.registrationLookup, domain.Name)
+	if err != nil {
+		c.renderer.Error(w, http.StatusBadGateway, DomainRDAPLookupFailedErrorMessage, err)
+		return
+	}
+	// the unknown values do not overwrite the stored data
+	if registration.Registrar != "" {
+		domain.Registrar = registration.Registrar
+	}
+	if !registration.RegisteredAt.IsZero() {
+		domain.RegisteredAt = registration.RegisteredAt.Format(model.DomainDateFormat)
+	}
+	if !registration.ExpiresAt.IsZero() {
+		domain.ExpiresAt = registration.ExpiresAt.Format(model.DomainDateFormat)
+	}
 	err = c.repositoryContainer.GetDomainRepository().UpdateDomain(domain)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, DomainCheckSSLFailedToUpdateDomainErrorMessage, err)
+		c.renderRepositoryError(w, DomainRDAPLookupFailedToUpdateDomainErrorMessage, err)
 		return
 	}
 	http.Redirect(w, r, "/admin/domain/view/"+strconv.FormatInt(domainID, 10), http.StatusSeeOther)
 }
+
+// DomainExpiringViewController is the controller for the expiring domains view.
+// It lists the domains that expire in the given number of days, ordered by the expiry date.
+// The already expired domains are also listed.
+func (c *Controller) DomainExpiringViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("domains.view") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	days := domainExpiringDefaultDays
+	if r.Method == http.MethodPost {
+		var err error
+		days, err = strconv.Atoi(r.FormValue("days"))
+		if err != nil || days < 0 {
+			c.renderer.Error(w, http.StatusBadRequest, DomainExpiringDaysInvalidErrorMessage, err)
+			return
+		}
+	}
+	now := time.Now()
+	filter := model.NewDomainFilter()
+	filter.ExpiresBefore = now.AddDate(0, 0, days).Format(model.DomainDateFormat)
+	domains, err := c.repositoryContainer.GetDomainRepository().GetDomains(filter)
+	if err != nil {
+		c.renderRepositoryError(w, DomainExpiringFailedToGetDomainsErrorMessage, err)
+		return
+	}
+	content := response.NewDomainExpiringListResponse(currentUser, domains, days, now)
+	err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
+	if err != nil {
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+	}
+}
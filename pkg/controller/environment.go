@@ -14,7 +14,11 @@ import (
 // GET /admin/environment/view/{environmentId}
 // It renders the environment view page.
 func (c *Controller) EnvironmentViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("environments.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -27,7 +31,7 @@ func (c *Controller) EnvironmentViewController(w http.ResponseWriter, r *http.Re
 	content := response.NewEnvironmentDetailResponse(currentUser, environment)
 	err = c.renderer.Template.RenderTemplate(w, "detail-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
 
@@ -42,7 +46,7 @@ func (c *Controller) environmentViewData(r *http.Request) (*model.Environment, i
 	}
 	environment, err := c.repositoryContainer.GetEnvironmentRepository().GetEnvironmentByID(environmentID)
 	if err != nil {
-		return nil, http.StatusInternalServerError, err
+		return nil, repositoryErrorStatus(err), err
 	}
 	return environment, http.StatusOK, nil
 }
@@ -51,7 +55,11 @@ func (c *Controller) environmentViewData(r *http.Request) (*model.Environment, i
 // On case of get request, it returns the environment create page.
 // On case of post request, it creates the environment and redirects to the list page.
 func (c *Controller) EnvironmentCreateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("environments.create") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -59,18 +67,18 @@ func (c *Controller) EnvironmentCreateViewController(w http.ResponseWriter, r *h
 	if r.Method == http.MethodGet {
 		servers, err := c.repositoryContainer.GetServerRepository().GetServers(model.NewServerFilter())
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, EnvironmentCreateFailedToGetServersErrorMessage, err)
+			c.renderRepositoryError(w, EnvironmentCreateFailedToGetServersErrorMessage, err)
 			return
 		}
 		databases, err := c.repositoryContainer.GetDatabaseRepository().GetDatabases(model.NewDatabaseFilter())
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, EnvironmentCreateFailedToGetDatabasesErrorMessage, err)
+			c.renderRepositoryError(w, EnvironmentCreateFailedToGetDatabasesErrorMessage, err)
 			return
 		}
 		content := response.NewCreateEnvironmentResponse(currentUser, servers, databases)
 		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -113,7 +121,7 @@ func (c *Controller) EnvironmentCreateViewController(w http.ResponseWriter, r *h
 
 		_, err = c.repositoryContainer.GetEnvironmentRepository().CreateEnvironment(name, description, serverIDs, databaseIDs, score)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, EnvironmentCreateCreateEnvironmentErrorMessage, err)
+			c.renderRepositoryError(w, EnvironmentCreateCreateEnvironmentErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/environment/list", http.StatusSeeOther)
@@ -125,7 +133,11 @@ func (c *Controller) EnvironmentCreateViewController(w http.ResponseWriter, r *h
 // On case of get request, it returns the environment update page.
 // On case of post request, it updates the environment and redirects to the list page.
 func (c *Controller) EnvironmentUpdateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("environments.update") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -142,25 +154,25 @@ func (c *Controller) EnvironmentUpdateViewController(w http.ResponseWriter, r *h
 	// get the environment
 	environment, err := c.repositoryContainer.GetEnvironmentRepository().GetEnvironmentByID(environmentID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, EnvironmentFailedToGetEnvironmentErrorMessage, err)
+		c.renderRepositoryError(w, EnvironmentFailedToGetEnvironmentErrorMessage, err)
 		return
 	}
 
 	if r.Method == http.MethodGet {
 		servers, err := c.repositoryContainer.GetServerRepository().GetServers(model.NewServerFilter())
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, EnvironmentUpdateFailedToGetServersErrorMessage, err)
+			c.renderRepositoryError(w, EnvironmentUpdateFailedToGetServersErrorMessage, err)
 			return
 		}
 		databases, err := c.repositoryContainer.GetDatabaseRepository().GetDatabases(model.NewDatabaseFilter())
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, EnvironmentUpdateFailedToGetDatabasesErrorMessage, err)
+			c.renderRepositoryError(w, EnvironmentUpdateFailedToGetDatabasesErrorMessage, err)
 			return
 		}
 		content := response.NewUpdateEnvironmentResponse(currentUser, environment, servers, databases)
 		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -208,7 +220,7 @@ func (c *Controller) EnvironmentUpdateViewController(w http.ResponseWriter, r *h
 		environment.Score = score
 		err = c.repositoryContainer.GetEnvironmentRepository().UpdateEnvironment(environment)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, EnvironmentUpdateUpdateEnvironmentErrorMessage, err)
+			c.renderRepositoryError(w, EnvironmentUpdateUpdateEnvironmentErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/environment/list", http.StatusSeeOther)
@@ -220,7 +232,11 @@ func (c *Controller) EnvironmentUpdateViewController(w http.ResponseWriter, r *h
 // It is responsible for deleting a environment.
 // It redirects to the environment list page.
 func (c *Controller) EnvironmentDeleteViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("environments.delete") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -236,7 +252,7 @@ func (c *Controller) EnvironmentDeleteViewController(w http.ResponseWriter, r *h
 	// delete the environment
 	err = c.repositoryContainer.GetEnvironmentRepository().DeleteEnvironment(environmentID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, EnvironmentDeleteFailedToDeleteErrorMessage, err)
+		c.renderRepositoryError(w, EnvironmentDeleteFailedToDeleteErrorMessage, err)
 		return
 	}
 	// redirect to the environment list
@@ -245,7 +261,11 @@ func (c *Controller) EnvironmentDeleteViewController(w http.ResponseWriter, r *h
 
 // EnvironmentListViewController is the controller for the environment list view.
 func (c *Controller) EnvironmentListViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("environments.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -274,22 +294,22 @@ func (c *Controller) EnvironmentListViewController(w http.ResponseWriter, r *htt
 	// get all environments
 	environments, err := c.repositoryContainer.GetEnvironmentRepository().GetEnvironments(filter)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, EnvironmentListFailedToGetEnvironmentsErrorMessage, err)
+		c.renderRepositoryError(w, EnvironmentListFailedToGetEnvironmentsErrorMessage, err)
 		return
 	}
 	servers, err := c.repositoryContainer.GetServerRepository().GetServers(model.NewServerFilter())
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, EnvironmentListFailedToGetServersErrorMessage, err)
+		c.renderRepositoryError(w, EnvironmentListFailedToGetServersErrorMessage, err)
 		return
 	}
 	databases, err := c.repositoryContainer.GetDatabaseRepository().GetDatabases(model.NewDatabaseFilter())
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, EnvironmentListFailedToGetDatabasesErrorMessage, err)
+		c.renderRepositoryError(w, EnvironmentListFailedToGetDatabasesErrorMessage, err)
 		return
 	}
 	content := response.NewEnvironmentListResponse(currentUser, environments, servers, databases, filter)
 	err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
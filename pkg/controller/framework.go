@@ -14,7 +14,11 @@ import (
 // GET /admin/framework/view/{frameworkId}
 // It renders the framework view page.
 func (c *Controller) FrameworkViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("frameworks.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -27,7 +31,7 @@ func (c *Controller) FrameworkViewController(w http.ResponseWriter, r *http.Requ
 	content := response.NewFrameworkDetailResponse(currentUser, framework)
 	err = c.renderer.Template.RenderTemplate(w, "detail-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
 
@@ -42,7 +46,7 @@ func (c *Controller) frameworkViewData(r *http.Request) (*model.Framework, int,
 	}
 	framework, err := c.repositoryContainer.GetFrameworkRepository().GetFrameworkByID(frameworkID)
 	if err != nil {
-		return nil, http.StatusInternalServerError, err
+		return nil, repositoryErrorStatus(err), err
 	}
 	return framework, http.StatusOK, nil
 }
@@ -51,7 +55,11 @@ func (c *Controller) frameworkViewData(r *http.Request) (*model.Framework, int,
 // On case of get request, it returns the framework create page.
 // On case of post request, it creates the framework and redirects to the list page.
 func (c *Controller) FrameworkCreateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("frameworks.create") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -60,7 +68,7 @@ func (c *Controller) FrameworkCreateViewController(w http.ResponseWriter, r *htt
 		content := response.NewCreateFrameworkResponse(currentUser)
 		err := c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -81,7 +89,7 @@ func (c *Controller) FrameworkCreateViewController(w http.ResponseWriter, r *htt
 
 		_, err = c.repositoryContainer.GetFrameworkRepository().CreateFramework(name, score)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, FrameworkCreateCreateFrameworkErrorMessage, err)
+			c.renderRepositoryError(w, FrameworkCreateCreateFrameworkErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/framework/list", http.StatusSeeOther)
@@ -93,7 +101,11 @@ func (c *Controller) FrameworkCreateViewController(w http.ResponseWriter, r *htt
 // On case of get request, it returns the framework update page.
 // On case of post request, it updates the framework and redirects to the list page.
 func (c *Controller) FrameworkUpdateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("frameworks.update") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -110,7 +122,7 @@ func (c *Controller) FrameworkUpdateViewController(w http.ResponseWriter, r *htt
 	// get the framework
 	framework, err := c.repositoryContainer.GetFrameworkRepository().GetFrameworkByID(frameworkID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, FrameworkFailedToGetFrameworkErrorMessage, err)
+		c.renderRepositoryError(w, FrameworkFailedToGetFrameworkErrorMessage, err)
 		return
 	}
 
@@ -118,7 +130,7 @@ func (c *Controller) FrameworkUpdateViewController(w http.ResponseWriter, r *htt
 		content := response.NewUpdateFrameworkResponse(currentUser, framework)
 		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -142,7 +154,7 @@ func (c *Controller) FrameworkUpdateViewController(w http.ResponseWriter, r *htt
 		framework.Score = score
 		err = c.repositoryContainer.GetFrameworkRepository().UpdateFramework(framework)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, FrameworkUpdateUpdateFrameworkErrorMessage, err)
+			c.renderRepositoryError(w, FrameworkUpdateUpdateFrameworkErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/framework/list", http.StatusSeeOther)
@@ -154,7 +166,11 @@ func (c *Controller) FrameworkUpdateViewController(w http.ResponseWriter, r *htt
 // It is responsible for deleting a framework.
 // It redirects to the framework list page.
 func (c *Controller) FrameworkDeleteViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("frameworks.delete") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -170,7 +186,7 @@ func (c *Controller) FrameworkDeleteViewController(w http.ResponseWriter, r *htt
 	// delete the framework
 	err = c.repositoryContainer.GetFrameworkRepository().DeleteFramework(frameworkID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, FrameworkDeleteFailedToDeleteErrorMessage, err)
+		c.renderRepositoryError(w, FrameworkDeleteFailedToDeleteErrorMessage, err)
 		return
 	}
 	// redirect to the framework list
@@ -179,7 +195,11 @@ func (c *Controller) FrameworkDeleteViewController(w http.ResponseWriter, r *htt
 
 // FrameworkListViewController is the controller for the framework list view.
 func (c *Controller) FrameworkListViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("frameworks.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -191,12 +211,12 @@ func (c *Controller) FrameworkListViewController(w http.ResponseWriter, r *http.
 	// get all frameworks
 	frameworks, err := c.repositoryContainer.GetFrameworkRepository().GetFrameworks(filter)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, FrameworkListFailedToGetFrameworksErrorMessage, err)
+		c.renderRepositoryError(w, FrameworkListFailedToGetFrameworksErrorMessage, err)
 		return
 	}
 	content := response.NewFrameworkListResponse(currentUser, frameworks, filter)
 	err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
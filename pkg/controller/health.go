@@ -2,10 +2,26 @@ package controller
 
 import (
 	"net/http"
+
+	"github.com/akosgarai/projectregister/pkg/health"
 )
 
-// HealthController is the health controller.
-// It is responsible for handling health checks.
-func (c *Controller) HealthController(w http.ResponseWriter, r *http.Request) {
-	c.renderer.Status(w, http.StatusOK)
+// HealthLiveController is the liveness check controller.
+// GET /health/live
+// It returns 200 while the process is able to serve requests, the dependencies are not checked.
+func (c *Controller) HealthLiveController(w http.ResponseWriter, r *http.Request) {
+	c.renderer.JSON(w, http.StatusOK, &health.Report{Status: health.StatusOK, Checks: []*health.Result{}})
+}
+
+// HealthReadyController is the readiness check controller.
+// GET /health/ready
+// It runs the dependency checks and returns the breakdown of the checks.
+// The status code is 503 if any of the checks is failed.
+func (c *Controller) HealthReadyController(w http.ResponseWriter, r *http.Request) {
+	report := c.healthChecker.Run(r.Context())
+	statusCode := http.StatusOK
+	if !report.OK() {
+		statusCode = http.StatusServiceUnavailable
+	}
+	c.renderer.JSON(w, statusCode, report)
 }
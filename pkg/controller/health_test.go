@@ -1,45 +1,83 @@
 package controller
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/akosgarai/projectregister/pkg/config"
+	"github.com/akosgarai/projectregister/pkg/health"
+	"github.com/akosgarai/projectregister/pkg/metrics"
 	"github.com/akosgarai/projectregister/pkg/render"
 	"github.com/akosgarai/projectregister/pkg/session"
 	"github.com/akosgarai/projectregister/pkg/testhelper"
 )
 
-func TestHealthCheckHandler(t *testing.T) {
-	// Create a request to pass to our handler. We don't have any query parameters for now, so we'll
-	// pass 'nil' as the third parameter.
-	req, err := http.NewRequest("GET", "/health", nil)
-	if err != nil {
-		t.Fatal(err)
-	}
-
+// getNewHealthController returns a controller with the given health checker.
+func getNewHealthController(healthChecker *health.Checker) *Controller {
 	testConfig := config.NewEnvironment(testhelper.TestConfigData)
-	c := New(
+	return New(
 		testhelper.NewRepositoryContainerMock(),
 		session.NewStore(testConfig),
 		testhelper.CSVStorageMock{},
-		render.NewRenderer(testConfig, render.NewTemplates()))
+		render.NewRenderer(testConfig, render.NewTemplates()),
+		nil,
+		"",
+		metrics.NewRegistry(),
+		"",
+		healthChecker,
+		nil)
+}
 
-	// We create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
+// TestHealthLiveController tests that the liveness check does not run the dependency checks.
+func TestHealthLiveController(t *testing.T) {
+	healthChecker := health.NewChecker(time.Second)
+	healthChecker.Add("database", func(ctx context.Context) error { return errors.New("connection refused") })
+	c := getNewHealthController(healthChecker)
+	req, err := http.NewRequest("GET", "/health/live", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
 	rr := httptest.NewRecorder()
-	handler := http.HandlerFunc(c.HealthController)
-
-	// Our handlers satisfy http.Handler, so we can call their ServeHTTP method
-	// directly and pass in our Request and ResponseRecorder.
+	handler := http.HandlerFunc(c.HealthLiveController)
 	handler.ServeHTTP(rr, req)
 
-	// Check the status code is what we expect.
-	testhelper.CheckResponseCode(t, rr, http.StatusOK)
+	testhelper.CheckResponse(t, rr, http.StatusOK, []string{"\"status\":\"ok\""})
+}
+
+// TestHealthReadyController tests the passed and the failed readiness checks.
+func TestHealthReadyController(t *testing.T) {
+	testData := []struct {
+		checkError     error
+		expectedStatus int
+		expectedReport string
+	}{
+		{nil, http.StatusOK, health.StatusOK},
+		{errors.New("connection refused"), http.StatusServiceUnavailable, health.StatusFailed},
+	}
+	for _, tt := range testData {
+		healthChecker := health.NewChecker(time.Second)
+		healthChecker.Add("database", func(ctx context.Context) error { return tt.checkError })
+		c := getNewHealthController(healthChecker)
+		req, err := http.NewRequest("GET", "/health/ready", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(c.HealthReadyController)
+		handler.ServeHTTP(rr, req)
 
-	// Check the response body is what we expect.
-	expected := ""
-	if rr.Body.String() != expected {
-		t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
+		testhelper.CheckResponseCode(t, rr, tt.expectedStatus)
+		var report health.Report
+		if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil {
+			t.Fatalf("Invalid json response: %v", err)
+		}
+		if report.Status != tt.expectedReport || len(report.Checks) != 1 || report.Checks[0].Name != "database" {
+			t.Errorf("Invalid report: %+v", report)
+		}
 	}
 }
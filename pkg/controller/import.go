@@ -0,0 +1,771 @@
+package controller
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/akosgarai/projectregister/pkg/controller/response"
+	"github.com/akosgarai/projectregister/pkg/model"
+	"github.com/akosgarai/projectregister/pkg/parser"
+	"github.com/akosgarai/projectregister/pkg/passwd"
+	"github.com/akosgarai/projectregister/pkg/storage"
+)
+
+const (
+	// uploadFormOverhead is the allowed size of the other multipart form fields next to the uploaded file.
+	uploadFormOverhead = 1 << 20
+	// importPasswordBytes is the number of the random bytes of the generated user passwords.
+	importPasswordBytes = 16
+)
+
+// importResource describes a resource of the generic import wizard.
+// The fields are the mapped fields in the order of the mapping form, the key is the unique field of the rows.
+// The importRow function imports the mapped row. On case of dry run it only checks the row,
+// and returns the description of the changes instead of creating the resource.
+type importResource struct {
+	label     string
+	privilege string
+	fields    []string
+	key       string
+	importRow func(c *Controller, rowData map[string]string, listSeparator string, dryRun bool) (string, error)
+}
+
+// importResources are the resources of the generic import wizard. The key is the url name of the resource.
+var importResources = map[string]*importResource{
+	"server": {
+		label:     "Servers",
+		privilege: "servers.create",
+		fields:    []string{"name", "description", "remote_address", "runtimes", "pools"},
+		key:       "name",
+		importRow: importServerRow,
+	},
+	"environment": {
+		label:     "Environments",
+		privilege: "environments.create",
+		fields:    []string{"name", "description", "score", "servers", "databases"},
+		key:       "name",
+		importRow: importEnvironmentRow,
+	},
+	"domain": {
+		label:     "Domains",
+		privilege: "domains.create",
+		fields:    []string{"name", "registrar", "registered_at", "expires_at", "auto_renew", "billing_client"},
+		key:       "name",
+		importRow: importDomainRow,
+	},
+	"user": {
+		label:     "Users",
+		privilege: "users.create",
+		fields:    []string{"name", "email", "role", "password"},
+		key:       "email",
+		importRow: importUserRow,
+	},
+}
+
+// importFile is the parsed content of an uploaded import file.
+// The first row is the number of the first data row in the file.
+type importFile struct {
+	options   parser.ImportOptions
+	hasHeader bool
+	header    []string
+	rows      [][]string
+	firstRow  int
+}
+
+// ImportViewController is the controller for the resource import upload form.
+// GET /admin/{resource}/import
+// POST /admin/{resource}/import
+// It stores the uploaded file and redirects to the mapping page.
+func (c *Controller) ImportViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	resourceName, resource, ok := c.importResourceFromRequest(w, r, currentUser)
+	if !ok {
+		return
+	}
+	if r.Method == http.MethodGet {
+		content := response.NewImportUploadFormResponse(currentUser, resourceName, resource.label)
+		err := c.renderer.Template.RenderTemplate(w, "form-page.html", content)
+		if err != nil {
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+		}
+	}
+	if r.Method == http.MethodPost {
+		fileID, query, ok := c.uploadImportFile(w, r, currentUser)
+		if !ok {
+			return
+		}
+		http.Redirect(w, r, fmt.Sprintf("/admin/%s/import-mapping/%s?%s", resourceName, fileID, query.Encode()), http.StatusSeeOther)
+	}
+}
+
+// ImportMappingViewController is the controller for the resource import mapping form.
+// GET /admin/{resource}/import-mapping/{fileId}
+// POST /admin/{resource}/import-mapping/{fileId}
+// The get method displays the first rows of the file and the mapping form,
+// the post method checks the mapped rows without importing them, and displays the preview.
+func (c *Controller) ImportMappingViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	resourceName, resource, ok := c.importResourceFromRequest(w, r, currentUser)
+	if !ok {
+		return
+	}
+	fileID, ok := c.importFileID(w, r)
+	if !ok {
+		return
+	}
+	if r.Method == http.MethodGet {
+		// the file of an other user is not found.
+		file, ok := c.readImportFile(w, r, currentUser, fileID, r.URL.Query())
+		if !ok {
+			return
+		}
+		content := response.NewImportMappingFormResponse(currentUser, resourceName, resource.label, resource.fields, fileID, file.rows, file.header, file.hasHeader, file.options)
+		err := c.renderer.Template.RenderTemplate(w, "import-mapping.html", content)
+		if err != nil {
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+		}
+	}
+	if r.Method == http.MethodPost {
+		mapping, options, ok := c.importMappingFromForm(w, r, fileID, resource.fields)
+		if !ok {
+			return
+		}
+		file, ok := c.readImportFile(w, r, currentUser, fileID, r.PostForm)
+		if !ok {
+			return
+		}
+		listSeparator := r.FormValue("list_separator")
+		rows := c.importPreviewRows(resource, file, mapping, listSeparator)
+		content := response.NewImportPreviewResponse(currentUser, resourceName, resource.label, resource.fields, fileID, mapping, listSeparator, file.hasHeader, options, rows)
+		err := c.renderer.Template.RenderTemplate(w, "import-mapping.html", content)
+		if err != nil {
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+		}
+	}
+}
+
+// ImportCommitController is the controller for the resource import.
+// POST /admin/{resource}/import-commit/{fileId}
+// It starts the import job of the previewed mapping and redirects to the job page.
+func (c *Controller) ImportCommitController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	resourceName, resource, ok := c.importResourceFromRequest(w, r, currentUser)
+	if !ok {
+		return
+	}
+	fileID, ok := c.importFileID(w, r)
+	if !ok {
+		return
+	}
+	mapping, options, ok := c.importMappingFromForm(w, r, fileID, resource.fields)
+	if !ok {
+		return
+	}
+	// the import runs in the background, its progress is displayed on the job page.
+	payload := &resourceImportPayload{
+		Resource:      resourceName,
+		FileID:        fileID,
+		Mapping:       mapping,
+		HasHeader:     r.FormValue("has_header") == "true",
+		Options:       options,
+		ListSeparator: r.FormValue("list_separator"),
+	}
+	c.enqueueJob(w, r, model.JobTypeResourceImport, payload, ImportFailedToStartErrorMessage)
+}
+
+// importResourceFromRequest returns the imported resource of the url and checks the privilege of the current user.
+// The error response is rendered if the resource is unknown or the user is not allowed to create it.
+func (c *Controller) importResourceFromRequest(w http.ResponseWriter, r *http.Request, currentUser *model.User) (string, *importResource, bool) {
+	resourceName := mux.Vars(r)["resource"]
+	resource, ok := importResources[resourceName]
+	if !ok {
+		c.renderer.Error(w, http.StatusNotFound, ImportUnknownResourceErrorMessage, nil)
+		return "", nil, false
+	}
+	if !currentUser.HasPrivilege(resource.privilege) {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return "", nil, false
+	}
+	return resourceName, resource, true
+}
+
+// importPreviewRows checks the mapped rows of the file without importing them.
+// The rows with the same key as a previous row are failed, as the import would fail on them.
+func (c *Controller) importPreviewRows(resource *importResource, file *importFile, mapping parser.ImportMapping, listSeparator string) []*response.ImportPreviewRow {
+	rows := []*response.ImportPreviewRow{}
+	keys := map[string]int{}
+	for rowIndex, line := range file.rows {
+		mappedRow := mapImportRow(mapping, line)
+		row := &response.ImportPreviewRow{Number: file.firstRow + rowIndex, RowData: mappedRow.RowData}
+		key := strings.ToLower(mappedRow.RowData[resource.key])
+		if mappedRow.ErrorMessage != "" {
+			row.Result, row.Failed = mappedRow.ErrorMessage, true
+		} else if previous, ok := keys[key]; ok && key != "" {
+			row.Result, row.Failed = fmt.Sprintf("the %s is the same as in row %d", resource.key, previous), true
+		} else if result, err := resource.importRow(c, mappedRow.RowData, listSeparator, true); err != nil {
+			row.Result, row.Failed = err.Error(), true
+		} else {
+			row.Result = result
+		}
+		if _, ok := keys[key]; !ok {
+			keys[key] = row.Number
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// uploadImportFile stores the uploaded import file of the current user.
+// It returns the file id and the query of the mapping page with the format, the parser options and the header flag.
+// The error response is rendered if the upload fails.
+func (c *Controller) uploadImportFile(w http.ResponseWriter, r *http.Request, currentUser *model.User) (string, url.Values, bool) {
+	fileID, format, err := c.storeImportFile(w, r, currentUser)
+	if err != nil {
+		c.renderer.Error(w, uploadErrorStatus(err), ImportFailedToSaveFileErrorMessage, err)
+		return "", nil, false
+	}
+	// The delimiter and the encoding are used by the csv files, the empty ones are detected.
+	options := parser.ImportOptions{Format: format, Sheet: strings.TrimSpace(r.FormValue("sheet"))}
+	if format == parser.FormatCSV {
+		if options.Delimiter, err = csvOptionName(r.FormValue("delimiter"), parser.CSVDelimiters); err != nil {
+			c.renderer.Error(w, http.StatusBadRequest, ImportInvalidOptionsErrorMessage, err)
+			return "", nil, false
+		}
+		if options.Encoding, err = csvOptionName(r.FormValue("encoding"), parser.CSVEncodings); err != nil {
+			c.renderer.Error(w, http.StatusBadRequest, ImportInvalidOptionsErrorMessage, err)
+			return "", nil, false
+		}
+	}
+	query := importOptionsQuery(options)
+	if r.FormValue("has_header") == "1" || options.HasHeader() {
+		query.Set("has_header", "true")
+	}
+	return fileID, query, true
+}
+
+// importFileID returns the validated file id of the url, so that it could not point outside of the upload directory.
+// On case of post method the file id of the form has to be the same.
+func (c *Controller) importFileID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	fileID := mux.Vars(r)["fileId"]
+	if !storage.ValidFileID(fileID) || (r.Method == http.MethodPost && r.FormValue("file_id") != fileID) {
+		c.renderer.Error(w, http.StatusBadRequest, ImportInvalidFileIDErrorMessage, storage.ErrInvalidFileID)
+		return "", false
+	}
+	return fileID, true
+}
+
+// readImportFile reads the uploaded file of the current user with the options and the header flag of the values.
+// The error response is rendered if the file could not be read.
+func (c *Controller) readImportFile(w http.ResponseWriter, r *http.Request, currentUser *model.User, fileID string, values url.Values) (*importFile, bool) {
+	options := importOptionsFromValues(values)
+	if err := options.Validate(); err != nil {
+		c.renderer.Error(w, http.StatusBadRequest, ImportInvalidOptionsErrorMessage, err)
+		return nil, false
+	}
+	data, err := c.csvStorage.Read(r.Context(), currentUser.ID, fileID, options)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, fs.ErrNotExist) {
+			statusCode = http.StatusNotFound
+		}
+		// the content could not be parsed in the format of the file, eg. invalid utf-8 or unknown sheet.
+		if errors.Is(err, parser.ErrInvalidFile) {
+			statusCode = http.StatusBadRequest
+		}
+		c.renderer.Error(w, statusCode, ImportFailedToReadFileErrorMessage, err)
+		return nil, false
+	}
+	file := &importFile{options: options, hasHeader: values.Get("has_header") == "true"}
+	file.header, file.rows, file.firstRow = splitImportHeader(data, file.hasHeader)
+	return file, true
+}
+
+// importMappingFromForm returns the mapping of the fields and the parser options of the submitted form.
+// The fields are mapped to the selected 0 based column indexes, or to the custom values if no column is selected.
+// The error response is rendered if the form is invalid.
+func (c *Controller) importMappingFromForm(w http.ResponseWriter, r *http.Request, fileID string, fields []string) (parser.ImportMapping, parser.ImportOptions, bool) {
+	mapping := parser.NewImportMapping(fields)
+	for _, field := range fields {
+		columnIndexRaw := r.FormValue(field)
+		if columnIndexRaw == "" {
+			mapping[field].CustomValue = r.FormValue(field + "_custom")
+			continue
+		}
+		columnIndex, err := strconv.Atoi(columnIndexRaw)
+		if err != nil || columnIndex < 0 {
+			c.renderer.Error(w, http.StatusBadRequest, ImportInvalidMappingErrorMessage, fmt.Errorf("invalid column %q of %s", columnIndexRaw, field))
+			return nil, parser.ImportOptions{}, false
+		}
+		mapping[field].ColumnIndex = columnIndex
+	}
+	options := importOptionsFromValues(r.PostForm)
+	if err := options.Validate(); err != nil {
+		c.renderer.Error(w, http.StatusBadRequest, ImportInvalidOptionsErrorMessage, err)
+		return nil, parser.ImportOptions{}, false
+	}
+	return mapping, options, true
+}
+
+// storeImportFile stores the uploaded import file of the current user in the file storage.
+// The request body is limited to the maximum upload size. It returns the file id, the format of the file and an error.
+func (c *Controller) storeImportFile(w http.ResponseWriter, r *http.Request, currentUser *model.User) (string, string, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, c.csvStorage.MaxSize()+uploadFormOverhead)
+	file, header, err := r.FormFile("csvfile")
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			return "", "", storage.ErrFileTooLarge
+		}
+		return "", "", err
+	}
+	// store the file
+	return c.csvStorage.Save(r.Context(), currentUser.ID, file, header)
+}
+
+// importOptionsQuery returns the not empty import options as query parameters.
+func importOptionsQuery(options parser.ImportOptions) url.Values {
+	query := url.Values{}
+	for name, value := range map[string]string{"format": options.Format, "delimiter": options.Delimiter, "encoding": options.Encoding, "sheet": options.Sheet} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	return query
+}
+
+// importOptionsFromValues returns the import options of the query parameters or the hidden form fields.
+func importOptionsFromValues(values url.Values) parser.ImportOptions {
+	return parser.ImportOptions{
+		Format:    values.Get("format"),
+		Delimiter: values.Get("delimiter"),
+		Encoding:  values.Get("encoding"),
+		Sheet:     values.Get("sheet"),
+	}
+}
+
+// csvOptionName returns the name of the selected 1 based option index. The empty selection is the empty name.
+func csvOptionName(value string, names []string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	index, err := strconv.Atoi(value)
+	if err != nil || index < 1 || index > len(names) {
+		return "", fmt.Errorf("invalid option %q", value)
+	}
+	return names[index-1], nil
+}
+
+// uploadErrorStatus returns the http status code of the failed upload.
+func uploadErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, storage.ErrFileTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, storage.ErrInvalidFileType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, http.ErrMissingFile):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// splitImportHeader splits the header from the data rows of the file.
+// It returns the header, the data rows and the number of the first data row in the file.
+func splitImportHeader(data [][]string, hasHeader bool) ([]string, [][]string, int) {
+	if hasHeader && len(data) > 0 {
+		return data[0], data[1:], 2
+	}
+	return []string{}, data, 1
+}
+
+// mapImportRow maps the row with the mapping, and trims the whitespaces of the mapped values.
+func mapImportRow(mapping parser.ImportMapping, row []string) *parser.ImportRow {
+	importRow := mapping.MapRow(row)
+	for field, value := range importRow.RowData {
+		importRow.RowData[field] = strings.TrimSpace(value)
+	}
+	return importRow
+}
+
+// importRelation type is a list of the named resources of an imported row, eg. the runtimes of a server.
+// The names are resolved before anything is stored, the missing resources are created only
+// when the whole row is valid, and they are deleted again if the row could not be stored.
+type importRelation struct {
+	kind    string
+	ids     []int64
+	missing []string
+	create  func(name string) (int64, error)
+	remove  func(id int64) error
+	created []int64
+}
+
+// resolveImportRelation returns the relation with the ids of the existing named resources of the kind.
+// The missing resources are created later with the create function, or the row fails if it is nil.
+func resolveImportRelation(kind string, names []string, get, create func(name string) (int64, error), remove func(id int64) error) (*importRelation, error) {
+	relation := &importRelation{kind: kind, ids: []int64{}, missing: []string{}, create: create, remove: remove, created: []int64{}}
+	for _, name := range names {
+		id, err := get(name)
+		if err == nil {
+			relation.ids = append(relation.ids, id)
+			continue
+		}
+		if !errors.Is(err, model.ErrNotFound) {
+			return nil, err
+		}
+		if create == nil {
+			return nil, fmt.Errorf("%s %s is not found", kind, name)
+		}
+		relation.missing = append(relation.missing, name)
+	}
+	return relation, nil
+}
+
+// importRelationNotes returns the notes of the resources that would be created in case of dry run.
+func importRelationNotes(relations ...*importRelation) []string {
+	notes := []string{}
+	for _, relation := range relations {
+		for _, name := range relation.missing {
+			notes = append(notes, fmt.Sprintf("new %s %s", relation.kind, name))
+		}
+	}
+	return notes
+}
+
+// importWithRelations creates the missing resources of the relations, then stores the row with the store function.
+// If any step fails, the created resources are deleted, so that the failed row does not leave orphans.
+func importWithRelations(store func() error, relations ...*importRelation) error {
+	for _, relation := range relations {
+		for _, name := range relation.missing {
+			id, err := relation.create(name)
+			if err != nil {
+				return removeImportRelations(err, relations)
+			}
+			relation.ids = append(relation.ids, id)
+			relation.created = append(relation.created, id)
+		}
+	}
+	if err := store(); err != nil {
+		return removeImportRelations(err, relations)
+	}
+	return nil
+}
+
+// removeImportRelations deletes the created resources of the relations after the row failed with the err.
+// It returns the err joined with the errors of the deletes.
+func removeImportRelations(err error, relations []*importRelation) error {
+	errs := []error{err}
+	for _, relation := range relations {
+		for _, id := range relation.created {
+			if removeErr := relation.remove(id); removeErr != nil {
+				errs = append(errs, fmt.Errorf("failed to delete the created %s %d: %w", relation.kind, id, removeErr))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// importDryRunResult returns the preview result of the row that would be imported.
+func importDryRunResult(kind, name string, notes []string) string {
+	if len(notes) == 0 {
+		return fmt.Sprintf("%s %s will be created", kind, name)
+	}
+	return fmt.Sprintf("%s %s will be created with %s", kind, name, strings.Join(notes, ", "))
+}
+
+// importNotExists returns an error if the named resource of the kind exists, eg. it is found by the get function.
+func importNotExists(kind, name string, get func() error) error {
+	err := get()
+	if err == nil {
+		return fmt.Errorf("%s %s already exists", kind, name)
+	}
+	if errors.Is(err, model.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// importServerRow imports the mapped row as a server.
+// The missing runtimes and pools are created.
+func importServerRow(c *Controller, rowData map[string]string, listSeparator string, dryRun bool) (string, error) {
+	name := rowData["name"]
+	if name == "" {
+		return "", errors.New("the name is required")
+	}
+	serverRepository := c.repositoryContainer.GetServerRepository()
+	if err := importNotExists("server", name, func() error {
+		_, err := serverRepository.GetServerByName(name)
+		return err
+	}); err != nil {
+		return "", err
+	}
+	runtimeRepository := c.repositoryContainer.GetRuntimeRepository()
+	runtimes, err := resolveImportRelation("runtime", parser.SplitList(rowData["runtimes"], listSeparator), func(name string) (int64, error) {
+		runtime, err := runtimeRepository.GetRuntimeByName(name)
+		if err != nil {
+			return 0, err
+		}
+		return runtime.ID, nil
+	}, func(name string) (int64, error) {
+		runtime, err := runtimeRepository.CreateRuntime(name, 0)
+		if err != nil {
+			return 0, err
+		}
+		return runtime.ID, nil
+	}, runtimeRepository.DeleteRuntime)
+	if err != nil {
+		return "", err
+	}
+	poolRepository := c.repositoryContainer.GetPoolRepository()
+	pools, err := resolveImportRelation("pool", parser.SplitList(rowData["pools"], listSeparator), func(name string) (int64, error) {
+		pool, err := poolRepository.GetPoolByName(name)
+		if err != nil {
+			return 0, err
+		}
+		return pool.ID, nil
+	}, func(name string) (int64, error) {
+		pool, err := poolRepository.CreatePool(name)
+		if err != nil {
+			return 0, err
+		}
+		return pool.ID, nil
+	}, poolRepository.DeletePool)
+	if err != nil {
+		return "", err
+	}
+	if dryRun {
+		return importDryRunResult("server", name, importRelationNotes(runtimes, pools)), nil
+	}
+	var server *model.Server
+	if err := importWithRelations(func() error {
+		server, err = serverRepository.CreateServer(name, rowData["description"], rowData["remote_address"], runtimes.ids, pools.ids)
+		return err
+	}, runtimes, pools); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("server %d is imported", server.ID), nil
+}
+
+// importEnvironmentRow imports the mapped row as an environment.
+// The servers have to exist, the missing databases are created. The empty score is 0.
+func importEnvironmentRow(c *Controller, rowData map[string]string, listSeparator string, dryRun bool) (string, error) {
+	name := rowData["name"]
+	if name == "" {
+		return "", errors.New("the name is required")
+	}
+	score := 0
+	if rowData["score"] != "" {
+		var err error
+		if score, err = strconv.Atoi(rowData["score"]); err != nil {
+			return "", fmt.Errorf("invalid score %q", rowData["score"])
+		}
+	}
+	environmentRepository := c.repositoryContainer.GetEnvironmentRepository()
+	if err := importNotExists("environment", name, func() error {
+		_, err := environmentRepository.GetEnvironmentByName(name)
+		return err
+	}); err != nil {
+		return "", err
+	}
+	serverRepository := c.repositoryContainer.GetServerRepository()
+	servers, err := resolveImportRelation("server", parser.SplitList(rowData["servers"], listSeparator), func(name string) (int64, error) {
+		server, err := serverRepository.GetServerByName(name)
+		if err != nil {
+			return 0, err
+		}
+		return server.ID, nil
+	}, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	databaseRepository := c.repositoryContainer.GetDatabaseRepository()
+	databases, err := resolveImportRelation("database", parser.SplitList(rowData["databases"], listSeparator), func(name string) (int64, error) {
+		database, err := databaseRepository.GetDatabaseByName(name)
+		if err != nil {
+			return 0, err
+		}
+		return database.ID, nil
+	}, func(name string) (int64, error) {
+		database, err := databaseRepository.CreateDatabase(name)
+		if err != nil {
+			return 0, err
+		}
+		return database.ID, nil
+	}, databaseRepository.DeleteDatabase)
+	if err != nil {
+		return "", err
+	}
+	if dryRun {
+		return importDryRunResult("environment", name, importRelationNotes(databases)), nil
+	}
+	var environment *model.Environment
+	if err := importWithRelations(func() error {
+		environment, err = environmentRepository.CreateEnvironment(name, rowData["description"], servers.ids, databases.ids, score)
+		return err
+	}, databases); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("environment %d is imported", environment.ID), nil
+}
+
+// importDomainRow imports the mapped row as a domain with its registration data.
+// The dates have to be in the model.DomainDateFormat format, the missing billing client is created.
+func importDomainRow(c *Controller, rowData map[string]string, listSeparator string, dryRun bool) (string, error) {
+	name := strings.ToLower(rowData["name"])
+	if name == "" {
+		return "", errors.New("the name is required")
+	}
+	for _, field := range []string{"registered_at", "expires_at"} {
+		if rowData[field] == "" {
+			continue
+		}
+		if _, err := time.Parse(model.DomainDateFormat, rowData[field]); err != nil {
+			return "", fmt.Errorf("invalid %s %q, the format is %s", field, rowData[field], model.DomainDateFormat)
+		}
+	}
+	autoRenew, err := importBool(rowData["auto_renew"])
+	if err != nil {
+		return "", fmt.Errorf("invalid auto_renew %q", rowData["auto_renew"])
+	}
+	domainRepository := c.repositoryContainer.GetDomainRepository()
+	if err := importNotExists("domain", name, func() error {
+		_, err := domainRepository.GetDomainByName(name)
+		return err
+	}); err != nil {
+		return "", err
+	}
+	clientRepository := c.repositoryContainer.GetClientRepository()
+	clientNames := []string{}
+	if rowData["billing_client"] != "" {
+		clientNames = append(clientNames, rowData["billing_client"])
+	}
+	clients, err := resolveImportRelation("client", clientNames, func(name string) (int64, error) {
+		client, err := clientRepository.GetClientByName(name)
+		if err != nil {
+			return 0, err
+		}
+		return client.ID, nil
+	}, func(name string) (int64, error) {
+		client, err := clientRepository.CreateClient(name)
+		if err != nil {
+			return 0, err
+		}
+		return client.ID, nil
+	}, clientRepository.DeleteClient)
+	if err != nil {
+		return "", err
+	}
+	if dryRun {
+		return importDryRunResult("domain", name, importRelationNotes(clients)), nil
+	}
+	domain := &model.Domain{
+		Name:         name,
+		Registrar:    rowData["registrar"],
+		RegisteredAt: rowData["registered_at"],
+		ExpiresAt:    rowData["expires_at"],
+		AutoRenew:    autoRenew,
+	}
+	if err := importWithRelations(func() error {
+		if len(clients.ids) > 0 {
+			domain.BillingClient = &model.Client{ID: clients.ids[0]}
+		}
+		domain, err = domainRepository.CreateDomainWithRegistration(domain)
+		return err
+	}, clients); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("domain %d is imported", domain.ID), nil
+}
+
+// importUserRow imports the mapped row as a user.
+// The role is identified by its name and it has to exist. The empty name is the email,
+// the empty password is a random one, that could be changed with the user reset-password command.
+func importUserRow(c *Controller, rowData map[string]string, listSeparator string, dryRun bool) (string, error) {
+	email := rowData["email"]
+	if email == "" {
+		return "", errors.New("the email is required")
+	}
+	if rowData["role"] == "" {
+		return "", errors.New("the role is required")
+	}
+	name := rowData["name"]
+	if name == "" {
+		name = email
+	}
+	userRepository := c.repositoryContainer.GetUserRepository()
+	if err := importNotExists("user", email, func() error {
+		_, err := userRepository.GetUserByEmail(email)
+		return err
+	}); err != nil {
+		return "", err
+	}
+	role, err := c.repositoryContainer.GetRoleRepository().GetRoleByName(rowData["role"])
+	if errors.Is(err, model.ErrNotFound) {
+		return "", fmt.Errorf("role %s is not found", rowData["role"])
+	}
+	if err != nil {
+		return "", err
+	}
+	password := rowData["password"]
+	if dryRun {
+		notes := []string{"role " + role.Name}
+		if password == "" {
+			notes = append(notes, "random password")
+		}
+		return importDryRunResult("user", email, notes), nil
+	}
+	if password == "" {
+		if password, err = randomImportPassword(); err != nil {
+			return "", err
+		}
+	}
+	hashedPassword, err := passwd.HashPassword(password)
+	if err != nil {
+		return "", err
+	}
+	user, err := userRepository.CreateUser(name, email, hashedPassword, role.ID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("user %d is imported", user.ID), nil
+}
+
+// importBool parses the boolean fields of the import. The empty value is false.
+func importBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "", "0", "no", "false":
+		return false, nil
+	case "1", "yes", "true":
+		return true, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", value)
+}
+
+// randomImportPassword generates a crypto random password for the imported users without password.
+func randomImportPassword() (string, error) {
+	b := make([]byte, importPasswordBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
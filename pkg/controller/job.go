@@ -0,0 +1,313 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/akosgarai/projectregister/pkg/controller/response"
+	"github.com/akosgarai/projectregister/pkg/domaincheck"
+	"github.com/akosgarai/projectregister/pkg/jobqueue"
+	"github.com/akosgarai/projectregister/pkg/model"
+	"github.com/akosgarai/projectregister/pkg/parser"
+)
+
+const (
+	// jobListLimit is the number of the displayed jobs.
+	jobListLimit = 100
+)
+
+// applicationImportPayload is the payload of the application import jobs.
+// The first row of the file is skipped if it is the header.
+type applicationImportPayload struct {
+	EnvironmentID   int64                           `json:"environment_id"`
+	FileID          string                          `json:"file_id"`
+	Mapping         parser.ApplicationImportMapping `json:"mapping"`
+	HasHeader       bool                            `json:"has_header"`
+	Options         parser.ImportOptions            `json:"options"`
+	DomainSeparator string                          `json:"domain_separator"`
+}
+
+// resourceImportPayload is the payload of the generic resource import jobs.
+// The resource is the url name of the imported resource, eg. server. The list fields are split with the list separator.
+type resourceImportPayload struct {
+	Resource      string               `json:"resource"`
+	FileID        string               `json:"file_id"`
+	Mapping       parser.ImportMapping `json:"mapping"`
+	HasHeader     bool                 `json:"has_header"`
+	Options       parser.ImportOptions `json:"options"`
+	ListSeparator string               `json:"list_separator"`
+}
+
+// RegisterJobHandlers registers the handlers of the background jobs in the job queue.
+func (c *Controller) RegisterJobHandlers() {
+	c.jobQueue.Register(model.JobTypeApplicationImport, c.applicationImportJob)
+	c.jobQueue.Register(model.JobTypeDomainCheck, c.domainCheckJob)
+	c.jobQueue.Register(model.JobTypeResourceImport, c.resourceImportJob)
+}
+
+// enqueueJob queues a job of the current user and redirects to the job view page.
+func (c *Controller) enqueueJob(w http.ResponseWriter, r *http.Request, jobType string, payload interface{}, errorMessage string) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	job, err := c.jobQueue.Enqueue(jobType, payload, currentUser.ID)
+	if err != nil {
+		c.renderRepositoryError(w, errorMessage, err)
+		return
+	}
+	http.Redirect(w, r, fmt.Sprintf("/admin/job/view/%d", job.ID), http.StatusSeeOther)
+}
+
+// JobViewController is the controller for the job view page.
+// GET /admin/job/view/{jobId}
+// It renders the job view page with the progress and the log.
+func (c *Controller) JobViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("jobs.view") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	job, statusCode, err := c.jobViewData(r)
+	if err != nil {
+		c.renderer.Error(w, statusCode, JobFailedToGetJobErrorMessage, err)
+		return
+	}
+	content := response.NewJobDetailResponse(currentUser, job)
+	err = c.renderer.Template.RenderTemplate(w, "detail-page.html", content)
+	if err != nil {
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+	}
+}
+
+// jobViewData gets the request as input, and returns the job data, status code and error.
+func (c *Controller) jobViewData(r *http.Request) (*model.Job, int, error) {
+	vars := mux.Vars(r)
+	jobIDVariable := vars["jobId"]
+	// it has to be converted to int64
+	jobID, err := strconv.ParseInt(jobIDVariable, 10, 64)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	job, err := c.repositoryContainer.GetJobRepository().GetJobByID(jobID)
+	if err != nil {
+		return nil, repositoryErrorStatus(err), err
+	}
+	return job, http.StatusOK, nil
+}
+
+// JobListViewController is the controller for the job list view.
+// It lists the latest jobs, they could be filtered by the status.
+func (c *Controller) JobListViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("jobs.view") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	// Define the empty filter here.
+	filter := model.NewJobFilter()
+	filter.Limit = jobListLimit
+	if r.Method == http.MethodPost && r.FormValue("status") != "" {
+		// On case of post request, the filter is not empty.
+		// The status is the 1-based index of the model.JobStatuses.
+		statusIndex, err := strconv.Atoi(r.FormValue("status"))
+		if err != nil || statusIndex < 1 || statusIndex > len(model.JobStatuses) {
+			c.renderer.Error(w, http.StatusBadRequest, JobStatusInvalidErrorMessage, err)
+			return
+		}
+		filter.Status = model.JobStatuses[statusIndex-1]
+	}
+	jobs, err := c.repositoryContainer.GetJobRepository().GetJobs(filter)
+	if err != nil {
+		c.renderRepositoryError(w, JobListFailedToGetJobsErrorMessage, err)
+		return
+	}
+	content := response.NewJobListResponse(currentUser, jobs, filter)
+	err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
+	if err != nil {
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+	}
+}
+
+// JobRetryViewController is the controller for the job retry form.
+// It queues the failed or cancelled job again, and redirects to the job view page.
+func (c *Controller) JobRetryViewController(w http.ResponseWriter, r *http.Request) {
+	c.jobAction(w, r, (*model.Job).CanRetry, c.repositoryContainer.GetJobRepository().RetryJob, JobRetryFailedErrorMessage)
+}
+
+// JobCancelViewController is the controller for the job cancel form.
+// The queued job is not started, the running job is stopped by its worker on the next progress update.
+// It redirects to the job view page.
+func (c *Controller) JobCancelViewController(w http.ResponseWriter, r *http.Request) {
+	c.jobAction(w, r, (*model.Job).CanCancel, c.repositoryContainer.GetJobRepository().CancelJob, JobCancelFailedErrorMessage)
+}
+
+// jobAction applies the status change on the job of the url, and redirects to the job view page.
+// The action is rejected with conflict if it is not allowed in the current status of the job.
+func (c *Controller) jobAction(w http.ResponseWriter, r *http.Request, allowed func(*model.Job) bool, action func(id int64) (*model.Job, error), errorMessage string) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("jobs.update") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	job, statusCode, err := c.jobViewData(r)
+	if err != nil {
+		c.renderer.Error(w, statusCode, JobFailedToGetJobErrorMessage, err)
+		return
+	}
+	if !allowed(job) {
+		c.renderer.Error(w, http.StatusConflict, errorMessage+". "+JobStatusConflictErrorMessage, nil)
+		return
+	}
+	if _, err := action(job.ID); err != nil {
+		c.renderRepositoryError(w, errorMessage, err)
+		return
+	}
+	http.Redirect(w, r, fmt.Sprintf("/admin/job/view/%d", job.ID), http.StatusSeeOther)
+}
+
+// applicationImportJob imports the rows of the uploaded csv, xlsx or json file to the environment.
+func (c *Controller) applicationImportJob(ctx context.Context, job *model.Job, progress *jobqueue.Progress) error {
+	var payload applicationImportPayload
+	if err := jobqueue.DecodePayload(job, &payload); err != nil {
+		return err
+	}
+	return c.importRowsJob(ctx, job, progress, payload.FileID, payload.Options, payload.HasHeader, parser.ImportMapping(payload.Mapping), func(rowData map[string]string) (string, error) {
+		app, err := c.importApplicationRow(payload.EnvironmentID, rowData, payload.DomainSeparator)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("application %d is imported", app.ID), nil
+	})
+}
+
+// resourceImportJob imports the rows of the uploaded file as the resources of the payload, eg. servers or users.
+func (c *Controller) resourceImportJob(ctx context.Context, job *model.Job, progress *jobqueue.Progress) error {
+	var payload resourceImportPayload
+	if err := jobqueue.DecodePayload(job, &payload); err != nil {
+		return err
+	}
+	resource, ok := importResources[payload.Resource]
+	if !ok {
+		return fmt.Errorf("unknown import resource %q", payload.Resource)
+	}
+	return c.importRowsJob(ctx, job, progress, payload.FileID, payload.Options, payload.HasHeader, payload.Mapping, func(rowData map[string]string) (string, error) {
+		return resource.importRow(c, rowData, payload.ListSeparator, false)
+	})
+}
+
+// importRowsJob maps the rows of the uploaded file of the job creator, and imports them with the importRow function.
+// The failed rows, eg. the rows without the mapped columns are logged, they do not fail the job.
+// The rows are numbered as the lines of the csv file or the rows of the sheet. The file is deleted after the import.
+// The retried job skips the rows of the stored progress, as the imported rows would be created again.
+func (c *Controller) importRowsJob(ctx context.Context, job *model.Job, progress *jobqueue.Progress, fileID string, options parser.ImportOptions, hasHeader bool, mapping parser.ImportMapping, importRow func(rowData map[string]string) (string, error)) error {
+	// the file is owned by the user who started the import.
+	data, err := c.csvStorage.Read(ctx, job.CreatedBy, fileID, options)
+	if err != nil {
+		return err
+	}
+	_, rows, firstRow := splitImportHeader(data, hasHeader)
+	if err := progress.SetTotal(len(rows)); err != nil {
+		return err
+	}
+	// the retried or reclaimed job resumes after the stored progress, the progress is stored after every row.
+	imported := 0
+	resumed := min(progress.Done(), len(rows))
+	for rowIndex := resumed; rowIndex < len(rows); rowIndex++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		mappedRow := mapImportRow(mapping, rows[rowIndex])
+		if mappedRow.ErrorMessage != "" {
+			progress.Logf("row %d: %s", firstRow+rowIndex, mappedRow.ErrorMessage)
+		} else if result, err := importRow(mappedRow.RowData); err != nil {
+			progress.Logf("row %d: %s", firstRow+rowIndex, err.Error())
+		} else {
+			imported++
+			progress.Logf("row %d: %s", firstRow+rowIndex, result)
+		}
+		if err := progress.Advance(); err != nil {
+			return err
+		}
+		if err := progress.Save(); err != nil {
+			return err
+		}
+	}
+	progress.Logf("%d of %d rows are imported", imported, len(rows)-resumed)
+	if err := c.csvStorage.Delete(ctx, job.CreatedBy, fileID); err != nil {
+		progress.Logf("failed to delete the uploaded file: %s", err.Error())
+	}
+	return nil
+}
+
+// domainCheckJob checks the ssl status and audits the security of the domains.
+// The failed domains are logged, and the job fails if any of them failed.
+func (c *Controller) domainCheckJob(ctx context.Context, job *model.Job, progress *jobqueue.Progress) error {
+	var payload model.DomainCheckPayload
+	if err := jobqueue.DecodePayload(job, &payload); err != nil {
+		return err
+	}
+	if err := progress.SetTotal(len(payload.DomainIDs)); err != nil {
+		return err
+	}
+	// the retried or reclaimed job resumes after the checked domains.
+	failed := 0
+	resumed := min(progress.Done(), len(payload.DomainIDs))
+	for _, domainID := range payload.DomainIDs[resumed:] {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		domain, err := c.checkDomain(domainID, payload.SSL, payload.Security)
+		if err != nil {
+			failed++
+			progress.Logf("domain %d: %s", domainID, err.Error())
+		} else {
+			progress.Logf("%s: ssl %t, security grade %s", domain.Name, domain.HasSSL, domain.SecurityGrade)
+		}
+		if err := progress.Advance(); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d domain checks failed", failed, len(payload.DomainIDs)-resumed)
+	}
+	return nil
+}
+
+// checkDomain runs the enabled checks on the domain and stores the results.
+func (c *Controller) checkDomain(domainID int64, ssl, security bool) (*model.Domain, error) {
+	domain, err := c.repositoryContainer.GetDomainRepository().GetDomainByID(domainID)
+	if err != nil {
+		return nil, err
+	}
+	if ssl {
+		domain.HasSSL = domaincheck.HasSSL(domain)
+		domain.LiveCertificateFingerprint = domaincheck.LiveCertificateFingerprint(domain)
+		domain.SSLCheckedAt = time.Now().Format(model.DomainCheckTimeFormat)
+	}
+	if security {
+		domaincheck.AuditSecurity(domain).Apply(domain)
+	}
+	if err := c.repositoryContainer.GetDomainRepository().UpdateDomain(domain); err != nil {
+		return nil, err
+	}
+	return domain, nil
+}
@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// MetricsController is the controller of the prometheus metrics.
+// GET /metrics
+// It returns the metrics in the prometheus text format.
+// The scrapers can not log in, so that the endpoint is public, unless the bearer token is configured.
+func (c *Controller) MetricsController(w http.ResponseWriter, r *http.Request) {
+	if !c.hasMetricsAccess(r) {
+		c.renderer.Error(w, http.StatusUnauthorized, MetricsUnauthorizedErrorMessage, nil)
+		return
+	}
+	c.metricsRegistry.ServeHTTP(w, r)
+}
+
+// hasMetricsAccess checks the bearer token of the prometheus requests.
+// Every request is allowed if the metrics token is not configured.
+func (c *Controller) hasMetricsAccess(r *http.Request) bool {
+	if c.metricsToken == "" {
+		return true
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(c.metricsToken)) == 1
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/akosgarai/projectregister/pkg/controller/response"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// NotificationSettingsViewController is the controller for the notification settings of the current user.
+// On case of get request, it returns the notification settings page.
+// On case of post request, it replaces the subscriptions of the user and redirects to the settings page.
+// Every user could manage the own settings, so that it does not require privilege.
+func (c *Controller) NotificationSettingsViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	subscriptionRepository := c.repositoryContainer.GetNotificationSubscriptionRepository()
+	if r.Method == http.MethodGet {
+		filter := model.NewNotificationSubscriptionFilter()
+		filter.UserIDs = []string{strconv.FormatInt(currentUser.ID, 10)}
+		subscriptions, err := subscriptionRepository.GetNotificationSubscriptions(filter)
+		if err != nil {
+			c.renderRepositoryError(w, NotificationSettingsFailedToGetSubscriptionsErrorMessage, err)
+			return
+		}
+		content := response.NewNotificationSettingsResponse(currentUser, *subscriptions)
+		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
+		if err != nil {
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+		}
+	}
+
+	if r.Method == http.MethodPost {
+		r.ParseForm()
+		subscriptions := model.NotificationSubscriptions{}
+		// the subscription checkboxes are sent with the 1-based rule indexes.
+		for index, rule := range model.NotificationRules {
+			if r.FormValue("rule_"+rule.Name) != strconv.Itoa(index+1) {
+				continue
+			}
+			subscription := &model.NotificationSubscription{
+				UserID: currentUser.ID,
+				Rule:   rule.Name,
+				Digest: r.FormValue("delivery_"+rule.Name) == strconv.Itoa(response.NotificationDeliveryDigest),
+			}
+			if rule.HasThreshold() {
+				threshold, err := strconv.Atoi(r.FormValue("threshold_" + rule.Name))
+				if err != nil || threshold <= 0 {
+					c.renderer.Error(w, http.StatusBadRequest, NotificationSettingsThresholdInvalidErrorMessage, err)
+					return
+				}
+				subscription.Threshold = threshold
+			}
+			subscriptions = append(subscriptions, subscription)
+		}
+		err := subscriptionRepository.SaveNotificationSubscriptions(currentUser.ID, subscriptions)
+		if err != nil {
+			c.renderRepositoryError(w, NotificationSettingsFailedToSaveSubscriptionsErrorMessage, err)
+			return
+		}
+		http.Redirect(w, r, "/admin/notification/settings", http.StatusSeeOther)
+		return
+	}
+}
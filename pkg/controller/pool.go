@@ -14,7 +14,11 @@ import (
 // GET /admin/pool/view/{poolId}
 // It renders the pool view page.
 func (c *Controller) PoolViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("pools.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -27,7 +31,7 @@ func (c *Controller) PoolViewController(w http.ResponseWriter, r *http.Request)
 	content := response.NewPoolDetailResponse(currentUser, pool)
 	err = c.renderer.Template.RenderTemplate(w, "detail-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
 
@@ -42,7 +46,7 @@ func (c *Controller) poolViewData(r *http.Request) (*model.Pool, int, error) {
 	}
 	pool, err := c.repositoryContainer.GetPoolRepository().GetPoolByID(poolID)
 	if err != nil {
-		return nil, http.StatusInternalServerError, err
+		return nil, repositoryErrorStatus(err), err
 	}
 	return pool, http.StatusOK, nil
 }
@@ -51,7 +55,11 @@ func (c *Controller) poolViewData(r *http.Request) (*model.Pool, int, error) {
 // On case of get request, it returns the pool create page.
 // On case of post request, it creates the pool and redirects to the list page.
 func (c *Controller) PoolCreateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("pools.create") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -60,7 +68,7 @@ func (c *Controller) PoolCreateViewController(w http.ResponseWriter, r *http.Req
 		content := response.NewCreatePoolResponse(currentUser)
 		err := c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -76,7 +84,7 @@ func (c *Controller) PoolCreateViewController(w http.ResponseWriter, r *http.Req
 
 		_, err := c.repositoryContainer.GetPoolRepository().CreatePool(name)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, PoolCreateCreatePoolErrorMessage, err)
+			c.renderRepositoryError(w, PoolCreateCreatePoolErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/pool/list", http.StatusSeeOther)
@@ -88,7 +96,11 @@ func (c *Controller) PoolCreateViewController(w http.ResponseWriter, r *http.Req
 // On case of get request, it returns the pool update page.
 // On case of post request, it updates the pool and redirects to the list page.
 func (c *Controller) PoolUpdateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("pools.update") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -105,7 +117,7 @@ func (c *Controller) PoolUpdateViewController(w http.ResponseWriter, r *http.Req
 	// get the pool
 	pool, err := c.repositoryContainer.GetPoolRepository().GetPoolByID(poolID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, PoolFailedToGetPoolErrorMessage, err)
+		c.renderRepositoryError(w, PoolFailedToGetPoolErrorMessage, err)
 		return
 	}
 
@@ -113,7 +125,7 @@ func (c *Controller) PoolUpdateViewController(w http.ResponseWriter, r *http.Req
 		content := response.NewUpdatePoolResponse(currentUser, pool)
 		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -131,7 +143,7 @@ func (c *Controller) PoolUpdateViewController(w http.ResponseWriter, r *http.Req
 		pool.Name = name
 		err = c.repositoryContainer.GetPoolRepository().UpdatePool(pool)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, PoolUpdateUpdatePoolErrorMessage, err)
+			c.renderRepositoryError(w, PoolUpdateUpdatePoolErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/pool/list", http.StatusSeeOther)
@@ -143,7 +155,11 @@ func (c *Controller) PoolUpdateViewController(w http.ResponseWriter, r *http.Req
 // It is responsible for deleting a pool.
 // It redirects to the pool list page.
 func (c *Controller) PoolDeleteViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("pools.delete") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -159,7 +175,7 @@ func (c *Controller) PoolDeleteViewController(w http.ResponseWriter, r *http.Req
 	// delete the pool
 	err = c.repositoryContainer.GetPoolRepository().DeletePool(poolID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, PoolDeleteFailedToDeleteErrorMessage, err)
+		c.renderRepositoryError(w, PoolDeleteFailedToDeleteErrorMessage, err)
 		return
 	}
 	// redirect to the pool list
@@ -168,7 +184,11 @@ func (c *Controller) PoolDeleteViewController(w http.ResponseWriter, r *http.Req
 
 // PoolListViewController is the controller for the pool list view.
 func (c *Controller) PoolListViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("pools.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -180,12 +200,12 @@ func (c *Controller) PoolListViewController(w http.ResponseWriter, r *http.Reque
 	// get all pools
 	pools, err := c.repositoryContainer.GetPoolRepository().GetPools(filter)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, PoolListFailedToGetPoolsErrorMessage, err)
+		c.renderRepositoryError(w, PoolListFailedToGetPoolsErrorMessage, err)
 		return
 	}
 	content := response.NewPoolListResponse(currentUser, pools, filter)
 	err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
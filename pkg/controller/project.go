@@ -14,7 +14,11 @@ import (
 // GET /admin/project/view/{projectId}
 // It renders the project view page.
 func (c *Controller) ProjectViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("projects.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -27,7 +31,7 @@ func (c *Controller) ProjectViewController(w http.ResponseWriter, r *http.Reques
 	content := response.NewProjectDetailResponse(currentUser, project)
 	err = c.renderer.Template.RenderTemplate(w, "detail-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
 
@@ -42,7 +46,7 @@ func (c *Controller) projectViewData(r *http.Request) (*model.Project, int, erro
 	}
 	project, err := c.repositoryContainer.GetProjectRepository().GetProjectByID(projectID)
 	if err != nil {
-		return nil, http.StatusInternalServerError, err
+		return nil, repositoryErrorStatus(err), err
 	}
 	return project, http.StatusOK, nil
 }
@@ -51,7 +55,11 @@ func (c *Controller) projectViewData(r *http.Request) (*model.Project, int, erro
 // On case of get request, it returns the project create page.
 // On case of post request, it creates the project and redirects to the list page.
 func (c *Controller) ProjectCreateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("projects.create") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -60,7 +68,7 @@ func (c *Controller) ProjectCreateViewController(w http.ResponseWriter, r *http.
 		content := response.NewCreateProjectResponse(currentUser)
 		err := c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -76,7 +84,7 @@ func (c *Controller) ProjectCreateViewController(w http.ResponseWriter, r *http.
 
 		_, err := c.repositoryContainer.GetProjectRepository().CreateProject(name)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, ProjectCreateCreateProjectErrorMessage, err)
+			c.renderRepositoryError(w, ProjectCreateCreateProjectErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/project/list", http.StatusSeeOther)
@@ -88,7 +96,11 @@ func (c *Controller) ProjectCreateViewController(w http.ResponseWriter, r *http.
 // On case of get request, it returns the project update page.
 // On case of post request, it updates the project and redirects to the list page.
 func (c *Controller) ProjectUpdateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("projects.update") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -105,7 +117,7 @@ func (c *Controller) ProjectUpdateViewController(w http.ResponseWriter, r *http.
 	// get the project
 	project, err := c.repositoryContainer.GetProjectRepository().GetProjectByID(projectID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ProjectFailedToGetProjectErrorMessage, err)
+		c.renderRepositoryError(w, ProjectFailedToGetProjectErrorMessage, err)
 		return
 	}
 
@@ -113,7 +125,7 @@ func (c *Controller) ProjectUpdateViewController(w http.ResponseWriter, r *http.
 		content := response.NewUpdateProjectResponse(currentUser, project)
 		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -131,7 +143,7 @@ func (c *Controller) ProjectUpdateViewController(w http.ResponseWriter, r *http.
 		project.Name = name
 		err = c.repositoryContainer.GetProjectRepository().UpdateProject(project)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, ProjectUpdateUpdateProjectErrorMessage, err)
+			c.renderRepositoryError(w, ProjectUpdateUpdateProjectErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/project/list", http.StatusSeeOther)
@@ -143,7 +155,11 @@ func (c *Controller) ProjectUpdateViewController(w http.ResponseWriter, r *http.
 // It is responsible for deleting a project.
 // It redirects to the project list page.
 func (c *Controller) ProjectDeleteViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("projects.delete") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -159,7 +175,7 @@ func (c *Controller) ProjectDeleteViewController(w http.ResponseWriter, r *http.
 	// delete the project
 	err = c.repositoryContainer.GetProjectRepository().DeleteProject(projectID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ProjectDeleteFailedToDeleteErrorMessage, err)
+		c.renderRepositoryError(w, ProjectDeleteFailedToDeleteErrorMessage, err)
 		return
 	}
 	// redirect to the project list
@@ -168,7 +184,11 @@ func (c *Controller) ProjectDeleteViewController(w http.ResponseWriter, r *http.
 
 // ProjectListViewController is the controller for the project list view.
 func (c *Controller) ProjectListViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("projects.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -184,12 +204,12 @@ func (c *Controller) ProjectListViewController(w http.ResponseWriter, r *http.Re
 	// get all projects
 	projects, err := c.repositoryContainer.GetProjectRepository().GetProjects(filter)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ProjectListFailedToGetProjectsErrorMessage, err)
+		c.renderRepositoryError(w, ProjectListFailedToGetProjectsErrorMessage, err)
 		return
 	}
 	content := response.NewProjectListResponse(currentUser, projects, filter)
 	err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
@@ -14,6 +14,12 @@ import (
 func NewApplicationDetailResponse(currentUser *model.User, app *model.Application) *DetailResponse {
 	headerText := "Application Detail"
 	headerContent := components.NewContentHeader(headerText, newDetailHeaderButtons(currentUser, "applications", fmt.Sprintf("%d", app.ID)))
+	if currentUser.HasPrivilege("applications.update") {
+		headerContent.Buttons = append([]*components.Link{components.NewLink("Domain Roles", fmt.Sprintf("/admin/application/domain-roles/%d", app.ID))}, headerContent.Buttons...)
+	}
+	if currentUser.HasPrivilege("domains.update") && app.PrimaryDomain() != nil {
+		headerContent.Buttons = append([]*components.Link{components.NewLink("Check", fmt.Sprintf("/admin/application/check/%d", app.ID))}, headerContent.Buttons...)
+	}
 	dbValues := &components.DetailValues{
 		{Value: app.Database.Name, Link: fmt.Sprintf("/admin/database/view/%d", app.Database.ID)},
 	}
@@ -31,7 +37,7 @@ func NewApplicationDetailResponse(currentUser *model.User, app *model.Applicatio
 	domainValues := &components.DetailValues{}
 	if app.Domains != nil {
 		for _, domain := range app.Domains {
-			*domainValues = append(*domainValues, &components.DetailValue{Value: domain.Name, Link: fmt.Sprintf("/admin/domain/view/%d", domain.ID)})
+			*domainValues = append(*domainValues, &components.DetailValue{Value: fmt.Sprintf("%s (%s)", domain.Name, app.DomainRole(domain.ID)), Link: fmt.Sprintf("/admin/domain/view/%d", domain.ID)})
 		}
 	}
 	details := &components.DetailItems{
@@ -100,7 +106,7 @@ func newApplicationFormResponse(
 ) *FormResponse {
 	headerContent := components.NewContentHeader(title, []*components.Link{components.NewLink("List", "/admin/application/list")})
 
-	var selectedClients, selectedProjects, selectedEnvironments, selectedDatabases, selectedRuntimes, selectedPools, selectedFrameworks, selectedDomains []int64
+	var selectedClients, selectedProjects, selectedEnvironments, selectedDatabases, selectedRuntimes, selectedPools, selectedFrameworks, selectedDomains, selectedPrimaryDomains []int64
 
 	if app.Client != nil {
 		selectedClients = []int64{app.Client.ID}
@@ -128,6 +134,9 @@ func newApplicationFormResponse(
 			selectedDomains = append(selectedDomains, domain.ID)
 		}
 	}
+	if primaryDomain := app.PrimaryDomain(); primaryDomain != nil {
+		selectedPrimaryDomains = []int64{primaryDomain.ID}
+	}
 	// add the application domains to the domains list.
 	for _, domain := range app.Domains {
 		*domains = append(*domains, domain)
@@ -159,6 +168,8 @@ func newApplicationFormResponse(
 		components.NewFormItem("Document Root", "document_root", "text", app.DocumentRoot, false, nil, nil),
 		// Domains.
 		components.NewFormItem("Domains", "domains", "checkboxgroup", "", false, domains.ToMap(), selectedDomains),
+		// Primary domain. It has to be one of the selected domains.
+		components.NewFormItem("Primary Domain", "primary_domain", "select", "", false, domains.ToMap(), selectedPrimaryDomains),
 	}
 	form := &components.Form{
 		Method: method,
@@ -281,16 +292,23 @@ func NewApplicationListResponse(
 			scoreColumn := &components.ListingColumn{Values: &components.ListingColumnValues{
 				{Value: fmt.Sprintf("Environment Score: %d", application.Environment.Score)},
 				{Value: fmt.Sprintf("Framework Score: %d", application.Framework.Score)},
-				{Value: fmt.Sprintf("Runtime Score: %d", application.Runtime.Score)},
-				{Value: fmt.Sprintf("Min Score: %d", int(scoreValue))}},
+				{Value: fmt.Sprintf("Runtime Score: %d", application.Runtime.Score)}},
 			}
+			// The security score of the domains is only counted after the audit.
+			if securityScore, audited := application.SecurityScore(); audited {
+				scoreValue = math.Min(scoreValue, float64(securityScore))
+				*scoreColumn.Values = append(*scoreColumn.Values, &components.ListingColumnValue{Value: fmt.Sprintf("Security Score: %d (%s)", securityScore, application.SecurityGrade())})
+			}
+			*scoreColumn.Values = append(*scoreColumn.Values, &components.ListingColumnValue{Value: fmt.Sprintf("Min Score: %d", int(scoreValue))})
 			columns = append(columns, scoreColumn)
 		}
 		if filter.IsVisibleColumn("Domains") {
+			// only the primary domain is displayed, the other domains are counted.
 			domainsColumn := &components.ListingColumn{Values: &components.ListingColumnValues{}}
-			if application.Domains != nil {
-				for _, domain := range application.Domains {
-					*domainsColumn.Values = append(*domainsColumn.Values, &components.ListingColumnValue{Value: domain.Name, Link: fmt.Sprintf("/admin/domain/view/%d", domain.ID)})
+			if primaryDomain := application.PrimaryDomain(); primaryDomain != nil {
+				*domainsColumn.Values = append(*domainsColumn.Values, &components.ListingColumnValue{Value: primaryDomain.Name, Link: fmt.Sprintf("/admin/domain/view/%d", primaryDomain.ID)})
+				if len(application.Domains) > 1 {
+					*domainsColumn.Values = append(*domainsColumn.Values, &components.ListingColumnValue{Value: fmt.Sprintf("+%d more", len(application.Domains)-1)})
 				}
 			}
 			columns = append(columns, domainsColumn)
@@ -346,19 +364,20 @@ func NewApplicationListResponse(
 	return NewListingResponse(headerText, currentUser, headerContent, &components.Listing{Header: listingHeader, Rows: &listingRows}, form)
 }
 
+// csvDelimiterLabels are the displayed names of the csv delimiters.
+var csvDelimiterLabels = map[string]string{
+	parser.DelimiterSemicolon: "Semicolon (;)",
+	parser.DelimiterComma:     "Comma (,)",
+	parser.DelimiterTab:       "Tab",
+}
+
 // NewApplicationImportToEnvironmentFormResponse is a constructor for the ApplicationImportToEnvironmentFormResponse struct.
+// The file could be a csv, an xlsx or a json file, the format is the extension of the file name.
 func NewApplicationImportToEnvironmentFormResponse(currentUser *model.User, env *model.Environment) *FormResponse {
 	headerText := "Import Application to Environment"
 	headerContent := components.NewContentHeader(headerText, []*components.Link{})
-	checkboxOptions := map[int64]string{
-		1: "Yes",
-	}
-	formItems := []*components.FormItem{
-		components.NewFormItem("CSV File", "csvfile", "file", "", true, nil, nil),
-		components.NewFormItem("Has Header", "has_header", "checkboxgroup", "true", false, checkboxOptions, nil),
-	}
 	form := &components.Form{
-		Items:     formItems,
+		Items:     importUploadFormItems(),
 		Action:    fmt.Sprintf("/admin/application/import-to-environment/%d", env.ID),
 		Method:    "POST",
 		Submit:    "Upload",
@@ -368,63 +387,19 @@ func NewApplicationImportToEnvironmentFormResponse(currentUser *model.User, env
 }
 
 // NewApplicationMappingToEnvironmentFormResponse is a constructor for the ApplicationMappingToEnvironmentFormResponse struct.
-func NewApplicationMappingToEnvironmentFormResponse(currentUser *model.User, env *model.Environment, fileID string, data [][]string, headers []string) *ApplicationImportMappingResponse {
+// The header flag and the import options of the upload are passed to the import in hidden inputs.
+func NewApplicationMappingToEnvironmentFormResponse(currentUser *model.User, env *model.Environment, fileID string, data [][]string, headers []string, hasHeader bool, options parser.ImportOptions) *ImportMappingResponse {
 	headerText := "Import Mapping to Environment"
 	headerContent := components.NewContentHeader(headerText, []*components.Link{})
-	// On case of the headers are not set, we need to set them.
-	// Indexes are starting from 1.
-	if len(headers) == 0 {
-		for i := range data[0] {
-			headers = append(headers, fmt.Sprintf("Column %d", i+1))
-		}
-	}
-	rows := components.ListingRows{}
 	// Add 5 rows to the listing as preview.
-	for i := 0; i < 5; i++ {
-		columns := components.ListingColumns{}
-		// if the data index is missing, we are done, no need to continue.
-		if len(data) <= i {
-			break
-		}
-		for _, dataItem := range data[i] {
-			columns = append(columns, &components.ListingColumn{Values: &components.ListingColumnValues{{Value: dataItem}}})
-		}
-		rows = append(rows, &components.ListingRow{Columns: &columns})
-	}
-
-	listing := &components.Listing{
-		Header: &components.ListingHeader{Headers: headers},
-		Rows:   &rows,
-	}
-	formMapping := parser.NewApplicationImportMapping()
+	listing, headers := importPreviewListing(data, headers)
 	formItems := []*components.FormItem{
 		components.NewFormItem("", "environment_id", "hidden", fmt.Sprintf("%d", env.ID), true, nil, nil),
 		components.NewFormItem("", "file_id", "hidden", fileID, true, nil, nil),
 	}
-	// Select the headers.
-	mappingOptions := map[int64]string{}
-	for i, header := range headers {
-		mappingOptions[int64(i)] = header
-	}
-	// Add the mapping rules to the form items.
-	formItemsInOrder := []string{"client", "project", "runtime", "pool", "domains", "framework", "database", "database_name", "database_user", "doc_root", "repository", "branch"}
-	for _, header := range formItemsInOrder {
-		// on case of we have header in the headers (case insensitive match), we set the column index.
-		// Then add 2 form items. One for the column index (select, options are the headers), and one for the custom value (text input).
-		// On case of the header is not in the headers, we keep the original value.
-		for i, headerItem := range headers {
-			if headerItem == header {
-				formMapping[header].ColumnIndex = i
-				break
-			}
-		}
-		if formMapping[header].ColumnIndex == -1 {
-			formItems = append(formItems, components.NewFormItem(header, header, "select", "", false, mappingOptions, nil))
-		} else {
-			formItems = append(formItems, components.NewFormItem(header, header, "select", "", false, mappingOptions, []int64{int64(formMapping[header].ColumnIndex)}))
-		}
-		formItems = append(formItems, components.NewFormItem(fmt.Sprintf("Custom %s name", header), fmt.Sprintf("%s_custom", header), "text", "", false, nil, nil))
-	}
+	formItems = append(formItems, importMappingFormItems(parser.ApplicationImportFields, headers)...)
+	formItems = append(formItems, components.NewFormItem("Domains separator (whitespace if empty)", "domains_separator", "text", "", false, nil, nil))
+	formItems = append(formItems, importOptionsFormItems(hasHeader, options)...)
 	form := &components.Form{
 		Items:     formItems,
 		Action:    fmt.Sprintf("/admin/application/mapping-to-environment/%d/%s", env.ID, fileID),
@@ -432,46 +407,43 @@ func NewApplicationMappingToEnvironmentFormResponse(currentUser *model.User, env
 		Submit:    "Upload",
 		Multipart: true,
 	}
-	return NewApplicationImportMappingResponse(headerText, currentUser, headerContent, listing, form)
+	return NewImportMappingResponse(headerText, currentUser, headerContent, listing, form)
 }
 
-// NewApplicationImportToEnvironmentListResponse is a constructor for the ApplicationImportToEnvironmentListResponse struct.
-func NewApplicationImportToEnvironmentListResponse(currentUser *model.User, env *model.Environment, fileID string, result *parser.ApplicationImportResult) *ListingResponse {
-	headerText := "Import Application to Environment"
-	headerContent := components.NewContentHeader(headerText, []*components.Link{})
-	listingHeader := &components.ListingHeader{
-		Headers: []string{"Client", "Project", "Database", "Runtime", "Pool", "Codebase", "Framework", "Document Root", "Domains", "Status"},
-	}
-	// create the rows
-	listingRows := components.ListingRows{}
-	for _, row := range *result {
-		columns := components.ListingColumns{}
-		columns = append(columns, &components.ListingColumn{Values: &components.ListingColumnValues{{Value: row.RowData["client"]}}})
-		columns = append(columns, &components.ListingColumn{Values: &components.ListingColumnValues{{Value: row.RowData["project"]}}})
-		databaseText := row.RowData["database"]
-		if row.RowData["db_name"] != "" {
-			databaseText = fmt.Sprintf("%s %s / %s", row.RowData["database"], row.RowData["db_user"], row.RowData["db_name"])
-		}
-		columns = append(columns, &components.ListingColumn{Values: &components.ListingColumnValues{{Value: databaseText}}})
-		columns = append(columns, &components.ListingColumn{Values: &components.ListingColumnValues{{Value: row.RowData["runtime"]}}})
-		columns = append(columns, &components.ListingColumn{Values: &components.ListingColumnValues{{Value: row.RowData["pool"]}}})
-		codebaseText := row.RowData["repository"]
-		if row.RowData["branch"] != "" {
-			codebaseText = fmt.Sprintf("%s (%s)", row.RowData["repository"], row.RowData["branch"])
+// NewApplicationDomainRolesFormResponse is a constructor for the FormResponse struct of the application domain roles.
+// Every domain has a role select, and the redirect target and status inputs that are used by the redirect role.
+// The role options are the 1 based indexes of the model.ApplicationDomainRoles.
+func NewApplicationDomainRolesFormResponse(currentUser *model.User, app *model.Application) *FormResponse {
+	headerText := "Application Domain Roles"
+	headerContent := components.NewContentHeader(headerText, []*components.Link{components.NewLink("View", fmt.Sprintf("/admin/application/view/%d", app.ID))})
+	roleOptions := map[int64]string{}
+	for index, role := range model.ApplicationDomainRoles {
+		roleOptions[int64(index+1)] = role
+	}
+	formItems := []*components.FormItem{}
+	for _, domain := range app.Domains {
+		role := app.DomainRole(domain.ID)
+		selectedRoles := []int64{}
+		for index, currentRole := range model.ApplicationDomainRoles {
+			if currentRole == role.Role {
+				selectedRoles = append(selectedRoles, int64(index+1))
+			}
 		}
-		columns = append(columns, &components.ListingColumn{Values: &components.ListingColumnValues{{Value: codebaseText}}})
-		columns = append(columns, &components.ListingColumn{Values: &components.ListingColumnValues{{Value: row.RowData["framework"]}}})
-		columns = append(columns, &components.ListingColumn{Values: &components.ListingColumnValues{{Value: row.RowData["doc_root"]}}})
-		columns = append(columns, &components.ListingColumn{Values: &components.ListingColumnValues{{Value: row.RowData["domains"]}}})
-		statusText := ""
-		if row.Application != nil {
-			statusText = "OK"
-		} else {
-			statusText = row.ErrorMessage
+		redirectStatus := ""
+		if role.RedirectStatus != 0 {
+			redirectStatus = fmt.Sprintf("%d", role.RedirectStatus)
 		}
-		columns = append(columns, &components.ListingColumn{Values: &components.ListingColumnValues{{Value: statusText}}})
-
-		listingRows = append(listingRows, &components.ListingRow{Columns: &columns})
+		formItems = append(formItems,
+			components.NewFormItem(domain.Name, fmt.Sprintf("role_%d", domain.ID), "select", "", true, roleOptions, selectedRoles),
+			components.NewFormItem("Redirect Target", fmt.Sprintf("redirect_target_%d", domain.ID), "text", role.RedirectTarget, false, nil, nil),
+			components.NewFormItem("Redirect Status", fmt.Sprintf("redirect_status_%d", domain.ID), "number", redirectStatus, false, nil, nil),
+		)
 	}
-	return NewListingResponse(headerText, currentUser, headerContent, &components.Listing{Header: listingHeader, Rows: &listingRows}, nil)
+	form := &components.Form{
+		Items:  formItems,
+		Action: fmt.Sprintf("/admin/application/domain-roles/%d", app.ID),
+		Method: "POST",
+		Submit: "Save",
+	}
+	return NewFormResponse(headerText, currentUser, headerContent, form)
 }
@@ -89,7 +89,7 @@ func TestNewCreateApplicationResponse(t *testing.T) {
 	if response.Header.Title != "Create Application" {
 		t.Errorf("Header is not set properly. Got: %v", response.Header)
 	}
-	if len(response.Form.Items) != 13 {
+	if len(response.Form.Items) != 14 {
 		t.Errorf("Form items are not set properly. Got: %v", response.Form.Items)
 	}
 }
@@ -156,7 +156,7 @@ func TestNewUpdateApplicationResponse(t *testing.T) {
 	if response.Header.Title != "Update Application" {
 		t.Errorf("Header is not set properly. Got: %v", response.Header)
 	}
-	if len(response.Form.Items) != 13 {
+	if len(response.Form.Items) != 14 {
 		t.Errorf("Form items are not set properly. Got: %v", response.Form.Items)
 	}
 }
@@ -239,7 +239,7 @@ func TestNewApplicationImportToEnvironmentFormResponse(t *testing.T) {
 	if response.Header.Title != "Import Application to Environment" {
 		t.Errorf("Header is not set properly. Got: %v", response.Header)
 	}
-	if len(response.Form.Items) != 2 {
+	if len(response.Form.Items) != 5 {
 		t.Errorf("Form items are not set properly. Got: %v", response.Form.Items)
 	}
 }
@@ -257,7 +257,7 @@ func TestNewApplicationMappingToEnvironmentFormResponseWithoutHeaders(t *testing
 	}
 	headers := []string{}
 
-	response := NewApplicationMappingToEnvironmentFormResponse(testUser, environment, fileID, csvData, headers)
+	response := NewApplicationMappingToEnvironmentFormResponse(testUser, environment, fileID, csvData, headers, false, parser.ImportOptions{})
 
 	if response.Title != "Import Mapping to Environment" {
 		t.Errorf("Title is not set properly. Got: %s", response.Title)
@@ -268,8 +268,8 @@ func TestNewApplicationMappingToEnvironmentFormResponseWithoutHeaders(t *testing
 	if response.Header.Title != "Import Mapping to Environment" {
 		t.Errorf("Header is not set properly. Got: %v", response.Header)
 	}
-	// 2 hidden input, 12 parameter for the header mapping, 12 parameter for custom value mapping
-	if len(response.Form.Items) != 26 {
+	// 2 hidden input, 12 parameter for the header mapping, 12 parameter for custom value mapping, the domains separator and 5 hidden options.
+	if len(response.Form.Items) != 32 {
 		t.Errorf("Form items are not set properly. Got: %v", response.Form.Items)
 	}
 	if len(*response.Listing.Rows) != len(csvData) {
@@ -277,6 +277,34 @@ func TestNewApplicationMappingToEnvironmentFormResponseWithoutHeaders(t *testing
 	}
 }
 
+// TestNewApplicationMappingToEnvironmentFormResponseRaggedRows is a test function for the NewApplicationMappingToEnvironmentFormResponse function.
+// It tests that the columns of the longest row are selectable, and the options are passed in the hidden inputs.
+func TestNewApplicationMappingToEnvironmentFormResponseRaggedRows(t *testing.T) {
+	testUser := testhelper.GetUserWithAccessToResources(1, []string{"applications.view"})
+	environment := &model.Environment{ID: 1, Name: "test"}
+	csvData := [][]string{{"c1"}, {"c2", "p2", "d2"}}
+	options := parser.ImportOptions{Format: parser.FormatCSV, Delimiter: parser.DelimiterComma, Encoding: parser.EncodingWindows1250}
+	response := NewApplicationMappingToEnvironmentFormResponse(testUser, environment, "test-identifier", csvData, []string{"client"}, true, options)
+	expectedHeaders := []string{"client", "Column 2", "Column 3"}
+	if len(response.Listing.Header.Headers) != 3 {
+		t.Fatalf("Headers are not set properly. Got: %v", response.Listing.Header.Headers)
+	}
+	for i, header := range expectedHeaders {
+		if response.Listing.Header.Headers[i] != header {
+			t.Errorf("Expected header %s, got %s", header, response.Listing.Header.Headers[i])
+		}
+	}
+	hidden := map[string]string{}
+	for _, item := range response.Form.Items {
+		if item.Type == "hidden" {
+			hidden[item.Name] = item.Value
+		}
+	}
+	if hidden["has_header"] != "true" || hidden["format"] != parser.FormatCSV || hidden["delimiter"] != parser.DelimiterComma || hidden["encoding"] != parser.EncodingWindows1250 {
+		t.Errorf("The options are not set properly. Got: %v", hidden)
+	}
+}
+
 // TestNewApplicationMappingToEnvironmentFormResponseWithHeaders is a test function for the NewApplicationMappingToEnvironmentFormResponse function.
 // It tests the response generation when the headers are set.
 func TestNewApplicationMappingToEnvironmentFormResponseWithHeaders(t *testing.T) {
@@ -290,7 +318,7 @@ func TestNewApplicationMappingToEnvironmentFormResponseWithHeaders(t *testing.T)
 	}
 	headers := []string{"header1", "project", "header3", "header4", "header5", "header6", "header7", "header8", "header9", "header10", "header11", "header12", "header13"}
 
-	response := NewApplicationMappingToEnvironmentFormResponse(testUser, environment, fileID, csvData, headers)
+	response := NewApplicationMappingToEnvironmentFormResponse(testUser, environment, fileID, csvData, headers, false, parser.ImportOptions{})
 
 	if response.Title != "Import Mapping to Environment" {
 		t.Errorf("Title is not set properly. Got: %s", response.Title)
@@ -301,8 +329,8 @@ func TestNewApplicationMappingToEnvironmentFormResponseWithHeaders(t *testing.T)
 	if response.Header.Title != "Import Mapping to Environment" {
 		t.Errorf("Header is not set properly. Got: %v", response.Header)
 	}
-	// 2 hidden input, 12 parameter for the header mapping, 12 parameter for custom value mapping
-	if len(response.Form.Items) != 26 {
+	// 2 hidden input, 12 parameter for the header mapping, 12 parameter for custom value mapping, the domains separator and 5 hidden options.
+	if len(response.Form.Items) != 32 {
 		t.Errorf("Form items are not set properly. Got: %v", response.Form.Items)
 	}
 	if len(*response.Listing.Rows) != len(csvData) {
@@ -333,66 +361,3 @@ func TestNewApplicationMappingToEnvironmentFormResponseWithHeaders(t *testing.T)
 		}
 	}
 }
-
-// TestNewApplicationImportToEnvironmentListResponse is a test function for the NewApplicationImportToEnvironmentListResponse function.
-// It tests the response generation.
-func TestNewApplicationImportToEnvironmentListResponse(t *testing.T) {
-	testUser := testhelper.GetUserWithAccessToResources(1, []string{"applications.view"})
-	environment := &model.Environment{
-		ID: 1, Name: "test", CreatedAt: "2020-01-01", UpdatedAt: "2020-01-01",
-	}
-	fileID := "test-identifier"
-	importResults := parser.NewApplicationImportResult()
-	// The first row contains a successful import.
-	importResults[1] = &parser.ApplicationImportRow{
-		ErrorMessage: "",
-		RowData: map[string]string{
-			"client":       "test client",
-			"project":      "test project",
-			"database":     "test database",
-			"db_name":      "test_db",
-			"db_user":      "test_user",
-			"runtime":      "test runtime",
-			"pool":         "test pool",
-			"repository":   "https://example.repository.com",
-			"branch":       "test_branch",
-			"framework":    "test_framework",
-			"documentRoot": "/var/www/html",
-			"domains":      "test-domain.com",
-		},
-		Application: &model.Application{ID: 11},
-	}
-	// The second row contains an error message.
-	importResults[2] = &parser.ApplicationImportRow{
-		ErrorMessage: "test error message",
-		RowData: map[string]string{
-			"client":       "this is wrong",
-			"project":      "test project",
-			"database":     "test database",
-			"db_name":      "test_db",
-			"db_user":      "test_user",
-			"runtime":      "test runtime",
-			"pool":         "test pool",
-			"repository":   "https://example.repository.com",
-			"branch":       "test_branch",
-			"framework":    "test_framework",
-			"documentRoot": "/var/www/html",
-			"domains":      "test-domain.com",
-		},
-		Application: nil,
-	}
-
-	response := NewApplicationImportToEnvironmentListResponse(testUser, environment, fileID, &importResults)
-	if response.Title != "Import Application to Environment" {
-		t.Errorf("Title is not set properly. Got: %s", response.Title)
-	}
-	if response.CurrentUser != testUser {
-		t.Errorf("User is not set properly. Got: %v", response.CurrentUser)
-	}
-	if response.Header.Title != "Import Application to Environment" {
-		t.Errorf("Header is not set properly. Got: %v", response.Header)
-	}
-	if len(*response.Listing.Rows) != 2 {
-		t.Errorf("Listing length is not set properly. Got: %d", len(*response.Listing.Rows))
-	}
-}
@@ -0,0 +1,148 @@
+package response
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/controller/response/components"
+	"github.com/akosgarai/projectregister/pkg/model"
+	"github.com/akosgarai/projectregister/pkg/transformers"
+)
+
+// NewCertificateDetailResponse is a constructor for the DetailResponse struct for a certificate.
+func NewCertificateDetailResponse(currentUser *model.User, certificate *model.Certificate) *DetailResponse {
+	headerText := "Certificate Detail"
+	headerContent := components.NewContentHeader(headerText, newDetailHeaderButtons(currentUser, "certificates", fmt.Sprintf("%d", certificate.ID)))
+	dnsNameValues := components.DetailValues{}
+	for _, dnsName := range certificate.DNSNames {
+		dnsNameValues = append(dnsNameValues, &components.DetailValue{Value: dnsName})
+	}
+	domainValues := components.DetailValues{}
+	for _, domain := range certificate.Domains {
+		domainValues = append(domainValues, &components.DetailValue{Value: domain.Name, Link: fmt.Sprintf("/admin/domain/view/%d", domain.ID)})
+	}
+	details := &components.DetailItems{
+		{Label: "ID", Value: &components.DetailValues{{Value: fmt.Sprintf("%d", certificate.ID)}}},
+		{Label: "Name", Value: &components.DetailValues{{Value: certificate.Name}}},
+		{Label: "Common Name", Value: &components.DetailValues{{Value: certificate.CommonName}}},
+		{Label: "Issuer", Value: &components.DetailValues{{Value: certificate.Issuer}}},
+		{Label: "Serial Number", Value: &components.DetailValues{{Value: certificate.SerialNumber}}},
+		{Label: "Fingerprint (SHA-256)", Value: &components.DetailValues{{Value: certificate.Fingerprint}}},
+		{Label: "DNS Names", Value: &dnsNameValues},
+		{Label: "Not Before", Value: &components.DetailValues{{Value: certificate.NotBefore}}},
+		{Label: "Not After", Value: &components.DetailValues{{Value: certificateExpiryText(certificate)}}},
+		{Label: "Domains", Value: &domainValues},
+		{Label: "Created At", Value: &components.DetailValues{{Value: certificate.CreatedAt}}},
+		{Label: "Updated At", Value: &components.DetailValues{{Value: certificate.UpdatedAt}}},
+	}
+	return NewDetailResponse(headerText, currentUser, headerContent, details)
+}
+
+// certificateExpiryText returns the expiration date of the certificate
+// with the expired flag, if the certificate is already expired.
+func certificateExpiryText(certificate *model.Certificate) string {
+	if certificate.IsExpired(time.Now()) {
+		return certificate.NotAfter + " (expired)"
+	}
+	return certificate.NotAfter
+}
+
+// NewCreateCertificateResponse is a constructor for the FormResponse struct for uploading a certificate.
+// The domains are linked automatically based on the SAN entries of the certificate.
+func NewCreateCertificateResponse(currentUser *model.User) *FormResponse {
+	title := "Upload Certificate"
+	headerContent := components.NewContentHeader(title, []*components.Link{components.NewLink("List", "/admin/certificate/list")})
+	formItems := []*components.FormItem{
+		// Name. The common name is used if it is empty.
+		components.NewFormItem("Name", "name", "text", "", false, nil, nil),
+		// PEM file.
+		components.NewFormItem("PEM File", "pemfile", "file", "", true, nil, nil),
+	}
+	form := &components.Form{
+		Items:     formItems,
+		Action:    "/admin/certificate/create",
+		Method:    "POST",
+		Submit:    "Upload",
+		Multipart: true,
+	}
+	return NewFormResponse(title, currentUser, headerContent, form)
+}
+
+// NewUpdateCertificateResponse is a constructor for the FormResponse struct for updating a certificate.
+func NewUpdateCertificateResponse(currentUser *model.User, certificate *model.Certificate, domains *model.Domains) *FormResponse {
+	title := "Update Certificate"
+	headerContent := components.NewContentHeader(title, []*components.Link{components.NewLink("List", "/admin/certificate/list")})
+	selectedDomains := []int64{}
+	for _, domain := range certificate.Domains {
+		selectedDomains = append(selectedDomains, domain.ID)
+	}
+	formItems := []*components.FormItem{
+		// Name.
+		components.NewFormItem("Name", "name", "text", certificate.Name, true, nil, nil),
+		// Domains.
+		components.NewFormItem("Domains", "domains", "checkboxgroup", "", false, domains.ToMap(), selectedDomains),
+	}
+	form := &components.Form{
+		Items:  formItems,
+		Action: fmt.Sprintf("/admin/certificate/update/%d", certificate.ID),
+		Method: "POST",
+		Submit: "Update",
+	}
+	return NewFormResponse(title, currentUser, headerContent, form)
+}
+
+// NewCertificateListResponse is a constructor for the ListingResponse struct of the certificates.
+func NewCertificateListResponse(currentUser *model.User, certificates *model.Certificates, domains *model.Domains, filter *model.CertificateFilter) *ListingResponse {
+	headerText := "Certificate List"
+	headerContent := components.NewContentHeader(headerText, []*components.Link{})
+	if currentUser.HasPrivilege("certificates.create") {
+		headerContent.Buttons = append(headerContent.Buttons, components.NewLink("Upload", "/admin/certificate/create"))
+	}
+	listingHeader := &components.ListingHeader{
+		Headers: []string{"ID", "Name", "Common Name", "Domains", "Expires At", "Actions"},
+	}
+	// create the rows
+	listingRows := components.ListingRows{}
+	userCanEdit := currentUser.HasPrivilege("certificates.update")
+	userCanDelete := currentUser.HasPrivilege("certificates.delete")
+	for _, certificate := range *certificates {
+		columns := components.ListingColumns{}
+		idColumn := &components.ListingColumn{&components.ListingColumnValues{{Value: fmt.Sprintf("%d", certificate.ID)}}}
+		columns = append(columns, idColumn)
+		nameColumn := &components.ListingColumn{&components.ListingColumnValues{{Value: certificate.Name}}}
+		columns = append(columns, nameColumn)
+		commonNameColumn := &components.ListingColumn{&components.ListingColumnValues{{Value: certificate.CommonName}}}
+		columns = append(columns, commonNameColumn)
+		domainValues := components.ListingColumnValues{}
+		for _, domain := range certificate.Domains {
+			domainValues = append(domainValues, &components.ListingColumnValue{Value: domain.Name})
+		}
+		columns = append(columns, &components.ListingColumn{&domainValues})
+		expiresAtColumn := &components.ListingColumn{&components.ListingColumnValues{{Value: certificateExpiryText(certificate)}}}
+		columns = append(columns, expiresAtColumn)
+		actionsColumn := components.ListingColumn{&components.ListingColumnValues{
+			{Value: "View", Link: fmt.Sprintf("/admin/certificate/view/%d", certificate.ID)},
+		}}
+		if userCanEdit {
+			*actionsColumn.Values = append(*actionsColumn.Values, &components.ListingColumnValue{Value: "Update", Link: fmt.Sprintf("/admin/certificate/update/%d", certificate.ID)})
+		}
+		if userCanDelete {
+			*actionsColumn.Values = append(*actionsColumn.Values, &components.ListingColumnValue{Value: "Delete", Link: fmt.Sprintf("/admin/certificate/delete/%d", certificate.ID), Form: true})
+		}
+		columns = append(columns, &actionsColumn)
+
+		listingRows = append(listingRows, &components.ListingRow{Columns: &columns})
+	}
+	/* Create the search form. The form items are the name and the domains. */
+	formItems := []*components.FormItem{
+		components.NewFormItem("Name", "name", "text", filter.Name, false, nil, nil),
+		components.NewFormItem("Domains", "domains", "multiselect", "", false, domains.ToMap(), transformers.StringSliceToInt64Slice(filter.DomainIDs)),
+	}
+	form := &components.Form{
+		Items:  formItems,
+		Action: "/admin/certificate/list",
+		Method: "POST",
+		Submit: "Search",
+	}
+	return NewListingResponse(headerText, currentUser, headerContent, &components.Listing{Header: listingHeader, Rows: &listingRows}, form)
+}
@@ -4,9 +4,11 @@ package components
 // It contains
 // - the label of the item
 // - the link.
+// On case of the Form set to true, the link is the action of a POST form.
 type Link struct {
 	Text string
 	Href string
+	Form bool
 }
 
 // NewLink is a constructor for the Link struct.
@@ -17,6 +19,15 @@ func NewLink(text, href string) *Link {
 	}
 }
 
+// NewFormLink is a constructor for the Link struct that is submitted as a POST form.
+func NewFormLink(text, href string) *Link {
+	return &Link{
+		Text: text,
+		Href: href,
+		Form: true,
+	}
+}
+
 // ContentHeader is the struct for the content header.
 // It contains
 // - the title of the page
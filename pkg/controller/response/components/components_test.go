@@ -16,6 +16,15 @@ func TestNewLink(t *testing.T) {
 	}
 }
 
+// TestNewFormLink tests the NewFormLink function.
+// It creates a new form Link and checks the fields.
+func TestNewFormLink(t *testing.T) {
+	link := NewFormLink("test", "/test")
+	if link.Text != "test" || link.Href != "/test" || !link.Form {
+		t.Errorf("Invalid form link: %+v", link)
+	}
+}
+
 // TestNewContentHeader tests the NewContentHeader function.
 // It creates a new ContentHeader and checks the fields.
 func TestNewContentHeader(t *testing.T) {
@@ -0,0 +1,126 @@
+package response
+
+import (
+	"fmt"
+
+	"github.com/akosgarai/projectregister/pkg/controller/response/components"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// NewCreateDNSRecordResponse is a constructor for the FormResponse struct for creating a dns record.
+func NewCreateDNSRecordResponse(currentUser *model.User, domain *model.Domain) *FormResponse {
+	record := &model.DNSRecord{Name: model.DNSRecordApexName, Type: model.DNSRecordTypeA, TTL: model.DNSRecordDefaultTTL}
+	return newDNSRecordFormResponse("Create DNS Record", currentUser, domain, record, fmt.Sprintf("/admin/dns-record/create/%d", domain.ID), "POST", "Create")
+}
+
+// NewUpdateDNSRecordResponse is a constructor for the FormResponse struct for updating a dns record.
+func NewUpdateDNSRecordResponse(currentUser *model.User, domain *model.Domain, record *model.DNSRecord) *FormResponse {
+	return newDNSRecordFormResponse("Update DNS Record", currentUser, domain, record, fmt.Sprintf("/admin/dns-record/update/%d", record.ID), "POST", "Update")
+}
+
+// newDNSRecordFormResponse is a constructor for the FormResponse struct for a dns record.
+// The options of the type select are the indexes of the supported types starting from 1.
+func newDNSRecordFormResponse(title string, currentUser *model.User, domain *model.Domain, record *model.DNSRecord, action, method, submitLabel string) *FormResponse {
+	headerContent := components.NewContentHeader(title, []*components.Link{components.NewLink("Domain", fmt.Sprintf("/admin/domain/view/%d", domain.ID))})
+	typeOptions := map[int64]string{}
+	selectedType := []int64{}
+	for index, recordType := range model.DNSRecordTypes {
+		typeOptions[int64(index+1)] = recordType
+		if recordType == record.Type {
+			selectedType = append(selectedType, int64(index+1))
+		}
+	}
+	formItems := []*components.FormItem{
+		// Name. It is relative to the domain, the domain itself is @.
+		components.NewFormItem("Name (relative to "+domain.Name+")", "name", "text", record.Name, true, nil, nil),
+		// Type.
+		components.NewFormItem("Type", "type", "select", "", true, typeOptions, selectedType),
+		// TTL.
+		components.NewFormItem("TTL", "ttl", "number", fmt.Sprintf("%d", record.TTL), false, nil, nil),
+		// Priority. It is used only by the MX records.
+		components.NewFormItem("Priority (MX)", "priority", "number", fmt.Sprintf("%d", record.Priority), false, nil, nil),
+		// Value.
+		components.NewFormItem("Value", "value", "text", record.Value, true, nil, nil),
+	}
+	form := &components.Form{
+		Items:  formItems,
+		Action: action,
+		Method: method,
+		Submit: submitLabel,
+	}
+	return NewFormResponse(title, currentUser, headerContent, form)
+}
+
+// NewDomainZoneImportResponse is a constructor for the FormResponse struct for the zone file import.
+// The origin is optional if the zone file contains $ORIGIN directive.
+func NewDomainZoneImportResponse(currentUser *model.User) *FormResponse {
+	title := "Import Zone File"
+	headerContent := components.NewContentHeader(title, []*components.Link{components.NewLink("List", "/admin/domain/list")})
+	formItems := []*components.FormItem{
+		components.NewFormItem("Origin", "origin", "text", "", false, nil, nil),
+		components.NewFormItem("Zone File", "zonefile", "file", "", true, nil, nil),
+	}
+	form := &components.Form{
+		Items:     formItems,
+		Action:    "/admin/domain/zone-import",
+		Method:    "POST",
+		Submit:    "Import",
+		Multipart: true,
+	}
+	return NewFormResponse(title, currentUser, headerContent, form)
+}
+
+// NewDNSRecordListResponse is a constructor for the ListingResponse struct of the dns records of a domain.
+func NewDNSRecordListResponse(currentUser *model.User, domain *model.Domain, records *model.DNSRecords, filter *model.DNSRecordFilter) *ListingResponse {
+	headerText := "DNS Records of " + domain.Name
+	headerContent := components.NewContentHeader(headerText, []*components.Link{components.NewLink("Domain", fmt.Sprintf("/admin/domain/view/%d", domain.ID))})
+	userCanEdit := currentUser.HasPrivilege("domains.update")
+	if userCanEdit {
+		headerContent.Buttons = append(headerContent.Buttons, components.NewLink("Create", fmt.Sprintf("/admin/dns-record/create/%d", domain.ID)))
+	}
+	headerContent.Buttons = append(headerContent.Buttons, components.NewLink("Export Zone", fmt.Sprintf("/admin/domain/zone-export/%d", domain.ID)))
+	listingHeader := &components.ListingHeader{
+		Headers: []string{"Name", "Type", "TTL", "Priority", "Value", "Actions"},
+	}
+	// create the rows
+	listingRows := components.ListingRows{}
+	for _, record := range *records {
+		columns := components.ListingColumns{}
+		columns = append(columns, &components.ListingColumn{&components.ListingColumnValues{{Value: record.Name}}})
+		columns = append(columns, &components.ListingColumn{&components.ListingColumnValues{{Value: record.Type}}})
+		columns = append(columns, &components.ListingColumn{&components.ListingColumnValues{{Value: fmt.Sprintf("%d", record.TTL)}}})
+		priority := ""
+		if record.Type == model.DNSRecordTypeMX {
+			priority = fmt.Sprintf("%d", record.Priority)
+		}
+		columns = append(columns, &components.ListingColumn{&components.ListingColumnValues{{Value: priority}}})
+		columns = append(columns, &components.ListingColumn{&components.ListingColumnValues{{Value: record.Value}}})
+		actionsColumn := components.ListingColumn{&components.ListingColumnValues{}}
+		if userCanEdit {
+			*actionsColumn.Values = append(*actionsColumn.Values, &components.ListingColumnValue{Value: "Update", Link: fmt.Sprintf("/admin/dns-record/update/%d", record.ID)})
+			*actionsColumn.Values = append(*actionsColumn.Values, &components.ListingColumnValue{Value: "Delete", Link: fmt.Sprintf("/admin/dns-record/delete/%d", record.ID), Form: true})
+		}
+		columns = append(columns, &actionsColumn)
+
+		listingRows = append(listingRows, &components.ListingRow{Columns: &columns})
+	}
+	/* Create the search form. The only form item is the type. */
+	typeOptions := map[int64]string{}
+	selectedType := []int64{}
+	for index, recordType := range model.DNSRecordTypes {
+		typeOptions[int64(index+1)] = recordType
+		if recordType == filter.Type {
+			selectedType = append(selectedType, int64(index+1))
+		}
+	}
+	formItems := []*components.FormItem{
+		components.NewFormItem("Type", "type", "select", "", false, typeOptions, selectedType),
+	}
+	form := &components.Form{
+		Items:  formItems,
+		Action: fmt.Sprintf("/admin/dns-record/list/%d", domain.ID),
+		Method: "POST",
+		Submit: "Search",
+	}
+	return NewListingResponse(headerText, currentUser, headerContent, &components.Listing{Header: listingHeader, Rows: &listingRows}, form)
+}
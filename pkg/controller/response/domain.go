@@ -2,51 +2,138 @@ package response
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/akosgarai/projectregister/pkg/controller/response/components"
 	"github.com/akosgarai/projectregister/pkg/model"
 )
 
 // NewDomainDetailResponse is a constructor for the DetailResponse struct for a domain.
-func NewDomainDetailResponse(currentUser *model.User, domain *model.Domain) *DetailResponse {
+// The certificates are the registered certificates, the records are the dns records of the domain zone.
+func NewDomainDetailResponse(currentUser *model.User, domain *model.Domain, certificates *model.Certificates, records *model.DNSRecords) *DetailResponse {
 	headerText := "Domain Detail"
 	headerContent := components.NewContentHeader(headerText, newDetailHeaderButtons(currentUser, "domains", fmt.Sprintf("%d", domain.ID)))
 	if currentUser.HasPrivilege("domains.update") {
 		headerContent.Buttons = append(
-			[]*components.Link{components.NewLink("Check", fmt.Sprintf("/admin/domain/check-ssl/%d", domain.ID))},
+			[]*components.Link{
+				components.NewLink("Check", fmt.Sprintf("/admin/domain/check-ssl/%d", domain.ID)),
+				components.NewLink("Audit", fmt.Sprintf("/admin/domain/check-security/%d", domain.ID)),
+			},
 			headerContent.Buttons...,
 		)
 	}
+	if currentUser.HasPrivilege("domains.update") {
+		headerContent.Buttons = append([]*components.Link{components.NewLink("RDAP", fmt.Sprintf("/admin/domain/rdap-lookup/%d", domain.ID))}, headerContent.Buttons...)
+	}
+	headerContent.Buttons = append([]*components.Link{components.NewLink("DNS Records", fmt.Sprintf("/admin/dns-record/list/%d", domain.ID))}, headerContent.Buttons...)
 	protocol := "http"
 	if domain.HasSSL {
 		protocol = "https"
 	}
+	findingValues := components.DetailValues{}
+	if domain.SecurityFindings != "" {
+		for _, finding := range strings.Split(domain.SecurityFindings, "\n") {
+			findingValues = append(findingValues, &components.DetailValue{Value: finding})
+		}
+	}
+	certificateValues := components.DetailValues{}
+	for _, certificate := range *certificates {
+		certificateValues = append(certificateValues, &components.DetailValue{Value: certificate.Name, Link: fmt.Sprintf("/admin/certificate/view/%d", certificate.ID)})
+	}
+	recordValues := components.DetailValues{}
+	for _, record := range *records {
+		recordValues = append(recordValues, &components.DetailValue{Value: record.Name + " " + record.String()})
+	}
+	billingClientValues := components.DetailValues{{Value: "-"}}
+	if domain.BillingClient != nil {
+		billingClientValues = components.DetailValues{{Value: domain.BillingClient.Name, Link: fmt.Sprintf("/admin/client/view/%d", domain.BillingClient.ID)}}
+	}
 	details := &components.DetailItems{
 		{Label: "ID", Value: &components.DetailValues{{Value: fmt.Sprintf("%d", domain.ID)}}},
 		{Label: "Name", Value: &components.DetailValues{{Value: domain.Name, Link: fmt.Sprintf("%s://%s", protocol, domain.Name)}}},
 		{Label: "Has SSL", Value: &components.DetailValues{{Value: fmt.Sprintf("%t", domain.HasSSL)}}},
+		{Label: "SSL Checked At", Value: &components.DetailValues{{Value: domainOptionalText(domain.SSLCheckedAt)}}},
+		{Label: "Registrar", Value: &components.DetailValues{{Value: domainOptionalText(domain.Registrar)}}},
+		{Label: "Registered At", Value: &components.DetailValues{{Value: domainOptionalText(domain.RegisteredAt)}}},
+		{Label: "Expires At", Value: &components.DetailValues{{Value: domainOptionalText(domain.ExpiresAt)}}},
+		{Label: "Auto Renew", Value: &components.DetailValues{{Value: fmt.Sprintf("%t", domain.AutoRenew)}}},
+		{Label: "Billing Client", Value: &billingClientValues},
+		{Label: "DNS Records", Value: &recordValues},
+		{Label: "Certificates", Value: &certificateValues},
+		{Label: "Live Certificate", Value: &components.DetailValues{{Value: domainLiveCertificateText(domain, *certificates)}}},
+		{Label: "Security Grade", Value: &components.DetailValues{{Value: domainSecurityGradeText(domain)}}},
+		{Label: "Security Findings", Value: &findingValues},
 		{Label: "Created At", Value: &components.DetailValues{{Value: domain.CreatedAt}}},
 		{Label: "Updated At", Value: &components.DetailValues{{Value: domain.UpdatedAt}}},
 	}
 	return NewDetailResponse(headerText, currentUser, headerContent, details)
 }
 
+// domainOptionalText returns the value or the placeholder if the value is unknown.
+func domainOptionalText(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// domainSecurityGradeText returns the grade with the score of the audited domain.
+func domainSecurityGradeText(domain *model.Domain) string {
+	if !domain.IsAudited() {
+		return "-"
+	}
+	return fmt.Sprintf("%s (%d)", domain.SecurityGrade, domain.SecurityScore)
+}
+
+// domainLiveCertificateText returns the fingerprint of the served certificate
+// with the mismatch flag, if the domain serves an unregistered certificate.
+func domainLiveCertificateText(domain *model.Domain, certificates model.Certificates) string {
+	if domain.LiveCertificateFingerprint == "" {
+		return "-"
+	}
+	if domain.HasCertificateMismatch(certificates) {
+		return domain.LiveCertificateFingerprint + " (mismatch)"
+	}
+	return domain.LiveCertificateFingerprint
+}
+
 // NewCreateDomainResponse is a constructor for the FormResponse struct for a domain.
-func NewCreateDomainResponse(currentUser *model.User) *FormResponse {
-	return newDomainFormResponse("Create Domain", currentUser, &model.Domain{}, "/admin/domain/create", "POST", "Create")
+// The clients are the options of the billing client.
+func NewCreateDomainResponse(currentUser *model.User, clients *model.Clients) *FormResponse {
+	return newDomainFormResponse("Create Domain", currentUser, &model.Domain{}, clients, "/admin/domain/create", "POST", "Create")
 }
 
 // NewUpdateDomainResponse is a constructor for the FormResponse struct for a domain.
-func NewUpdateDomainResponse(currentUser *model.User, domain *model.Domain) *FormResponse {
-	return newDomainFormResponse("Update Domain", currentUser, domain, fmt.Sprintf("/admin/domain/update/%d", domain.ID), "POST", "Update")
+// The clients are the options of the billing client.
+func NewUpdateDomainResponse(currentUser *model.User, domain *model.Domain, clients *model.Clients) *FormResponse {
+	return newDomainFormResponse("Update Domain", currentUser, domain, clients, fmt.Sprintf("/admin/domain/update/%d", domain.ID), "POST", "Update")
 }
 
 // newDomainFormResponse is a constructor for the FormResponse struct for a domain.
-func newDomainFormResponse(title string, currentUser *model.User, domain *model.Domain, action, method, submitLabel string) *FormResponse {
+func newDomainFormResponse(title string, currentUser *model.User, domain *model.Domain, clients *model.Clients, action, method, submitLabel string) *FormResponse {
 	headerContent := components.NewContentHeader(title, []*components.Link{components.NewLink("List", "/admin/domain/list")})
+	selectedClients := []int64{}
+	if domain.BillingClient != nil {
+		selectedClients = append(selectedClients, domain.BillingClient.ID)
+	}
+	selectedAutoRenew := []int64{}
+	if domain.AutoRenew {
+		selectedAutoRenew = append(selectedAutoRenew, 1)
+	}
 	formItems := []*components.FormItem{
 		// Name.
 		components.NewFormItem("Name", "name", "text", domain.Name, true, nil, nil),
+		// Registrar.
+		components.NewFormItem("Registrar", "registrar", "text", domain.Registrar, false, nil, nil),
+		// Registered At.
+		components.NewFormItem("Registered At", "registered_at", "date", domain.RegisteredAt, false, nil, nil),
+		// Expires At.
+		components.NewFormItem("Expires At", "expires_at", "date", domain.ExpiresAt, false, nil, nil),
+		// Auto Renew.
+		components.NewFormItem("Auto Renew", "auto_renew", "checkboxgroup", "", false, map[int64]string{1: "Yes"}, selectedAutoRenew),
+		// Billing Client.
+		components.NewFormItem("Billing Client", "billing_client", "select", "", false, clients.ToMap(), selectedClients),
 	}
 	form := &components.Form{
 		Items:  formItems,
@@ -58,14 +145,18 @@ func newDomainFormResponse(title string, currentUser *model.User, domain *model.
 }
 
 // NewDomainListResponse is a constructor for the ListingResponse struct of the domains.
-func NewDomainListResponse(currentUser *model.User, domains *model.Domains, filter *model.DomainFilter) *ListingResponse {
+// The certificates are used for flagging the domains that serve unregistered certificate.
+func NewDomainListResponse(currentUser *model.User, domains *model.Domains, certificates *model.Certificates, filter *model.DomainFilter) *ListingResponse {
 	headerText := "Domain List"
 	headerContent := components.NewContentHeader(headerText, []*components.Link{})
 	if currentUser.HasPrivilege("domains.create") {
 		headerContent.Buttons = append(headerContent.Buttons, components.NewLink("Create", "/admin/domain/create"))
+		headerContent.Buttons = append(headerContent.Buttons, components.NewLink("Import", "/admin/domain/import"))
+		headerContent.Buttons = append(headerContent.Buttons, components.NewLink("Import Zone", "/admin/domain/zone-import"))
 	}
+	headerContent.Buttons = append(headerContent.Buttons, components.NewLink("Expiring", "/admin/domain/expiring"))
 	listingHeader := &components.ListingHeader{
-		Headers: []string{"ID", "Name", "Has SSL", "Actions"},
+		Headers: []string{"ID", "Name", "Has SSL", "Certificate", "Security Grade", "Expires At", "Actions"},
 	}
 	// create the rows
 	listingRows := components.ListingRows{}
@@ -79,6 +170,19 @@ func NewDomainListResponse(currentUser *model.User, domains *model.Domains, filt
 		columns = append(columns, nameColumn)
 		hasSSLColumn := &components.ListingColumn{&components.ListingColumnValues{{Value: fmt.Sprintf("%t", domain.HasSSL)}}}
 		columns = append(columns, hasSSLColumn)
+		certificateText := "-"
+		if domainCertificates := certificates.ForDomain(domain.ID); len(domainCertificates) > 0 {
+			certificateText = "registered"
+			if domain.HasCertificateMismatch(domainCertificates) {
+				certificateText = "mismatch"
+			}
+		}
+		certificateColumn := &components.ListingColumn{&components.ListingColumnValues{{Value: certificateText}}}
+		columns = append(columns, certificateColumn)
+		securityGradeColumn := &components.ListingColumn{&components.ListingColumnValues{{Value: domainSecurityGradeText(domain)}}}
+		columns = append(columns, securityGradeColumn)
+		expiresAtColumn := &components.ListingColumn{&components.ListingColumnValues{{Value: domainOptionalText(domain.ExpiresAt)}}}
+		columns = append(columns, expiresAtColumn)
 		actionsColumn := components.ListingColumn{&components.ListingColumnValues{
 			{Value: "View", Link: fmt.Sprintf("/admin/domain/view/%d", domain.ID)},
 		}}
@@ -104,3 +208,59 @@ func NewDomainListResponse(currentUser *model.User, domains *model.Domains, filt
 	}
 	return NewListingResponse(headerText, currentUser, headerContent, &components.Listing{Header: listingHeader, Rows: &listingRows}, form)
 }
+
+// NewDomainExpiringListResponse is a constructor for the ListingResponse struct of the expiring domains.
+// The domains are expected to be ordered by the expiry date. The days is the value of the search form.
+func NewDomainExpiringListResponse(currentUser *model.User, domains *model.Domains, days int, now time.Time) *ListingResponse {
+	headerText := "Expiring Domains"
+	headerContent := components.NewContentHeader(headerText, []*components.Link{components.NewLink("List", "/admin/domain/list")})
+	listingHeader := &components.ListingHeader{
+		Headers: []string{"Name", "Registrar", "Expires At", "Days Left", "Auto Renew", "Billing Client", "Actions"},
+	}
+	// create the rows
+	listingRows := components.ListingRows{}
+	userCanEdit := currentUser.HasPrivilege("domains.update")
+	for _, domain := range *domains {
+		columns := components.ListingColumns{}
+		nameColumn := &components.ListingColumn{&components.ListingColumnValues{{Value: domain.Name}}}
+		columns = append(columns, nameColumn)
+		registrarColumn := &components.ListingColumn{&components.ListingColumnValues{{Value: domainOptionalText(domain.Registrar)}}}
+		columns = append(columns, registrarColumn)
+		expiresAtColumn := &components.ListingColumn{&components.ListingColumnValues{{Value: domain.ExpiresAt}}}
+		columns = append(columns, expiresAtColumn)
+		daysLeft := domain.DaysUntilExpiry(now)
+		daysLeftText := fmt.Sprintf("%d", daysLeft)
+		if daysLeft < 0 {
+			daysLeftText += " (expired)"
+		}
+		daysLeftColumn := &components.ListingColumn{&components.ListingColumnValues{{Value: daysLeftText}}}
+		columns = append(columns, daysLeftColumn)
+		autoRenewColumn := &components.ListingColumn{&components.ListingColumnValues{{Value: fmt.Sprintf("%t", domain.AutoRenew)}}}
+		columns = append(columns, autoRenewColumn)
+		billingClientValues := components.ListingColumnValues{{Value: "-"}}
+		if domain.BillingClient != nil {
+			billingClientValues = components.ListingColumnValues{{Value: domain.BillingClient.Name, Link: fmt.Sprintf("/admin/client/view/%d", domain.BillingClient.ID)}}
+		}
+		columns = append(columns, &components.ListingColumn{&billingClientValues})
+		actionsColumn := components.ListingColumn{&components.ListingColumnValues{
+			{Value: "View", Link: fmt.Sprintf("/admin/domain/view/%d", domain.ID)},
+		}}
+		if userCanEdit {
+			*actionsColumn.Values = append(*actionsColumn.Values, &components.ListingColumnValue{Value: "Update", Link: fmt.Sprintf("/admin/domain/update/%d", domain.ID)})
+		}
+		columns = append(columns, &actionsColumn)
+
+		listingRows = append(listingRows, &components.ListingRow{Columns: &columns})
+	}
+	/* Create the search form. The only form item is the number of days. */
+	formItems := []*components.FormItem{
+		components.NewFormItem("Days", "days", "number", fmt.Sprintf("%d", days), true, nil, nil),
+	}
+	form := &components.Form{
+		Items:  formItems,
+		Action: "/admin/domain/expiring",
+		Method: "POST",
+		Submit: "Search",
+	}
+	return NewListingResponse(headerText, currentUser, headerContent, &components.Listing{Header: listingHeader, Rows: &listingRows}, form)
+}
@@ -17,7 +17,7 @@ func TestNewDomainDetailResponse(t *testing.T) {
 		UpdatedAt: "2020-01-01",
 	}
 	testUser := testhelper.GetUserWithAccessToResources(1, []string{"domains.view"})
-	response := NewDomainDetailResponse(testUser, domain)
+	response := NewDomainDetailResponse(testUser, domain, &model.Certificates{}, &model.DNSRecords{})
 	if response.Title != "Domain Detail" {
 		t.Errorf("Title is not set properly. Got: %s", response.Title)
 	}
@@ -27,7 +27,7 @@ func TestNewDomainDetailResponse(t *testing.T) {
 	if response.Header.Title != "Domain Detail" {
 		t.Errorf("Header is not set properly. Got: %v", response.Header)
 	}
-	if len(*response.Details) != 4 {
+	if len(*response.Details) != 16 {
 		t.Errorf("Details is not set properly. Got: %v", response.Details)
 	}
 }
@@ -36,7 +36,7 @@ func TestNewDomainDetailResponse(t *testing.T) {
 // It tests the response generation.
 func TestNewCreateDomainResponse(t *testing.T) {
 	testUser := testhelper.GetUserWithAccessToResources(1, []string{"domains.view"})
-	response := NewCreateDomainResponse(testUser)
+	response := NewCreateDomainResponse(testUser, &model.Clients{})
 	if response.Title != "Create Domain" {
 		t.Errorf("Title is not set properly. Got: %s", response.Title)
 	}
@@ -46,7 +46,7 @@ func TestNewCreateDomainResponse(t *testing.T) {
 	if response.Header.Title != "Create Domain" {
 		t.Errorf("Header is not set properly. Got: %v", response.Header)
 	}
-	if len(response.Form.Items) != 1 {
+	if len(response.Form.Items) != 6 {
 		t.Errorf("Form items are not set properly. Got: %v", response.Form.Items)
 	}
 }
@@ -59,7 +59,7 @@ func TestNewUpdateDomainResponse(t *testing.T) {
 		Name: "test",
 	}
 	testUser := testhelper.GetUserWithAccessToResources(1, []string{"domains.view"})
-	response := NewUpdateDomainResponse(testUser, domain)
+	response := NewUpdateDomainResponse(testUser, domain, &model.Clients{})
 	if response.Title != "Update Domain" {
 		t.Errorf("Title is not set properly. Got: %s", response.Title)
 	}
@@ -69,7 +69,7 @@ func TestNewUpdateDomainResponse(t *testing.T) {
 	if response.Header.Title != "Update Domain" {
 		t.Errorf("Header is not set properly. Got: %v", response.Header)
 	}
-	if len(response.Form.Items) != 1 {
+	if len(response.Form.Items) != 6 {
 		t.Errorf("Form items are not set properly. Got: %v", response.Form.Items)
 	}
 }
@@ -93,6 +93,7 @@ func NewEnvironmentListResponse(currentUser *model.User, environments *model.Env
 	headerContent := components.NewContentHeader(headerText, []*components.Link{})
 	if currentUser.HasPrivilege("environments.create") {
 		headerContent.Buttons = append(headerContent.Buttons, components.NewLink("Create", "/admin/environment/create"))
+		headerContent.Buttons = append(headerContent.Buttons, components.NewLink("Import", "/admin/environment/import"))
 	}
 	listingHeader := &components.ListingHeader{
 		Headers: []string{"ID", "Name", "Description", "Score", "Actions"},
@@ -0,0 +1,221 @@
+package response
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/akosgarai/projectregister/pkg/controller/response/components"
+	"github.com/akosgarai/projectregister/pkg/model"
+	"github.com/akosgarai/projectregister/pkg/parser"
+)
+
+const (
+	// importPreviewRows is the number of the file rows that are displayed on the mapping page.
+	importPreviewRows = 5
+	// importCheckedRows is the maximum number of the checked rows that are displayed on the preview page.
+	importCheckedRows = 50
+)
+
+// ImportPreviewRow is a mapped and checked row of the import preview.
+// The number is the row number in the file, the result is the outcome of the dry run or the error of the row.
+type ImportPreviewRow struct {
+	Number  int
+	RowData map[string]string
+	Result  string
+	Failed  bool
+}
+
+// importUploadFormItems returns the file input and the parser options of the import upload forms.
+// The delimiter and the encoding options are the 1 based indexes of the parser.CSVDelimiters and the parser.CSVEncodings,
+// they are detected if they are not selected. The sheet is the name of the imported sheet of the xlsx files.
+func importUploadFormItems() []*components.FormItem {
+	checkboxOptions := map[int64]string{
+		1: "Yes",
+	}
+	delimiterOptions := map[int64]string{}
+	for index, delimiter := range parser.CSVDelimiters {
+		delimiterOptions[int64(index+1)] = csvDelimiterLabels[delimiter]
+	}
+	encodingOptions := map[int64]string{}
+	for index, encoding := range parser.CSVEncodings {
+		encodingOptions[int64(index+1)] = encoding
+	}
+	return []*components.FormItem{
+		components.NewFormItem("File (csv, xlsx or json)", "csvfile", "file", "", true, nil, nil),
+		components.NewFormItem("Has Header", "has_header", "checkboxgroup", "true", false, checkboxOptions, nil),
+		components.NewFormItem("Delimiter (detected if not selected)", "delimiter", "select", "", false, delimiterOptions, nil),
+		components.NewFormItem("Encoding (detected if not selected)", "encoding", "select", "", false, encodingOptions, nil),
+		components.NewFormItem("Sheet (the first one if empty)", "sheet", "text", "", false, nil, nil),
+	}
+}
+
+// importPreviewListing returns the listing of the first rows of the file, and the column names.
+// On case of the headers are not set, or some rows are longer than the header, the columns are named by their 1 based index.
+func importPreviewListing(data [][]string, headers []string) (*components.Listing, []string) {
+	columns := 0
+	for _, row := range data {
+		columns = max(columns, len(row))
+	}
+	for i := len(headers); i < columns; i++ {
+		headers = append(headers, fmt.Sprintf("Column %d", i+1))
+	}
+	rows := components.ListingRows{}
+	for i := 0; i < importPreviewRows && i < len(data); i++ {
+		columns := components.ListingColumns{}
+		for _, dataItem := range data[i] {
+			columns = append(columns, &components.ListingColumn{Values: &components.ListingColumnValues{{Value: dataItem}}})
+		}
+		rows = append(rows, &components.ListingRow{Columns: &columns})
+	}
+	return &components.Listing{Header: &components.ListingHeader{Headers: headers}, Rows: &rows}, headers
+}
+
+// importMappingFormItems returns a column select and a custom value input for every field.
+// The column options are the 0 based column indexes, the column with the same name as the field is selected.
+func importMappingFormItems(fields, headers []string) []*components.FormItem {
+	mappingOptions := map[int64]string{}
+	for i, header := range headers {
+		mappingOptions[int64(i)] = header
+	}
+	formItems := []*components.FormItem{}
+	for _, field := range fields {
+		selected := []int64{}
+		for i, header := range headers {
+			if strings.EqualFold(strings.TrimSpace(header), field) {
+				selected = append(selected, int64(i))
+				break
+			}
+		}
+		formItems = append(formItems,
+			components.NewFormItem(field, field, "select", "", false, mappingOptions, selected),
+			components.NewFormItem(fmt.Sprintf("Custom %s name", field), fmt.Sprintf("%s_custom", field), "text", "", false, nil, nil),
+		)
+	}
+	return formItems
+}
+
+// importOptionsFormItems returns the hidden inputs of the header flag and the parser options of the upload.
+func importOptionsFormItems(hasHeader bool, options parser.ImportOptions) []*components.FormItem {
+	hasHeaderValue := ""
+	if hasHeader {
+		hasHeaderValue = "true"
+	}
+	return []*components.FormItem{
+		components.NewFormItem("", "has_header", "hidden", hasHeaderValue, false, nil, nil),
+		components.NewFormItem("", "format", "hidden", options.Format, false, nil, nil),
+		components.NewFormItem("", "delimiter", "hidden", options.Delimiter, false, nil, nil),
+		components.NewFormItem("", "encoding", "hidden", options.Encoding, false, nil, nil),
+		components.NewFormItem("", "sheet", "hidden", options.Sheet, false, nil, nil),
+	}
+}
+
+// NewImportUploadFormResponse is a constructor for the FormResponse struct of the resource import upload.
+// The resource is the url name of the imported resource, eg. server, the label is its displayed name.
+func NewImportUploadFormResponse(currentUser *model.User, resource, label string) *FormResponse {
+	headerText := fmt.Sprintf("Import %s", label)
+	headerContent := components.NewContentHeader(headerText, []*components.Link{components.NewLink("List", fmt.Sprintf("/admin/%s/list", resource))})
+	form := &components.Form{
+		Items:     importUploadFormItems(),
+		Action:    fmt.Sprintf("/admin/%s/import", resource),
+		Method:    "POST",
+		Submit:    "Upload",
+		Multipart: true,
+	}
+	return NewFormResponse(headerText, currentUser, headerContent, form)
+}
+
+// NewImportMappingFormResponse is a constructor for the ImportMappingResponse struct of the resource import mapping.
+// The first rows of the file are displayed, and the fields could be mapped to the columns or to custom values.
+// The list fields are split with the list separator. The mapping is checked on the preview page.
+func NewImportMappingFormResponse(currentUser *model.User, resource, label string, fields []string, fileID string, data [][]string, headers []string, hasHeader bool, options parser.ImportOptions) *ImportMappingResponse {
+	headerText := fmt.Sprintf("Import %s Mapping", label)
+	headerContent := components.NewContentHeader(headerText, []*components.Link{})
+	listing, headers := importPreviewListing(data, headers)
+	formItems := []*components.FormItem{
+		components.NewFormItem("", "file_id", "hidden", fileID, true, nil, nil),
+	}
+	formItems = append(formItems, importMappingFormItems(fields, headers)...)
+	formItems = append(formItems, components.NewFormItem("List separator (whitespace if empty)", "list_separator", "text", "", false, nil, nil))
+	formItems = append(formItems, importOptionsFormItems(hasHeader, options)...)
+	form := &components.Form{
+		Items:     formItems,
+		Action:    fmt.Sprintf("/admin/%s/import-mapping/%s", resource, fileID),
+		Method:    "POST",
+		Submit:    "Preview",
+		Multipart: true,
+	}
+	return NewImportMappingResponse(headerText, currentUser, headerContent, listing, form)
+}
+
+// NewImportPreviewResponse is a constructor for the ImportMappingResponse struct of the resource import preview.
+// The listing contains the mapped fields and the dry run result of the first checked rows,
+// and the form passes the mapping to the import in hidden inputs.
+func NewImportPreviewResponse(currentUser *model.User, resource, label string, fields []string, fileID string, mapping parser.ImportMapping, listSeparator string, hasHeader bool, options parser.ImportOptions, rows []*ImportPreviewRow) *ImportMappingResponse {
+	failed := 0
+	for _, row := range rows {
+		if row.Failed {
+			failed++
+		}
+	}
+	headerText := fmt.Sprintf("Import %s Preview: %d of %d rows are ready", label, len(rows)-failed, len(rows))
+	headerContent := components.NewContentHeader(headerText, []*components.Link{
+		components.NewLink("Back to the mapping", fmt.Sprintf("/admin/%s/import-mapping/%s?%s", resource, fileID, importMappingQuery(hasHeader, options))),
+	})
+	listingRows := components.ListingRows{}
+	for i := 0; i < importCheckedRows && i < len(rows); i++ {
+		columns := components.ListingColumns{
+			&components.ListingColumn{Values: &components.ListingColumnValues{{Value: strconv.Itoa(rows[i].Number)}}},
+		}
+		for _, field := range fields {
+			columns = append(columns, &components.ListingColumn{Values: &components.ListingColumnValues{{Value: rows[i].RowData[field]}}})
+		}
+		columns = append(columns, &components.ListingColumn{Values: &components.ListingColumnValues{{Value: rows[i].Result}}})
+		listingRows = append(listingRows, &components.ListingRow{Columns: &columns})
+	}
+	listing := &components.Listing{
+		Header: &components.ListingHeader{Headers: append(append([]string{"Row"}, fields...), "Result")},
+		Rows:   &listingRows,
+	}
+	formItems := []*components.FormItem{
+		components.NewFormItem("", "file_id", "hidden", fileID, true, nil, nil),
+	}
+	for _, field := range fields {
+		columnIndex := ""
+		customValue := ""
+		if rule, ok := mapping[field]; ok && rule.ColumnIndex != -1 {
+			columnIndex = strconv.Itoa(rule.ColumnIndex)
+		} else if ok {
+			customValue = rule.CustomValue
+		}
+		formItems = append(formItems,
+			components.NewFormItem("", field, "hidden", columnIndex, false, nil, nil),
+			components.NewFormItem("", field+"_custom", "hidden", customValue, false, nil, nil),
+		)
+	}
+	formItems = append(formItems, components.NewFormItem("", "list_separator", "hidden", listSeparator, false, nil, nil))
+	formItems = append(formItems, importOptionsFormItems(hasHeader, options)...)
+	form := &components.Form{
+		Items:     formItems,
+		Action:    fmt.Sprintf("/admin/%s/import-commit/%s", resource, fileID),
+		Method:    "POST",
+		Submit:    "Import",
+		Multipart: true,
+	}
+	return NewImportMappingResponse(headerText, currentUser, headerContent, listing, form)
+}
+
+// importMappingQuery returns the query of the mapping page with the header flag and the parser options.
+func importMappingQuery(hasHeader bool, options parser.ImportOptions) string {
+	query := url.Values{}
+	for name, value := range map[string]string{"format": options.Format, "delimiter": options.Delimiter, "encoding": options.Encoding, "sheet": options.Sheet} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	if hasHeader {
+		query.Set("has_header", "true")
+	}
+	return query.Encode()
+}
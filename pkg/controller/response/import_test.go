@@ -0,0 +1,95 @@
+package response
+
+import (
+	"testing"
+
+	"github.com/akosgarai/projectregister/pkg/parser"
+	"github.com/akosgarai/projectregister/pkg/testhelper"
+)
+
+// TestNewImportUploadFormResponse is a test function for the NewImportUploadFormResponse function.
+// It tests the response generation.
+func TestNewImportUploadFormResponse(t *testing.T) {
+	testUser := testhelper.GetUserWithAccessToResources(1, []string{"servers.create"})
+	response := NewImportUploadFormResponse(testUser, "server", "Servers")
+	if response.Title != "Import Servers" {
+		t.Errorf("Title is not set properly. Got: %s", response.Title)
+	}
+	if response.CurrentUser != testUser {
+		t.Errorf("User is not set properly. Got: %v", response.CurrentUser)
+	}
+	if response.Form.Action != "/admin/server/import" || !response.Form.Multipart {
+		t.Errorf("Form is not set properly. Got: %v", response.Form)
+	}
+	// the file, the header flag, the delimiter, the encoding and the sheet.
+	if len(response.Form.Items) != 5 {
+		t.Errorf("Form items are not set properly. Got: %v", response.Form.Items)
+	}
+}
+
+// TestNewImportMappingFormResponse is a test function for the NewImportMappingFormResponse function.
+// It tests that the columns with the field names are selected.
+func TestNewImportMappingFormResponse(t *testing.T) {
+	testUser := testhelper.GetUserWithAccessToResources(1, []string{"users.create"})
+	fields := []string{"name", "email", "role", "password"}
+	data := [][]string{{"Admin", "admin@example.com", "Admin"}}
+	response := NewImportMappingFormResponse(testUser, "user", "Users", fields, "test-identifier", data, []string{"Email", "Name"}, true, parser.ImportOptions{Format: parser.FormatXLSX})
+	if response.Title != "Import Users Mapping" {
+		t.Errorf("Title is not set properly. Got: %s", response.Title)
+	}
+	if response.Form.Action != "/admin/user/import-mapping/test-identifier" {
+		t.Errorf("Form action is not set properly. Got: %s", response.Form.Action)
+	}
+	// the file id, 4 column selects, 4 custom values, the list separator and 5 hidden options.
+	if len(response.Form.Items) != 15 {
+		t.Errorf("Form items are not set properly. Got: %v", response.Form.Items)
+	}
+	if len(*response.Listing.Rows) != len(data) {
+		t.Errorf("Invalid preview list length. Got: %d instead of %d", len(*response.Listing.Rows), len(data))
+	}
+	expectedSelected := map[string]string{"name": "Name", "email": "Email"}
+	for _, item := range response.Form.Items {
+		if item.Type != "select" {
+			continue
+		}
+		for _, option := range item.Options {
+			if option.Selected != (expectedSelected[item.Name] == option.Value) {
+				t.Errorf("Invalid selection of the %s option of %s. Got: %t", option.Value, item.Name, option.Selected)
+			}
+		}
+	}
+}
+
+// TestNewImportPreviewResponse is a test function for the NewImportPreviewResponse function.
+// It tests the summary of the checked rows, and that the mapping is passed in the hidden inputs.
+func TestNewImportPreviewResponse(t *testing.T) {
+	testUser := testhelper.GetUserWithAccessToResources(1, []string{"servers.create"})
+	fields := []string{"name", "runtimes"}
+	mapping := parser.NewImportMapping(fields)
+	mapping["name"].ColumnIndex = 0
+	mapping["runtimes"].CustomValue = "php8"
+	rows := []*ImportPreviewRow{
+		{Number: 2, RowData: map[string]string{"name": "web1", "runtimes": "php8"}, Result: "server web1 will be created"},
+		{Number: 3, RowData: map[string]string{"name": "web2", "runtimes": "php8"}, Result: "server web2 already exists", Failed: true},
+	}
+	response := NewImportPreviewResponse(testUser, "server", "Servers", fields, "test-identifier", mapping, ",", true, parser.ImportOptions{Format: parser.FormatCSV}, rows)
+	if response.Title != "Import Servers Preview: 1 of 2 rows are ready" {
+		t.Errorf("Title is not set properly. Got: %s", response.Title)
+	}
+	if response.Form.Action != "/admin/server/import-commit/test-identifier" {
+		t.Errorf("Form action is not set properly. Got: %s", response.Form.Action)
+	}
+	if len(*response.Listing.Rows) != len(rows) || len(response.Listing.Header.Headers) != len(fields)+2 {
+		t.Errorf("Listing is not set properly. Got: %v", response.Listing)
+	}
+	hidden := map[string]string{}
+	for _, item := range response.Form.Items {
+		hidden[item.Name] = item.Value
+	}
+	if hidden["name"] != "0" || hidden["name_custom"] != "" || hidden["runtimes"] != "" || hidden["runtimes_custom"] != "php8" {
+		t.Errorf("The mapping is not set properly. Got: %v", hidden)
+	}
+	if hidden["list_separator"] != "," || hidden["has_header"] != "true" || hidden["format"] != parser.FormatCSV {
+		t.Errorf("The options are not set properly. Got: %v", hidden)
+	}
+}
@@ -0,0 +1,138 @@
+package response
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/akosgarai/projectregister/pkg/controller/response/components"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// jobTypeLabels maps the job types to the displayed names.
+var jobTypeLabels = map[string]string{
+	model.JobTypeApplicationImport: "Application import",
+	model.JobTypeDomainCheck:       "Domain check",
+	model.JobTypeResourceImport:    "Resource import",
+}
+
+// jobTypeLabel returns the displayed name of the job type. The unknown types are displayed as they are.
+func jobTypeLabel(jobType string) string {
+	if label, ok := jobTypeLabels[jobType]; ok {
+		return label
+	}
+	return jobType
+}
+
+// jobProgress returns the progress of the job in the "done / total (percent%)" format.
+func jobProgress(job *model.Job) string {
+	return fmt.Sprintf("%d / %d (%d%%)", job.Progress, job.Total, job.Percent())
+}
+
+// JobStatusOptions returns the status options of the job filter.
+// The keys are the 1-based indexes of the model.JobStatuses list.
+func JobStatusOptions() map[int64]string {
+	options := map[int64]string{}
+	for index, status := range model.JobStatuses {
+		options[int64(index+1)] = status
+	}
+	return options
+}
+
+// NewJobDetailResponse is a constructor for the DetailResponse struct for a job.
+// The retry and the cancel actions are displayed if the job is in the proper status.
+func NewJobDetailResponse(currentUser *model.User, job *model.Job) *DetailResponse {
+	headerText := "Job Detail"
+	jobURL := fmt.Sprintf("/admin/job/view/%d", job.ID)
+	headerButtons := []*components.Link{components.NewLink("Refresh", jobURL)}
+	if currentUser.HasPrivilege("jobs.update") {
+		if job.CanRetry() {
+			headerButtons = append(headerButtons, components.NewFormLink("Retry", fmt.Sprintf("/admin/job/retry/%d", job.ID)))
+		}
+		if job.CanCancel() {
+			headerButtons = append(headerButtons, components.NewFormLink("Cancel", fmt.Sprintf("/admin/job/cancel/%d", job.ID)))
+		}
+	}
+	headerButtons = append(headerButtons, components.NewLink("List", "/admin/job/list"))
+	headerContent := components.NewContentHeader(headerText, headerButtons)
+	createdBy := &components.DetailValue{Value: "system"}
+	if job.CreatedBy != 0 {
+		createdBy = &components.DetailValue{Value: fmt.Sprintf("%d", job.CreatedBy), Link: fmt.Sprintf("/admin/user/view/%d", job.CreatedBy)}
+	}
+	logValues := components.DetailValues{}
+	for _, line := range strings.Split(strings.TrimSuffix(job.Log, "\n"), "\n") {
+		logValues = append(logValues, &components.DetailValue{Value: line})
+	}
+	details := &components.DetailItems{
+		{Label: "ID", Value: &components.DetailValues{{Value: fmt.Sprintf("%d", job.ID)}}},
+		{Label: "Type", Value: &components.DetailValues{{Value: jobTypeLabel(job.Type)}}},
+		{Label: "Status", Value: &components.DetailValues{{Value: job.Status}}},
+		{Label: "Progress", Value: &components.DetailValues{{Value: jobProgress(job)}}},
+		{Label: "Attempts", Value: &components.DetailValues{{Value: fmt.Sprintf("%d", job.Attempts)}}},
+		{Label: "Error", Value: &components.DetailValues{{Value: job.Error}}},
+		{Label: "Created By", Value: &components.DetailValues{createdBy}},
+		{Label: "Created At", Value: &components.DetailValues{{Value: job.CreatedAt}}},
+		{Label: "Started At", Value: &components.DetailValues{{Value: job.StartedAt}}},
+		{Label: "Finished At", Value: &components.DetailValues{{Value: job.FinishedAt}}},
+		{Label: "Log", Value: &logValues},
+	}
+	return NewDetailResponse(headerText, currentUser, headerContent, details)
+}
+
+// NewJobListResponse is a constructor for the ListingResponse struct of the jobs.
+// The jobs are expected to be ordered by the id, the latest is the first.
+func NewJobListResponse(currentUser *model.User, jobs *model.Jobs, filter *model.JobFilter) *ListingResponse {
+	headerText := "Job List"
+	headerContent := components.NewContentHeader(headerText, []*components.Link{})
+	listingHeader := &components.ListingHeader{
+		Headers: []string{"ID", "Type", "Status", "Progress", "Attempts", "Created At", "Finished At", "Actions"},
+	}
+	// create the rows
+	listingRows := components.ListingRows{}
+	userCanEdit := currentUser.HasPrivilege("jobs.update")
+	for _, job := range *jobs {
+		columns := components.ListingColumns{}
+		idColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: fmt.Sprintf("%d", job.ID)}}}
+		columns = append(columns, idColumn)
+		typeColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: jobTypeLabel(job.Type)}}}
+		columns = append(columns, typeColumn)
+		statusColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: job.Status}}}
+		columns = append(columns, statusColumn)
+		progressColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: jobProgress(job)}}}
+		columns = append(columns, progressColumn)
+		attemptsColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: fmt.Sprintf("%d", job.Attempts)}}}
+		columns = append(columns, attemptsColumn)
+		createdAtColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: job.CreatedAt}}}
+		columns = append(columns, createdAtColumn)
+		finishedAtColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: job.FinishedAt}}}
+		columns = append(columns, finishedAtColumn)
+		actionsColumn := components.ListingColumn{Values: &components.ListingColumnValues{
+			{Value: "View", Link: fmt.Sprintf("/admin/job/view/%d", job.ID)},
+		}}
+		if userCanEdit && job.CanRetry() {
+			*actionsColumn.Values = append(*actionsColumn.Values, &components.ListingColumnValue{Value: "Retry", Link: fmt.Sprintf("/admin/job/retry/%d", job.ID), Form: true})
+		}
+		if userCanEdit && job.CanCancel() {
+			*actionsColumn.Values = append(*actionsColumn.Values, &components.ListingColumnValue{Value: "Cancel", Link: fmt.Sprintf("/admin/job/cancel/%d", job.ID), Form: true})
+		}
+		columns = append(columns, &actionsColumn)
+
+		listingRows = append(listingRows, &components.ListingRow{Columns: &columns})
+	}
+	/* Create the search form. The only form item is the status. */
+	selectedStatuses := []int64{}
+	for index, status := range model.JobStatuses {
+		if status == filter.Status {
+			selectedStatuses = append(selectedStatuses, int64(index+1))
+		}
+	}
+	formItems := []*components.FormItem{
+		components.NewFormItem("Status", "status", "select", "", false, JobStatusOptions(), selectedStatuses),
+	}
+	form := &components.Form{
+		Items:  formItems,
+		Action: "/admin/job/list",
+		Method: "POST",
+		Submit: "Search",
+	}
+	return NewListingResponse(headerText, currentUser, headerContent, &components.Listing{Header: listingHeader, Rows: &listingRows}, form)
+}
@@ -0,0 +1,68 @@
+package response
+
+import (
+	"testing"
+
+	"github.com/akosgarai/projectregister/pkg/model"
+	"github.com/akosgarai/projectregister/pkg/testhelper"
+)
+
+// TestNewJobDetailResponse is a test function for the NewJobDetailResponse function.
+// It tests that the actions depend on the status of the job and the privileges of the user.
+func TestNewJobDetailResponse(t *testing.T) {
+	testData := []struct {
+		status          string
+		privileges      []string
+		expectedButtons []string
+	}{
+		{model.JobStatusRunning, []string{"jobs.view", "jobs.update"}, []string{"Refresh", "Cancel", "List"}},
+		{model.JobStatusFailed, []string{"jobs.view", "jobs.update"}, []string{"Refresh", "Retry", "List"}},
+		{model.JobStatusFailed, []string{"jobs.view"}, []string{"Refresh", "List"}},
+		{model.JobStatusSucceeded, []string{"jobs.view", "jobs.update"}, []string{"Refresh", "List"}},
+	}
+	for _, tt := range testData {
+		job := &model.Job{ID: 1, Type: model.JobTypeDomainCheck, Status: tt.status, Progress: 1, Total: 4, Log: "first\nsecond\n"}
+		response := NewJobDetailResponse(testhelper.GetUserWithAccessToResources(1, tt.privileges), job)
+		if len(response.Header.Buttons) != len(tt.expectedButtons) {
+			t.Fatalf("Invalid buttons for %s. Got: %v", tt.status, response.Header.Buttons)
+		}
+		for index, text := range tt.expectedButtons {
+			if response.Header.Buttons[index].Text != text {
+				t.Errorf("Expected %s button, got %s", text, response.Header.Buttons[index].Text)
+			}
+		}
+		details := *response.Details
+		if (*details[3].Value)[0].Value != "1 / 4 (25%)" {
+			t.Errorf("Invalid progress. Got: %s", (*details[3].Value)[0].Value)
+		}
+		if len(*details[10].Value) != 2 {
+			t.Errorf("Expected 2 log lines. Got: %v", details[10].Value)
+		}
+	}
+}
+
+// TestNewJobListResponse is a test function for the NewJobListResponse function.
+// It tests the response generation.
+func TestNewJobListResponse(t *testing.T) {
+	jobs := &model.Jobs{
+		{ID: 2, Type: model.JobTypeApplicationImport, Status: model.JobStatusQueued},
+		{ID: 1, Type: model.JobTypeDomainCheck, Status: model.JobStatusSucceeded},
+	}
+	filter := model.NewJobFilter()
+	filter.Status = model.JobStatusQueued
+	testUser := testhelper.GetUserWithAccessToResources(1, []string{"jobs.view", "jobs.update"})
+	response := NewJobListResponse(testUser, jobs, filter)
+	if response.Title != "Job List" {
+		t.Errorf("Title is not set properly. Got: %s", response.Title)
+	}
+	if len(*response.Listing.Rows) != 2 {
+		t.Errorf("Rows are not set properly. Got: %v", response.Listing.Rows)
+	}
+	actions := *(*(*response.Listing.Rows)[0].Columns)[7].Values
+	if len(actions) != 2 || actions[1].Value != "Cancel" || !actions[1].Form {
+		t.Errorf("Expected view and cancel actions. Got: %v", actions)
+	}
+	if !response.Form.Items[0].Options[1].Selected {
+		t.Errorf("The queued status is not selected.")
+	}
+}
@@ -0,0 +1,51 @@
+package response
+
+import (
+	"fmt"
+
+	"github.com/akosgarai/projectregister/pkg/controller/response/components"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+const (
+	// NotificationDeliveryImmediate is the option key of the immediate delivery.
+	NotificationDeliveryImmediate = 1
+	// NotificationDeliveryDigest is the option key of the daily digest delivery.
+	NotificationDeliveryDigest = 2
+)
+
+// NewNotificationSettingsResponse is a constructor for the FormResponse struct for the notification settings of the current user.
+// Every rule has a subscription checkbox, a threshold field if the rule has threshold, and a delivery select.
+func NewNotificationSettingsResponse(currentUser *model.User, subscriptions model.NotificationSubscriptions) *FormResponse {
+	title := "Notification Settings"
+	headerContent := components.NewContentHeader(title, []*components.Link{})
+	formItems := []*components.FormItem{}
+	deliveryOptions := map[int64]string{NotificationDeliveryImmediate: "Immediately", NotificationDeliveryDigest: "Daily digest"}
+	for index, rule := range model.NotificationRules {
+		// the option keys are the 1-based rule indexes, so that the checkbox ids are unique on the page.
+		optionKey := int64(index + 1)
+		subscription := subscriptions.ByRule(rule.Name)
+		selectedSubscribed := []int64{}
+		threshold := rule.DefaultThreshold
+		selectedDelivery := []int64{NotificationDeliveryImmediate}
+		if subscription != nil {
+			selectedSubscribed = append(selectedSubscribed, optionKey)
+			threshold = subscription.Threshold
+			if subscription.Digest {
+				selectedDelivery = []int64{NotificationDeliveryDigest}
+			}
+		}
+		formItems = append(formItems, components.NewFormItem(rule.Label, "rule_"+rule.Name, "checkboxgroup", "", false, map[int64]string{optionKey: "Subscribed"}, selectedSubscribed))
+		if rule.HasThreshold() {
+			formItems = append(formItems, components.NewFormItem(rule.ThresholdLabel, "threshold_"+rule.Name, "number", fmt.Sprintf("%d", threshold), false, nil, nil))
+		}
+		formItems = append(formItems, components.NewFormItem("Delivery", "delivery_"+rule.Name, "select", "", false, deliveryOptions, selectedDelivery))
+	}
+	form := &components.Form{
+		Items:  formItems,
+		Action: "/admin/notification/settings",
+		Method: "POST",
+		Submit: "Save",
+	}
+	return NewFormResponse(title, currentUser, headerContent, form)
+}
@@ -0,0 +1,27 @@
+package response
+
+import (
+	"testing"
+
+	"github.com/akosgarai/projectregister/pkg/model"
+	"github.com/akosgarai/projectregister/pkg/testhelper"
+)
+
+// TestNewNotificationSettingsResponse is a test function for the NewNotificationSettingsResponse function.
+// It tests the response generation.
+func TestNewNotificationSettingsResponse(t *testing.T) {
+	testUser := testhelper.GetUserWithAccessToResources(1, []string{})
+	subscriptions := model.NotificationSubscriptions{
+		{UserID: 1, Rule: model.NotificationRuleCertificateExpiry, Threshold: 30, Digest: true},
+	}
+	response := NewNotificationSettingsResponse(testUser, subscriptions)
+	if response.Title != "Notification Settings" {
+		t.Errorf("Title is not set properly. Got: %s", response.Title)
+	}
+	if len(response.Form.Items) != 8 {
+		t.Errorf("Form items are not set properly. Got: %v", response.Form.Items)
+	}
+	if response.Form.Items[1].Value != "30" {
+		t.Errorf("The threshold is not set properly. Got: %s", response.Form.Items[1].Value)
+	}
+}
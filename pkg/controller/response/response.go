@@ -27,6 +27,8 @@ func NewResponse(title string, user *model.User, header *components.ContentHeade
 			sideMenu = append(sideMenu, components.NewLink(resource, "/admin/"+resource+"/list"))
 		}
 	}
+	// every user could manage the own notification settings.
+	sideMenu = append(sideMenu, components.NewLink("notifications", "/admin/notification/settings"))
 
 	return &Response{
 		Title:       title,
@@ -101,17 +103,17 @@ func NewFormResponse(title string, currentUser *model.User, header *components.C
 	}
 }
 
-// ApplicationImportMappingResponse is the struct for the application import mapping page.
+// ImportMappingResponse is the struct for the import mapping and preview pages.
 // It contains the preview listing and the mapping form.
-type ApplicationImportMappingResponse struct {
+type ImportMappingResponse struct {
 	*Response
 	Listing *components.Listing
 	Form    *components.Form
 }
 
-// NewApplicationImportMappingResponse is a constructor for the ApplicationImportMappingResponse struct.
-func NewApplicationImportMappingResponse(title string, currentUser *model.User, header *components.ContentHeader, previewListing *components.Listing, mappingForm *components.Form) *ApplicationImportMappingResponse {
-	return &ApplicationImportMappingResponse{
+// NewImportMappingResponse is a constructor for the ImportMappingResponse struct.
+func NewImportMappingResponse(title string, currentUser *model.User, header *components.ContentHeader, previewListing *components.Listing, mappingForm *components.Form) *ImportMappingResponse {
+	return &ImportMappingResponse{
 		Response: NewResponse(title, currentUser, header),
 		Listing:  previewListing,
 		Form:     mappingForm,
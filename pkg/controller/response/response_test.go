@@ -153,9 +153,9 @@ func TestNewFormResponse(t *testing.T) {
 	}
 }
 
-// TestNewApplicationImportMappingResponse is a test function for the NewApplicationImportMappingResponse function.
+// TestNewImportMappingResponse is a test function for the NewImportMappingResponse function.
 // It tests the response generation.
-func TestNewApplicationImportMappingResponse(t *testing.T) {
+func TestNewImportMappingResponse(t *testing.T) {
 	title := "title"
 	testUser := testhelper.GetUserWithAccessToResources(1, []string{"users.view"})
 	header := components.NewContentHeader("header", []*components.Link{})
@@ -167,7 +167,7 @@ func TestNewApplicationImportMappingResponse(t *testing.T) {
 	mappingForm := &components.Form{
 		Items: formItems,
 	}
-	response := NewApplicationImportMappingResponse(title, testUser, header, listing, mappingForm)
+	response := NewImportMappingResponse(title, testUser, header, listing, mappingForm)
 	if response.Title != title {
 		t.Errorf("Title is not set properly. Got: %s", response.Title)
 	}
@@ -0,0 +1,90 @@
+package response
+
+import (
+	"fmt"
+
+	"github.com/akosgarai/projectregister/pkg/controller/response/components"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// scheduledTaskNextRun returns the displayed next run of the task. The disabled tasks are not scheduled.
+func scheduledTaskNextRun(task *model.ScheduledTask) string {
+	if !task.Enabled {
+		return "disabled"
+	}
+	return task.NextRunAt
+}
+
+// NewUpdateScheduledTaskResponse is a constructor for the FormResponse struct for the scheduled task update page.
+// Only the schedule and the enabled flag could be changed.
+func NewUpdateScheduledTaskResponse(currentUser *model.User, task *model.ScheduledTask) *FormResponse {
+	title := "Update Scheduled Task " + task.Name
+	headerContent := components.NewContentHeader(title, []*components.Link{components.NewLink("List", "/admin/scheduled-task/list")})
+	selectedEnabled := []int64{}
+	if task.Enabled {
+		selectedEnabled = append(selectedEnabled, 1)
+	}
+	formItems := []*components.FormItem{
+		// Schedule.
+		components.NewFormItem("Schedule (minute hour day month weekday)", "schedule", "text", task.Schedule, true, nil, nil),
+		// Enabled.
+		components.NewFormItem("Enabled", "enabled", "checkboxgroup", "", false, map[int64]string{1: "Enabled"}, selectedEnabled),
+	}
+	form := &components.Form{
+		Items:  formItems,
+		Action: fmt.Sprintf("/admin/scheduled-task/update/%d", task.ID),
+		Method: "POST",
+		Submit: "Update",
+	}
+	return NewFormResponse(title, currentUser, headerContent, form)
+}
+
+// NewScheduledTaskListResponse is a constructor for the ListingResponse struct of the scheduled tasks.
+// The outcome of the last run and the next run are displayed for every task.
+func NewScheduledTaskListResponse(currentUser *model.User, tasks *model.ScheduledTasks) *ListingResponse {
+	headerText := "Scheduled Task List"
+	headerContent := components.NewContentHeader(headerText, []*components.Link{})
+	listingHeader := &components.ListingHeader{
+		Headers: []string{"Name", "Description", "Schedule", "Last Run", "Status", "Duration", "Outcome", "Next Run", "Actions"},
+	}
+	// create the rows
+	listingRows := components.ListingRows{}
+	userCanEdit := currentUser.HasPrivilege("scheduled_tasks.update")
+	for _, task := range *tasks {
+		columns := components.ListingColumns{}
+		nameColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: task.Name}}}
+		columns = append(columns, nameColumn)
+		descriptionColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: task.Description}}}
+		columns = append(columns, descriptionColumn)
+		scheduleColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: task.Schedule}}}
+		columns = append(columns, scheduleColumn)
+		lastRunColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: task.LastRunAt}}}
+		columns = append(columns, lastRunColumn)
+		statusColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: task.LastStatus}}}
+		columns = append(columns, statusColumn)
+		// the duration is not known until the first run is finished.
+		duration := ""
+		if task.LastStatus != "" && task.LastStatus != model.ScheduledTaskStatusRunning {
+			duration = fmt.Sprintf("%d ms", task.LastDurationMs)
+		}
+		durationColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: duration}}}
+		columns = append(columns, durationColumn)
+		outcomeColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: task.LastMessage}}}
+		columns = append(columns, outcomeColumn)
+		nextRunColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: scheduledTaskNextRun(task)}}}
+		columns = append(columns, nextRunColumn)
+		actionsColumn := components.ListingColumn{Values: &components.ListingColumnValues{}}
+		if userCanEdit {
+			*actionsColumn.Values = append(*actionsColumn.Values, &components.ListingColumnValue{Value: "Update", Link: fmt.Sprintf("/admin/scheduled-task/update/%d", task.ID)})
+			if task.Enabled {
+				*actionsColumn.Values = append(*actionsColumn.Values, &components.ListingColumnValue{Value: "Disable", Link: fmt.Sprintf("/admin/scheduled-task/disable/%d", task.ID), Form: true})
+			} else {
+				*actionsColumn.Values = append(*actionsColumn.Values, &components.ListingColumnValue{Value: "Enable", Link: fmt.Sprintf("/admin/scheduled-task/enable/%d", task.ID), Form: true})
+			}
+		}
+		columns = append(columns, &actionsColumn)
+
+		listingRows = append(listingRows, &components.ListingRow{Columns: &columns})
+	}
+	return NewListingResponse(headerText, currentUser, headerContent, &components.Listing{Header: listingHeader, Rows: &listingRows}, nil)
+}
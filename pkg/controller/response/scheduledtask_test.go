@@ -0,0 +1,69 @@
+package response
+
+import (
+	"testing"
+
+	"github.com/akosgarai/projectregister/pkg/model"
+	"github.com/akosgarai/projectregister/pkg/testhelper"
+)
+
+// TestNewScheduledTaskListResponse is a test function for the NewScheduledTaskListResponse function.
+// It tests that the enabled tasks could be disabled and the disabled ones could be enabled.
+func TestNewScheduledTaskListResponse(t *testing.T) {
+	tasks := &model.ScheduledTasks{
+		{ID: 1, Name: model.ScheduledTaskDomainChecks, Schedule: "0 3 * * *", Enabled: true, LastStatus: model.ScheduledTaskStatusSucceeded, LastDurationMs: 12, NextRunAt: "2024-05-16 03:00"},
+		{ID: 2, Name: model.ScheduledTaskUploadCleanup, Schedule: "30 * * * *", Enabled: false},
+	}
+	testUser := testhelper.GetUserWithAccessToResources(1, []string{"scheduled_tasks.view", "scheduled_tasks.update"})
+	response := NewScheduledTaskListResponse(testUser, tasks)
+	if response.Title != "Scheduled Task List" {
+		t.Errorf("Title is not set properly. Got: %s", response.Title)
+	}
+	rows := *response.Listing.Rows
+	if len(rows) != 2 {
+		t.Fatalf("Rows are not set properly. Got: %v", rows)
+	}
+	testData := []struct {
+		row              int
+		expectedDuration string
+		expectedNextRun  string
+		expectedAction   string
+	}{
+		{0, "12 ms", "2024-05-16 03:00", "Disable"},
+		{1, "", "disabled", "Enable"},
+	}
+	for _, tt := range testData {
+		columns := *rows[tt.row].Columns
+		if (*columns[5].Values)[0].Value != tt.expectedDuration {
+			t.Errorf("Expected duration %s, got %s", tt.expectedDuration, (*columns[5].Values)[0].Value)
+		}
+		if (*columns[7].Values)[0].Value != tt.expectedNextRun {
+			t.Errorf("Expected next run %s, got %s", tt.expectedNextRun, (*columns[7].Values)[0].Value)
+		}
+		actions := *columns[8].Values
+		if len(actions) != 2 || actions[1].Value != tt.expectedAction || !actions[1].Form {
+			t.Errorf("Expected update and %s actions. Got: %v", tt.expectedAction, actions)
+		}
+	}
+	// without update privilege there is no action.
+	response = NewScheduledTaskListResponse(testhelper.GetUserWithAccessToResources(1, []string{"scheduled_tasks.view"}), tasks)
+	if actions := *(*(*response.Listing.Rows)[0].Columns)[8].Values; len(actions) != 0 {
+		t.Errorf("Expected no actions. Got: %v", actions)
+	}
+}
+
+// TestNewUpdateScheduledTaskResponse is a test function for the NewUpdateScheduledTaskResponse function.
+func TestNewUpdateScheduledTaskResponse(t *testing.T) {
+	task := &model.ScheduledTask{ID: 3, Name: model.ScheduledTaskStatisticsSnapshot, Schedule: "0 0 * * *", Enabled: true}
+	testUser := testhelper.GetUserWithAccessToResources(1, []string{"scheduled_tasks.view", "scheduled_tasks.update"})
+	response := NewUpdateScheduledTaskResponse(testUser, task)
+	if response.Form.Action != "/admin/scheduled-task/update/3" {
+		t.Errorf("Action is not set properly. Got: %s", response.Form.Action)
+	}
+	if response.Form.Items[0].Value != "0 0 * * *" {
+		t.Errorf("Schedule is not set properly. Got: %s", response.Form.Items[0].Value)
+	}
+	if !response.Form.Items[1].Options[1].Selected {
+		t.Errorf("The enabled option is not selected.")
+	}
+}
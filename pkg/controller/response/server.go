@@ -88,6 +88,7 @@ func NewServerListResponse(currentUser *model.User, servers *model.Servers, pool
 	headerContent := components.NewContentHeader(headerText, []*components.Link{})
 	if currentUser.HasPrivilege("servers.create") {
 		headerContent.Buttons = append(headerContent.Buttons, components.NewLink("Create", "/admin/server/create"))
+		headerContent.Buttons = append(headerContent.Buttons, components.NewLink("Import", "/admin/server/import"))
 	}
 	listingHeader := &components.ListingHeader{
 		Headers: []string{"ID", "Name", "Remote Address", "Description", "Actions"},
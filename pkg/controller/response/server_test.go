@@ -100,7 +100,7 @@ func TestNewServerListResponse(t *testing.T) {
 	if response.Header.Title != "Server List" {
 		t.Errorf("Header is not set properly. Got: %v", response.Header)
 	}
-	if len(response.Header.Buttons) != 1 {
+	if len(response.Header.Buttons) != 2 {
 		t.Errorf("Header buttons are not set properly. Got: %v", response.Header.Buttons)
 	}
 }
@@ -70,6 +70,7 @@ func NewUserListResponse(currentUser *model.User, users []*model.User, roles *mo
 	headerContent := components.NewContentHeader(headerText, []*components.Link{})
 	if currentUser.HasPrivilege("users.create") {
 		headerContent.Buttons = append(headerContent.Buttons, components.NewLink("Create", "/admin/user/create"))
+		headerContent.Buttons = append(headerContent.Buttons, components.NewLink("Import", "/admin/user/import"))
 	}
 	listingHeader := &components.ListingHeader{
 		Headers: []string{"ID", "Name", "Email", "Role", "Actions"},
@@ -92,7 +92,7 @@ func TestNewUserListResponse(t *testing.T) {
 	if response.Header.Title != "User List" {
 		t.Errorf("Header is not set properly. Got: %v", response.Header)
 	}
-	if len(response.Header.Buttons) != 1 {
+	if len(response.Header.Buttons) != 2 {
 		t.Errorf("Header buttons are not set properly. Got: %v", response.Header.Buttons)
 	}
 }
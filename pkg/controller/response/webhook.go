@@ -0,0 +1,190 @@
+package response
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/akosgarai/projectregister/pkg/controller/response/components"
+	"github.com/akosgarai/projectregister/pkg/event"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// WebhookEventTypeOptions returns the event type options of the webhook form.
+// The keys are the 1-based indexes of the event.Types list.
+func WebhookEventTypeOptions() map[int64]string {
+	options := map[int64]string{}
+	for index, eventType := range event.Types() {
+		options[int64(index+1)] = eventType
+	}
+	return options
+}
+
+// NewWebhookDetailResponse is a constructor for the DetailResponse struct for a webhook.
+// The secret is not displayed.
+func NewWebhookDetailResponse(currentUser *model.User, webhook *model.Webhook) *DetailResponse {
+	headerText := "Webhook Detail"
+	headerButtons := newDetailHeaderButtons(currentUser, "webhooks", fmt.Sprintf("%d", webhook.ID))
+	headerButtons = append(headerButtons, components.NewLink("Deliveries", fmt.Sprintf("/admin/webhook/deliveries/%d", webhook.ID)))
+	headerContent := components.NewContentHeader(headerText, headerButtons)
+	eventTypeValues := components.DetailValues{}
+	for _, eventType := range webhook.EventTypes {
+		eventTypeValues = append(eventTypeValues, &components.DetailValue{Value: eventType})
+	}
+	secret := "not set"
+	if webhook.Secret != "" {
+		secret = "set"
+	}
+	details := &components.DetailItems{
+		{Label: "ID", Value: &components.DetailValues{{Value: fmt.Sprintf("%d", webhook.ID)}}},
+		{Label: "Name", Value: &components.DetailValues{{Value: webhook.Name}}},
+		{Label: "URL", Value: &components.DetailValues{{Value: webhook.URL}}},
+		{Label: "Event Types", Value: &eventTypeValues},
+		{Label: "Secret", Value: &components.DetailValues{{Value: secret}}},
+		{Label: "Active", Value: &components.DetailValues{{Value: fmt.Sprintf("%t", webhook.Active)}}},
+		{Label: "Created At", Value: &components.DetailValues{{Value: webhook.CreatedAt}}},
+		{Label: "Updated At", Value: &components.DetailValues{{Value: webhook.UpdatedAt}}},
+	}
+	return NewDetailResponse(headerText, currentUser, headerContent, details)
+}
+
+// newWebhookFormResponse is a constructor for the FormResponse struct for a webhook.
+// The secret is required only for the new webhooks, on update the empty value keeps the current secret.
+func newWebhookFormResponse(title string, currentUser *model.User, webhook *model.Webhook, action, method, submitLabel string) *FormResponse {
+	headerContent := components.NewContentHeader(title, []*components.Link{components.NewLink("List", "/admin/webhook/list")})
+	selectedEventTypes := []int64{}
+	for index, eventType := range event.Types() {
+		if webhook.Subscribes(eventType) {
+			selectedEventTypes = append(selectedEventTypes, int64(index+1))
+		}
+	}
+	selectedActive := []int64{}
+	if webhook.Active {
+		selectedActive = append(selectedActive, 1)
+	}
+	secretLabel := "Secret"
+	if webhook.ID != 0 {
+		secretLabel = "Secret (leave empty to keep the current one)"
+	}
+	formItems := []*components.FormItem{
+		// Name.
+		components.NewFormItem("Name", "name", "text", webhook.Name, true, nil, nil),
+		// URL.
+		components.NewFormItem("URL", "url", "text", webhook.URL, true, nil, nil),
+		// Secret.
+		components.NewFormItem(secretLabel, "secret", "password", "", webhook.ID == 0, nil, nil),
+		// Event types.
+		components.NewFormItem("Event Types", "event_types", "checkboxgroup", "", true, WebhookEventTypeOptions(), selectedEventTypes),
+		// Active.
+		components.NewFormItem("Active", "active", "checkboxgroup", "", false, map[int64]string{1: "Active"}, selectedActive),
+	}
+	form := &components.Form{
+		Items:  formItems,
+		Action: action,
+		Method: method,
+		Submit: submitLabel,
+	}
+	return NewFormResponse(title, currentUser, headerContent, form)
+}
+
+// NewCreateWebhookResponse is a constructor for the FormResponse struct for the webhook create page.
+func NewCreateWebhookResponse(currentUser *model.User) *FormResponse {
+	return newWebhookFormResponse("Create Webhook", currentUser, &model.Webhook{Active: true}, "/admin/webhook/create", "POST", "Create")
+}
+
+// NewUpdateWebhookResponse is a constructor for the FormResponse struct for the webhook update page.
+func NewUpdateWebhookResponse(currentUser *model.User, webhook *model.Webhook) *FormResponse {
+	return newWebhookFormResponse("Update Webhook", currentUser, webhook, fmt.Sprintf("/admin/webhook/update/%d", webhook.ID), "POST", "Update")
+}
+
+// NewWebhookListResponse is a constructor for the ListingResponse struct of the webhooks.
+func NewWebhookListResponse(currentUser *model.User, webhooks *model.Webhooks, filter *model.WebhookFilter) *ListingResponse {
+	headerText := "Webhook List"
+	headerContent := components.NewContentHeader(headerText, []*components.Link{})
+	if currentUser.HasPrivilege("webhooks.create") {
+		headerContent.Buttons = append(headerContent.Buttons, components.NewLink("Create", "/admin/webhook/create"))
+	}
+	listingHeader := &components.ListingHeader{
+		Headers: []string{"ID", "Name", "URL", "Event Types", "Active", "Actions"},
+	}
+	// create the rows
+	listingRows := components.ListingRows{}
+	userCanEdit := currentUser.HasPrivilege("webhooks.update")
+	userCanDelete := currentUser.HasPrivilege("webhooks.delete")
+	for _, webhook := range *webhooks {
+		columns := components.ListingColumns{}
+		idColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: fmt.Sprintf("%d", webhook.ID)}}}
+		columns = append(columns, idColumn)
+		nameColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: webhook.Name}}}
+		columns = append(columns, nameColumn)
+		urlColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: webhook.URL}}}
+		columns = append(columns, urlColumn)
+		eventTypesColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: strings.Join(webhook.EventTypes, ", ")}}}
+		columns = append(columns, eventTypesColumn)
+		activeColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: fmt.Sprintf("%t", webhook.Active)}}}
+		columns = append(columns, activeColumn)
+		actionsColumn := components.ListingColumn{Values: &components.ListingColumnValues{
+			{Value: "View", Link: fmt.Sprintf("/admin/webhook/view/%d", webhook.ID)},
+			{Value: "Deliveries", Link: fmt.Sprintf("/admin/webhook/deliveries/%d", webhook.ID)},
+		}}
+		if userCanEdit {
+			*actionsColumn.Values = append(*actionsColumn.Values, &components.ListingColumnValue{Value: "Update", Link: fmt.Sprintf("/admin/webhook/update/%d", webhook.ID)})
+		}
+		if userCanDelete {
+			*actionsColumn.Values = append(*actionsColumn.Values, &components.ListingColumnValue{Value: "Delete", Link: fmt.Sprintf("/admin/webhook/delete/%d", webhook.ID), Form: true})
+		}
+		columns = append(columns, &actionsColumn)
+
+		listingRows = append(listingRows, &components.ListingRow{Columns: &columns})
+	}
+	/* Create the search form. The only form item is the name. */
+	formItems := []*components.FormItem{
+		components.NewFormItem("Name", "name", "text", filter.Name, false, nil, nil),
+	}
+	form := &components.Form{
+		Items:  formItems,
+		Action: "/admin/webhook/list",
+		Method: "POST",
+		Submit: "Search",
+	}
+	return NewListingResponse(headerText, currentUser, headerContent, &components.Listing{Header: listingHeader, Rows: &listingRows}, form)
+}
+
+// NewWebhookDeliveryListResponse is a constructor for the ListingResponse struct of the webhook deliveries.
+// The deliveries are expected to be ordered by the time, the latest is the first.
+func NewWebhookDeliveryListResponse(currentUser *model.User, webhook *model.Webhook, deliveries *model.WebhookDeliveries) *ListingResponse {
+	headerText := "Deliveries of " + webhook.Name
+	headerContent := components.NewContentHeader(headerText, []*components.Link{
+		components.NewLink("Webhook", fmt.Sprintf("/admin/webhook/view/%d", webhook.ID)),
+		components.NewLink("List", "/admin/webhook/list"),
+	})
+	listingHeader := &components.ListingHeader{
+		Headers: []string{"Time", "Event", "Event ID", "Attempt", "Status", "Error", "Duration"},
+	}
+	// create the rows
+	listingRows := components.ListingRows{}
+	for _, delivery := range *deliveries {
+		columns := components.ListingColumns{}
+		timeColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: delivery.CreatedAt}}}
+		columns = append(columns, timeColumn)
+		eventColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: delivery.EventType}}}
+		columns = append(columns, eventColumn)
+		eventIDColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: delivery.EventID}}}
+		columns = append(columns, eventIDColumn)
+		attemptColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: fmt.Sprintf("%d", delivery.Attempt)}}}
+		columns = append(columns, attemptColumn)
+		// the status code is 0 if the request failed without response
+		status := "-"
+		if delivery.StatusCode != 0 {
+			status = fmt.Sprintf("%d", delivery.StatusCode)
+		}
+		statusColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: status}}}
+		columns = append(columns, statusColumn)
+		errorColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: delivery.Error}}}
+		columns = append(columns, errorColumn)
+		durationColumn := &components.ListingColumn{Values: &components.ListingColumnValues{{Value: fmt.Sprintf("%d ms", delivery.DurationMs)}}}
+		columns = append(columns, durationColumn)
+
+		listingRows = append(listingRows, &components.ListingRow{Columns: &columns})
+	}
+	return NewListingResponse(headerText, currentUser, headerContent, &components.Listing{Header: listingHeader, Rows: &listingRows}, nil)
+}
@@ -0,0 +1,56 @@
+package response
+
+import (
+	"testing"
+
+	"github.com/akosgarai/projectregister/pkg/event"
+	"github.com/akosgarai/projectregister/pkg/model"
+	"github.com/akosgarai/projectregister/pkg/testhelper"
+)
+
+// TestNewWebhookDetailResponse is a test function for the NewWebhookDetailResponse function.
+// It tests the response generation.
+func TestNewWebhookDetailResponse(t *testing.T) {
+	webhook := &model.Webhook{
+		ID:         1,
+		Name:       "test",
+		URL:        "https://example.com/hook",
+		Secret:     "secret",
+		EventTypes: []string{"domain.created", "domain.deleted"},
+		Active:     true,
+	}
+	testUser := testhelper.GetUserWithAccessToResources(1, []string{"webhooks.view"})
+	response := NewWebhookDetailResponse(testUser, webhook)
+	if response.Title != "Webhook Detail" {
+		t.Errorf("Title is not set properly. Got: %s", response.Title)
+	}
+	if len(*response.Details) != 8 {
+		t.Errorf("Details is not set properly. Got: %v", response.Details)
+	}
+	for _, item := range *response.Details {
+		if (*item.Value)[0].Value == webhook.Secret {
+			t.Errorf("The secret must not be displayed.")
+		}
+	}
+}
+
+// TestNewUpdateWebhookResponse is a test function for the NewUpdateWebhookResponse function.
+// It tests the response generation.
+func TestNewUpdateWebhookResponse(t *testing.T) {
+	webhook := &model.Webhook{
+		ID:         1,
+		Name:       "test",
+		EventTypes: []string{event.Types()[0]},
+	}
+	testUser := testhelper.GetUserWithAccessToResources(1, []string{"webhooks.update"})
+	response := NewUpdateWebhookResponse(testUser, webhook)
+	if response.Title != "Update Webhook" {
+		t.Errorf("Title is not set properly. Got: %s", response.Title)
+	}
+	if len(response.Form.Items) != 5 {
+		t.Errorf("Form items are not set properly. Got: %v", response.Form.Items)
+	}
+	if response.Form.Items[2].Required {
+		t.Errorf("The secret is optional on update.")
+	}
+}
@@ -14,7 +14,11 @@ import (
 // GET /admin/role/view/{roleId}
 // It renders the role view page.
 func (c *Controller) RoleViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("roles.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -27,7 +31,7 @@ func (c *Controller) RoleViewController(w http.ResponseWriter, r *http.Request)
 	content := response.NewRoleDetailResponse(currentUser, role)
 	err = c.renderer.Template.RenderTemplate(w, "detail-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
 
@@ -42,7 +46,7 @@ func (c *Controller) roleViewData(r *http.Request) (*model.Role, int, error) {
 	}
 	role, err := c.repositoryContainer.GetRoleRepository().GetRoleByID(roleID)
 	if err != nil {
-		return nil, http.StatusInternalServerError, err
+		return nil, repositoryErrorStatus(err), err
 	}
 	return role, http.StatusOK, nil
 }
@@ -51,7 +55,11 @@ func (c *Controller) roleViewData(r *http.Request) (*model.Role, int, error) {
 // On case of get request, it returns the role create page.
 // On case of post request, it creates the role and redirects to the list page.
 func (c *Controller) RoleCreateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("roles.create") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -59,13 +67,13 @@ func (c *Controller) RoleCreateViewController(w http.ResponseWriter, r *http.Req
 	if r.Method == http.MethodGet {
 		resources, err := c.repositoryContainer.GetResourceRepository().GetResources()
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, RoleFailedToGetResourcesErrorMessage, err)
+			c.renderRepositoryError(w, RoleFailedToGetResourcesErrorMessage, err)
 			return
 		}
 		content := response.NewCreateRoleResponse(currentUser, resources)
 		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -92,7 +100,7 @@ func (c *Controller) RoleCreateViewController(w http.ResponseWriter, r *http.Req
 
 		_, err := c.repositoryContainer.GetRoleRepository().CreateRole(name, resourceIDs)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, RoleCreateCreateRoleErrorMessage, err)
+			c.renderRepositoryError(w, RoleCreateCreateRoleErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/role/list", http.StatusSeeOther)
@@ -104,7 +112,11 @@ func (c *Controller) RoleCreateViewController(w http.ResponseWriter, r *http.Req
 // On case of get request, it returns the role update page.
 // On case of post request, it updates the role and redirects to the list page.
 func (c *Controller) RoleUpdateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("roles.update") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -121,20 +133,20 @@ func (c *Controller) RoleUpdateViewController(w http.ResponseWriter, r *http.Req
 	// get the role
 	role, err := c.repositoryContainer.GetRoleRepository().GetRoleByID(roleID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, RoleFailedToGetRoleErrorMessage, err)
+		c.renderRepositoryError(w, RoleFailedToGetRoleErrorMessage, err)
 		return
 	}
 
 	if r.Method == http.MethodGet {
 		resources, err := c.repositoryContainer.GetResourceRepository().GetResources()
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, RoleFailedToGetResourcesErrorMessage, err)
+			c.renderRepositoryError(w, RoleFailedToGetResourcesErrorMessage, err)
 			return
 		}
 		content := response.NewUpdateRoleResponse(currentUser, role, resources)
 		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -163,7 +175,7 @@ func (c *Controller) RoleUpdateViewController(w http.ResponseWriter, r *http.Req
 		}
 		err = c.repositoryContainer.GetRoleRepository().UpdateRole(role, resourceIDs)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, RoleUpdateUpdateRoleErrorMessage, err)
+			c.renderRepositoryError(w, RoleUpdateUpdateRoleErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/role/list", http.StatusSeeOther)
@@ -175,7 +187,11 @@ func (c *Controller) RoleUpdateViewController(w http.ResponseWriter, r *http.Req
 // It is responsible for deleting a role.
 // It redirects to the role list page.
 func (c *Controller) RoleDeleteViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("roles.delete") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -191,7 +207,7 @@ func (c *Controller) RoleDeleteViewController(w http.ResponseWriter, r *http.Req
 	// delete the role
 	err = c.repositoryContainer.GetRoleRepository().DeleteRole(roleID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, RoleDeleteFailedToDeleteErrorMessage, err)
+		c.renderRepositoryError(w, RoleDeleteFailedToDeleteErrorMessage, err)
 		return
 	}
 	// redirect to the role list
@@ -200,7 +216,11 @@ func (c *Controller) RoleDeleteViewController(w http.ResponseWriter, r *http.Req
 
 // RoleListViewController is the controller for the role list view.
 func (c *Controller) RoleListViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("roles.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -212,12 +232,12 @@ func (c *Controller) RoleListViewController(w http.ResponseWriter, r *http.Reque
 	// get all roles
 	roles, err := c.repositoryContainer.GetRoleRepository().GetRoles(filter)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, RoleListFailedToGetRolesErrorMessage, err)
+		c.renderRepositoryError(w, RoleListFailedToGetRolesErrorMessage, err)
 		return
 	}
 	content := response.NewRoleListResponse(currentUser, roles, filter)
 	err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
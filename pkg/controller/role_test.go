@@ -6,10 +6,13 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 
 	"github.com/akosgarai/projectregister/pkg/config"
+	"github.com/akosgarai/projectregister/pkg/health"
+	"github.com/akosgarai/projectregister/pkg/metrics"
 	"github.com/akosgarai/projectregister/pkg/model"
 	"github.com/akosgarai/projectregister/pkg/render"
 	"github.com/akosgarai/projectregister/pkg/session"
@@ -37,7 +40,13 @@ func TestRoleViewControllerWithoutPrivilege(t *testing.T) {
 		testhelper.NewRepositoryContainerMock(),
 		sessionStore,
 		testhelper.CSVStorageMock{},
-		render.NewRenderer(testConfig, render.NewTemplates()))
+		render.NewRenderer(testConfig, render.NewTemplates()),
+		nil,
+		"",
+		metrics.NewRegistry(),
+		"",
+		health.NewChecker(time.Second),
+		nil)
 
 	testData := []struct {
 		Method       string
@@ -83,7 +92,13 @@ func getRoleViewController(resources []string, repositoryMock *testhelper.Reposi
 		repositoryMock,
 		sessionStore,
 		testhelper.CSVStorageMock{},
-		render.NewRenderer(testConfig, render.NewTemplates()))
+		render.NewRenderer(testConfig, render.NewTemplates()),
+		nil,
+		"",
+		metrics.NewRegistry(),
+		"",
+		health.NewChecker(time.Second),
+		nil)
 	c.CacheTemplates()
 	return c
 }
@@ -14,7 +14,11 @@ import (
 // GET /admin/runtime/view/{runtimeId}
 // It renders the runtime view page.
 func (c *Controller) RuntimeViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("runtimes.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -27,7 +31,7 @@ func (c *Controller) RuntimeViewController(w http.ResponseWriter, r *http.Reques
 	content := response.NewRuntimeDetailResponse(currentUser, runtime)
 	err = c.renderer.Template.RenderTemplate(w, "detail-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
 
@@ -42,7 +46,7 @@ func (c *Controller) runtimeViewData(r *http.Request) (*model.Runtime, int, erro
 	}
 	runtime, err := c.repositoryContainer.GetRuntimeRepository().GetRuntimeByID(runtimeID)
 	if err != nil {
-		return nil, http.StatusInternalServerError, err
+		return nil, repositoryErrorStatus(err), err
 	}
 	return runtime, http.StatusOK, nil
 }
@@ -51,7 +55,11 @@ func (c *Controller) runtimeViewData(r *http.Request) (*model.Runtime, int, erro
 // On case of get request, it returns the runtime create page.
 // On case of post request, it creates the runtime and redirects to the list page.
 func (c *Controller) RuntimeCreateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("runtimes.create") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -60,7 +68,7 @@ func (c *Controller) RuntimeCreateViewController(w http.ResponseWriter, r *http.
 		content := response.NewCreateRuntimeResponse(currentUser)
 		err := c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -81,7 +89,7 @@ func (c *Controller) RuntimeCreateViewController(w http.ResponseWriter, r *http.
 
 		_, err = c.repositoryContainer.GetRuntimeRepository().CreateRuntime(name, score)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, RuntimeCreateCreateRuntimeErrorMessage, err)
+			c.renderRepositoryError(w, RuntimeCreateCreateRuntimeErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/runtime/list", http.StatusSeeOther)
@@ -93,7 +101,11 @@ func (c *Controller) RuntimeCreateViewController(w http.ResponseWriter, r *http.
 // On case of get request, it returns the runtime update page.
 // On case of post request, it updates the runtime and redirects to the list page.
 func (c *Controller) RuntimeUpdateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("runtimes.update") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -110,7 +122,7 @@ func (c *Controller) RuntimeUpdateViewController(w http.ResponseWriter, r *http.
 	// get the runtime
 	runtime, err := c.repositoryContainer.GetRuntimeRepository().GetRuntimeByID(runtimeID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, RuntimeFailedToGetRuntimeErrorMessage, err)
+		c.renderRepositoryError(w, RuntimeFailedToGetRuntimeErrorMessage, err)
 		return
 	}
 
@@ -118,7 +130,7 @@ func (c *Controller) RuntimeUpdateViewController(w http.ResponseWriter, r *http.
 		content := response.NewUpdateRuntimeResponse(currentUser, runtime)
 		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -142,7 +154,7 @@ func (c *Controller) RuntimeUpdateViewController(w http.ResponseWriter, r *http.
 		runtime.Score = score
 		err = c.repositoryContainer.GetRuntimeRepository().UpdateRuntime(runtime)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, RuntimeUpdateUpdateRuntimeErrorMessage, err)
+			c.renderRepositoryError(w, RuntimeUpdateUpdateRuntimeErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/runtime/list", http.StatusSeeOther)
@@ -154,7 +166,11 @@ func (c *Controller) RuntimeUpdateViewController(w http.ResponseWriter, r *http.
 // It is responsible for deleting a runtime.
 // It redirects to the runtime list page.
 func (c *Controller) RuntimeDeleteViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("runtimes.delete") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -170,7 +186,7 @@ func (c *Controller) RuntimeDeleteViewController(w http.ResponseWriter, r *http.
 	// delete the runtime
 	err = c.repositoryContainer.GetRuntimeRepository().DeleteRuntime(runtimeID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, RuntimeDeleteFailedToDeleteErrorMessage, err)
+		c.renderRepositoryError(w, RuntimeDeleteFailedToDeleteErrorMessage, err)
 		return
 	}
 	// redirect to the runtime list
@@ -179,7 +195,11 @@ func (c *Controller) RuntimeDeleteViewController(w http.ResponseWriter, r *http.
 
 // RuntimeListViewController is the controller for the runtime list view.
 func (c *Controller) RuntimeListViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("runtimes.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -191,12 +211,12 @@ func (c *Controller) RuntimeListViewController(w http.ResponseWriter, r *http.Re
 	// get all runtimes
 	runtimes, err := c.repositoryContainer.GetRuntimeRepository().GetRuntimes(filter)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, RuntimeListFailedToGetRuntimesErrorMessage, err)
+		c.renderRepositoryError(w, RuntimeListFailedToGetRuntimesErrorMessage, err)
 		return
 	}
 	content := response.NewRuntimeListResponse(currentUser, runtimes, filter)
 	err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
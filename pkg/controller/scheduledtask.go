@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/akosgarai/projectregister/pkg/controller/response"
+	"github.com/akosgarai/projectregister/pkg/model"
+	"github.com/akosgarai/projectregister/pkg/scheduler"
+)
+
+// scheduledTaskViewData gets the request as input, and returns the scheduled task data, status code and error.
+func (c *Controller) scheduledTaskViewData(r *http.Request) (*model.ScheduledTask, int, error) {
+	vars := mux.Vars(r)
+	taskIDVariable := vars["taskId"]
+	// it has to be converted to int64
+	taskID, err := strconv.ParseInt(taskIDVariable, 10, 64)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	task, err := c.repositoryContainer.GetScheduledTaskRepository().GetScheduledTaskByID(taskID)
+	if err != nil {
+		return nil, repositoryErrorStatus(err), err
+	}
+	return task, http.StatusOK, nil
+}
+
+// scheduledTaskNextRun validates the cron expression and returns the next run of the task.
+// The disabled task has no next run, it is the zero time.
+func scheduledTaskNextRun(expression string, enabled bool) (time.Time, error) {
+	schedule, err := scheduler.ParseSchedule(expression)
+	if err != nil || !enabled {
+		return time.Time{}, err
+	}
+	nextRunAt := schedule.Next(time.Now())
+	if nextRunAt.IsZero() {
+		return nextRunAt, errors.New("the schedule has no next run: " + expression)
+	}
+	return nextRunAt, nil
+}
+
+// ScheduledTaskListViewController is the controller for the scheduled task list view.
+// It lists the tasks with the outcome of their last run and their next run.
+func (c *Controller) ScheduledTaskListViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("scheduled_tasks.view") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	tasks, err := c.repositoryContainer.GetScheduledTaskRepository().GetScheduledTasks()
+	if err != nil {
+		c.renderRepositoryError(w, ScheduledTaskListFailedToGetTasksErrorMessage, err)
+		return
+	}
+	content := response.NewScheduledTaskListResponse(currentUser, tasks)
+	err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
+	if err != nil {
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+	}
+}
+
+// ScheduledTaskUpdateViewController is the controller for the scheduled task update view.
+// On case of get request, it returns the scheduled task update page.
+// On case of post request, it updates the schedule of the task and redirects to the list page.
+func (c *Controller) ScheduledTaskUpdateViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("scheduled_tasks.update") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	task, statusCode, err := c.scheduledTaskViewData(r)
+	if err != nil {
+		c.renderer.Error(w, statusCode, ScheduledTaskFailedToGetTaskErrorMessage, err)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		content := response.NewUpdateScheduledTaskResponse(currentUser, task)
+		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
+		if err != nil {
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+		}
+	}
+
+	if r.Method == http.MethodPost {
+		expression := strings.Join(strings.Fields(r.FormValue("schedule")), " ")
+		enabled := r.FormValue("enabled") == "1"
+		nextRunAt, err := scheduledTaskNextRun(expression, enabled)
+		if err != nil {
+			c.renderer.Error(w, http.StatusBadRequest, ScheduledTaskScheduleInvalidErrorMessage, err)
+			return
+		}
+		_, err = c.repositoryContainer.GetScheduledTaskRepository().UpdateScheduledTask(task.ID, expression, enabled, nextRunAt)
+		if err != nil {
+			c.renderRepositoryError(w, ScheduledTaskUpdateFailedErrorMessage, err)
+			return
+		}
+		http.Redirect(w, r, "/admin/scheduled-task/list", http.StatusSeeOther)
+		return
+	}
+}
+
+// ScheduledTaskEnableViewController is the controller for the scheduled task enable form.
+// It schedules the next run of the task, and redirects to the list page.
+func (c *Controller) ScheduledTaskEnableViewController(w http.ResponseWriter, r *http.Request) {
+	c.scheduledTaskToggle(w, r, true)
+}
+
+// ScheduledTaskDisableViewController is the controller for the scheduled task disable form.
+// The disabled task is not executed until it is enabled again. It redirects to the list page.
+func (c *Controller) ScheduledTaskDisableViewController(w http.ResponseWriter, r *http.Request) {
+	c.scheduledTaskToggle(w, r, false)
+}
+
+// scheduledTaskToggle sets the enabled flag of the task of the url, and redirects to the list page.
+func (c *Controller) scheduledTaskToggle(w http.ResponseWriter, r *http.Request, enabled bool) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("scheduled_tasks.update") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	task, statusCode, err := c.scheduledTaskViewData(r)
+	if err != nil {
+		c.renderer.Error(w, statusCode, ScheduledTaskFailedToGetTaskErrorMessage, err)
+		return
+	}
+	nextRunAt, err := scheduledTaskNextRun(task.Schedule, enabled)
+	if err != nil {
+		c.renderer.Error(w, http.StatusBadRequest, ScheduledTaskScheduleInvalidErrorMessage, err)
+		return
+	}
+	_, err = c.repositoryContainer.GetScheduledTaskRepository().UpdateScheduledTask(task.ID, task.Schedule, enabled, nextRunAt)
+	if err != nil {
+		c.renderRepositoryError(w, ScheduledTaskUpdateFailedErrorMessage, err)
+		return
+	}
+	http.Redirect(w, r, "/admin/scheduled-task/list", http.StatusSeeOther)
+}
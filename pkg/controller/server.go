@@ -14,7 +14,11 @@ import (
 // GET /admin/server/view/{serverId}
 // It renders the server view page.
 func (c *Controller) ServerViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("servers.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -27,7 +31,7 @@ func (c *Controller) ServerViewController(w http.ResponseWriter, r *http.Request
 	content := response.NewServerDetailResponse(currentUser, server)
 	err = c.renderer.Template.RenderTemplate(w, "detail-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
 
@@ -42,7 +46,7 @@ func (c *Controller) serverViewData(r *http.Request) (*model.Server, int, error)
 	}
 	server, err := c.repositoryContainer.GetServerRepository().GetServerByID(serverID)
 	if err != nil {
-		return nil, http.StatusInternalServerError, err
+		return nil, repositoryErrorStatus(err), err
 	}
 	return server, http.StatusOK, nil
 }
@@ -51,7 +55,11 @@ func (c *Controller) serverViewData(r *http.Request) (*model.Server, int, error)
 // On case of get request, it returns the server create page.
 // On case of post request, it creates the server and redirects to the list page.
 func (c *Controller) ServerCreateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("servers.create") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -59,18 +67,18 @@ func (c *Controller) ServerCreateViewController(w http.ResponseWriter, r *http.R
 	if r.Method == http.MethodGet {
 		runtimes, err := c.repositoryContainer.GetRuntimeRepository().GetRuntimes(model.NewRuntimeFilter())
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, ServerCreateFailedToGetRuntimesErrorMessage, err)
+			c.renderRepositoryError(w, ServerCreateFailedToGetRuntimesErrorMessage, err)
 			return
 		}
 		pools, err := c.repositoryContainer.GetPoolRepository().GetPools(model.NewPoolFilter())
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, ServerCreateFailedToGetPoolsErrorMessage, err)
+			c.renderRepositoryError(w, ServerCreateFailedToGetPoolsErrorMessage, err)
 			return
 		}
 		content := response.NewCreateServerResponse(currentUser, pools, runtimes)
 		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -107,7 +115,7 @@ func (c *Controller) ServerCreateViewController(w http.ResponseWriter, r *http.R
 
 		_, err := c.repositoryContainer.GetServerRepository().CreateServer(name, description, remoteAddress, runtimeIDs, poolIDs)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, ServerCreateCreateServerErrorMessage, err)
+			c.renderRepositoryError(w, ServerCreateCreateServerErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/server/list", http.StatusSeeOther)
@@ -119,7 +127,11 @@ func (c *Controller) ServerCreateViewController(w http.ResponseWriter, r *http.R
 // On case of get request, it returns the server update page.
 // On case of post request, it updates the server and redirects to the list page.
 func (c *Controller) ServerUpdateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("servers.update") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -136,25 +148,25 @@ func (c *Controller) ServerUpdateViewController(w http.ResponseWriter, r *http.R
 	// get the server
 	server, err := c.repositoryContainer.GetServerRepository().GetServerByID(serverID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ServerFailedToGetServerErrorMessage, err)
+		c.renderRepositoryError(w, ServerFailedToGetServerErrorMessage, err)
 		return
 	}
 
 	if r.Method == http.MethodGet {
 		runtimes, err := c.repositoryContainer.GetRuntimeRepository().GetRuntimes(model.NewRuntimeFilter())
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, ServerCreateFailedToGetRuntimesErrorMessage, err)
+			c.renderRepositoryError(w, ServerCreateFailedToGetRuntimesErrorMessage, err)
 			return
 		}
 		pools, err := c.repositoryContainer.GetPoolRepository().GetPools(model.NewPoolFilter())
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, ServerCreateFailedToGetPoolsErrorMessage, err)
+			c.renderRepositoryError(w, ServerCreateFailedToGetPoolsErrorMessage, err)
 			return
 		}
 		content := response.NewUpdateServerResponse(currentUser, server, pools, runtimes)
 		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -198,7 +210,7 @@ func (c *Controller) ServerUpdateViewController(w http.ResponseWriter, r *http.R
 		}
 		err = c.repositoryContainer.GetServerRepository().UpdateServer(server)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, ServerUpdateUpdateServerErrorMessage, err)
+			c.renderRepositoryError(w, ServerUpdateUpdateServerErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/server/list", http.StatusSeeOther)
@@ -210,7 +222,11 @@ func (c *Controller) ServerUpdateViewController(w http.ResponseWriter, r *http.R
 // It is responsible for deleting a server.
 // It redirects to the server list page.
 func (c *Controller) ServerDeleteViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("servers.delete") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -226,7 +242,7 @@ func (c *Controller) ServerDeleteViewController(w http.ResponseWriter, r *http.R
 	// delete the server
 	err = c.repositoryContainer.GetServerRepository().DeleteServer(serverID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ServerDeleteFailedToDeleteErrorMessage, err)
+		c.renderRepositoryError(w, ServerDeleteFailedToDeleteErrorMessage, err)
 		return
 	}
 	// redirect to the server list
@@ -235,7 +251,11 @@ func (c *Controller) ServerDeleteViewController(w http.ResponseWriter, r *http.R
 
 // ServerListViewController is the controller for the server list view.
 func (c *Controller) ServerListViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("servers.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -265,24 +285,24 @@ func (c *Controller) ServerListViewController(w http.ResponseWriter, r *http.Req
 	// get all servers
 	servers, err := c.repositoryContainer.GetServerRepository().GetServers(filter)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ServerListFailedToGetServersErrorMessage, err)
+		c.renderRepositoryError(w, ServerListFailedToGetServersErrorMessage, err)
 		return
 	}
 	// get all runtimes
 	runtimes, err := c.repositoryContainer.GetRuntimeRepository().GetRuntimes(model.NewRuntimeFilter())
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ServerListFailedToGetRuntimesErrorMessage, err)
+		c.renderRepositoryError(w, ServerListFailedToGetRuntimesErrorMessage, err)
 		return
 	}
 	// get all pools
 	pools, err := c.repositoryContainer.GetPoolRepository().GetPools(model.NewPoolFilter())
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, ServerListFailedToGetPoolsErrorMessage, err)
+		c.renderRepositoryError(w, ServerListFailedToGetPoolsErrorMessage, err)
 		return
 	}
 	content := response.NewServerListResponse(currentUser, servers, pools, runtimes, filter)
 	err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/akosgarai/projectregister/pkg/httpsd"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// ServiceDiscoveryController is the controller of the prometheus http service discovery.
+// GET /sd/targets
+// It returns the application domains as target groups in the http_sd_config json format.
+// The applications could be filtered with the same parameters as the application list.
+// If the servers=1 parameter is set, the remote addresses of the environment servers are also returned.
+// It is protected with the metrics token, as it is consumed by prometheus. Unlike the metrics,
+// it lists the whole inventory, so that it is not found if the metrics token is not set.
+func (c *Controller) ServiceDiscoveryController(w http.ResponseWriter, r *http.Request) {
+	if c.metricsToken == "" {
+		c.renderer.Error(w, http.StatusNotFound, ServiceDiscoveryNotFoundErrorMessage, nil)
+		return
+	}
+	if !c.hasMetricsAccess(r) {
+		c.renderer.Error(w, http.StatusUnauthorized, MetricsUnauthorizedErrorMessage, nil)
+		return
+	}
+	filter := model.NewApplicationFilter()
+	if err := applicationFilterFromForm(r, filter); err != nil {
+		c.renderer.Error(w, http.StatusBadRequest, ApplicationListFilterInvalidErrorMessage, err)
+		return
+	}
+	applications, err := c.repositoryContainer.GetApplicationRepository().GetApplications(filter)
+	if err != nil {
+		c.renderRepositoryError(w, ServiceDiscoveryFailedToGetApplicationsErrorMessage, err)
+		return
+	}
+	groups := httpsd.FromApplications(applications)
+	if r.FormValue("servers") == "1" {
+		// the servers belong to the environments, so that they are listed once per environment.
+		environments := []*model.Environment{}
+		seen := map[int64]bool{}
+		for _, application := range *applications {
+			if seen[application.Environment.ID] {
+				continue
+			}
+			seen[application.Environment.ID] = true
+			environment, err := c.repositoryContainer.GetEnvironmentRepository().GetEnvironmentByID(application.Environment.ID)
+			if err != nil {
+				c.renderRepositoryError(w, ServiceDiscoveryFailedToGetEnvironmentErrorMessage, err)
+				return
+			}
+			environments = append(environments, environment)
+		}
+		groups = append(groups, httpsd.FromServers(environments)...)
+	}
+	c.renderer.JSON(w, http.StatusOK, groups)
+}
@@ -13,7 +13,11 @@ import (
 
 // UserViewController is the controller for the user view page.
 func (c *Controller) UserViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("users.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -26,7 +30,7 @@ func (c *Controller) UserViewController(w http.ResponseWriter, r *http.Request)
 	content := response.NewUserDetailResponse(currentUser, u)
 	err = c.renderer.Template.RenderTemplate(w, "detail-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
 
@@ -54,7 +58,7 @@ func (c *Controller) userViewData(r *http.Request) (*model.User, int, error) {
 	}
 	u, err := c.repositoryContainer.GetUserRepository().GetUserByID(userID)
 	if err != nil {
-		return nil, http.StatusInternalServerError, err
+		return nil, repositoryErrorStatus(err), err
 	}
 	return u, http.StatusOK, nil
 }
@@ -63,7 +67,11 @@ func (c *Controller) userViewData(r *http.Request) (*model.User, int, error) {
 // On case of get request, it returns the user create page.
 // On case of post request, it creates the user and redirects to the list page.
 func (c *Controller) UserCreateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("users.create") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -72,13 +80,13 @@ func (c *Controller) UserCreateViewController(w http.ResponseWriter, r *http.Req
 		// get all roles
 		roles, err := c.repositoryContainer.GetRoleRepository().GetRoles(model.NewRoleFilter())
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, UserFailedToGetRolesErrorMessage, err)
+			c.renderRepositoryError(w, UserFailedToGetRolesErrorMessage, err)
 			return
 		}
 		content := response.NewCreateUserResponse(currentUser, roles)
 		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -108,7 +116,7 @@ func (c *Controller) UserCreateViewController(w http.ResponseWriter, r *http.Req
 		}
 		_, err = c.repositoryContainer.GetUserRepository().CreateUser(name, email, string(password), roleID)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, UserCreateCreateUserErrorMessagePrefix, err)
+			c.renderRepositoryError(w, UserCreateCreateUserErrorMessagePrefix, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/user/list", http.StatusSeeOther)
@@ -142,7 +150,7 @@ func (c *Controller) UserCreateAPIController(w http.ResponseWriter, r *http.Requ
 	// create the user
 	user, err := c.repositoryContainer.GetUserRepository().CreateUser(name, email, string(hashedPassword), roleID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, UserCreateCreateUserErrorMessagePrefix, err)
+		c.renderRepositoryError(w, UserCreateCreateUserErrorMessagePrefix, err)
 		return
 	}
 	// return the user as JSON
@@ -153,7 +161,11 @@ func (c *Controller) UserCreateAPIController(w http.ResponseWriter, r *http.Requ
 // On case of get request, it returns the user update page.
 // On case of post request, it updates the user and redirects to the list page.
 func (c *Controller) UserUpdateViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("users.update") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -170,7 +182,7 @@ func (c *Controller) UserUpdateViewController(w http.ResponseWriter, r *http.Req
 	// get the user
 	user, err := c.repositoryContainer.GetUserRepository().GetUserByID(userID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, UserUpdateFailedToGetUserErrorMessage, err)
+		c.renderRepositoryError(w, UserUpdateFailedToGetUserErrorMessage, err)
 		return
 	}
 
@@ -178,13 +190,13 @@ func (c *Controller) UserUpdateViewController(w http.ResponseWriter, r *http.Req
 		// get all roles
 		roles, err := c.repositoryContainer.GetRoleRepository().GetRoles(model.NewRoleFilter())
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, UserFailedToGetRolesErrorMessage, err)
+			c.renderRepositoryError(w, UserFailedToGetRolesErrorMessage, err)
 			return
 		}
 		content := response.NewUpdateUserResponse(currentUser, user, roles)
 		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
 		if err != nil {
-			panic(err)
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 		}
 	}
 
@@ -222,7 +234,7 @@ func (c *Controller) UserUpdateViewController(w http.ResponseWriter, r *http.Req
 		user.Role.ID = roleID
 		err = c.repositoryContainer.GetUserRepository().UpdateUser(user)
 		if err != nil {
-			c.renderer.Error(w, http.StatusInternalServerError, UserUpdateFailedToUpdateUserErrorMessage, err)
+			c.renderRepositoryError(w, UserUpdateFailedToUpdateUserErrorMessage, err)
 			return
 		}
 		http.Redirect(w, r, "/admin/user/list", http.StatusSeeOther)
@@ -251,7 +263,7 @@ func (c *Controller) UserUpdateAPIController(w http.ResponseWriter, r *http.Requ
 	// get the user
 	user, err := c.repositoryContainer.GetUserRepository().GetUserByID(userID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, UserUpdateFailedToGetUserErrorMessage, err)
+		c.renderRepositoryError(w, UserUpdateFailedToGetUserErrorMessage, err)
 		return
 	}
 	hashedPassword, err := passwd.HashPassword(password)
@@ -272,7 +284,7 @@ func (c *Controller) UserUpdateAPIController(w http.ResponseWriter, r *http.Requ
 	user.Role.ID = roleID
 	err = c.repositoryContainer.GetUserRepository().UpdateUser(user)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, UserUpdateFailedToUpdateUserErrorMessage, err)
+		c.renderRepositoryError(w, UserUpdateFailedToUpdateUserErrorMessage, err)
 		return
 	}
 	// return the updated user as JSON
@@ -283,7 +295,11 @@ func (c *Controller) UserUpdateAPIController(w http.ResponseWriter, r *http.Requ
 // It is responsible for deleting a user.
 // It redirects to the user list page.
 func (c *Controller) UserDeleteViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("users.delete") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -299,7 +315,7 @@ func (c *Controller) UserDeleteViewController(w http.ResponseWriter, r *http.Req
 	// delete the user
 	err = c.repositoryContainer.GetUserRepository().DeleteUser(userID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, UserDeleteFailedToDeleteErrorMessage, err)
+		c.renderRepositoryError(w, UserDeleteFailedToDeleteErrorMessage, err)
 		return
 	}
 	// redirect to the user list
@@ -322,7 +338,7 @@ func (c *Controller) UserDeleteAPIController(w http.ResponseWriter, r *http.Requ
 	// delete the user
 	err = c.repositoryContainer.GetUserRepository().DeleteUser(userID)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, UserDeleteFailedToDeleteErrorMessage, err)
+		c.renderRepositoryError(w, UserDeleteFailedToDeleteErrorMessage, err)
 		return
 	}
 	// return success
@@ -331,7 +347,11 @@ func (c *Controller) UserDeleteAPIController(w http.ResponseWriter, r *http.Requ
 
 // UserListViewController is the controller for the user list view.
 func (c *Controller) UserListViewController(w http.ResponseWriter, r *http.Request) {
-	currentUser := c.CurrentUser(r)
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
 	if !currentUser.HasPrivilege("users.view") {
 		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
 		return
@@ -356,19 +376,19 @@ func (c *Controller) UserListViewController(w http.ResponseWriter, r *http.Reque
 	// get all users
 	users, err := c.repositoryContainer.GetUserRepository().GetUsers(filter)
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, UserFailedToGetUserErrorMessage, err)
+		c.renderRepositoryError(w, UserFailedToGetUserErrorMessage, err)
 		return
 	}
 	// get all roles
 	roles, err := c.repositoryContainer.GetRoleRepository().GetRoles(model.NewRoleFilter())
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, UserFailedToGetRolesErrorMessage, err)
+		c.renderRepositoryError(w, UserFailedToGetRolesErrorMessage, err)
 		return
 	}
 	content := response.NewUserListResponse(currentUser, users, roles, filter)
 	err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
 	if err != nil {
-		panic(err)
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
 	}
 }
 
@@ -380,7 +400,7 @@ func (c *Controller) UserListAPIController(w http.ResponseWriter, r *http.Reques
 	// get all users
 	users, err := c.repositoryContainer.GetUserRepository().GetUsers(model.NewUserFilter())
 	if err != nil {
-		c.renderer.Error(w, http.StatusInternalServerError, UserListFailedToGetUsersErrorMessage, err)
+		c.renderRepositoryError(w, UserListFailedToGetUsersErrorMessage, err)
 		return
 	}
 	// return the users as JSON
@@ -6,10 +6,13 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 
 	"github.com/akosgarai/projectregister/pkg/config"
+	"github.com/akosgarai/projectregister/pkg/health"
+	"github.com/akosgarai/projectregister/pkg/metrics"
 	"github.com/akosgarai/projectregister/pkg/model"
 	"github.com/akosgarai/projectregister/pkg/render"
 	"github.com/akosgarai/projectregister/pkg/session"
@@ -27,7 +30,13 @@ func getViewController(repositoryMock *testhelper.RepositoryContainerMock) *Cont
 		repositoryMock,
 		sessionStore,
 		testhelper.CSVStorageMock{},
-		render.NewRenderer(testConfig, render.NewTemplates()))
+		render.NewRenderer(testConfig, render.NewTemplates()),
+		nil,
+		"",
+		metrics.NewRegistry(),
+		"",
+		health.NewChecker(time.Second),
+		nil)
 	c.CacheTemplates()
 	return c
 }
@@ -195,7 +204,13 @@ func getCreateController(repositoryMock *testhelper.RepositoryContainerMock) *Co
 		repositoryMock,
 		sessionStore,
 		testhelper.CSVStorageMock{},
-		render.NewRenderer(testConfig, render.NewTemplates()))
+		render.NewRenderer(testConfig, render.NewTemplates()),
+		nil,
+		"",
+		metrics.NewRegistry(),
+		"",
+		health.NewChecker(time.Second),
+		nil)
 	c.CacheTemplates()
 	return c
 }
@@ -438,7 +453,13 @@ func getUpdateController(repositoryMock *testhelper.RepositoryContainerMock) *Co
 		repositoryMock,
 		sessionStore,
 		testhelper.CSVStorageMock{},
-		render.NewRenderer(testConfig, render.NewTemplates()))
+		render.NewRenderer(testConfig, render.NewTemplates()),
+		nil,
+		"",
+		metrics.NewRegistry(),
+		"",
+		health.NewChecker(time.Second),
+		nil)
 	c.CacheTemplates()
 	return c
 }
@@ -780,7 +801,13 @@ func getDeleteController(repositoryMock *testhelper.RepositoryContainerMock) *Co
 		repositoryMock,
 		sessionStore,
 		testhelper.CSVStorageMock{},
-		render.NewRenderer(testConfig, render.NewTemplates()))
+		render.NewRenderer(testConfig, render.NewTemplates()),
+		nil,
+		"",
+		metrics.NewRegistry(),
+		"",
+		health.NewChecker(time.Second),
+		nil)
 	c.CacheTemplates()
 	return c
 }
@@ -957,7 +984,13 @@ func getListController(repositoryMock *testhelper.RepositoryContainerMock) *Cont
 		repositoryMock,
 		sessionStore,
 		testhelper.CSVStorageMock{},
-		render.NewRenderer(testConfig, render.NewTemplates()))
+		render.NewRenderer(testConfig, render.NewTemplates()),
+		nil,
+		"",
+		metrics.NewRegistry(),
+		"",
+		health.NewChecker(time.Second),
+		nil)
 	c.CacheTemplates()
 	return c
 }
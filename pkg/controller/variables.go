@@ -3,6 +3,8 @@ package controller
 var (
 	// ApplicationApplicationIDInvalidErrorMessage is the error message prefix for the invalid application id.
 	ApplicationApplicationIDInvalidErrorMessage = "Invalid application id"
+	// ApplicationCheckPrimaryDomainMissingErrorMessage is the error message for the application check without primary domain.
+	ApplicationCheckPrimaryDomainMissingErrorMessage = "The application does not have primary domain"
 	// ApplicationCreateClientIDInvalidErrorMessage is the error message for the invalid client id in the application form.
 	ApplicationCreateClientIDInvalidErrorMessage = "Invalid client id"
 	// ApplicationCreateCreateApplicationErrorMessage is the error message for the failed application creation.
@@ -33,6 +35,8 @@ var (
 	ApplicationCreateFrameworkIDInvalidErrorMessage = "Invalid framework id"
 	// ApplicationCreatePoolIDInvalidErrorMessage is the error message for the invalid pool id in the application form.
 	ApplicationCreatePoolIDInvalidErrorMessage = "Invalid pool id"
+	// ApplicationCreatePrimaryDomainInvalidErrorMessage is the error message for the invalid primary domain in the application form.
+	ApplicationCreatePrimaryDomainInvalidErrorMessage = "The primary domain has to be one of the selected domains"
 	// ApplicationCreateProjectIDInvalidErrorMessage is the error message for the invalid project id in the application form.
 	ApplicationCreateProjectIDInvalidErrorMessage = "Invalid project id"
 	// ApplicationCreateRequiredFieldMissing is the error message for the required fields in the application create.
@@ -41,22 +45,64 @@ var (
 	ApplicationCreateRuntimeIDInvalidErrorMessage = "Invalid runtime id"
 	// ApplicationDeleteFailedToDeleteErrorMessage is the error message for the failed application deletion.
 	ApplicationDeleteFailedToDeleteErrorMessage = "Failed to delete the application"
+	// ApplicationDomainRolesInvalidErrorMessage is the error message for the invalid application domain roles.
+	ApplicationDomainRolesInvalidErrorMessage = "Invalid domain roles"
 	// ApplicationFailedToGetApplicationErrorMessage is the error message for the failed application get.
 	ApplicationFailedToGetApplicationErrorMessage = "Failed to get application data"
 	// ApplicationImportFailedToGetEnvironmentErrorMessage is the error message for the failed environment get.
 	ApplicationImportFailedToGetEnvironmentErrorMessage = "Failed to get environment"
-	// ApplicationImportFailedToSaveFileErrorMessage is the error message for the failed file save.
-	ApplicationImportFailedToSaveFileErrorMessage = "Failed to save the file"
 	// ApplicationImportInvalidEnvironmentIDErrorMessage is the error message for the invalid environment id in the application import form.
 	ApplicationImportInvalidEnvironmentIDErrorMessage = "Invalid environment id"
 	// ApplicationListFailedToGetApplicationsErrorMessage is the error message for the failed applications get.
 	ApplicationListFailedToGetApplicationsErrorMessage = "Failed to get applications"
+	// ApplicationListFilterInvalidErrorMessage is the error message for the invalid id in the application filter.
+	ApplicationListFilterInvalidErrorMessage = "Invalid filter id"
+	// ApplicationUpdateDomainRolesErrorMessage is the error message for the failed application domain roles update.
+	ApplicationUpdateDomainRolesErrorMessage = "Failed to update the domain roles"
 	// ApplicationUpdateUpdateApplicationErrorMessage is the error message for the failed application update.
 	ApplicationUpdateUpdateApplicationErrorMessage = "Failed to update the application"
 	// AuthFailedToGenerateSessionKeyErrorMessage is the error message for the failed session key generation.
 	AuthFailedToGenerateSessionKeyErrorMessage = "Failed to generate session key"
+	// CalendarFeedFailedToGetCertificatesErrorMessage is the error message for the failed certificates get in the calendar feed.
+	CalendarFeedFailedToGetCertificatesErrorMessage = "Failed to get certificates"
+	// CalendarFeedFailedToGetDomainsErrorMessage is the error message for the failed domains get in the calendar feed.
+	CalendarFeedFailedToGetDomainsErrorMessage = "Failed to get domains"
+	// CalendarFeedFailedToWriteErrorMessage is the error message for the failed calendar generation.
+	CalendarFeedFailedToWriteErrorMessage = "Failed to generate the calendar"
+	// CalendarFeedNotFoundErrorMessage is the error message for the disabled feed and the invalid token.
+	CalendarFeedNotFoundErrorMessage = "Not Found"
 	// ClientClientIDInvalidErrorMessage is the error message prefix for the invalid client id.
 	ClientClientIDInvalidErrorMessage = "Invalid client id"
+	// CertificateCertificateIDInvalidErrorMessage is the error message prefix for the invalid certificate id.
+	CertificateCertificateIDInvalidErrorMessage = "Invalid certificate id"
+	// CertificateCreateAlreadyRegisteredErrorMessage is the error message for the already registered certificate.
+	CertificateCreateAlreadyRegisteredErrorMessage = "The certificate is already registered"
+	// CertificateCreateCreateCertificateErrorMessage is the error message for the failed certificate creation.
+	CertificateCreateCreateCertificateErrorMessage = "Failed to create the certificate"
+	// CertificateCreateFailedToGetDomainsErrorMessage is the error message for the failed domains get in the certificate create.
+	CertificateCreateFailedToGetDomainsErrorMessage = "Failed to get domains"
+	// CertificateCreateFailedToReadFileErrorMessage is the error message for the failed PEM file read.
+	CertificateCreateFailedToReadFileErrorMessage = "Failed to read the PEM file"
+	// CertificateCreateInvalidPEMErrorMessage is the error message for the invalid PEM file.
+	CertificateCreateInvalidPEMErrorMessage = "The file does not contain a valid PEM encoded certificate"
+	// CertificateCreateRequiredFieldMissing is the error message for the required fields in the certificate create.
+	CertificateCreateRequiredFieldMissing = "PEM file is required"
+	// CertificateDeleteFailedToDeleteErrorMessage is the error message for the failed certificate deletion.
+	CertificateDeleteFailedToDeleteErrorMessage = "Failed to delete the certificate"
+	// CertificateFailedToGetCertificateErrorMessage is the error message for the failed certificate get.
+	CertificateFailedToGetCertificateErrorMessage = "Failed to get certificate data"
+	// CertificateListFailedToGetCertificatesErrorMessage is the error message for the failed certificates get.
+	CertificateListFailedToGetCertificatesErrorMessage = "Failed to get certificates"
+	// CertificateListFailedToGetDomainsErrorMessage is the error message for the failed domains get in the certificate list.
+	CertificateListFailedToGetDomainsErrorMessage = "Failed to get domains"
+	// CertificateUpdateDomainIDInvalidErrorMessage is the error message for the invalid domain id in the certificate form.
+	CertificateUpdateDomainIDInvalidErrorMessage = "Invalid domain id"
+	// CertificateUpdateFailedToGetDomainsErrorMessage is the error message for the failed domains get in the certificate update.
+	CertificateUpdateFailedToGetDomainsErrorMessage = "Failed to get domains"
+	// CertificateUpdateRequiredFieldMissing is the error message for the required fields in the certificate update.
+	CertificateUpdateRequiredFieldMissing = "Name is required"
+	// CertificateUpdateUpdateCertificateErrorMessage is the error message for the failed certificate update.
+	CertificateUpdateUpdateCertificateErrorMessage = "Failed to update the certificate"
 	// ClientCreateCreateClientErrorMessage is the error message for the failed client creation.
 	ClientCreateCreateClientErrorMessage = "Failed to create the client"
 	// ClientCreateRequiredFieldMissing is the error message for the required fields in the client create.
@@ -71,6 +117,28 @@ var (
 	ClientUpdateRequiredFieldMissing = "Name is required"
 	// ClientUpdateUpdateClientErrorMessage is the error message for the failed client update.
 	ClientUpdateUpdateClientErrorMessage = "Failed to update the client"
+	// DNSRecordCreateCreateDNSRecordErrorMessage is the error message for the failed dns record creation.
+	DNSRecordCreateCreateDNSRecordErrorMessage = "Failed to create the dns record"
+	// DNSRecordDeleteFailedToDeleteErrorMessage is the error message for the failed dns record deletion.
+	DNSRecordDeleteFailedToDeleteErrorMessage = "Failed to delete the dns record"
+	// DNSRecordFailedToGetDNSRecordErrorMessage is the error message for the failed dns record get.
+	DNSRecordFailedToGetDNSRecordErrorMessage = "Failed to get dns record data"
+	// DNSRecordInvalidErrorMessage is the error message for the invalid dns record data.
+	DNSRecordInvalidErrorMessage = "Invalid dns record"
+	// DNSRecordListFailedToGetDNSRecordsErrorMessage is the error message for the failed dns records get.
+	DNSRecordListFailedToGetDNSRecordsErrorMessage = "Failed to get dns records"
+	// DNSRecordPriorityInvalidErrorMessage is the error message for the invalid MX priority in the dns record form.
+	DNSRecordPriorityInvalidErrorMessage = "Invalid priority"
+	// DNSRecordRecordIDInvalidErrorMessage is the error message prefix for the invalid dns record id.
+	DNSRecordRecordIDInvalidErrorMessage = "Invalid dns record id"
+	// DNSRecordRequiredFieldMissing is the error message for the required fields in the dns record form.
+	DNSRecordRequiredFieldMissing = "Value is required"
+	// DNSRecordTTLInvalidErrorMessage is the error message for the invalid ttl in the dns record form.
+	DNSRecordTTLInvalidErrorMessage = "Invalid ttl"
+	// DNSRecordTypeInvalidErrorMessage is the error message for the invalid type in the dns record form.
+	DNSRecordTypeInvalidErrorMessage = "Invalid record type"
+	// DNSRecordUpdateUpdateDNSRecordErrorMessage is the error message for the failed dns record update.
+	DNSRecordUpdateUpdateDNSRecordErrorMessage = "Failed to update the dns record"
 	// DatabaseCreateCreateDatabaseErrorMessage is the error message for the failed database creation.
 	DatabaseCreateCreateDatabaseErrorMessage = "Failed to create the database"
 	// DatabaseCreateRequiredFieldMissing is the error message for the required fields in the database create.
@@ -87,24 +155,62 @@ var (
 	DatabaseUpdateRequiredFieldMissing = "Name is required"
 	// DatabaseUpdateUpdateDatabaseErrorMessage is the error message for the failed database update.
 	DatabaseUpdateUpdateDatabaseErrorMessage = "Failed to update the database"
-	// DomainCheckSSLFailedToUpdateDomainErrorMessage is the error message for the failed domain update.
-	DomainCheckSSLFailedToUpdateDomainErrorMessage = "Failed to update the domain"
+	// DomainCheckFailedToStartErrorMessage is the error message for the failed domain check job creation.
+	DomainCheckFailedToStartErrorMessage = "Failed to start the domain check"
 	// DomainCreateCreateDomainErrorMessage is the error message for the failed domain creation.
 	DomainCreateCreateDomainErrorMessage = "Failed to create the domain"
+	// DomainCreateFailedToGetClientsErrorMessage is the error message for the failed clients get in the domain create.
+	DomainCreateFailedToGetClientsErrorMessage = "Failed to get clients"
+	// DomainCreateInvalidRegistrationErrorMessage is the error message for the invalid registration data in the domain create.
+	DomainCreateInvalidRegistrationErrorMessage = "Invalid registration data"
 	// DomainCreateRequiredFieldMissing is the error message for the required fields in the domain create.
 	DomainCreateRequiredFieldMissing = "Name is required"
 	// DomainDeleteFailedToDeleteErrorMessage is the error message for the failed domain deletion.
 	DomainDeleteFailedToDeleteErrorMessage = "Failed to delete the domain"
 	// DomainDomainIDInvalidErrorMessage is the error message prefix for the invalid domain id.
 	DomainDomainIDInvalidErrorMessage = "Invalid domain id"
+	// DomainExpiringDaysInvalidErrorMessage is the error message for the invalid number of days in the expiring domains search.
+	DomainExpiringDaysInvalidErrorMessage = "Invalid number of days"
+	// DomainExpiringFailedToGetDomainsErrorMessage is the error message for the failed expiring domains get.
+	DomainExpiringFailedToGetDomainsErrorMessage = "Failed to get domains"
+	// DomainFailedToGetCertificatesErrorMessage is the error message for the failed certificates get of the domain.
+	DomainFailedToGetCertificatesErrorMessage = "Failed to get certificates"
+	// DomainFailedToGetDNSRecordsErrorMessage is the error message for the failed dns records get of the domain.
+	DomainFailedToGetDNSRecordsErrorMessage = "Failed to get dns records"
 	// DomainFailedToGetDomainErrorMessage is the error message for the failed domain get.
 	DomainFailedToGetDomainErrorMessage = "Failed to get domain data"
+	// DomainListFailedToGetCertificatesErrorMessage is the error message for the failed certificates get.
+	DomainListFailedToGetCertificatesErrorMessage = "Failed to get certificates"
 	// DomainListFailedToGetDomainsErrorMessage is the error message for the failed domains get.
 	DomainListFailedToGetDomainsErrorMessage = "Failed to get domains"
+	// DomainRDAPLookupDisabledErrorMessage is the error message for the disabled registration lookup.
+	DomainRDAPLookupDisabledErrorMessage = "The registration lookup is disabled"
+	// DomainRDAPLookupFailedErrorMessage is the error message for the failed registration lookup.
+	DomainRDAPLookupFailedErrorMessage = "Failed to get the registration data"
+	// DomainRDAPLookupFailedToUpdateDomainErrorMessage is the error message for the failed domain update after the registration lookup.
+	DomainRDAPLookupFailedToUpdateDomainErrorMessage = "Failed to update the domain"
+	// DomainUpdateFailedToGetClientsErrorMessage is the error message for the failed clients get in the domain update.
+	DomainUpdateFailedToGetClientsErrorMessage = "Failed to get clients"
+	// DomainUpdateInvalidRegistrationErrorMessage is the error message for the invalid registration data in the domain update.
+	DomainUpdateInvalidRegistrationErrorMessage = "Invalid registration data"
 	// DomainUpdateRequiredFieldMissing is the error message for the required fields in the domain update.
 	DomainUpdateRequiredFieldMissing = "Name is required"
 	// DomainUpdateUpdateDomainErrorMessage is the error message for the failed domain update.
 	DomainUpdateUpdateDomainErrorMessage = "Failed to update the domain"
+	// DomainZoneExportFailedToExportErrorMessage is the error message for the failed zone file export.
+	DomainZoneExportFailedToExportErrorMessage = "Failed to export the zone file"
+	// DomainZoneImportFailedToCreateDNSRecordErrorMessage is the error message for the failed dns record creation in the zone import.
+	DomainZoneImportFailedToCreateDNSRecordErrorMessage = "Failed to create the dns record"
+	// DomainZoneImportFailedToCreateDomainErrorMessage is the error message for the failed domain creation in the zone import.
+	DomainZoneImportFailedToCreateDomainErrorMessage = "Failed to create the domain"
+	// DomainZoneImportFailedToUpdateDomainErrorMessage is the error message for the failed domain update in the zone import.
+	DomainZoneImportFailedToUpdateDomainErrorMessage = "Failed to update the domain"
+	// DomainZoneImportFailedToGetDNSRecordsErrorMessage is the error message for the failed dns records get in the zone import.
+	DomainZoneImportFailedToGetDNSRecordsErrorMessage = "Failed to get dns records"
+	// DomainZoneImportInvalidZoneFileErrorMessage is the error message for the invalid zone file.
+	DomainZoneImportInvalidZoneFileErrorMessage = "Invalid zone file"
+	// DomainZoneImportRequiredFieldMissing is the error message for the required fields in the zone import.
+	DomainZoneImportRequiredFieldMissing = "Zone file is required"
 	// EnvironmentCreateCreateEnvironmentErrorMessage is the error message for the failed environment creation.
 	EnvironmentCreateCreateEnvironmentErrorMessage = "Failed to create the environment"
 	// EnvironmentCreateDatabaseIDInvalidErrorMessage is the error message for the invalid database id in the environment form.
@@ -162,6 +268,44 @@ var (
 	FrameworkUpdateRequiredFieldMissing = "Name is required"
 	// FrameworkUpdateUpdateFrameworkErrorMessage is the error message for the failed framework update.
 	FrameworkUpdateUpdateFrameworkErrorMessage = "Failed to update the framework"
+	// ImportFailedToReadFileErrorMessage is the error message for the failed uploaded file read.
+	ImportFailedToReadFileErrorMessage = "Failed to read the file."
+	// ImportFailedToSaveFileErrorMessage is the error message for the failed file save.
+	ImportFailedToSaveFileErrorMessage = "Failed to save the file"
+	// ImportFailedToStartErrorMessage is the error message for the failed import job creation.
+	ImportFailedToStartErrorMessage = "Failed to start the import"
+	// ImportInvalidFileIDErrorMessage is the error message for the invalid file id in the import forms.
+	ImportInvalidFileIDErrorMessage = "Invalid file id"
+	// ImportInvalidMappingErrorMessage is the error message for the invalid column index in the import mapping forms.
+	ImportInvalidMappingErrorMessage = "Invalid mapping"
+	// ImportInvalidOptionsErrorMessage is the error message for the unknown format, csv delimiter or encoding in the import forms.
+	ImportInvalidOptionsErrorMessage = "Invalid format, delimiter or encoding"
+	// ImportUnknownResourceErrorMessage is the error message for the resources that could not be imported.
+	ImportUnknownResourceErrorMessage = "Unknown import resource"
+	// JobCancelFailedErrorMessage is the error message for the failed job cancellation.
+	JobCancelFailedErrorMessage = "Failed to cancel the job"
+	// JobFailedToGetJobErrorMessage is the error message for the failed job get.
+	JobFailedToGetJobErrorMessage = "Failed to get job data"
+	// JobListFailedToGetJobsErrorMessage is the error message for the failed jobs get.
+	JobListFailedToGetJobsErrorMessage = "Failed to get jobs"
+	// JobRetryFailedErrorMessage is the error message for the failed job retry.
+	JobRetryFailedErrorMessage = "Failed to retry the job"
+	// JobStatusConflictErrorMessage is the error message for the action that is not allowed in the status of the job.
+	JobStatusConflictErrorMessage = "The action is not allowed in the current status of the job."
+	// JobStatusInvalidErrorMessage is the error message for the invalid status in the job filter.
+	JobStatusInvalidErrorMessage = "Invalid job status"
+
+	// MetricsUnauthorizedErrorMessage is the error message for the metrics request without valid token.
+	MetricsUnauthorizedErrorMessage = "Unauthorized"
+	// NotAuthenticatedErrorMessage is the error message for the requests without valid session.
+	NotAuthenticatedErrorMessage = "Not authenticated"
+
+	// NotificationSettingsFailedToGetSubscriptionsErrorMessage is the error message for the failed notification subscriptions get.
+	NotificationSettingsFailedToGetSubscriptionsErrorMessage = "Failed to get the notification settings"
+	// NotificationSettingsFailedToSaveSubscriptionsErrorMessage is the error message for the failed notification subscriptions save.
+	NotificationSettingsFailedToSaveSubscriptionsErrorMessage = "Failed to save the notification settings"
+	// NotificationSettingsThresholdInvalidErrorMessage is the error message for the invalid threshold in the notification settings form.
+	NotificationSettingsThresholdInvalidErrorMessage = "Invalid threshold, it has to be a positive number"
 
 	// PoolCreateCreatePoolErrorMessage is the error message for the failed pool creation.
 	PoolCreateCreatePoolErrorMessage = "Failed to create the pool"
@@ -195,6 +339,14 @@ var (
 	ProjectUpdateRequiredFieldMissing = "Name is required"
 	// ProjectUpdateUpdateProjectErrorMessage is the error message for the failed project update.
 	ProjectUpdateUpdateProjectErrorMessage = "Failed to update the project"
+	// RepositoryConflictErrorMessage is the friendly message of the unique constraint violations.
+	RepositoryConflictErrorMessage = "An item with the same name already exists"
+	// RenderTemplateFailedErrorMessage is the error message for the failed page rendering.
+	RenderTemplateFailedErrorMessage = "Failed to render the page"
+	// RepositoryForeignKeyErrorMessage is the friendly message of the foreign key violations.
+	RepositoryForeignKeyErrorMessage = "The item is referenced by other items or it references a missing item"
+	// RepositoryNotFoundErrorMessage is the friendly message of the missing items.
+	RepositoryNotFoundErrorMessage = "The requested item does not exist"
 	// RoleFailedToGetRoleErrorMessage is the error message for the failed role get.
 	RoleFailedToGetRoleErrorMessage = "Failed to get role data"
 	// RoleFailedToGetResourcesErrorMessage is the error message for the failed resources get.
@@ -231,6 +383,14 @@ var (
 	RuntimeUpdateRequiredFieldMissing = "Name is required"
 	// RuntimeUpdateUpdateRuntimeErrorMessage is the error message for the failed runtime update.
 	RuntimeUpdateUpdateRuntimeErrorMessage = "Failed to update the runtime"
+	// ScheduledTaskFailedToGetTaskErrorMessage is the error message for the failed scheduled task get.
+	ScheduledTaskFailedToGetTaskErrorMessage = "Failed to get scheduled task data"
+	// ScheduledTaskListFailedToGetTasksErrorMessage is the error message for the failed scheduled tasks get.
+	ScheduledTaskListFailedToGetTasksErrorMessage = "Failed to get scheduled tasks"
+	// ScheduledTaskScheduleInvalidErrorMessage is the error message for the invalid cron expression in the scheduled task form.
+	ScheduledTaskScheduleInvalidErrorMessage = "Invalid schedule, it has to be a cron expression with a next run, eg. 0 3 * * *"
+	// ScheduledTaskUpdateFailedErrorMessage is the error message for the failed scheduled task update.
+	ScheduledTaskUpdateFailedErrorMessage = "Failed to update the scheduled task"
 	// ServerCreateCreateServerErrorMessage is the error message for the failed server creation.
 	ServerCreateCreateServerErrorMessage = "Failed to create the server"
 	// ServerCreateRequiredFieldMissing is the error message for the required fields in the server create.
@@ -267,6 +427,14 @@ var (
 	ServerUpdatePoolIDInvalidErrorMessage = "Invalid pool id"
 	// ServerUpdateRuntimeIDInvalidErrorMessage is the error message for the invalid runtime id in the server form.
 	ServerUpdateRuntimeIDInvalidErrorMessage = "Invalid runtime id"
+
+	// ServiceDiscoveryFailedToGetApplicationsErrorMessage is the error message for the failed applications get of the service discovery.
+	ServiceDiscoveryFailedToGetApplicationsErrorMessage = "Failed to get applications"
+	// ServiceDiscoveryFailedToGetEnvironmentErrorMessage is the error message for the failed environment get of the service discovery.
+	ServiceDiscoveryFailedToGetEnvironmentErrorMessage = "Failed to get environment"
+	// ServiceDiscoveryNotFoundErrorMessage is the error message of the service discovery without metrics token.
+	ServiceDiscoveryNotFoundErrorMessage = "Not Found"
+
 	// UserCreateRequiredFieldMissing is the error message for the required fields in the user create.
 	UserCreateRequiredFieldMissing = "Name, email, password and role are required"
 	// UserCreateCreateUserErrorMessagePrefix is the error message prefix for the failed user creation.
@@ -291,4 +459,24 @@ var (
 	UserFailedToGetUserErrorMessage = "Failed to get user data"
 	// UserFailedToGetRolesErrorMessage is the error message for the failed roles get.
 	UserFailedToGetRolesErrorMessage = "Failed to get roles"
+	// WebhookCreateCreateWebhookErrorMessage is the error message for the failed webhook creation.
+	WebhookCreateCreateWebhookErrorMessage = "Failed to create the webhook"
+	// WebhookDeleteFailedToDeleteErrorMessage is the error message for the failed webhook deletion.
+	WebhookDeleteFailedToDeleteErrorMessage = "Failed to delete the webhook"
+	// WebhookDeliveryListFailedToGetDeliveriesErrorMessage is the error message for the failed deliveries get.
+	WebhookDeliveryListFailedToGetDeliveriesErrorMessage = "Failed to get the webhook deliveries"
+	// WebhookEventTypeInvalidErrorMessage is the error message for the invalid event type in the webhook form.
+	WebhookEventTypeInvalidErrorMessage = "Invalid event type"
+	// WebhookFailedToGetWebhookErrorMessage is the error message for the failed webhook get.
+	WebhookFailedToGetWebhookErrorMessage = "Failed to get webhook data"
+	// WebhookListFailedToGetWebhooksErrorMessage is the error message for the failed webhooks get.
+	WebhookListFailedToGetWebhooksErrorMessage = "Failed to get webhooks"
+	// WebhookRequiredFieldMissing is the error message for the required fields in the webhook form.
+	WebhookRequiredFieldMissing = "Name, url, secret and at least one event type are required"
+	// WebhookUpdateUpdateWebhookErrorMessage is the error message for the failed webhook update.
+	WebhookUpdateUpdateWebhookErrorMessage = "Failed to update the webhook"
+	// WebhookURLInvalidErrorMessage is the error message for the invalid webhook url.
+	WebhookURLInvalidErrorMessage = "Invalid url, it has to be an absolute http or https url"
+	// WebhookWebhookIDInvalidErrorMessage is the error message for the invalid webhook id.
+	WebhookWebhookIDInvalidErrorMessage = "Invalid webhook id"
 )
@@ -0,0 +1,275 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/akosgarai/projectregister/pkg/controller/response"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+const (
+	// webhookDeliveryListLimit is the number of the displayed deliveries.
+	webhookDeliveryListLimit = 100
+)
+
+// WebhookViewController is the controller for the webhook view page.
+// GET /admin/webhook/view/{webhookId}
+// It renders the webhook view page.
+func (c *Controller) WebhookViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("webhooks.view") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	webhook, statusCode, err := c.webhookViewData(r)
+	if err != nil {
+		c.renderer.Error(w, statusCode, WebhookFailedToGetWebhookErrorMessage, err)
+		return
+	}
+	content := response.NewWebhookDetailResponse(currentUser, webhook)
+	err = c.renderer.Template.RenderTemplate(w, "detail-page.html", content)
+	if err != nil {
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+	}
+}
+
+// webhookViewData gets the request as input, and returns the webhook data, status code and error.
+func (c *Controller) webhookViewData(r *http.Request) (*model.Webhook, int, error) {
+	vars := mux.Vars(r)
+	webhookIDVariable := vars["webhookId"]
+	// it has to be converted to int64
+	webhookID, err := strconv.ParseInt(webhookIDVariable, 10, 64)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	webhook, err := c.repositoryContainer.GetWebhookRepository().GetWebhookByID(webhookID)
+	if err != nil {
+		return nil, repositoryErrorStatus(err), err
+	}
+	return webhook, http.StatusOK, nil
+}
+
+// webhookFromForm sets the webhook fields from the submitted form.
+// The empty secret keeps the current one, so that it is not needed to be typed again on update.
+// It returns the error message and the error if the form is invalid.
+func webhookFromForm(r *http.Request, webhook *model.Webhook) (string, error) {
+	r.ParseForm()
+	name := r.FormValue("name")
+	webhookURL := r.FormValue("url")
+	if name == "" || webhookURL == "" {
+		return WebhookRequiredFieldMissing, errors.New("missing required field")
+	}
+	parsedURL, err := url.Parse(webhookURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return WebhookURLInvalidErrorMessage, errors.New("invalid url: " + webhookURL)
+	}
+	// the event types are sent as the 1-based indexes of the options.
+	options := response.WebhookEventTypeOptions()
+	eventTypes := []string{}
+	for _, indexRaw := range r.Form["event_types"] {
+		index, err := strconv.ParseInt(indexRaw, 10, 64)
+		if err != nil {
+			return WebhookEventTypeInvalidErrorMessage, err
+		}
+		eventType, ok := options[index]
+		if !ok {
+			return WebhookEventTypeInvalidErrorMessage, errors.New("unknown event type: " + indexRaw)
+		}
+		eventTypes = append(eventTypes, eventType)
+	}
+	if len(eventTypes) == 0 {
+		return WebhookRequiredFieldMissing, errors.New("missing event types")
+	}
+	secret := r.FormValue("secret")
+	if secret == "" && webhook.Secret == "" {
+		return WebhookRequiredFieldMissing, errors.New("missing secret")
+	}
+	webhook.Name = name
+	webhook.URL = webhookURL
+	webhook.EventTypes = eventTypes
+	webhook.Active = r.FormValue("active") == "1"
+	if secret != "" {
+		webhook.Secret = secret
+	}
+	return "", nil
+}
+
+// WebhookCreateViewController is the controller for the webhook create view.
+// On case of get request, it returns the webhook create page.
+// On case of post request, it creates the webhook and redirects to the list page.
+func (c *Controller) WebhookCreateViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("webhooks.create") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	if r.Method == http.MethodGet {
+		content := response.NewCreateWebhookResponse(currentUser)
+		err := c.renderer.Template.RenderTemplate(w, "form-page.html", content)
+		if err != nil {
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+		}
+	}
+
+	if r.Method == http.MethodPost {
+		webhook := &model.Webhook{}
+		if message, err := webhookFromForm(r, webhook); err != nil {
+			c.renderer.Error(w, http.StatusBadRequest, message, err)
+			return
+		}
+		_, err := c.repositoryContainer.GetWebhookRepository().CreateWebhook(webhook.Name, webhook.URL, webhook.Secret, webhook.EventTypes, webhook.Active)
+		if err != nil {
+			c.renderRepositoryError(w, WebhookCreateCreateWebhookErrorMessage, err)
+			return
+		}
+		http.Redirect(w, r, "/admin/webhook/list", http.StatusSeeOther)
+		return
+	}
+}
+
+// WebhookUpdateViewController is the controller for the webhook update view.
+// On case of get request, it returns the webhook update page.
+// On case of post request, it updates the webhook and redirects to the list page.
+func (c *Controller) WebhookUpdateViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("webhooks.update") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	webhook, statusCode, err := c.webhookViewData(r)
+	if err != nil {
+		c.renderer.Error(w, statusCode, WebhookFailedToGetWebhookErrorMessage, err)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		content := response.NewUpdateWebhookResponse(currentUser, webhook)
+		err = c.renderer.Template.RenderTemplate(w, "form-page.html", content)
+		if err != nil {
+			c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+		}
+	}
+
+	if r.Method == http.MethodPost {
+		if message, err := webhookFromForm(r, webhook); err != nil {
+			c.renderer.Error(w, http.StatusBadRequest, message, err)
+			return
+		}
+		err = c.repositoryContainer.GetWebhookRepository().UpdateWebhook(webhook)
+		if err != nil {
+			c.renderRepositoryError(w, WebhookUpdateUpdateWebhookErrorMessage, err)
+			return
+		}
+		http.Redirect(w, r, "/admin/webhook/list", http.StatusSeeOther)
+		return
+	}
+}
+
+// WebhookDeleteViewController is the controller for the webhook delete form.
+// It is responsible for deleting a webhook with its delivery history.
+// It redirects to the webhook list page.
+func (c *Controller) WebhookDeleteViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("webhooks.delete") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	vars := mux.Vars(r)
+	webhookIDVariable := vars["webhookId"]
+	// it has to be converted to int64
+	webhookID, err := strconv.ParseInt(webhookIDVariable, 10, 64)
+	if err != nil {
+		c.renderer.Error(w, http.StatusBadRequest, WebhookWebhookIDInvalidErrorMessage, err)
+		return
+	}
+	err = c.repositoryContainer.GetWebhookRepository().DeleteWebhook(webhookID)
+	if err != nil {
+		c.renderRepositoryError(w, WebhookDeleteFailedToDeleteErrorMessage, err)
+		return
+	}
+	http.Redirect(w, r, "/admin/webhook/list", http.StatusSeeOther)
+}
+
+// WebhookListViewController is the controller for the webhook list view.
+func (c *Controller) WebhookListViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("webhooks.view") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	// Define the empty filter here.
+	filter := model.NewWebhookFilter()
+	if r.Method == http.MethodPost {
+		// On case of post request, the filter is not empty.
+		// Set the values based on the form values.
+		filter.Name = r.FormValue("name")
+	}
+	webhooks, err := c.repositoryContainer.GetWebhookRepository().GetWebhooks(filter)
+	if err != nil {
+		c.renderRepositoryError(w, WebhookListFailedToGetWebhooksErrorMessage, err)
+		return
+	}
+	content := response.NewWebhookListResponse(currentUser, webhooks, filter)
+	err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
+	if err != nil {
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+	}
+}
+
+// WebhookDeliveryListViewController is the controller for the delivery history of a webhook.
+// GET /admin/webhook/deliveries/{webhookId}
+// It lists the latest delivery attempts with the response status.
+func (c *Controller) WebhookDeliveryListViewController(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := c.CurrentUser(r)
+	if err != nil {
+		c.renderNotAuthenticated(w, r, err)
+		return
+	}
+	if !currentUser.HasPrivilege("webhooks.view") {
+		c.renderer.Error(w, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	webhook, statusCode, err := c.webhookViewData(r)
+	if err != nil {
+		c.renderer.Error(w, statusCode, WebhookFailedToGetWebhookErrorMessage, err)
+		return
+	}
+	filter := model.NewWebhookDeliveryFilter()
+	filter.WebhookIDs = []string{strconv.FormatInt(webhook.ID, 10)}
+	filter.Limit = webhookDeliveryListLimit
+	deliveries, err := c.repositoryContainer.GetWebhookDeliveryRepository().GetWebhookDeliveries(filter)
+	if err != nil {
+		c.renderRepositoryError(w, WebhookDeliveryListFailedToGetDeliveriesErrorMessage, err)
+		return
+	}
+	content := response.NewWebhookDeliveryListResponse(currentUser, webhook, deliveries)
+	err = c.renderer.Template.RenderTemplate(w, "listing-page.html", content)
+	if err != nil {
+		c.renderer.Error(w, http.StatusInternalServerError, RenderTemplateFailedErrorMessage, err)
+	}
+}
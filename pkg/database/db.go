@@ -1,7 +1,11 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"time"
 
 	// pq is the driver for the postgres database
 	_ "github.com/lib/pq"
@@ -9,6 +13,13 @@ import (
 	"github.com/akosgarai/projectregister/pkg/config"
 )
 
+const (
+	// connectTimeout is the timeout of the connection check on connect.
+	connectTimeout = 10 * time.Second
+	// migrationTable is the table of the applied migration version.
+	migrationTable = "schema_migrations"
+)
+
 // DB type for database
 type DB struct {
 	envConfig *config.Environment
@@ -23,20 +34,93 @@ func NewDB(envConfig *config.Environment) *DB {
 }
 
 // Connect connects to the database
+// The connection is checked with a ping, so that the unreachable database is reported on startup.
 func (d *DB) Connect() error {
 	db, err := sql.Open("postgres", getDatabaseURL(d.envConfig))
 	if err != nil {
 		return err
 	}
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return err
+	}
 	d.database = db
 	return nil
 }
 
+// Ping checks the database connection
+func (d *DB) Ping(ctx context.Context) error {
+	return d.database.PingContext(ctx)
+}
+
+// MigrationVersion returns the applied migration version and the dirty flag
+// The version is 0 if no migration has been applied.
+func (d *DB) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	var version int64
+	var dirty bool
+	err := d.database.QueryRowContext(ctx, "SELECT version, dirty FROM "+migrationTable+" LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return uint(version), dirty, nil
+}
+
+// WithAdvisoryLock runs the function while it holds the session level advisory lock of the key.
+// It returns false without running the function if the lock is held by another session, eg. another instance.
+// The lock is held on a dedicated connection, that is discarded if the unlock fails, so that the lock is released.
+func (d *DB) WithAdvisoryLock(ctx context.Context, key int64, fn func()) (bool, error) {
+	conn, err := d.database.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked); err != nil {
+		return false, err
+	}
+	if !locked {
+		return false, nil
+	}
+	defer func() {
+		// the unlock is executed even if the context is cancelled during the function.
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key); err != nil {
+			conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+	}()
+	fn()
+	return true, nil
+}
+
+// WithTransaction runs the function in a transaction. The transaction is committed if the function succeeds,
+// otherwise it is rolled back, so that the multi statement writes are not stored partially.
+func (d *DB) WithTransaction(fn func(tx *sql.Tx) error) error {
+	tx, err := d.database.Begin()
+	if err != nil {
+		return err
+	}
+	// the rollback after the commit is a no-op, it releases the transaction on error and panic.
+	defer tx.Rollback()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 // Close closes the database connection
 func (d *DB) Close() error {
 	return d.database.Close()
 }
 
+// Stats returns the connection pool statistics
+func (d *DB) Stats() sql.DBStats {
+	return d.database.Stats()
+}
+
 // Exec executes a query
 func (d *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
 	return d.database.Exec(query, args...)
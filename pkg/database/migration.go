@@ -1,34 +1,128 @@
 package database
 
 import (
+	"errors"
+	"io/fs"
+	"os"
+
 	"github.com/golang-migrate/migrate/v4"
 	// import the postgres driver
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 
+	"github.com/akosgarai/projectregister/db"
 	"github.com/akosgarai/projectregister/pkg/config"
 )
 
 // Migration type
 type Migration struct {
 	envConfig *config.Environment
+	// migrationFiles is the file system of the migrations. It is the embedded migration directory,
+	// or the migration directory on the disk if the assets are loaded from the disk.
+	migrationFiles fs.FS
 }
 
 // NewMigration creates a new instance of the migration.
 func NewMigration(envConfig *config.Environment) *Migration {
+	migrationFiles := db.Migrations()
+	if envConfig.GetAssetsFromDisk() {
+		migrationFiles = os.DirFS(envConfig.GetMigrationDirectoryPath())
+	}
 	return &Migration{
-		envConfig: envConfig,
+		envConfig:      envConfig,
+		migrationFiles: migrationFiles,
+	}
+}
+
+// source returns the migration source of the migration files.
+func (m *Migration) source() (source.Driver, error) {
+	return iofs.New(m.migrationFiles, ".")
+}
+
+// open opens the migrations on the database.
+func (m *Migration) open() (*migrate.Migrate, error) {
+	migrations, err := m.source()
+	if err != nil {
+		return nil, err
 	}
+	return migrate.NewWithSourceInstance("iofs", migrations, getDatabaseURL(m.envConfig))
+}
+
+// run opens the migrations, executes the action and closes the migrations.
+func (m *Migration) run(action func(migration *migrate.Migrate) error) error {
+	migration, err := m.open()
+	if err != nil {
+		return err
+	}
+	defer migration.Close()
+	return action(migration)
 }
 
 // Up executes the migrations
+// It does not fail if the database is already up to date.
 func (m *Migration) Up() error {
-	migration, err := migrate.New(
-		"file://"+m.envConfig.GetMigrationDirectoryPath(),
-		getDatabaseURL(m.envConfig))
-	if err != nil {
+	return m.run(func(migration *migrate.Migrate) error {
+		if err := migration.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			return err
+		}
+		return nil
+	})
+}
+
+// Down rolls back the given number of migrations.
+func (m *Migration) Down(steps int) error {
+	if steps < 1 {
+		return errors.New("the number of steps has to be positive")
+	}
+	return m.run(func(migration *migrate.Migrate) error {
+		return migration.Steps(-steps)
+	})
+}
+
+// Force sets the migration version without executing the migrations and clears the dirty flag.
+// It is used for the recovery of a failed migration.
+func (m *Migration) Force(version int) error {
+	return m.run(func(migration *migrate.Migrate) error {
+		return migration.Force(version)
+	})
+}
+
+// Version returns the applied migration version and the dirty flag.
+// The version is 0 if no migration has been applied.
+func (m *Migration) Version() (uint, bool, error) {
+	var version uint
+	var dirty bool
+	err := m.run(func(migration *migrate.Migrate) error {
+		var err error
+		version, dirty, err = migration.Version()
+		if errors.Is(err, migrate.ErrNilVersion) {
+			return nil
+		}
 		return err
+	})
+	return version, dirty, err
+}
+
+// LatestVersion returns the version of the latest migration.
+func (m *Migration) LatestVersion() (uint, error) {
+	migrations, err := m.source()
+	if err != nil {
+		return 0, err
+	}
+	defer migrations.Close()
+	version, err := migrations.First()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		next, err := migrations.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
 	}
-	migration.Up()
-	return nil
 }
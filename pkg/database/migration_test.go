@@ -0,0 +1,42 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/akosgarai/projectregister/pkg/config"
+)
+
+// TestLatestVersion tests the latest version of the migration directory.
+func TestLatestVersion(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"000001_init.up.sql", "000001_init.down.sql", "000003_users.up.sql", "000003_users.down.sql", "000002_roles.up.sql"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("SELECT 1;"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	migration := NewMigration(config.NewEnvironment(map[string]string{config.AssetsFromDiskEnvName: "true", config.MigrationDirectoryPathEnvName: dir}))
+	version, err := migration.LatestVersion()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if version != 3 {
+		t.Errorf("Invalid latest version. Expected: 3, got: %d", version)
+	}
+}
+
+// TestLatestVersionEmbedded tests that the embedded migrations are the migrations of the db directory.
+func TestLatestVersionEmbedded(t *testing.T) {
+	embedded, err := NewMigration(config.DefaultEnvironment()).LatestVersion()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	disk, err := NewMigration(config.NewEnvironment(map[string]string{config.AssetsFromDiskEnvName: "true", config.MigrationDirectoryPathEnvName: "../../db/migrations"})).LatestVersion()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if embedded != disk {
+		t.Errorf("Invalid embedded latest version. Expected: %d, got: %d", disk, embedded)
+	}
+}
@@ -1,17 +1,20 @@
 package repository
 
 import (
+	"database/sql"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/event"
 	"github.com/akosgarai/projectregister/pkg/model"
 )
 
 // ApplicationRepository type
 type ApplicationRepository struct {
-	db *database.DB
+	db     *database.DB
+	events *event.Bus
 }
 
 // NewApplicationRepository creates a new application repository
@@ -27,23 +30,29 @@ func NewApplicationRepository(db *database.DB) *ApplicationRepository {
 func (a *ApplicationRepository) CreateApplication(
 	clientID, projectID, environmentID, databaseID, runtimeID, poolID, frameworkID int64,
 	repository, branch, dbName, dbUser, docRoot string,
-	domains []int64) (*model.Application, error) {
+	domains []*model.ApplicationDomain) (*model.Application, error) {
 	var appID int64
-	query := "INSERT INTO applications (client_id, project_id, env_id, database_id, runtime_id, pool_id, repository, branch, db_name, db_user, framework_id, document_root) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id"
-	err := a.db.QueryRow(query, clientID, projectID, environmentID, databaseID, runtimeID, poolID, repository, branch, dbName, dbUser, frameworkID, docRoot).Scan(&appID)
-	if err != nil {
-		return nil, err
-	}
-	// create the application domain relations
-	for _, domainID := range domains {
-		query = "INSERT INTO application_to_domains (application_id, domain_id) VALUES ($1, $2)"
-		_, err = a.db.Exec(query, appID, domainID)
+	// the application and its domain relations are created in one transaction,
+	// so that the failed relation, eg. a second primary domain does not leave an application without domains.
+	err := a.db.WithTransaction(func(tx *sql.Tx) error {
+		query := "INSERT INTO applications (client_id, project_id, env_id, database_id, runtime_id, pool_id, repository, branch, db_name, db_user, framework_id, document_root) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id"
+		err := tx.QueryRow(query, clientID, projectID, environmentID, databaseID, runtimeID, poolID, repository, branch, dbName, dbUser, frameworkID, docRoot).Scan(&appID)
 		if err != nil {
-			return nil, err
+			return err
 		}
+		return insertApplicationDomains(tx, appID, domains)
+	})
+	if err != nil {
+		return nil, typedError(err)
+	}
+
+	application, err := a.GetApplicationByID(appID)
+	if err != nil {
+		return nil, typedError(err)
 	}
+	a.events.Publish(event.New(event.ResourceApplication, event.ActionCreated, application.ID, application))
 
-	return a.GetApplicationByID(appID)
+	return application, nil
 }
 
 // GetApplicationByID gets a application by id
@@ -71,7 +80,7 @@ func (a *ApplicationRepository) GetApplicationByID(id int64) (*model.Application
 		&application.Repository, &application.Branch, &application.DBName, &application.DBUser, &application.DocumentRoot, &application.CreatedAt, &application.UpdatedAt)
 
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 
 	return a.withRelations(&application)
@@ -81,31 +90,44 @@ func (a *ApplicationRepository) GetApplicationByID(id int64) (*model.Application
 // the input parameter is the application
 // it returns an error
 func (a *ApplicationRepository) UpdateApplication(application *model.Application) error {
-	query := "UPDATE applications SET client_id = $1, project_id = $2, env_id = $3, database_id = $4, runtime_id = $5, pool_id = $6, repository = $7, branch = $8, db_name = $9, db_user = $10, framework_id = $11, document_root = $12, updated_at = $13 WHERE id = $14"
-	now := time.Now().Format("2006-01-02 15:04:05.999999-07:00")
-	_, err := a.db.Exec(query, application.Client.ID, application.Project.ID, application.Environment.ID, application.Database.ID, application.Runtime.ID, application.Pool.ID, application.Repository, application.Branch, application.DBName, application.DBUser, application.Framework.ID, application.DocumentRoot, now, application.ID)
-
-	if err != nil {
-		return err
-	}
-
-	// update the application domain relations
-	query = "DELETE FROM application_to_domains WHERE application_id = $1"
-	_, err = a.db.Exec(query, application.ID)
-	if err != nil {
-		return err
-	}
+	domains := []*model.ApplicationDomain{}
 	for _, domain := range application.Domains {
-		query = "INSERT INTO application_to_domains (application_id, domain_id) VALUES ($1, $2)"
-		_, err = a.db.Exec(query, application.ID, domain.ID)
+		role := application.DomainRole(domain.ID)
+		domains = append(domains, &model.ApplicationDomain{DomainID: domain.ID, Role: role.Role, RedirectTarget: role.RedirectTarget, RedirectStatus: role.RedirectStatus})
+	}
+	// the application and its rewritten domain relations are stored in one transaction,
+	// so that the failed relation does not leave the application without domains.
+	err := a.db.WithTransaction(func(tx *sql.Tx) error {
+		query := "UPDATE applications SET client_id = $1, project_id = $2, env_id = $3, database_id = $4, runtime_id = $5, pool_id = $6, repository = $7, branch = $8, db_name = $9, db_user = $10, framework_id = $11, document_root = $12, updated_at = $13 WHERE id = $14"
+		now := time.Now().Format("2006-01-02 15:04:05.999999-07:00")
+		_, err := tx.Exec(query, application.Client.ID, application.Project.ID, application.Environment.ID, application.Database.ID, application.Runtime.ID, application.Pool.ID, application.Repository, application.Branch, application.DBName, application.DBUser, application.Framework.ID, application.DocumentRoot, now, application.ID)
 		if err != nil {
 			return err
 		}
+		if _, err := tx.Exec("DELETE FROM application_to_domains WHERE application_id = $1", application.ID); err != nil {
+			return err
+		}
+		return insertApplicationDomains(tx, application.ID, domains)
+	})
+	if err != nil {
+		return typedError(err)
 	}
+	a.events.Publish(event.New(event.ResourceApplication, event.ActionUpdated, application.ID, application))
 
 	return nil
 }
 
+// insertApplicationDomains creates the domain relations of the application in the transaction
+func insertApplicationDomains(tx *sql.Tx, applicationID int64, domains []*model.ApplicationDomain) error {
+	query := "INSERT INTO application_to_domains (application_id, domain_id, role, redirect_target, redirect_status) VALUES ($1, $2, $3, $4, $5)"
+	for _, domain := range domains {
+		if _, err := tx.Exec(query, applicationID, domain.DomainID, domain.Role, domain.RedirectTarget, domain.RedirectStatus); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // DeleteApplication deletes a application
 // the input parameter is the application id
 // it returns an error
@@ -113,11 +135,15 @@ func (a *ApplicationRepository) DeleteApplication(id int64) error {
 	query := "DELETE FROM application_to_domains WHERE application_id = $1"
 	_, err := a.db.Exec(query, id)
 	if err != nil {
-		return err
+		return typedError(err)
 	}
 	query = "DELETE FROM applications WHERE id = $1"
 	_, err = a.db.Exec(query, id)
-	return err
+	if err != nil {
+		return typedError(err)
+	}
+	a.events.Publish(event.New(event.ResourceApplication, event.ActionDeleted, id, nil))
+	return nil
 }
 
 // GetApplications gets all applications
@@ -193,18 +219,18 @@ func (a *ApplicationRepository) GetApplications(filters *model.ApplicationFilter
 	}
 	rows, err := a.db.Query(query, params...)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
 		var id int64
 		err = rows.Scan(&id)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		applicationWithRelations, err := a.GetApplicationByID(id)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		applications = append(applications, applicationWithRelations)
 	}
@@ -214,24 +240,25 @@ func (a *ApplicationRepository) GetApplications(filters *model.ApplicationFilter
 // withRelations function gets a application as input and returns a application with the relations
 func (a *ApplicationRepository) withRelations(application *model.Application) (*model.Application, error) {
 	domainRepository := NewDomainRepository(a.db)
-	// get the application domains
-	query := "SELECT domain_id FROM application_to_domains WHERE application_id = $1"
+	// get the application domains, the primary domain is the first one
+	query := "SELECT domain_id, role, redirect_target, redirect_status FROM application_to_domains WHERE application_id = $1 ORDER BY role <> 'primary', domain_id"
 	rows, err := a.db.Query(query, application.ID)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
-		var domainID int64
-		err = rows.Scan(&domainID)
+		role := &model.ApplicationDomain{}
+		err = rows.Scan(&role.DomainID, &role.Role, &role.RedirectTarget, &role.RedirectStatus)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
-		domain, err := domainRepository.GetDomainByID(domainID)
+		domain, err := domainRepository.GetDomainByID(role.DomainID)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		application.Domains = append(application.Domains, domain)
+		application.DomainRoles = append(application.DomainRoles, role)
 	}
 
 	return application, nil
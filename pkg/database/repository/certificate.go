@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/event"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+const (
+	// certificateDNSNamesSeparator is the separator of the dns names in the dns_names column.
+	certificateDNSNamesSeparator = ","
+)
+
+// CertificateRepository type
+type CertificateRepository struct {
+	db     *database.DB
+	events *event.Bus
+}
+
+// NewCertificateRepository creates a new certificate repository
+func NewCertificateRepository(db *database.DB) *CertificateRepository {
+	return &CertificateRepository{
+		db: db,
+	}
+}
+
+// CreateCertificate creates a new certificate
+// the input parameters are the parsed certificate fields and the linked domain ids
+// it returns the created certificate and an error
+func (r *CertificateRepository) CreateCertificate(name, commonName, issuer, serialNumber, fingerprint string, dnsNames []string, notBefore, notAfter, pemData string, domainIDs []int64) (*model.Certificate, error) {
+	var certificateID int64
+	query := "INSERT INTO certificates (name, common_name, issuer, serial_number, fingerprint, dns_names, not_before, not_after, pem) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id"
+	err := r.db.QueryRow(query, name, commonName, issuer, serialNumber, fingerprint, strings.Join(dnsNames, certificateDNSNamesSeparator), notBefore, notAfter, pemData).Scan(&certificateID)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	// create the certificate domain relations
+	for _, domainID := range domainIDs {
+		query = "INSERT INTO certificate_to_domains (certificate_id, domain_id) VALUES ($1, $2)"
+		_, err = r.db.Exec(query, certificateID, domainID)
+		if err != nil {
+			return nil, typedError(err)
+		}
+	}
+
+	certificate, err := r.GetCertificateByID(certificateID)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceCertificate, event.ActionCreated, certificate.ID, certificate))
+
+	return certificate, nil
+}
+
+// GetCertificateByID gets a certificate by id
+// the input parameter is the certificate id
+// it returns the certificate and an error
+func (r *CertificateRepository) GetCertificateByID(id int64) (*model.Certificate, error) {
+	query := "SELECT * FROM certificates WHERE id = $1"
+	certificate, err := r.scanCertificate(r.db.QueryRow(query, id))
+	if err != nil {
+		return nil, typedError(err)
+	}
+
+	return r.withRelations(certificate)
+}
+
+// GetCertificateByFingerprint gets a certificate by fingerprint
+// the input parameter is the SHA-256 fingerprint
+// it returns the certificate and an error
+func (r *CertificateRepository) GetCertificateByFingerprint(fingerprint string) (*model.Certificate, error) {
+	query := "SELECT * FROM certificates WHERE fingerprint = $1"
+	certificate, err := r.scanCertificate(r.db.QueryRow(query, fingerprint))
+	if err != nil {
+		return nil, typedError(err)
+	}
+
+	return r.withRelations(certificate)
+}
+
+// UpdateCertificate updates a certificate
+// The certificate data is immutable, only the name and the domains could be updated.
+// the input parameter is the certificate
+// it returns an error
+func (r *CertificateRepository) UpdateCertificate(certificate *model.Certificate) error {
+	query := "UPDATE certificates SET name = $1, updated_at = $2 WHERE id = $3"
+	now := time.Now().Format("2006-01-02 15:04:05.999999-07:00")
+	_, err := r.db.Exec(query, certificate.Name, now, certificate.ID)
+	if err != nil {
+		return typedError(err)
+	}
+
+	// update the certificate domain relations
+	query = "DELETE FROM certificate_to_domains WHERE certificate_id = $1"
+	_, err = r.db.Exec(query, certificate.ID)
+	if err != nil {
+		return typedError(err)
+	}
+	for _, domain := range certificate.Domains {
+		query = "INSERT INTO certificate_to_domains (certificate_id, domain_id) VALUES ($1, $2)"
+		_, err = r.db.Exec(query, certificate.ID, domain.ID)
+		if err != nil {
+			return typedError(err)
+		}
+	}
+	r.events.Publish(event.New(event.ResourceCertificate, event.ActionUpdated, certificate.ID, certificate))
+
+	return nil
+}
+
+// DeleteCertificate deletes a certificate
+// the input parameter is the certificate id
+// it returns an error
+func (r *CertificateRepository) DeleteCertificate(id int64) error {
+	query := "DELETE FROM certificate_to_domains WHERE certificate_id = $1"
+	_, err := r.db.Exec(query, id)
+	if err != nil {
+		return typedError(err)
+	}
+	query = "DELETE FROM certificates WHERE id = $1"
+	_, err = r.db.Exec(query, id)
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceCertificate, event.ActionDeleted, id, nil))
+	return nil
+}
+
+// GetCertificates gets all certificates ordered by the expiration
+// it returns the certificates and an error
+func (r *CertificateRepository) GetCertificates(filters *model.CertificateFilter) (*model.Certificates, error) {
+	var certificates model.Certificates
+	query := "SELECT id FROM certificates"
+	params := []interface{}{}
+	whereConditions := []string{}
+	if filters.Name != "" {
+		index := len(params) + 1
+		whereConditions = append(whereConditions, "(name LIKE '%' || $"+strconv.Itoa(index)+" || '%' OR dns_names LIKE '%' || $"+strconv.Itoa(index)+" || '%')")
+		params = append(params, filters.Name)
+	}
+	if len(filters.DomainIDs) > 0 {
+		index := len(params) + 1
+		whereConditions = append(whereConditions, "id IN (SELECT certificate_id FROM certificate_to_domains WHERE domain_id = ANY($"+strconv.Itoa(index)+"::bigint[]))")
+		params = append(params, "{"+strings.Join(filters.DomainIDs, ",")+"}")
+	}
+	if len(whereConditions) > 0 {
+		query += " WHERE " + strings.Join(whereConditions, " AND ")
+	}
+	query += " ORDER BY not_after"
+	rows, err := r.db.Query(query, params...)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, typedError(err)
+		}
+		certificate, err := r.GetCertificateByID(id)
+		if err != nil {
+			return nil, typedError(err)
+		}
+		certificates = append(certificates, certificate)
+	}
+	return &certificates, nil
+}
+
+// scanCertificate scans the certificate columns from the row
+func (r *CertificateRepository) scanCertificate(row interface{ Scan(...interface{}) error }) (*model.Certificate, error) {
+	var certificate model.Certificate
+	var dnsNames string
+	err := row.Scan(&certificate.ID, &certificate.Name, &certificate.CommonName, &certificate.Issuer, &certificate.SerialNumber, &certificate.Fingerprint, &dnsNames, &certificate.NotBefore, &certificate.NotAfter, &certificate.PEM, &certificate.CreatedAt, &certificate.UpdatedAt)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	certificate.DNSNames = []string{}
+	if dnsNames != "" {
+		certificate.DNSNames = strings.Split(dnsNames, certificateDNSNamesSeparator)
+	}
+	return &certificate, nil
+}
+
+// withRelations function gets a certificate as input and returns a certificate with the relations
+func (r *CertificateRepository) withRelations(certificate *model.Certificate) (*model.Certificate, error) {
+	domainRepository := NewDomainRepository(r.db)
+	// get the certificate domains
+	query := "SELECT domain_id FROM certificate_to_domains WHERE certificate_id = $1"
+	rows, err := r.db.Query(query, certificate.ID)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var domainID int64
+		err = rows.Scan(&domainID)
+		if err != nil {
+			return nil, typedError(err)
+		}
+		domain, err := domainRepository.GetDomainByID(domainID)
+		if err != nil {
+			return nil, typedError(err)
+		}
+		certificate.Domains = append(certificate.Domains, domain)
+	}
+
+	return certificate, nil
+}
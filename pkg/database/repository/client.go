@@ -4,12 +4,14 @@ import (
 	"time"
 
 	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/event"
 	"github.com/akosgarai/projectregister/pkg/model"
 )
 
 // ClientRepository type
 type ClientRepository struct {
-	db *database.DB
+	db     *database.DB
+	events *event.Bus
 }
 
 // NewClientRepository creates a new client repository
@@ -26,8 +28,12 @@ func (r *ClientRepository) CreateClient(name string) (*model.Client, error) {
 	var client model.Client
 	query := "INSERT INTO clients (name) VALUES ($1) RETURNING *"
 	err := r.db.QueryRow(query, name).Scan(&client.ID, &client.Name, &client.CreatedAt, &client.UpdatedAt)
+	if err != nil {
+		return &client, typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceClient, event.ActionCreated, client.ID, &client))
 
-	return &client, err
+	return &client, nil
 }
 
 // GetClientByName gets a client by name
@@ -38,7 +44,7 @@ func (r *ClientRepository) GetClientByName(name string) (*model.Client, error) {
 	query := "SELECT * FROM clients WHERE name = $1"
 	err := r.db.QueryRow(query, name).Scan(&client.ID, &client.Name, &client.CreatedAt, &client.UpdatedAt)
 
-	return &client, err
+	return &client, typedError(err)
 }
 
 // GetClientByID gets a client by id
@@ -49,7 +55,7 @@ func (r *ClientRepository) GetClientByID(id int64) (*model.Client, error) {
 	query := "SELECT * FROM clients WHERE id = $1"
 	err := r.db.QueryRow(query, id).Scan(&client.ID, &client.Name, &client.CreatedAt, &client.UpdatedAt)
 
-	return &client, err
+	return &client, typedError(err)
 }
 
 // UpdateClient updates a client
@@ -59,8 +65,12 @@ func (r *ClientRepository) UpdateClient(client *model.Client) error {
 	query := "UPDATE clients SET name = $1, updated_at = $2 WHERE id = $3"
 	now := time.Now().Format("2006-01-02 15:04:05.999999-07:00")
 	_, err := r.db.Exec(query, client.Name, now, client.ID)
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceClient, event.ActionUpdated, client.ID, client))
 
-	return err
+	return nil
 }
 
 // DeleteClient deletes a client
@@ -69,7 +79,11 @@ func (r *ClientRepository) UpdateClient(client *model.Client) error {
 func (r *ClientRepository) DeleteClient(id int64) error {
 	query := "DELETE FROM clients WHERE id = $1"
 	_, err := r.db.Exec(query, id)
-	return err
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceClient, event.ActionDeleted, id, nil))
+	return nil
 }
 
 // GetClients gets all clients
@@ -85,14 +99,14 @@ func (r *ClientRepository) GetClients(filters *model.ClientFilter) (*model.Clien
 	}
 	rows, err := r.db.Query(query, params...)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
 		var client model.Client
 		err = rows.Scan(&client.ID, &client.Name, &client.CreatedAt, &client.UpdatedAt)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		clients = append(clients, &client)
 	}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/event"
 	"github.com/akosgarai/projectregister/pkg/model"
 )
 
@@ -9,8 +10,10 @@ import (
 // It implements the RepositoryContainer interface.
 type ContainerRepository struct {
 	applications *ApplicationRepository
+	certificates *CertificateRepository
 	clients      *ClientRepository
 	databases    *DatabaseRepository
+	dnsRecords   *DNSRecordRepository
 	domains      *DomainRepository
 	environments *EnvironmentRepository
 	pools        *PoolRepository
@@ -21,14 +24,28 @@ type ContainerRepository struct {
 	servers      *ServerRepository
 	users        *UserRepository
 	frameworks   *FrameworkRepository
+
+	webhooks          *WebhookRepository
+	webhookDeliveries *WebhookDeliveryRepository
+
+	notificationSubscriptions *NotificationSubscriptionRepository
+	notificationDeliveries    *NotificationDeliveryRepository
+
+	jobs *JobRepository
+
+	scheduledTasks      *ScheduledTaskRepository
+	statisticsSnapshots *StatisticsSnapshotRepository
 }
 
 // NewContainerRepository creates a new container repository
-func NewContainerRepository(db *database.DB) *ContainerRepository {
-	return &ContainerRepository{
+// The resource repositories publish the change events to the events bus, it could be nil.
+func NewContainerRepository(db *database.DB, events *event.Bus) *ContainerRepository {
+	container := &ContainerRepository{
 		applications: NewApplicationRepository(db),
+		certificates: NewCertificateRepository(db),
 		clients:      NewClientRepository(db),
 		databases:    NewDatabaseRepository(db),
+		dnsRecords:   NewDNSRecordRepository(db),
 		domains:      NewDomainRepository(db),
 		environments: NewEnvironmentRepository(db),
 		pools:        NewPoolRepository(db),
@@ -39,7 +56,33 @@ func NewContainerRepository(db *database.DB) *ContainerRepository {
 		servers:      NewServerRepository(db),
 		users:        NewUserRepository(db),
 		frameworks:   NewFrameworkRepository(db),
+
+		webhooks:          NewWebhookRepository(db),
+		webhookDeliveries: NewWebhookDeliveryRepository(db),
+
+		notificationSubscriptions: NewNotificationSubscriptionRepository(db),
+		notificationDeliveries:    NewNotificationDeliveryRepository(db),
+
+		jobs: NewJobRepository(db),
+
+		scheduledTasks:      NewScheduledTaskRepository(db),
+		statisticsSnapshots: NewStatisticsSnapshotRepository(db),
 	}
+	container.applications.events = events
+	container.certificates.events = events
+	container.clients.events = events
+	container.databases.events = events
+	container.dnsRecords.events = events
+	container.domains.events = events
+	container.environments.events = events
+	container.pools.events = events
+	container.projects.events = events
+	container.roles.events = events
+	container.runtimes.events = events
+	container.servers.events = events
+	container.users.events = events
+	container.frameworks.events = events
+	return container
 }
 
 // GetApplicationRepository returns the application repository
@@ -47,6 +90,11 @@ func (r *ContainerRepository) GetApplicationRepository() model.ApplicationReposi
 	return r.applications
 }
 
+// GetCertificateRepository returns the certificate repository
+func (r *ContainerRepository) GetCertificateRepository() model.CertificateRepository {
+	return r.certificates
+}
+
 // GetClientRepository returns the client repository
 func (r *ContainerRepository) GetClientRepository() model.ClientRepository {
 	return r.clients
@@ -57,6 +105,11 @@ func (r *ContainerRepository) GetDatabaseRepository() model.DatabaseRepository {
 	return r.databases
 }
 
+// GetDNSRecordRepository returns the dns record repository
+func (r *ContainerRepository) GetDNSRecordRepository() model.DNSRecordRepository {
+	return r.dnsRecords
+}
+
 // GetDomainRepository returns the domain repository
 func (r *ContainerRepository) GetDomainRepository() model.DomainRepository {
 	return r.domains
@@ -106,3 +159,38 @@ func (r *ContainerRepository) GetUserRepository() model.UserRepository {
 func (r *ContainerRepository) GetFrameworkRepository() model.FrameworkRepository {
 	return r.frameworks
 }
+
+// GetWebhookRepository returns the webhook repository
+func (r *ContainerRepository) GetWebhookRepository() model.WebhookRepository {
+	return r.webhooks
+}
+
+// GetWebhookDeliveryRepository returns the webhook delivery repository
+func (r *ContainerRepository) GetWebhookDeliveryRepository() model.WebhookDeliveryRepository {
+	return r.webhookDeliveries
+}
+
+// GetNotificationSubscriptionRepository returns the notification subscription repository
+func (r *ContainerRepository) GetNotificationSubscriptionRepository() model.NotificationSubscriptionRepository {
+	return r.notificationSubscriptions
+}
+
+// GetNotificationDeliveryRepository returns the notification delivery repository
+func (r *ContainerRepository) GetNotificationDeliveryRepository() model.NotificationDeliveryRepository {
+	return r.notificationDeliveries
+}
+
+// GetJobRepository returns the job repository
+func (r *ContainerRepository) GetJobRepository() model.JobRepository {
+	return r.jobs
+}
+
+// GetScheduledTaskRepository returns the scheduled task repository
+func (r *ContainerRepository) GetScheduledTaskRepository() model.ScheduledTaskRepository {
+	return r.scheduledTasks
+}
+
+// GetStatisticsSnapshotRepository returns the statistics snapshot repository
+func (r *ContainerRepository) GetStatisticsSnapshotRepository() model.StatisticsSnapshotRepository {
+	return r.statisticsSnapshots
+}
@@ -4,12 +4,14 @@ import (
 	"time"
 
 	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/event"
 	"github.com/akosgarai/projectregister/pkg/model"
 )
 
 // DatabaseRepository type
 type DatabaseRepository struct {
-	db *database.DB
+	db     *database.DB
+	events *event.Bus
 }
 
 // NewDatabaseRepository creates a new database repository
@@ -26,8 +28,12 @@ func (r *DatabaseRepository) CreateDatabase(name string) (*model.Database, error
 	var database model.Database
 	query := "INSERT INTO databases (name) VALUES ($1) RETURNING *"
 	err := r.db.QueryRow(query, name).Scan(&database.ID, &database.Name, &database.CreatedAt, &database.UpdatedAt)
+	if err != nil {
+		return &database, typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceDatabase, event.ActionCreated, database.ID, &database))
 
-	return &database, err
+	return &database, nil
 }
 
 // GetDatabaseByName gets a database by name
@@ -38,7 +44,7 @@ func (r *DatabaseRepository) GetDatabaseByName(name string) (*model.Database, er
 	query := "SELECT * FROM databases WHERE name = $1"
 	err := r.db.QueryRow(query, name).Scan(&database.ID, &database.Name, &database.CreatedAt, &database.UpdatedAt)
 
-	return &database, err
+	return &database, typedError(err)
 }
 
 // GetDatabaseByID gets a database by id
@@ -49,7 +55,7 @@ func (r *DatabaseRepository) GetDatabaseByID(id int64) (*model.Database, error)
 	query := "SELECT * FROM databases WHERE id = $1"
 	err := r.db.QueryRow(query, id).Scan(&database.ID, &database.Name, &database.CreatedAt, &database.UpdatedAt)
 
-	return &database, err
+	return &database, typedError(err)
 }
 
 // UpdateDatabase updates a database
@@ -59,8 +65,12 @@ func (r *DatabaseRepository) UpdateDatabase(database *model.Database) error {
 	query := "UPDATE databases SET name = $1, updated_at = $2 WHERE id = $3"
 	now := time.Now().Format("2006-01-02 15:04:05.999999-07:00")
 	_, err := r.db.Exec(query, database.Name, now, database.ID)
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceDatabase, event.ActionUpdated, database.ID, database))
 
-	return err
+	return nil
 }
 
 // DeleteDatabase deletes a database
@@ -69,7 +79,11 @@ func (r *DatabaseRepository) UpdateDatabase(database *model.Database) error {
 func (r *DatabaseRepository) DeleteDatabase(id int64) error {
 	query := "DELETE FROM databases WHERE id = $1"
 	_, err := r.db.Exec(query, id)
-	return err
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceDatabase, event.ActionDeleted, id, nil))
+	return nil
 }
 
 // GetDatabases gets all databases
@@ -85,14 +99,14 @@ func (r *DatabaseRepository) GetDatabases(filters *model.DatabaseFilter) (*model
 	}
 	rows, err := r.db.Query(query, params...)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
 		var database model.Database
 		err = rows.Scan(&database.ID, &database.Name, &database.CreatedAt, &database.UpdatedAt)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		databases = append(databases, &database)
 	}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/event"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// DNSRecordRepository type
+type DNSRecordRepository struct {
+	db     *database.DB
+	events *event.Bus
+}
+
+// NewDNSRecordRepository creates a new dns record repository
+func NewDNSRecordRepository(db *database.DB) *DNSRecordRepository {
+	return &DNSRecordRepository{
+		db: db,
+	}
+}
+
+// CreateDNSRecord creates a new dns record
+// the input parameters are the zone domain id and the record data
+// it returns the created record and an error
+func (r *DNSRecordRepository) CreateDNSRecord(domainID int64, name, recordType string, ttl, priority int, value string) (*model.DNSRecord, error) {
+	var record model.DNSRecord
+	query := "INSERT INTO dns_records (domain_id, name, type, ttl, priority, value) VALUES ($1, $2, $3, $4, $5, $6) RETURNING *"
+	err := r.db.QueryRow(query, domainID, name, recordType, ttl, priority, value).Scan(&record.ID, &record.DomainID, &record.Name, &record.Type, &record.TTL, &record.Priority, &record.Value, &record.CreatedAt, &record.UpdatedAt)
+	if err != nil {
+		return &record, typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceDNSRecord, event.ActionCreated, record.ID, &record))
+
+	return &record, nil
+}
+
+// GetDNSRecordByID gets a dns record by id
+// the input parameter is the record id
+// it returns the record and an error
+func (r *DNSRecordRepository) GetDNSRecordByID(id int64) (*model.DNSRecord, error) {
+	var record model.DNSRecord
+	query := "SELECT * FROM dns_records WHERE id = $1"
+	err := r.db.QueryRow(query, id).Scan(&record.ID, &record.DomainID, &record.Name, &record.Type, &record.TTL, &record.Priority, &record.Value, &record.CreatedAt, &record.UpdatedAt)
+
+	return &record, typedError(err)
+}
+
+// UpdateDNSRecord updates a dns record
+// the input parameter is the record
+// it returns an error
+func (r *DNSRecordRepository) UpdateDNSRecord(record *model.DNSRecord) error {
+	query := "UPDATE dns_records SET name = $1, type = $2, ttl = $3, priority = $4, value = $5, updated_at = $6 WHERE id = $7"
+	now := time.Now().Format("2006-01-02 15:04:05.999999-07:00")
+	_, err := r.db.Exec(query, record.Name, record.Type, record.TTL, record.Priority, record.Value, now, record.ID)
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceDNSRecord, event.ActionUpdated, record.ID, record))
+
+	return nil
+}
+
+// DeleteDNSRecord deletes a dns record
+// the input parameter is the record id
+// it returns an error
+func (r *DNSRecordRepository) DeleteDNSRecord(id int64) error {
+	query := "DELETE FROM dns_records WHERE id = $1"
+	_, err := r.db.Exec(query, id)
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceDNSRecord, event.ActionDeleted, id, nil))
+	return nil
+}
+
+// GetDNSRecords gets the dns records ordered by the name and the type
+// it returns the records and an error
+func (r *DNSRecordRepository) GetDNSRecords(filters *model.DNSRecordFilter) (*model.DNSRecords, error) {
+	var records model.DNSRecords
+	query := "SELECT * FROM dns_records"
+	params := []interface{}{}
+	whereConditions := []string{}
+	if len(filters.DomainIDs) > 0 {
+		index := len(params) + 1
+		whereConditions = append(whereConditions, "domain_id = ANY($"+strconv.Itoa(index)+"::bigint[])")
+		params = append(params, "{"+strings.Join(filters.DomainIDs, ",")+"}")
+	}
+	if filters.Type != "" {
+		index := len(params) + 1
+		whereConditions = append(whereConditions, "type = $"+strconv.Itoa(index))
+		params = append(params, filters.Type)
+	}
+	if len(whereConditions) > 0 {
+		query += " WHERE " + strings.Join(whereConditions, " AND ")
+	}
+	query += " ORDER BY name, type, id"
+	rows, err := r.db.Query(query, params...)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var record model.DNSRecord
+		err = rows.Scan(&record.ID, &record.DomainID, &record.Name, &record.Type, &record.TTL, &record.Priority, &record.Value, &record.CreatedAt, &record.UpdatedAt)
+		if err != nil {
+			return nil, typedError(err)
+		}
+		records = append(records, &record)
+	}
+	return &records, nil
+}
@@ -1,15 +1,20 @@
 package repository
 
 import (
+	"database/sql"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/event"
 	"github.com/akosgarai/projectregister/pkg/model"
 )
 
 // DomainRepository type
 type DomainRepository struct {
-	db *database.DB
+	db     *database.DB
+	events *event.Bus
 }
 
 // NewDomainRepository creates a new domain repository
@@ -23,44 +28,74 @@ func NewDomainRepository(db *database.DB) *DomainRepository {
 // the input parameter is the name
 // it returns the created domain and an error
 func (r *DomainRepository) CreateDomain(name string) (*model.Domain, error) {
-	var domain model.Domain
 	query := "INSERT INTO domains (name) VALUES ($1) RETURNING *"
-	err := r.db.QueryRow(query, name).Scan(&domain.ID, &domain.Name, &domain.CreatedAt, &domain.UpdatedAt)
+	domain, err := r.scanDomain(r.db.QueryRow(query, name))
+	if err != nil {
+		return nil, typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceDomain, event.ActionCreated, domain.ID, domain))
+
+	return domain, nil
+}
+
+// CreateDomainWithRegistration creates a new domain with its registration data
+// the input parameter is the domain, the name, the registrar, the dates, the auto renew flag and the billing client are stored
+// it returns the created domain and an error
+func (r *DomainRepository) CreateDomainWithRegistration(domain *model.Domain) (*model.Domain, error) {
+	query := "INSERT INTO domains (name, registrar, registered_at, expires_at, auto_renew, billing_client_id) VALUES ($1, $2, $3, $4, $5, $6) RETURNING *"
+	// the empty dates and the missing client are stored as null
+	registeredAt := sql.NullString{String: domain.RegisteredAt, Valid: domain.RegisteredAt != ""}
+	expiresAt := sql.NullString{String: domain.ExpiresAt, Valid: domain.ExpiresAt != ""}
+	billingClientID := sql.NullInt64{}
+	if domain.BillingClient != nil {
+		billingClientID = sql.NullInt64{Int64: domain.BillingClient.ID, Valid: true}
+	}
+	created, err := r.scanDomain(r.db.QueryRow(query, domain.Name, domain.Registrar, registeredAt, expiresAt, domain.AutoRenew, billingClientID))
+	if err != nil {
+		return nil, typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceDomain, event.ActionCreated, created.ID, created))
 
-	return &domain, err
+	return created, nil
 }
 
 // GetDomainByName gets a domain by name
 // the input parameter is the domain name
 // it returns the domain and an error
 func (r *DomainRepository) GetDomainByName(name string) (*model.Domain, error) {
-	var domain model.Domain
 	query := "SELECT * FROM domains WHERE name = $1"
-	err := r.db.QueryRow(query, name).Scan(&domain.ID, &domain.Name, &domain.CreatedAt, &domain.UpdatedAt, &domain.HasSSL)
-
-	return &domain, err
+	return r.scanDomain(r.db.QueryRow(query, name))
 }
 
 // GetDomainByID gets a domain by id
 // the input parameter is the domain id
 // it returns the domain and an error
 func (r *DomainRepository) GetDomainByID(id int64) (*model.Domain, error) {
-	var domain model.Domain
 	query := "SELECT * FROM domains WHERE id = $1"
-	err := r.db.QueryRow(query, id).Scan(&domain.ID, &domain.Name, &domain.CreatedAt, &domain.UpdatedAt, &domain.HasSSL)
-
-	return &domain, err
+	return r.scanDomain(r.db.QueryRow(query, id))
 }
 
 // UpdateDomain updates a domain
 // the input parameter is the domain
 // it returns an error
 func (r *DomainRepository) UpdateDomain(domain *model.Domain) error {
-	query := "UPDATE domains SET name = $1, has_ssl = $2, updated_at = $3 WHERE id = $4"
+	query := "UPDATE domains SET name = $1, has_ssl = $2, security_score = $3, security_grade = $4, security_findings = $5, live_certificate_fingerprint = $6, registrar = $7, registered_at = $8, expires_at = $9, auto_renew = $10, billing_client_id = $11, ssl_checked_at = $12, zone_ttl = $13, updated_at = $14 WHERE id = $15"
 	now := time.Now().Format("2006-01-02 15:04:05.999999-07:00")
-	_, err := r.db.Exec(query, domain.Name, domain.HasSSL, now, domain.ID)
+	// the empty dates and the missing client are stored as null
+	registeredAt := sql.NullString{String: domain.RegisteredAt, Valid: domain.RegisteredAt != ""}
+	expiresAt := sql.NullString{String: domain.ExpiresAt, Valid: domain.ExpiresAt != ""}
+	sslCheckedAt := sql.NullString{String: domain.SSLCheckedAt, Valid: domain.SSLCheckedAt != ""}
+	billingClientID := sql.NullInt64{}
+	if domain.BillingClient != nil {
+		billingClientID = sql.NullInt64{Int64: domain.BillingClient.ID, Valid: true}
+	}
+	_, err := r.db.Exec(query, domain.Name, domain.HasSSL, domain.SecurityScore, domain.SecurityGrade, domain.SecurityFindings, domain.LiveCertificateFingerprint, domain.Registrar, registeredAt, expiresAt, domain.AutoRenew, billingClientID, sslCheckedAt, domain.ZoneTTL, now, domain.ID)
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceDomain, event.ActionUpdated, domain.ID, domain))
 
-	return err
+	return nil
 }
 
 // DeleteDomain deletes a domain
@@ -69,7 +104,11 @@ func (r *DomainRepository) UpdateDomain(domain *model.Domain) error {
 func (r *DomainRepository) DeleteDomain(id int64) error {
 	query := "DELETE FROM domains WHERE id = $1"
 	_, err := r.db.Exec(query, id)
-	return err
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceDomain, event.ActionDeleted, id, nil))
+	return nil
 }
 
 // GetDomains gets all domains
@@ -79,22 +118,34 @@ func (r *DomainRepository) GetDomains(filters *model.DomainFilter) (*model.Domai
 	var domains model.Domains
 	query := "SELECT * FROM domains"
 	params := []interface{}{}
+	whereConditions := []string{}
 	if filters.Name != "" {
-		query += " WHERE name LIKE '%' || $1 || '%'"
+		index := len(params) + 1
+		whereConditions = append(whereConditions, "name LIKE '%' || $"+strconv.Itoa(index)+" || '%'")
 		params = append(params, filters.Name)
 	}
+	if filters.ExpiresBefore != "" {
+		index := len(params) + 1
+		whereConditions = append(whereConditions, "expires_at <= $"+strconv.Itoa(index))
+		params = append(params, filters.ExpiresBefore)
+	}
+	if len(whereConditions) > 0 {
+		query += " WHERE " + strings.Join(whereConditions, " AND ")
+	}
+	if filters.ExpiresBefore != "" {
+		query += " ORDER BY expires_at, name"
+	}
 	rows, err := r.db.Query(query, params...)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
-		var domain model.Domain
-		err = rows.Scan(&domain.ID, &domain.Name, &domain.CreatedAt, &domain.UpdatedAt, &domain.HasSSL)
+		domain, err := r.scanDomain(rows)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
-		domains = append(domains, &domain)
+		domains = append(domains, domain)
 	}
 	return &domains, nil
 }
@@ -107,16 +158,40 @@ func (r *DomainRepository) GetFreeDomains() (*model.Domains, error) {
 	query := "SELECT * FROM domains WHERE id NOT IN (SELECT domain_id FROM application_to_domains)"
 	rows, err := r.db.Query(query)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
-		var domain model.Domain
-		err = rows.Scan(&domain.ID, &domain.Name, &domain.CreatedAt, &domain.UpdatedAt, &domain.HasSSL)
+		domain, err := r.scanDomain(rows)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
-		domains = append(domains, &domain)
+		domains = append(domains, domain)
 	}
 	return &domains, nil
 }
+
+// scanDomain scans the domain columns from the row and loads the billing client
+func (r *DomainRepository) scanDomain(row interface{ Scan(...interface{}) error }) (*model.Domain, error) {
+	var domain model.Domain
+	var registeredAt, expiresAt, sslCheckedAt sql.NullTime
+	var billingClientID sql.NullInt64
+	err := row.Scan(&domain.ID, &domain.Name, &domain.CreatedAt, &domain.UpdatedAt, &domain.HasSSL, &domain.SecurityScore, &domain.SecurityGrade, &domain.SecurityFindings, &domain.LiveCertificateFingerprint,
+		&domain.Registrar, &registeredAt, &expiresAt, &domain.AutoRenew, &billingClientID, &sslCheckedAt, &domain.ZoneTTL)
+	if err != nil {
+		return &domain, typedError(err)
+	}
+	if registeredAt.Valid {
+		domain.RegisteredAt = registeredAt.Time.Format(model.DomainDateFormat)
+	}
+	if expiresAt.Valid {
+		domain.ExpiresAt = expiresAt.Time.Format(model.DomainDateFormat)
+	}
+	if sslCheckedAt.Valid {
+		domain.SSLCheckedAt = sslCheckedAt.Time.Format(model.DomainCheckTimeFormat)
+	}
+	if billingClientID.Valid {
+		domain.BillingClient, err = NewClientRepository(r.db).GetClientByID(billingClientID.Int64)
+	}
+	return &domain, typedError(err)
+}
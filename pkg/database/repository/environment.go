@@ -6,12 +6,14 @@ import (
 	"time"
 
 	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/event"
 	"github.com/akosgarai/projectregister/pkg/model"
 )
 
 // EnvironmentRepository type
 type EnvironmentRepository struct {
-	db *database.DB
+	db     *database.DB
+	events *event.Bus
 }
 
 // NewEnvironmentRepository creates a new environment repository
@@ -29,14 +31,14 @@ func (r *EnvironmentRepository) CreateEnvironment(name, description string, serv
 	query := "INSERT INTO environments (name, description, score) VALUES ($1, $2, $3) RETURNING *"
 	err := r.db.QueryRow(query, name, description, score).Scan(&environment.ID, &environment.Name, &environment.Description, &environment.CreatedAt, &environment.UpdatedAt, &environment.Score)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	// insert the server ids to the environment_to_servers table
 	for _, id := range serverIDs {
 		query = "INSERT INTO environment_to_servers (environment_id, server_id) VALUES ($1, $2)"
 		_, err = r.db.Exec(query, environment.ID, id)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 	}
 	// insert the database ids to the environment_to_databases table
@@ -44,11 +46,17 @@ func (r *EnvironmentRepository) CreateEnvironment(name, description string, serv
 		query = "INSERT INTO environment_to_databases (environment_id, database_id) VALUES ($1, $2)"
 		_, err = r.db.Exec(query, environment.ID, id)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 	}
 
-	return r.withRelations(&environment)
+	created, err := r.withRelations(&environment)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceEnvironment, event.ActionCreated, created.ID, created))
+
+	return created, nil
 }
 
 // GetEnvironmentByName gets a environment by name
@@ -59,7 +67,7 @@ func (r *EnvironmentRepository) GetEnvironmentByName(name string) (*model.Enviro
 	query := "SELECT * FROM environments WHERE name = $1"
 	err := r.db.QueryRow(query, name).Scan(&environment.ID, &environment.Name, &environment.Description, &environment.CreatedAt, &environment.UpdatedAt, &environment.Score)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	return r.withRelations(&environment)
 }
@@ -72,7 +80,7 @@ func (r *EnvironmentRepository) GetEnvironmentByID(id int64) (*model.Environment
 	query := "SELECT * FROM environments WHERE id = $1"
 	err := r.db.QueryRow(query, id).Scan(&environment.ID, &environment.Name, &environment.Description, &environment.CreatedAt, &environment.UpdatedAt, &environment.Score)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	return r.withRelations(&environment)
 }
@@ -89,32 +97,33 @@ func (r *EnvironmentRepository) UpdateEnvironment(environment *model.Environment
 	query = "DELETE FROM environment_to_servers WHERE environment_id = $1"
 	_, err = r.db.Exec(query, environment.ID)
 	if err != nil {
-		return err
+		return typedError(err)
 	}
 	// insert the server ids to the environment_to_servers table
 	for _, server := range environment.Servers {
 		query = "INSERT INTO environment_to_servers (environment_id, server_id) VALUES ($1, $2)"
 		_, err = r.db.Exec(query, environment.ID, server.ID)
 		if err != nil {
-			return err
+			return typedError(err)
 		}
 	}
 	// delete the environment ids from the environment_to_databases table
 	query = "DELETE FROM environment_to_databases WHERE environment_id = $1"
 	_, err = r.db.Exec(query, environment.ID)
 	if err != nil {
-		return err
+		return typedError(err)
 	}
 	// insert the database ids to the environment_to_databases table
 	for _, database := range environment.Databases {
 		query = "INSERT INTO environment_to_databases (environment_id, database_id) VALUES ($1, $2)"
 		_, err = r.db.Exec(query, environment.ID, database.ID)
 		if err != nil {
-			return err
+			return typedError(err)
 		}
 	}
+	r.events.Publish(event.New(event.ResourceEnvironment, event.ActionUpdated, environment.ID, environment))
 
-	return err
+	return nil
 }
 
 // DeleteEnvironment deletes a environment
@@ -125,18 +134,22 @@ func (r *EnvironmentRepository) DeleteEnvironment(id int64) error {
 	query := "DELETE FROM environment_to_servers WHERE environment_id = $1"
 	_, err := r.db.Exec(query, id)
 	if err != nil {
-		return err
+		return typedError(err)
 	}
 	// delete the environment ids from the environment_to_databases table
 	query = "DELETE FROM environment_to_databases WHERE environment_id = $1"
 	_, err = r.db.Exec(query, id)
 	if err != nil {
-		return err
+		return typedError(err)
 	}
 
 	query = "DELETE FROM environments WHERE id = $1"
 	_, err = r.db.Exec(query, id)
-	return err
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceEnvironment, event.ActionDeleted, id, nil))
+	return nil
 }
 
 // GetEnvironments gets all environments
@@ -172,18 +185,18 @@ func (r *EnvironmentRepository) GetEnvironments(filters *model.EnvironmentFilter
 	}
 	rows, err := r.db.Query(query, params...)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
 		var environment model.Environment
 		err = rows.Scan(&environment.ID, &environment.Name, &environment.Description, &environment.CreatedAt, &environment.UpdatedAt, &environment.Score)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		environmentWithRelations, err := r.withRelations(&environment)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		environments = append(environments, environmentWithRelations)
 	}
@@ -195,7 +208,7 @@ func (r *EnvironmentRepository) withRelations(environment *model.Environment) (*
 	query := "SELECT server_id FROM environment_to_servers WHERE environment_id = $1"
 	rows, err := r.db.Query(query, environment.ID)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	serverRepository := NewServerRepository(r.db)
 	defer rows.Close()
@@ -203,11 +216,11 @@ func (r *EnvironmentRepository) withRelations(environment *model.Environment) (*
 		var serverID int64
 		err = rows.Scan(&serverID)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		server, err := serverRepository.GetServerByID(serverID)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		environment.Servers = append(environment.Servers, server)
 	}
@@ -215,7 +228,7 @@ func (r *EnvironmentRepository) withRelations(environment *model.Environment) (*
 	query = "SELECT database_id FROM environment_to_databases WHERE environment_id = $1"
 	rows, err = r.db.Query(query, environment.ID)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	databaseRepository := NewDatabaseRepository(r.db)
 	defer rows.Close()
@@ -223,11 +236,11 @@ func (r *EnvironmentRepository) withRelations(environment *model.Environment) (*
 		var databaseID int64
 		err = rows.Scan(&databaseID)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		database, err := databaseRepository.GetDatabaseByID(databaseID)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		environment.Databases = append(environment.Databases, database)
 	}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+const (
+	// pqUniqueViolation is the postgres error code of the unique constraint violations.
+	pqUniqueViolation = "23505"
+	// pqForeignKeyViolation is the postgres error code of the foreign key constraint violations.
+	pqForeignKeyViolation = "23503"
+)
+
+// typedError maps the database errors to the typed errors of the model package.
+// The original error is kept in the chain, so that it could be logged.
+// The nil, the already typed and the unknown errors are returned as they are.
+func typedError(err error) error {
+	if err == nil || errors.Is(err, model.ErrNotFound) || errors.Is(err, model.ErrConflict) || errors.Is(err, model.ErrForeignKey) {
+		return err
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %w", model.ErrNotFound, err)
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case pqUniqueViolation:
+			return fmt.Errorf("%w: %w", model.ErrConflict, err)
+		case pqForeignKeyViolation:
+			return fmt.Errorf("%w: %w", model.ErrForeignKey, err)
+		}
+	}
+	return err
+}
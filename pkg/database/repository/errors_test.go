@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// TestTypedError tests the mapping of the database errors to the typed errors.
+func TestTypedError(t *testing.T) {
+	unknownError := errors.New("connection refused")
+	testData := []struct {
+		err      error
+		expected error
+	}{
+		{sql.ErrNoRows, model.ErrNotFound},
+		{&pq.Error{Code: pqUniqueViolation}, model.ErrConflict},
+		{&pq.Error{Code: pqForeignKeyViolation}, model.ErrForeignKey},
+		{&pq.Error{Code: "42P01"}, nil},
+		{unknownError, nil},
+	}
+	for _, tt := range testData {
+		err := typedError(tt.err)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("The original error has to be kept in the chain. Got: %v", err)
+		}
+		if tt.expected != nil && !errors.Is(err, tt.expected) {
+			t.Errorf("Invalid typed error for '%v'. Expected: %v, got: %v", tt.err, tt.expected, err)
+		}
+		if tt.expected == nil && err != tt.err {
+			t.Errorf("The unknown error has to be returned as it is. Got: %v", err)
+		}
+	}
+	if typedError(nil) != nil {
+		t.Error("The nil error has to be returned as nil.")
+	}
+	wrapped := typedError(sql.ErrNoRows)
+	if typedError(wrapped) != wrapped {
+		t.Error("The typed error must not be wrapped again.")
+	}
+}
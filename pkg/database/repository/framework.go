@@ -4,12 +4,14 @@ import (
 	"time"
 
 	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/event"
 	"github.com/akosgarai/projectregister/pkg/model"
 )
 
 // FrameworkRepository type
 type FrameworkRepository struct {
-	db *database.DB
+	db     *database.DB
+	events *event.Bus
 }
 
 // NewFrameworkRepository creates a new framework repository
@@ -26,8 +28,12 @@ func (r *FrameworkRepository) CreateFramework(name string, score int) (*model.Fr
 	var framework model.Framework
 	query := "INSERT INTO frameworks (name, score) VALUES ($1, $2) RETURNING *"
 	err := r.db.QueryRow(query, name, score).Scan(&framework.ID, &framework.Name, &framework.Score, &framework.CreatedAt, &framework.UpdatedAt)
+	if err != nil {
+		return &framework, typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceFramework, event.ActionCreated, framework.ID, &framework))
 
-	return &framework, err
+	return &framework, nil
 }
 
 // GetFrameworkByName gets a framework by name
@@ -38,7 +44,7 @@ func (r *FrameworkRepository) GetFrameworkByName(name string) (*model.Framework,
 	query := "SELECT * FROM frameworks WHERE name = $1"
 	err := r.db.QueryRow(query, name).Scan(&framework.ID, &framework.Name, &framework.Score, &framework.CreatedAt, &framework.UpdatedAt)
 
-	return &framework, err
+	return &framework, typedError(err)
 }
 
 // GetFrameworkByID gets a framework by id
@@ -49,7 +55,7 @@ func (r *FrameworkRepository) GetFrameworkByID(id int64) (*model.Framework, erro
 	query := "SELECT * FROM frameworks WHERE id = $1"
 	err := r.db.QueryRow(query, id).Scan(&framework.ID, &framework.Name, &framework.Score, &framework.CreatedAt, &framework.UpdatedAt)
 
-	return &framework, err
+	return &framework, typedError(err)
 }
 
 // UpdateFramework updates a framework
@@ -59,8 +65,12 @@ func (r *FrameworkRepository) UpdateFramework(framework *model.Framework) error
 	query := "UPDATE frameworks SET name = $1, score = $2, updated_at = $3 WHERE id = $4"
 	now := time.Now().Format("2006-01-02 15:04:05.999999-07:00")
 	_, err := r.db.Exec(query, framework.Name, framework.Score, now, framework.ID)
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceFramework, event.ActionUpdated, framework.ID, framework))
 
-	return err
+	return nil
 }
 
 // DeleteFramework deletes a framework
@@ -69,7 +79,11 @@ func (r *FrameworkRepository) UpdateFramework(framework *model.Framework) error
 func (r *FrameworkRepository) DeleteFramework(id int64) error {
 	query := "DELETE FROM frameworks WHERE id = $1"
 	_, err := r.db.Exec(query, id)
-	return err
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceFramework, event.ActionDeleted, id, nil))
+	return nil
 }
 
 // GetFrameworks gets all frameworks
@@ -85,14 +99,14 @@ func (r *FrameworkRepository) GetFrameworks(filters *model.FrameworkFilter) (*mo
 	}
 	rows, err := r.db.Query(query, params...)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
 		var framework model.Framework
 		err = rows.Scan(&framework.ID, &framework.Name, &framework.Score, &framework.CreatedAt, &framework.UpdatedAt)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		frameworks = append(frameworks, &framework)
 	}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// JobRepository type
+type JobRepository struct {
+	db *database.DB
+}
+
+// NewJobRepository creates a new job repository
+func NewJobRepository(db *database.DB) *JobRepository {
+	return &JobRepository{
+		db: db,
+	}
+}
+
+// CreateJob queues a new job
+// the input parameters are the job type, the json encoded payload and the id of the user who created the job
+// it returns the created job and an error
+func (r *JobRepository) CreateJob(jobType, payload string, createdBy int64) (*model.Job, error) {
+	query := "INSERT INTO jobs (type, payload, status, created_by) VALUES ($1, $2, $3, $4) RETURNING *"
+	creator := sql.NullInt64{Int64: createdBy, Valid: createdBy > 0}
+	return r.scanJob(r.db.QueryRow(query, jobType, payload, model.JobStatusQueued, creator))
+}
+
+// GetJobByID gets a job by id
+// the input parameter is the job id
+// it returns the job and an error
+func (r *JobRepository) GetJobByID(id int64) (*model.Job, error) {
+	query := "SELECT * FROM jobs WHERE id = $1"
+	return r.scanJob(r.db.QueryRow(query, id))
+}
+
+// GetJobs gets the jobs, the latest one is the first
+// it returns the jobs and an error
+func (r *JobRepository) GetJobs(filters *model.JobFilter) (*model.Jobs, error) {
+	var jobs model.Jobs
+	query := "SELECT * FROM jobs"
+	params := []interface{}{}
+	whereConditions := []string{}
+	if filters.Type != "" {
+		index := len(params) + 1
+		whereConditions = append(whereConditions, "type = $"+strconv.Itoa(index))
+		params = append(params, filters.Type)
+	}
+	if filters.Status != "" {
+		index := len(params) + 1
+		whereConditions = append(whereConditions, "status = $"+strconv.Itoa(index))
+		params = append(params, filters.Status)
+	}
+	if len(whereConditions) > 0 {
+		query += " WHERE " + strings.Join(whereConditions, " AND ")
+	}
+	query += " ORDER BY id DESC"
+	if filters.Limit > 0 {
+		query += " LIMIT " + strconv.Itoa(filters.Limit)
+	}
+	rows, err := r.db.Query(query, params...)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		job, err := r.scanJob(rows)
+		if err != nil {
+			return nil, typedError(err)
+		}
+		jobs = append(jobs, job)
+	}
+	return &jobs, nil
+}
+
+// ClaimJob marks the oldest queued job as running with the lease and returns it.
+// The running jobs with expired lease are claimed again, as their worker is crashed or lost the database.
+// The jobs that are locked by other workers are skipped, so that a job is processed only once.
+// it returns model.ErrNotFound if there is no job to claim
+func (r *JobRepository) ClaimJob(lease time.Duration) (*model.Job, error) {
+	query := "UPDATE jobs SET status = $1, attempts = attempts + 1, error = '', lease_expires_at = now() + $3 * interval '1 second', " +
+		"started_at = now(), finished_at = NULL, updated_at = now() " +
+		"WHERE id = (SELECT id FROM jobs WHERE status = $2 OR (status = $1 AND (lease_expires_at IS NULL OR lease_expires_at < now())) " +
+		"ORDER BY id FOR UPDATE SKIP LOCKED LIMIT 1) RETURNING *"
+	return r.scanJob(r.db.QueryRow(query, model.JobStatusRunning, model.JobStatusQueued, lease.Seconds()))
+}
+
+// RenewJobLease extends the lease of a running job
+// it returns model.ErrNotFound if the job is not running, eg. it is cancelled
+func (r *JobRepository) RenewJobLease(id int64, lease time.Duration) (*model.Job, error) {
+	query := "UPDATE jobs SET lease_expires_at = now() + $1 * interval '1 second' WHERE id = $2 AND status = $3 RETURNING *"
+	return r.scanJob(r.db.QueryRow(query, lease.Seconds(), id, model.JobStatusRunning))
+}
+
+// UpdateJobProgress updates the progress of a running job and appends the lines to the log
+// it returns model.ErrNotFound if the job is not running, eg. it is cancelled
+func (r *JobRepository) UpdateJobProgress(id int64, progress, total int, logLines string) (*model.Job, error) {
+	query := "UPDATE jobs SET progress = $1, total = $2, log = log || $3, updated_at = now() WHERE id = $4 AND status = $5 RETURNING *"
+	return r.scanJob(r.db.QueryRow(query, progress, total, logLines, id, model.JobStatusRunning))
+}
+
+// FinishJob sets the final status and progress of a running job and appends the lines to the log
+// it returns model.ErrNotFound if the job is not running, eg. it is cancelled
+func (r *JobRepository) FinishJob(id int64, progress int, status, errorMessage, logLines string) (*model.Job, error) {
+	query := "UPDATE jobs SET status = $1, progress = $2, error = $3, log = log || $4, finished_at = now(), updated_at = now() WHERE id = $5 AND status = $6 RETURNING *"
+	return r.scanJob(r.db.QueryRow(query, status, progress, errorMessage, logLines, id, model.JobStatusRunning))
+}
+
+// RetryJob queues a failed or cancelled job again. The progress and the log are kept, so that the job resumes after the processed items.
+// it returns model.ErrNotFound if the job is not failed nor cancelled
+func (r *JobRepository) RetryJob(id int64) (*model.Job, error) {
+	query := "UPDATE jobs SET status = $1, error = '', started_at = NULL, finished_at = NULL, updated_at = now() " +
+		"WHERE id = $2 AND status IN ($3, $4) RETURNING *"
+	return r.scanJob(r.db.QueryRow(query, model.JobStatusQueued, id, model.JobStatusFailed, model.JobStatusCancelled))
+}
+
+// CancelJob cancels a queued or running job. The running job is stopped by its worker on the next progress update.
+// it returns model.ErrNotFound if the job is not queued nor running
+func (r *JobRepository) CancelJob(id int64) (*model.Job, error) {
+	query := "UPDATE jobs SET status = $1, finished_at = now(), updated_at = now() WHERE id = $2 AND status IN ($3, $4) RETURNING *"
+	return r.scanJob(r.db.QueryRow(query, model.JobStatusCancelled, id, model.JobStatusQueued, model.JobStatusRunning))
+}
+
+// scanJob scans the job columns from the row
+func (r *JobRepository) scanJob(row interface{ Scan(...interface{}) error }) (*model.Job, error) {
+	var job model.Job
+	var createdBy sql.NullInt64
+	var startedAt, finishedAt, leaseExpiresAt sql.NullTime
+	err := row.Scan(&job.ID, &job.Type, &job.Payload, &job.Status, &job.Progress, &job.Total, &job.Log, &job.Error, &job.Attempts,
+		&createdBy, &job.CreatedAt, &startedAt, &finishedAt, &job.UpdatedAt, &leaseExpiresAt)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	if createdBy.Valid {
+		job.CreatedBy = createdBy.Int64
+	}
+	if startedAt.Valid {
+		job.StartedAt = startedAt.Time.Format(model.JobTimeFormat)
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = finishedAt.Time.Format(model.JobTimeFormat)
+	}
+	if leaseExpiresAt.Valid {
+		job.LeaseExpiresAt = leaseExpiresAt.Time.Format(model.JobTimeFormat)
+	}
+	return &job, nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// NotificationSubscriptionRepository type
+type NotificationSubscriptionRepository struct {
+	db *database.DB
+}
+
+// NewNotificationSubscriptionRepository creates a new notification subscription repository
+func NewNotificationSubscriptionRepository(db *database.DB) *NotificationSubscriptionRepository {
+	return &NotificationSubscriptionRepository{
+		db: db,
+	}
+}
+
+// GetNotificationSubscriptions gets the subscriptions ordered by the user and the rule
+// it returns the subscriptions and an error
+func (r *NotificationSubscriptionRepository) GetNotificationSubscriptions(filters *model.NotificationSubscriptionFilter) (*model.NotificationSubscriptions, error) {
+	var subscriptions model.NotificationSubscriptions
+	query := "SELECT * FROM notification_subscriptions"
+	params := []interface{}{}
+	whereConditions := []string{}
+	if len(filters.UserIDs) > 0 {
+		index := len(params) + 1
+		whereConditions = append(whereConditions, "user_id = ANY($"+strconv.Itoa(index)+"::bigint[])")
+		params = append(params, "{"+strings.Join(filters.UserIDs, ",")+"}")
+	}
+	if len(whereConditions) > 0 {
+		query += " WHERE " + strings.Join(whereConditions, " AND ")
+	}
+	query += " ORDER BY user_id, rule"
+	rows, err := r.db.Query(query, params...)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var subscription model.NotificationSubscription
+		err = rows.Scan(&subscription.ID, &subscription.UserID, &subscription.Rule, &subscription.Threshold, &subscription.Digest, &subscription.CreatedAt, &subscription.UpdatedAt)
+		if err != nil {
+			return nil, typedError(err)
+		}
+		subscriptions = append(subscriptions, &subscription)
+	}
+	return &subscriptions, nil
+}
+
+// SaveNotificationSubscriptions replaces the subscriptions of the user
+// the input parameters are the user id and the new subscriptions
+// it returns an error
+func (r *NotificationSubscriptionRepository) SaveNotificationSubscriptions(userID int64, subscriptions model.NotificationSubscriptions) error {
+	query := "DELETE FROM notification_subscriptions WHERE user_id = $1"
+	_, err := r.db.Exec(query, userID)
+	if err != nil {
+		return typedError(err)
+	}
+	for _, subscription := range subscriptions {
+		query = "INSERT INTO notification_subscriptions (user_id, rule, threshold, digest) VALUES ($1, $2, $3, $4)"
+		_, err = r.db.Exec(query, userID, subscription.Rule, subscription.Threshold, subscription.Digest)
+		if err != nil {
+			return typedError(err)
+		}
+	}
+	return nil
+}
+
+// NotificationDeliveryRepository type
+type NotificationDeliveryRepository struct {
+	db *database.DB
+}
+
+// NewNotificationDeliveryRepository creates a new notification delivery repository
+func NewNotificationDeliveryRepository(db *database.DB) *NotificationDeliveryRepository {
+	return &NotificationDeliveryRepository{
+		db: db,
+	}
+}
+
+// GetNotificationDeliveryKeys gets the keys of the deliveries that are sent to the user
+// the input parameter is the user id
+// it returns the keys and an error
+func (r *NotificationDeliveryRepository) GetNotificationDeliveryKeys(userID int64) ([]string, error) {
+	keys := []string{}
+	query := "SELECT delivery_key FROM notification_deliveries WHERE user_id = $1 ORDER BY delivery_key"
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key string
+		if err = rows.Scan(&key); err != nil {
+			return nil, typedError(err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// CreateNotificationDelivery stores the delivery key of the user
+// The existing key is not duplicated.
+// it returns an error
+func (r *NotificationDeliveryRepository) CreateNotificationDelivery(userID int64, key string) error {
+	query := "INSERT INTO notification_deliveries (user_id, delivery_key) VALUES ($1, $2) ON CONFLICT (user_id, delivery_key) DO NOTHING"
+	_, err := r.db.Exec(query, userID, key)
+	return typedError(err)
+}
+
+// DeleteNotificationDelivery deletes the delivery key of the user
+// it returns an error
+func (r *NotificationDeliveryRepository) DeleteNotificationDelivery(userID int64, key string) error {
+	query := "DELETE FROM notification_deliveries WHERE user_id = $1 AND delivery_key = $2"
+	_, err := r.db.Exec(query, userID, key)
+	return typedError(err)
+}
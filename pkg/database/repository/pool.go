@@ -4,12 +4,14 @@ import (
 	"time"
 
 	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/event"
 	"github.com/akosgarai/projectregister/pkg/model"
 )
 
 // PoolRepository type
 type PoolRepository struct {
-	db *database.DB
+	db     *database.DB
+	events *event.Bus
 }
 
 // NewPoolRepository creates a new pool repository
@@ -26,8 +28,12 @@ func (r *PoolRepository) CreatePool(name string) (*model.Pool, error) {
 	var pool model.Pool
 	query := "INSERT INTO pools (name) VALUES ($1) RETURNING *"
 	err := r.db.QueryRow(query, name).Scan(&pool.ID, &pool.Name, &pool.CreatedAt, &pool.UpdatedAt)
+	if err != nil {
+		return &pool, typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourcePool, event.ActionCreated, pool.ID, &pool))
 
-	return &pool, err
+	return &pool, nil
 }
 
 // GetPoolByName gets a pool by name
@@ -38,7 +44,7 @@ func (r *PoolRepository) GetPoolByName(name string) (*model.Pool, error) {
 	query := "SELECT * FROM pools WHERE name = $1"
 	err := r.db.QueryRow(query, name).Scan(&pool.ID, &pool.Name, &pool.CreatedAt, &pool.UpdatedAt)
 
-	return &pool, err
+	return &pool, typedError(err)
 }
 
 // GetPoolByID gets a pool by id
@@ -49,7 +55,7 @@ func (r *PoolRepository) GetPoolByID(id int64) (*model.Pool, error) {
 	query := "SELECT * FROM pools WHERE id = $1"
 	err := r.db.QueryRow(query, id).Scan(&pool.ID, &pool.Name, &pool.CreatedAt, &pool.UpdatedAt)
 
-	return &pool, err
+	return &pool, typedError(err)
 }
 
 // UpdatePool updates a pool
@@ -59,8 +65,12 @@ func (r *PoolRepository) UpdatePool(pool *model.Pool) error {
 	query := "UPDATE pools SET name = $1, updated_at = $2 WHERE id = $3"
 	now := time.Now().Format("2006-01-02 15:04:05.999999-07:00")
 	_, err := r.db.Exec(query, pool.Name, now, pool.ID)
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourcePool, event.ActionUpdated, pool.ID, pool))
 
-	return err
+	return nil
 }
 
 // DeletePool deletes a pool
@@ -69,7 +79,11 @@ func (r *PoolRepository) UpdatePool(pool *model.Pool) error {
 func (r *PoolRepository) DeletePool(id int64) error {
 	query := "DELETE FROM pools WHERE id = $1"
 	_, err := r.db.Exec(query, id)
-	return err
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourcePool, event.ActionDeleted, id, nil))
+	return nil
 }
 
 // GetPools gets all pools
@@ -85,14 +99,14 @@ func (r *PoolRepository) GetPools(filters *model.PoolFilter) (*model.Pools, erro
 	}
 	rows, err := r.db.Query(query, params...)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
 		var pool model.Pool
 		err = rows.Scan(&pool.ID, &pool.Name, &pool.CreatedAt, &pool.UpdatedAt)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		pools = append(pools, &pool)
 	}
@@ -4,12 +4,14 @@ import (
 	"time"
 
 	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/event"
 	"github.com/akosgarai/projectregister/pkg/model"
 )
 
 // ProjectRepository type
 type ProjectRepository struct {
-	db *database.DB
+	db     *database.DB
+	events *event.Bus
 }
 
 // NewProjectRepository creates a new project repository
@@ -26,8 +28,12 @@ func (r *ProjectRepository) CreateProject(name string) (*model.Project, error) {
 	var project model.Project
 	query := "INSERT INTO projects (name) VALUES ($1) RETURNING *"
 	err := r.db.QueryRow(query, name).Scan(&project.ID, &project.Name, &project.CreatedAt, &project.UpdatedAt)
+	if err != nil {
+		return &project, typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceProject, event.ActionCreated, project.ID, &project))
 
-	return &project, err
+	return &project, nil
 }
 
 // GetProjectByName gets a project by name
@@ -38,7 +44,7 @@ func (r *ProjectRepository) GetProjectByName(name string) (*model.Project, error
 	query := "SELECT * FROM projects WHERE name = $1"
 	err := r.db.QueryRow(query, name).Scan(&project.ID, &project.Name, &project.CreatedAt, &project.UpdatedAt)
 
-	return &project, err
+	return &project, typedError(err)
 }
 
 // GetProjectByID gets a project by id
@@ -49,7 +55,7 @@ func (r *ProjectRepository) GetProjectByID(id int64) (*model.Project, error) {
 	query := "SELECT * FROM projects WHERE id = $1"
 	err := r.db.QueryRow(query, id).Scan(&project.ID, &project.Name, &project.CreatedAt, &project.UpdatedAt)
 
-	return &project, err
+	return &project, typedError(err)
 }
 
 // UpdateProject updates a project
@@ -59,8 +65,12 @@ func (r *ProjectRepository) UpdateProject(project *model.Project) error {
 	query := "UPDATE projects SET name = $1, updated_at = $2 WHERE id = $3"
 	now := time.Now().Format("2006-01-02 15:04:05.999999-07:00")
 	_, err := r.db.Exec(query, project.Name, now, project.ID)
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceProject, event.ActionUpdated, project.ID, project))
 
-	return err
+	return nil
 }
 
 // DeleteProject deletes a project
@@ -69,7 +79,11 @@ func (r *ProjectRepository) UpdateProject(project *model.Project) error {
 func (r *ProjectRepository) DeleteProject(id int64) error {
 	query := "DELETE FROM projects WHERE id = $1"
 	_, err := r.db.Exec(query, id)
-	return err
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceProject, event.ActionDeleted, id, nil))
+	return nil
 }
 
 // GetProjects gets all projects
@@ -85,14 +99,14 @@ func (r *ProjectRepository) GetProjects(filters *model.ProjectFilter) (*model.Pr
 	}
 	rows, err := r.db.Query(query, params...)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
 		var project model.Project
 		err = rows.Scan(&project.ID, &project.Name, &project.CreatedAt, &project.UpdatedAt)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		projects = append(projects, &project)
 	}
@@ -25,14 +25,14 @@ func (r *ResourceRepository) GetResources() (*model.Resources, error) {
 	query := "SELECT * FROM resources"
 	rows, err := r.db.Query(query)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
 		var res model.Resource
 		err = rows.Scan(&res.ID, &res.Name)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		resources = append(resources, &res)
 	}
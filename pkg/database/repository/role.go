@@ -4,12 +4,14 @@ import (
 	"time"
 
 	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/event"
 	"github.com/akosgarai/projectregister/pkg/model"
 )
 
 // RoleRepository type
 type RoleRepository struct {
-	db *database.DB
+	db     *database.DB
+	events *event.Bus
 }
 
 // NewRoleRepository creates a new role repository
@@ -27,17 +29,18 @@ func (r *RoleRepository) CreateRole(name string, resourceIDs []int64) (*model.Ro
 	query := "INSERT INTO roles (name) VALUES ($1) RETURNING *"
 	err := r.db.QueryRow(query, name).Scan(&role.ID, &role.Name, &role.CreatedAt, &role.UpdatedAt)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	// insert the role_to_resource records
 	for _, resourceID := range resourceIDs {
 		query = "INSERT INTO role_to_resources (role_id, resource_id) VALUES ($1, $2)"
 		_, err = r.db.Exec(query, role.ID, resourceID)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 	}
-	return &role, err
+	r.publish(event.ActionCreated, role.ID)
+	return &role, nil
 }
 
 // GetRoleByName gets a role by name
@@ -48,24 +51,24 @@ func (r *RoleRepository) GetRoleByName(name string) (*model.Role, error) {
 	query := "SELECT * FROM roles WHERE name = $1"
 	err := r.db.QueryRow(query, name).Scan(&role.ID, &role.Name, &role.CreatedAt, &role.UpdatedAt)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	// get the resources
 	query = "SELECT r.id, r.name FROM resources r JOIN role_to_resources rr ON r.id = rr.resource_id WHERE rr.role_id = $1"
 	rows, err := r.db.Query(query, role.ID)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
 		var resource model.Resource
 		err = rows.Scan(&resource.ID, &resource.Name)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		role.Resources = append(role.Resources, &resource)
 	}
-	return &role, err
+	return &role, typedError(err)
 }
 
 // GetRoleByID gets a role by id
@@ -77,24 +80,24 @@ func (r *RoleRepository) GetRoleByID(id int64) (*model.Role, error) {
 	query := "SELECT * FROM roles WHERE id = $1"
 	err := r.db.QueryRow(query, id).Scan(&role.ID, &role.Name, &role.CreatedAt, &role.UpdatedAt)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	// get the resources
 	query = "SELECT r.id, r.name FROM resources r JOIN role_to_resources rr ON r.id = rr.resource_id WHERE rr.role_id = $1"
 	rows, err := r.db.Query(query, role.ID)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
 		var resource model.Resource
 		err = rows.Scan(&resource.ID, &resource.Name)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		role.Resources = append(role.Resources, &resource)
 	}
-	return &role, err
+	return &role, typedError(err)
 }
 
 // UpdateRole updates a role
@@ -105,22 +108,23 @@ func (r *RoleRepository) UpdateRole(role *model.Role, resources []int64) error {
 	now := time.Now().Format("2006-01-02 15:04:05.999999-07:00")
 	_, err := r.db.Exec(query, role.Name, now, role.ID)
 	if err != nil {
-		return err
+		return typedError(err)
 	}
 	// delete the role_to_resources records
 	query = "DELETE FROM role_to_resources WHERE role_id = $1"
 	_, err = r.db.Exec(query, role.ID)
 	if err != nil {
-		return err
+		return typedError(err)
 	}
 	// insert the role_to_resources records
 	for _, resourceID := range resources {
 		query = "INSERT INTO role_to_resources (role_id, resource_id) VALUES ($1, $2)"
 		_, err = r.db.Exec(query, role.ID, resourceID)
 		if err != nil {
-			return err
+			return typedError(err)
 		}
 	}
+	r.publish(event.ActionUpdated, role.ID)
 	return nil
 }
 
@@ -130,7 +134,23 @@ func (r *RoleRepository) UpdateRole(role *model.Role, resources []int64) error {
 func (r *RoleRepository) DeleteRole(id int64) error {
 	query := "DELETE FROM roles WHERE id = $1"
 	_, err := r.db.Exec(query, id)
-	return err
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceRole, event.ActionDeleted, id, nil))
+	return nil
+}
+
+// publish publishes the role change event with the stored role, including its resources.
+func (r *RoleRepository) publish(action string, id int64) {
+	if r.events == nil {
+		return
+	}
+	role, err := r.GetRoleByID(id)
+	if err != nil {
+		return
+	}
+	r.events.Publish(event.New(event.ResourceRole, action, id, role))
 }
 
 // GetRoles gets all roles
@@ -146,14 +166,14 @@ func (r *RoleRepository) GetRoles(filters *model.RoleFilter) (*model.Roles, erro
 	}
 	rows, err := r.db.Query(query, params...)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
 		var role model.Role
 		err = rows.Scan(&role.ID, &role.Name, &role.CreatedAt, &role.UpdatedAt)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		roles = append(roles, &role)
 	}
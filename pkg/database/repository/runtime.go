@@ -4,12 +4,14 @@ import (
 	"time"
 
 	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/event"
 	"github.com/akosgarai/projectregister/pkg/model"
 )
 
 // RuntimeRepository type
 type RuntimeRepository struct {
-	db *database.DB
+	db     *database.DB
+	events *event.Bus
 }
 
 // NewRuntimeRepository creates a new runtime repository
@@ -26,8 +28,12 @@ func (r *RuntimeRepository) CreateRuntime(name string, score int) (*model.Runtim
 	var runtime model.Runtime
 	query := "INSERT INTO runtimes (name, score) VALUES ($1, $2) RETURNING *"
 	err := r.db.QueryRow(query, name, score).Scan(&runtime.ID, &runtime.Name, &runtime.CreatedAt, &runtime.UpdatedAt, &runtime.Score)
+	if err != nil {
+		return &runtime, typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceRuntime, event.ActionCreated, runtime.ID, &runtime))
 
-	return &runtime, err
+	return &runtime, nil
 }
 
 // GetRuntimeByName gets a runtime by name
@@ -38,7 +44,7 @@ func (r *RuntimeRepository) GetRuntimeByName(name string) (*model.Runtime, error
 	query := "SELECT * FROM runtimes WHERE name = $1"
 	err := r.db.QueryRow(query, name).Scan(&runtime.ID, &runtime.Name, &runtime.CreatedAt, &runtime.UpdatedAt, &runtime.Score)
 
-	return &runtime, err
+	return &runtime, typedError(err)
 }
 
 // GetRuntimeByID gets a runtime by id
@@ -49,7 +55,7 @@ func (r *RuntimeRepository) GetRuntimeByID(id int64) (*model.Runtime, error) {
 	query := "SELECT * FROM runtimes WHERE id = $1"
 	err := r.db.QueryRow(query, id).Scan(&runtime.ID, &runtime.Name, &runtime.CreatedAt, &runtime.UpdatedAt, &runtime.Score)
 
-	return &runtime, err
+	return &runtime, typedError(err)
 }
 
 // UpdateRuntime updates a runtime
@@ -59,8 +65,12 @@ func (r *RuntimeRepository) UpdateRuntime(runtime *model.Runtime) error {
 	query := "UPDATE runtimes SET name = $1, score = $2, updated_at = $3 WHERE id = $4"
 	now := time.Now().Format("2006-01-02 15:04:05.999999-07:00")
 	_, err := r.db.Exec(query, runtime.Name, runtime.Score, now, runtime.ID)
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceRuntime, event.ActionUpdated, runtime.ID, runtime))
 
-	return err
+	return nil
 }
 
 // DeleteRuntime deletes a runtime
@@ -69,7 +79,11 @@ func (r *RuntimeRepository) UpdateRuntime(runtime *model.Runtime) error {
 func (r *RuntimeRepository) DeleteRuntime(id int64) error {
 	query := "DELETE FROM runtimes WHERE id = $1"
 	_, err := r.db.Exec(query, id)
-	return err
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceRuntime, event.ActionDeleted, id, nil))
+	return nil
 }
 
 // GetRuntimes gets all runtimes
@@ -85,14 +99,14 @@ func (r *RuntimeRepository) GetRuntimes(filters *model.RuntimeFilter) (*model.Ru
 	}
 	rows, err := r.db.Query(query, params...)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
 		var runtime model.Runtime
 		err = rows.Scan(&runtime.ID, &runtime.Name, &runtime.CreatedAt, &runtime.UpdatedAt, &runtime.Score)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		runtimes = append(runtimes, &runtime)
 	}
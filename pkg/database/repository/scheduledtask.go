@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// ScheduledTaskRepository type
+type ScheduledTaskRepository struct {
+	db *database.DB
+}
+
+// NewScheduledTaskRepository creates a new scheduled task repository
+func NewScheduledTaskRepository(db *database.DB) *ScheduledTaskRepository {
+	return &ScheduledTaskRepository{
+		db: db,
+	}
+}
+
+// GetScheduledTaskByID gets a scheduled task by id
+// the input parameter is the task id
+// it returns the task and an error
+func (r *ScheduledTaskRepository) GetScheduledTaskByID(id int64) (*model.ScheduledTask, error) {
+	query := "SELECT * FROM scheduled_tasks WHERE id = $1"
+	return r.scanScheduledTask(r.db.QueryRow(query, id))
+}
+
+// GetScheduledTasks gets the scheduled tasks ordered by the name
+// it returns the tasks and an error
+func (r *ScheduledTaskRepository) GetScheduledTasks() (*model.ScheduledTasks, error) {
+	var tasks model.ScheduledTasks
+	query := "SELECT * FROM scheduled_tasks ORDER BY name"
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		task, err := r.scanScheduledTask(rows)
+		if err != nil {
+			return nil, typedError(err)
+		}
+		tasks = append(tasks, task)
+	}
+	return &tasks, nil
+}
+
+// UpdateScheduledTask updates the schedule, the enabled flag and the next run of a task
+// the zero nextRunAt is stored as null
+// it returns the updated task and an error
+func (r *ScheduledTaskRepository) UpdateScheduledTask(id int64, schedule string, enabled bool, nextRunAt time.Time) (*model.ScheduledTask, error) {
+	query := "UPDATE scheduled_tasks SET schedule = $1, enabled = $2, next_run_at = $3, updated_at = now() WHERE id = $4 RETURNING *"
+	return r.scanScheduledTask(r.db.QueryRow(query, schedule, enabled, nullTime(nextRunAt), id))
+}
+
+// StartScheduledTask marks the due task as running and moves its next run forward
+// it returns model.ErrNotFound if the task is disabled or it is not due, eg. it is already started by another instance
+func (r *ScheduledTaskRepository) StartScheduledTask(id int64, startedAt, nextRunAt time.Time) (*model.ScheduledTask, error) {
+	query := "UPDATE scheduled_tasks SET last_run_at = $1, last_status = $2, last_message = '', next_run_at = $3, updated_at = now() " +
+		"WHERE id = $4 AND enabled AND next_run_at <= $1 RETURNING *"
+	return r.scanScheduledTask(r.db.QueryRow(query, startedAt, model.ScheduledTaskStatusRunning, nullTime(nextRunAt), id))
+}
+
+// FinishScheduledTask stores the outcome of the last run
+// it returns the updated task and an error
+func (r *ScheduledTaskRepository) FinishScheduledTask(id int64, status, message string, durationMs int64) (*model.ScheduledTask, error) {
+	query := "UPDATE scheduled_tasks SET last_status = $1, last_message = $2, last_duration_ms = $3, updated_at = now() WHERE id = $4 RETURNING *"
+	return r.scanScheduledTask(r.db.QueryRow(query, status, message, durationMs, id))
+}
+
+// scanScheduledTask scans the scheduled task columns from the row
+func (r *ScheduledTaskRepository) scanScheduledTask(row interface{ Scan(...interface{}) error }) (*model.ScheduledTask, error) {
+	var task model.ScheduledTask
+	var lastRunAt, nextRunAt sql.NullTime
+	err := row.Scan(&task.ID, &task.Name, &task.Description, &task.Schedule, &task.Enabled, &lastRunAt,
+		&task.LastStatus, &task.LastMessage, &task.LastDurationMs, &nextRunAt, &task.UpdatedAt)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	if lastRunAt.Valid {
+		task.LastRunAt = lastRunAt.Time.Format(model.ScheduledTaskTimeFormat)
+	}
+	if nextRunAt.Valid {
+		task.NextRunAt = nextRunAt.Time.Format(model.ScheduledTaskTimeFormat)
+	}
+	return &task, nil
+}
+
+// nullTime returns the time as a nullable value, the zero time is null.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// StatisticsSnapshotRepository type
+type StatisticsSnapshotRepository struct {
+	db *database.DB
+}
+
+// NewStatisticsSnapshotRepository creates a new statistics snapshot repository
+func NewStatisticsSnapshotRepository(db *database.DB) *StatisticsSnapshotRepository {
+	return &StatisticsSnapshotRepository{
+		db: db,
+	}
+}
+
+// CreateStatisticsSnapshot counts the inventory and stores the counts
+// it returns the created snapshot and an error
+func (r *StatisticsSnapshotRepository) CreateStatisticsSnapshot() (*model.StatisticsSnapshot, error) {
+	query := "INSERT INTO statistics_snapshots (clients, projects, environments, applications, domains, domains_without_ssl, servers, databases, certificates) " +
+		"SELECT (SELECT count(*) FROM clients), (SELECT count(*) FROM projects), (SELECT count(*) FROM environments), " +
+		"(SELECT count(*) FROM applications), (SELECT count(*) FROM domains), (SELECT count(*) FROM domains WHERE has_ssl IS NOT TRUE), " +
+		"(SELECT count(*) FROM servers), (SELECT count(*) FROM databases), (SELECT count(*) FROM certificates) RETURNING *"
+	var snapshot model.StatisticsSnapshot
+	err := r.db.QueryRow(query).Scan(&snapshot.ID, &snapshot.Clients, &snapshot.Projects, &snapshot.Environments, &snapshot.Applications,
+		&snapshot.Domains, &snapshot.DomainsWithoutSSL, &snapshot.Servers, &snapshot.Databases, &snapshot.Certificates, &snapshot.CreatedAt)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	return &snapshot, nil
+}
@@ -6,12 +6,14 @@ import (
 	"time"
 
 	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/event"
 	"github.com/akosgarai/projectregister/pkg/model"
 )
 
 // ServerRepository type
 type ServerRepository struct {
-	db *database.DB
+	db     *database.DB
+	events *event.Bus
 }
 
 // NewServerRepository creates a new server repository
@@ -29,14 +31,14 @@ func (r *ServerRepository) CreateServer(name, description, remoteAddress string,
 	query := "INSERT INTO servers (name, description, remote_address) VALUES ($1, $2, $3) RETURNING *"
 	err := r.db.QueryRow(query, name, description, remoteAddress).Scan(&server.ID, &server.Name, &server.Description, &server.RemoteAddr, &server.CreatedAt, &server.UpdatedAt)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	// create the server runtime relations
 	for _, runtimeID := range runtimes {
 		query = "INSERT INTO server_to_runtime (server_id, runtime_id) VALUES ($1, $2)"
 		_, err = r.db.Exec(query, server.ID, runtimeID)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 	}
 	// create the server pool relations
@@ -44,11 +46,17 @@ func (r *ServerRepository) CreateServer(name, description, remoteAddress string,
 		query = "INSERT INTO server_to_pool (server_id, pool_id) VALUES ($1, $2)"
 		_, err = r.db.Exec(query, server.ID, poolID)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 	}
 
-	return r.withRelations(&server)
+	created, err := r.withRelations(&server)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceServer, event.ActionCreated, created.ID, created))
+
+	return created, nil
 }
 
 // GetServerByName gets a server by name
@@ -59,7 +67,7 @@ func (r *ServerRepository) GetServerByName(name string) (*model.Server, error) {
 	query := "SELECT * FROM servers WHERE name = $1"
 	err := r.db.QueryRow(query, name).Scan(&server.ID, &server.Name, &server.Description, &server.RemoteAddr, &server.CreatedAt, &server.UpdatedAt)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 
 	return r.withRelations(&server)
@@ -73,7 +81,7 @@ func (r *ServerRepository) GetServerByRemoteAddress(remoteAddress string) (*mode
 	query := "SELECT * FROM servers WHERE remote_address = $1"
 	err := r.db.QueryRow(query, remoteAddress).Scan(&server.ID, &server.Name, &server.Description, &server.RemoteAddr, &server.CreatedAt, &server.UpdatedAt)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 
 	return r.withRelations(&server)
@@ -88,7 +96,7 @@ func (r *ServerRepository) GetServerByID(id int64) (*model.Server, error) {
 	err := r.db.QueryRow(query, id).Scan(&server.ID, &server.Name, &server.Description, &server.RemoteAddr, &server.CreatedAt, &server.UpdatedAt)
 
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 
 	return r.withRelations(&server)
@@ -103,20 +111,20 @@ func (r *ServerRepository) UpdateServer(server *model.Server) error {
 	_, err := r.db.Exec(query, server.Name, now, server.ID)
 
 	if err != nil {
-		return err
+		return typedError(err)
 	}
 
 	// update the server runtime relations
 	query = "DELETE FROM server_to_runtime WHERE server_id = $1"
 	_, err = r.db.Exec(query, server.ID)
 	if err != nil {
-		return err
+		return typedError(err)
 	}
 	for _, runtime := range server.Runtimes {
 		query = "INSERT INTO server_to_runtime (server_id, runtime_id) VALUES ($1, $2)"
 		_, err = r.db.Exec(query, server.ID, runtime.ID)
 		if err != nil {
-			return err
+			return typedError(err)
 		}
 	}
 
@@ -124,15 +132,16 @@ func (r *ServerRepository) UpdateServer(server *model.Server) error {
 	query = "DELETE FROM server_to_pool WHERE server_id = $1"
 	_, err = r.db.Exec(query, server.ID)
 	if err != nil {
-		return err
+		return typedError(err)
 	}
 	for _, pool := range server.Pools {
 		query = "INSERT INTO server_to_pool (server_id, pool_id) VALUES ($1, $2)"
 		_, err = r.db.Exec(query, server.ID, pool.ID)
 		if err != nil {
-			return err
+			return typedError(err)
 		}
 	}
+	r.events.Publish(event.New(event.ResourceServer, event.ActionUpdated, server.ID, server))
 
 	return nil
 }
@@ -144,16 +153,20 @@ func (r *ServerRepository) DeleteServer(id int64) error {
 	query := "DELETE FROM server_to_runtime WHERE server_id = $1"
 	_, err := r.db.Exec(query, id)
 	if err != nil {
-		return err
+		return typedError(err)
 	}
 	query = "DELETE FROM server_to_pool WHERE server_id = $1"
 	_, err = r.db.Exec(query, id)
 	if err != nil {
-		return err
+		return typedError(err)
 	}
 	query = "DELETE FROM servers WHERE id = $1"
 	_, err = r.db.Exec(query, id)
-	return err
+	if err != nil {
+		return typedError(err)
+	}
+	r.events.Publish(event.New(event.ResourceServer, event.ActionDeleted, id, nil))
+	return nil
 }
 
 // GetServers gets all servers
@@ -194,18 +207,18 @@ func (r *ServerRepository) GetServers(filters *model.ServerFilter) (*model.Serve
 	}
 	rows, err := r.db.Query(query, params...)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
 		var server model.Server
 		err = rows.Scan(&server.ID, &server.Name, &server.Description, &server.RemoteAddr, &server.CreatedAt, &server.UpdatedAt)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		serverWithRelations, err := r.withRelations(&server)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		servers = append(servers, serverWithRelations)
 	}
@@ -220,18 +233,18 @@ func (r *ServerRepository) withRelations(server *model.Server) (*model.Server, e
 	query := "SELECT runtime_id FROM server_to_runtime WHERE server_id = $1"
 	rows, err := r.db.Query(query, server.ID)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
 		var runtimeID int64
 		err = rows.Scan(&runtimeID)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		runtime, err := runtimeRepository.GetRuntimeByID(runtimeID)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		server.Runtimes = append(server.Runtimes, runtime)
 	}
@@ -240,18 +253,18 @@ func (r *ServerRepository) withRelations(server *model.Server) (*model.Server, e
 	query = "SELECT pool_id FROM server_to_pool WHERE server_id = $1"
 	rows, err = r.db.Query(query, server.ID)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
 		var poolID int64
 		err = rows.Scan(&poolID)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		pool, err := poolRepository.GetPoolByID(poolID)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		server.Pools = append(server.Pools, pool)
 	}
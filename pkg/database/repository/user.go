@@ -6,12 +6,14 @@ import (
 	"time"
 
 	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/event"
 	"github.com/akosgarai/projectregister/pkg/model"
 )
 
 // UserRepository type
 type UserRepository struct {
-	db *database.DB
+	db     *database.DB
+	events *event.Bus
 }
 
 // NewUserRepository creates a new user repository
@@ -29,12 +31,17 @@ func (u *UserRepository) CreateUser(username, email, password string, roleID int
 	query := "INSERT INTO users (name, email, password, role_id) VALUES ($1, $2, $3, $4) RETURNING *"
 	err := u.db.QueryRow(query, username, email, password, roleID).Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.CreatedAt, &user.UpdatedAt, &roleID)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	role := model.Role{}
 	role.ID = roleID
 	user.Role = &role
-	return u.withRole(&user)
+	created, err := u.withRole(&user)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	u.events.Publish(event.New(event.ResourceUser, event.ActionCreated, created.ID, created))
+	return created, nil
 }
 
 // GetUserByEmail gets a user by email
@@ -44,7 +51,7 @@ func (u *UserRepository) GetUserByEmail(email string) (*model.User, error) {
 	var roleID int64
 	err := u.db.QueryRow(query, email).Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.CreatedAt, &user.UpdatedAt, &roleID)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	role := model.Role{}
 	role.ID = roleID
@@ -60,7 +67,7 @@ func (u *UserRepository) GetUserByID(id int64) (*model.User, error) {
 	var roleID int64
 	err := u.db.QueryRow(query, id).Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.CreatedAt, &user.UpdatedAt, &roleID)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	role := model.Role{}
 	role.ID = roleID
@@ -73,14 +80,22 @@ func (u *UserRepository) UpdateUser(user *model.User) error {
 	query := "UPDATE users SET name = $1, email = $2, password = $3, updated_at = $4, role_id = $5 WHERE id = $6"
 	now := time.Now().Format("2006-01-02 15:04:05.999999-07:00")
 	_, err := u.db.Exec(query, user.Name, user.Email, user.Password, now, user.Role.ID, user.ID)
-	return err
+	if err != nil {
+		return typedError(err)
+	}
+	u.events.Publish(event.New(event.ResourceUser, event.ActionUpdated, user.ID, user))
+	return nil
 }
 
 // DeleteUser deletes a user
 func (u *UserRepository) DeleteUser(id int64) error {
 	query := "DELETE FROM users WHERE id = $1"
 	_, err := u.db.Exec(query, id)
-	return err
+	if err != nil {
+		return typedError(err)
+	}
+	u.events.Publish(event.New(event.ResourceUser, event.ActionDeleted, id, nil))
+	return nil
 }
 
 // GetUsers gets all users
@@ -110,7 +125,7 @@ func (u *UserRepository) GetUsers(filters *model.UserFilter) ([]*model.User, err
 	}
 	rows, err := u.db.Query(query, params...)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
@@ -118,7 +133,7 @@ func (u *UserRepository) GetUsers(filters *model.UserFilter) ([]*model.User, err
 		var role model.Role
 		err = rows.Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.CreatedAt, &user.UpdatedAt, &role.ID, &role.Name, &role.CreatedAt, &role.UpdatedAt)
 		if err != nil {
-			return nil, err
+			return nil, typedError(err)
 		}
 		user.Role = &role
 		users = append(users, &user)
@@ -131,7 +146,7 @@ func (u *UserRepository) withRole(user *model.User) (*model.User, error) {
 	roleRepository := NewRoleRepository(u.db)
 	role, err := roleRepository.GetRoleByID(user.Role.ID)
 	if err != nil {
-		return nil, err
+		return nil, typedError(err)
 	}
 	user.Role = role
 	return user, nil
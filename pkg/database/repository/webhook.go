@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/database"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+const (
+	// webhookEventTypesSeparator is the separator of the event types in the event_types column.
+	webhookEventTypesSeparator = ","
+)
+
+// WebhookRepository type
+type WebhookRepository struct {
+	db *database.DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *database.DB) *WebhookRepository {
+	return &WebhookRepository{
+		db: db,
+	}
+}
+
+// CreateWebhook creates a new webhook
+// the input parameters are the name, the url, the signing secret, the subscribed event types and the active flag
+// it returns the created webhook and an error
+func (r *WebhookRepository) CreateWebhook(name, url, secret string, eventTypes []string, active bool) (*model.Webhook, error) {
+	query := "INSERT INTO webhooks (name, url, secret, event_types, active) VALUES ($1, $2, $3, $4, $5) RETURNING *"
+	return r.scanWebhook(r.db.QueryRow(query, name, url, secret, strings.Join(eventTypes, webhookEventTypesSeparator), active))
+}
+
+// GetWebhookByID gets a webhook by id
+// the input parameter is the webhook id
+// it returns the webhook and an error
+func (r *WebhookRepository) GetWebhookByID(id int64) (*model.Webhook, error) {
+	query := "SELECT * FROM webhooks WHERE id = $1"
+	return r.scanWebhook(r.db.QueryRow(query, id))
+}
+
+// UpdateWebhook updates a webhook
+// the input parameter is the webhook
+// it returns an error
+func (r *WebhookRepository) UpdateWebhook(webhook *model.Webhook) error {
+	query := "UPDATE webhooks SET name = $1, url = $2, secret = $3, event_types = $4, active = $5, updated_at = $6 WHERE id = $7"
+	now := time.Now().Format("2006-01-02 15:04:05.999999-07:00")
+	_, err := r.db.Exec(query, webhook.Name, webhook.URL, webhook.Secret, strings.Join(webhook.EventTypes, webhookEventTypesSeparator), webhook.Active, now, webhook.ID)
+
+	return typedError(err)
+}
+
+// DeleteWebhook deletes a webhook
+// The deliveries of the webhook are deleted by the database.
+// the input parameter is the webhook id
+// it returns an error
+func (r *WebhookRepository) DeleteWebhook(id int64) error {
+	query := "DELETE FROM webhooks WHERE id = $1"
+	_, err := r.db.Exec(query, id)
+	return typedError(err)
+}
+
+// GetWebhooks gets the webhooks
+// it returns the webhooks and an error
+func (r *WebhookRepository) GetWebhooks(filters *model.WebhookFilter) (*model.Webhooks, error) {
+	var webhooks model.Webhooks
+	query := "SELECT * FROM webhooks"
+	params := []interface{}{}
+	whereConditions := []string{}
+	if filters.Name != "" {
+		index := len(params) + 1
+		whereConditions = append(whereConditions, "name LIKE '%' || $"+strconv.Itoa(index)+" || '%'")
+		params = append(params, filters.Name)
+	}
+	if filters.EventType != "" {
+		index := len(params) + 1
+		whereConditions = append(whereConditions, "active = TRUE AND $"+strconv.Itoa(index)+" = ANY(string_to_array(event_types, '"+webhookEventTypesSeparator+"'))")
+		params = append(params, filters.EventType)
+	}
+	if len(whereConditions) > 0 {
+		query += " WHERE " + strings.Join(whereConditions, " AND ")
+	}
+	query += " ORDER BY name"
+	rows, err := r.db.Query(query, params...)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		webhook, err := r.scanWebhook(rows)
+		if err != nil {
+			return nil, typedError(err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return &webhooks, nil
+}
+
+// scanWebhook scans the webhook columns from the row
+func (r *WebhookRepository) scanWebhook(row interface{ Scan(...interface{}) error }) (*model.Webhook, error) {
+	var webhook model.Webhook
+	var eventTypes string
+	err := row.Scan(&webhook.ID, &webhook.Name, &webhook.URL, &webhook.Secret, &eventTypes, &webhook.Active, &webhook.CreatedAt, &webhook.UpdatedAt)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	webhook.EventTypes = []string{}
+	if eventTypes != "" {
+		webhook.EventTypes = strings.Split(eventTypes, webhookEventTypesSeparator)
+	}
+	return &webhook, nil
+}
+
+// WebhookDeliveryRepository type
+type WebhookDeliveryRepository struct {
+	db *database.DB
+}
+
+// NewWebhookDeliveryRepository creates a new webhook delivery repository
+func NewWebhookDeliveryRepository(db *database.DB) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{
+		db: db,
+	}
+}
+
+// CreateWebhookDelivery logs a delivery attempt
+// the input parameter is the delivery
+// it returns the stored delivery and an error
+func (r *WebhookDeliveryRepository) CreateWebhookDelivery(delivery *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	query := "INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, attempt, status_code, error, duration_ms) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *"
+	return r.scanWebhookDelivery(r.db.QueryRow(query, delivery.WebhookID, delivery.EventID, delivery.EventType, delivery.Payload, delivery.Attempt, delivery.StatusCode, delivery.Error, delivery.DurationMs))
+}
+
+// GetWebhookDeliveries gets the deliveries, the latest one is the first
+// it returns the deliveries and an error
+func (r *WebhookDeliveryRepository) GetWebhookDeliveries(filters *model.WebhookDeliveryFilter) (*model.WebhookDeliveries, error) {
+	var deliveries model.WebhookDeliveries
+	query := "SELECT * FROM webhook_deliveries"
+	params := []interface{}{}
+	whereConditions := []string{}
+	if len(filters.WebhookIDs) > 0 {
+		index := len(params) + 1
+		whereConditions = append(whereConditions, "webhook_id = ANY($"+strconv.Itoa(index)+"::bigint[])")
+		params = append(params, "{"+strings.Join(filters.WebhookIDs, ",")+"}")
+	}
+	if len(whereConditions) > 0 {
+		query += " WHERE " + strings.Join(whereConditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+	if filters.Limit > 0 {
+		query += " LIMIT " + strconv.Itoa(filters.Limit)
+	}
+	rows, err := r.db.Query(query, params...)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		delivery, err := r.scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, typedError(err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return &deliveries, nil
+}
+
+// scanWebhookDelivery scans the delivery columns from the row
+func (r *WebhookDeliveryRepository) scanWebhookDelivery(row interface{ Scan(...interface{}) error }) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	err := row.Scan(&delivery.ID, &delivery.WebhookID, &delivery.EventID, &delivery.EventType, &delivery.Payload, &delivery.Attempt, &delivery.StatusCode, &delivery.Error, &delivery.DurationMs, &delivery.CreatedAt)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	return &delivery, nil
+}
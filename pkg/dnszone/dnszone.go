@@ -0,0 +1,498 @@
+package dnszone
+
+// This package contains the BIND zone file import and export related functions.
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+const (
+	// txtChunkSize is the maximum length of a character string in the TXT records.
+	txtChunkSize = 255
+)
+
+var (
+	// ErrMissingOrigin is returned if the zone origin is neither given nor set by $ORIGIN.
+	ErrMissingOrigin = errors.New("the zone origin is missing")
+)
+
+// Zone type is the result of the zone file parsing.
+// The Origin is the apex domain name without the trailing dot.
+// The Skipped contains the types of the records that are not supported, like SOA or NS.
+type Zone struct {
+	Origin  string
+	TTL     int
+	Records model.DNSRecords
+	Skipped []string
+}
+
+// token is a word of the zone file line. The quoted flag is set for the character strings.
+type token struct {
+	value  string
+	quoted bool
+}
+
+// Parse parses the BIND zone file. The zone origin is the given origin, or the first $ORIGIN directive if it is empty.
+// The relative names of the file are resolved with the origin of the latest $ORIGIN directive,
+// but the record names are relative to the zone origin, so the names out of the zone are errors.
+// Only the A, AAAA, CNAME, MX and TXT records are imported, the rest of them are skipped.
+// The targets of the CNAME and MX records are absolute.
+func Parse(r io.Reader, origin string) (*Zone, error) {
+	zone := &Zone{Origin: normalizeName(origin), TTL: model.DNSRecordDefaultTTL, Records: model.DNSRecords{}, Skipped: []string{}}
+	currentOrigin := zone.Origin
+	defaultTTL := -1
+	lastTTL := model.DNSRecordDefaultTTL
+	lastOwner := ""
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for {
+		tokens, startsWithBlank, startLine, err := readEntry(scanner, &lineNumber)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", startLine, err)
+		}
+		if tokens == nil {
+			break
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+		// directives
+		switch strings.ToUpper(tokens[0].value) {
+		case "$ORIGIN":
+			if len(tokens) < 2 {
+				return nil, fmt.Errorf("line %d: invalid $ORIGIN directive", startLine)
+			}
+			currentOrigin = normalizeName(absoluteName(tokens[1].value, currentOrigin))
+			if zone.Origin == "" {
+				zone.Origin = currentOrigin
+			}
+			continue
+		case "$TTL":
+			if len(tokens) < 2 {
+				return nil, fmt.Errorf("line %d: invalid $TTL directive", startLine)
+			}
+			ttl, err := parseTTL(tokens[1].value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", startLine, err)
+			}
+			defaultTTL = ttl
+			zone.TTL = ttl
+			continue
+		case "$INCLUDE", "$GENERATE":
+			return nil, fmt.Errorf("line %d: the %s directive is not supported", startLine, tokens[0].value)
+		}
+		if zone.Origin == "" {
+			return nil, fmt.Errorf("line %d: %w", startLine, ErrMissingOrigin)
+		}
+		// the owner is inherited from the previous record if the line starts with blank.
+		// It is stored as absolute name, as the origin could be changed before the next record.
+		if !startsWithBlank {
+			lastOwner = absoluteName(tokens[0].value, currentOrigin)
+			tokens = tokens[1:]
+		}
+		if lastOwner == "" {
+			return nil, fmt.Errorf("line %d: the owner name is missing", startLine)
+		}
+		ttl := defaultTTL
+		if ttl < 0 {
+			ttl = lastTTL
+		}
+		// the ttl and the class are optional and could be in any order
+		for len(tokens) > 0 {
+			if isClass(tokens[0].value) {
+				tokens = tokens[1:]
+				continue
+			}
+			parsed, err := parseTTL(tokens[0].value)
+			if err != nil {
+				break
+			}
+			ttl = parsed
+			lastTTL = parsed
+			tokens = tokens[1:]
+		}
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("line %d: the record type is missing", startLine)
+		}
+		recordType := strings.ToUpper(tokens[0].value)
+		if !isSupportedType(recordType) {
+			zone.Skipped = append(zone.Skipped, recordType)
+			continue
+		}
+		name, err := relativeName(lastOwner, zone.Origin)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", startLine, err)
+		}
+		record := &model.DNSRecord{Name: name, Type: recordType, TTL: ttl}
+		if err := setRecordData(record, tokens[1:], currentOrigin); err != nil {
+			return nil, fmt.Errorf("line %d: %w", startLine, err)
+		}
+		if err := ValidateRecord(record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", startLine, err)
+		}
+		zone.Records = append(zone.Records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return zone, nil
+}
+
+// readEntry reads the next entry of the zone file. The entries in parentheses could be multiline.
+// It returns nil tokens at the end of the input.
+func readEntry(scanner *bufio.Scanner, lineNumber *int) ([]token, bool, int, error) {
+	var tokens []token
+	startsWithBlank := false
+	startLine := 0
+	depth := 0
+	for scanner.Scan() {
+		*lineNumber++
+		line := scanner.Text()
+		if startLine == 0 {
+			startLine = *lineNumber
+			startsWithBlank = len(line) > 0 && (line[0] == ' ' || line[0] == '\t')
+			tokens = []token{}
+		}
+		lineTokens, lineDepth, err := tokenize(line)
+		if err != nil {
+			return nil, false, startLine, err
+		}
+		tokens = append(tokens, lineTokens...)
+		depth += lineDepth
+		if depth < 0 {
+			return nil, false, startLine, errors.New("unbalanced parentheses")
+		}
+		if depth == 0 {
+			return tokens, startsWithBlank, startLine, nil
+		}
+	}
+	if depth > 0 {
+		return nil, false, startLine, errors.New("unbalanced parentheses")
+	}
+	return tokens, startsWithBlank, startLine, nil
+}
+
+// tokenize splits the line to tokens. The comments are dropped, the quoted strings are unescaped.
+// It returns the tokens and the change of the parentheses depth.
+func tokenize(line string) ([]token, int, error) {
+	tokens := []token{}
+	depth := 0
+	current := strings.Builder{}
+	inWord := false
+	flush := func() {
+		if inWord {
+			tokens = append(tokens, token{value: current.String()})
+			current.Reset()
+			inWord = false
+		}
+	}
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == ';':
+			flush()
+			return tokens, depth, nil
+		case c == '(':
+			flush()
+			depth++
+		case c == ')':
+			flush()
+			depth--
+		case c == ' ' || c == '\t' || c == '\r':
+			flush()
+		case c == '"':
+			flush()
+			value, end, err := readQuoted(line, i+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			tokens = append(tokens, token{value: value, quoted: true})
+			i = end
+		default:
+			current.WriteByte(c)
+			inWord = true
+		}
+	}
+	flush()
+	return tokens, depth, nil
+}
+
+// readQuoted reads the quoted string that starts at the given index.
+// It returns the unescaped value and the index of the closing quote.
+func readQuoted(line string, start int) (string, int, error) {
+	value := strings.Builder{}
+	for i := start; i < len(line); i++ {
+		c := line[i]
+		if c == '"' {
+			return value.String(), i, nil
+		}
+		if c != '\\' || i+1 >= len(line) {
+			value.WriteByte(c)
+			continue
+		}
+		// \DDD is a decimal byte, the other escaped characters are taken literally
+		if i+3 < len(line) && isDigits(line[i+1:i+4]) {
+			code, _ := strconv.Atoi(line[i+1 : i+4])
+			value.WriteByte(byte(code))
+			i += 3
+			continue
+		}
+		value.WriteByte(line[i+1])
+		i++
+	}
+	return "", 0, errors.New("unterminated quoted string")
+}
+
+// isDigits checks if the string contains only decimal digits.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// isClass checks if the token is a dns class.
+func isClass(value string) bool {
+	switch strings.ToUpper(value) {
+	case "IN", "CH", "HS", "CS":
+		return true
+	}
+	return false
+}
+
+// isSupportedType checks if the record type is supported.
+func isSupportedType(recordType string) bool {
+	for _, supported := range model.DNSRecordTypes {
+		if supported == recordType {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTTL parses the ttl value. The BIND unit suffixes (s, m, h, d, w) are supported.
+func parseTTL(value string) (int, error) {
+	if isDigits(value) {
+		return strconv.Atoi(value)
+	}
+	units := map[byte]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+	total := 0
+	number := ""
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c >= '0' && c <= '9' {
+			number += string(c)
+			continue
+		}
+		multiplier, ok := units[c|0x20]
+		if !ok || number == "" {
+			return 0, fmt.Errorf("invalid ttl: %s", value)
+		}
+		n, _ := strconv.Atoi(number)
+		total += n * multiplier
+		number = ""
+	}
+	if number != "" {
+		return 0, fmt.Errorf("invalid ttl: %s", value)
+	}
+	return total, nil
+}
+
+// setRecordData sets the type specific data of the record from the rdata tokens.
+func setRecordData(record *model.DNSRecord, tokens []token, origin string) error {
+	if len(tokens) == 0 {
+		return fmt.Errorf("the data of the %s record is missing", record.Type)
+	}
+	switch record.Type {
+	case model.DNSRecordTypeMX:
+		if len(tokens) != 2 {
+			return errors.New("the MX record needs priority and exchange")
+		}
+		priority, err := strconv.Atoi(tokens[0].value)
+		if err != nil {
+			return fmt.Errorf("invalid MX priority: %s", tokens[0].value)
+		}
+		record.Priority = priority
+		record.Value = absoluteName(tokens[1].value, origin)
+	case model.DNSRecordTypeCNAME:
+		record.Value = absoluteName(tokens[0].value, origin)
+	case model.DNSRecordTypeTXT:
+		// the character strings are concatenated
+		value := strings.Builder{}
+		for _, t := range tokens {
+			value.WriteString(t.value)
+		}
+		record.Value = value.String()
+	default:
+		record.Value = tokens[0].value
+	}
+	return nil
+}
+
+// NormalizeRecord normalizes the record that is given by the user in the zone of the origin.
+// The name is set relative to the origin, the targets of the CNAME and MX records are set absolute.
+func NormalizeRecord(record *model.DNSRecord, origin string) error {
+	origin = normalizeName(origin)
+	name, err := relativeName(record.Name, origin)
+	if err != nil {
+		return err
+	}
+	record.Name = name
+	record.Type = strings.ToUpper(record.Type)
+	if record.Type == model.DNSRecordTypeCNAME || record.Type == model.DNSRecordTypeMX {
+		record.Value = absoluteName(record.Value, origin)
+	}
+	return nil
+}
+
+// ValidateRecord validates the type specific data of the record.
+func ValidateRecord(record *model.DNSRecord) error {
+	if !isSupportedType(record.Type) {
+		return fmt.Errorf("unsupported record type: %s", record.Type)
+	}
+	if record.TTL < 0 {
+		return fmt.Errorf("invalid ttl: %d", record.TTL)
+	}
+	if record.Name != model.DNSRecordApexName && !isValidHostname(record.Name, true) {
+		return fmt.Errorf("invalid record name: %s", record.Name)
+	}
+	switch record.Type {
+	case model.DNSRecordTypeA:
+		ip := net.ParseIP(record.Value)
+		if ip == nil || ip.To4() == nil {
+			return fmt.Errorf("invalid IPv4 address: %s", record.Value)
+		}
+	case model.DNSRecordTypeAAAA:
+		ip := net.ParseIP(record.Value)
+		if ip == nil || ip.To4() != nil {
+			return fmt.Errorf("invalid IPv6 address: %s", record.Value)
+		}
+	case model.DNSRecordTypeCNAME, model.DNSRecordTypeMX:
+		if !isValidHostname(strings.TrimSuffix(record.Value, "."), false) {
+			return fmt.Errorf("invalid target name: %s", record.Value)
+		}
+		if record.Type == model.DNSRecordTypeMX && (record.Priority < 0 || record.Priority > 65535) {
+			return fmt.Errorf("invalid MX priority: %d", record.Priority)
+		}
+	case model.DNSRecordTypeTXT:
+		if record.Value == "" {
+			return errors.New("the TXT record is empty")
+		}
+	}
+	return nil
+}
+
+// isValidHostname checks the labels of the name. The wildcard label is allowed only for the owner names.
+func isValidHostname(name string, owner bool) bool {
+	if name == "" || len(name) > 253 {
+		return false
+	}
+	for i, label := range strings.Split(name, ".") {
+		if label == "*" && owner && i == 0 {
+			continue
+		}
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for j := 0; j < len(label); j++ {
+			c := label[j] | 0x20
+			if (c < 'a' || c > 'z') && (label[j] < '0' || label[j] > '9') && label[j] != '-' && label[j] != '_' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// normalizeName returns the lower case name without the trailing dot.
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+}
+
+// absoluteName returns the fully qualified name with trailing dot.
+func absoluteName(name, origin string) string {
+	if name == model.DNSRecordApexName {
+		return normalizeName(origin) + "."
+	}
+	if strings.HasSuffix(name, ".") {
+		return strings.ToLower(name)
+	}
+	return strings.ToLower(name) + "." + normalizeName(origin) + "."
+}
+
+// relativeName returns the name relative to the origin. The origin itself is "@".
+func relativeName(name, origin string) (string, error) {
+	if name == model.DNSRecordApexName || name == "" {
+		return model.DNSRecordApexName, nil
+	}
+	if !strings.HasSuffix(name, ".") {
+		return strings.ToLower(name), nil
+	}
+	name = normalizeName(name)
+	if name == origin {
+		return model.DNSRecordApexName, nil
+	}
+	if !strings.HasSuffix(name, "."+origin) {
+		return "", fmt.Errorf("the name %s is out of the zone %s", name, origin)
+	}
+	return strings.TrimSuffix(name, "."+origin), nil
+}
+
+// Export writes the zone file of the origin with the default ttl of the zone. The records are ordered by the name and the type.
+func Export(w io.Writer, origin string, ttl int, records model.DNSRecords) error {
+	origin = normalizeName(origin)
+	sorted := make(model.DNSRecords, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Name != sorted[j].Name {
+			// the apex records are the first ones
+			if sorted[i].Name == model.DNSRecordApexName || sorted[j].Name == model.DNSRecordApexName {
+				return sorted[i].Name == model.DNSRecordApexName
+			}
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].Type < sorted[j].Type
+	})
+	buffer := bufio.NewWriter(w)
+	fmt.Fprintf(buffer, "$ORIGIN %s.\n", origin)
+	fmt.Fprintf(buffer, "$TTL %d\n", ttl)
+	for _, record := range sorted {
+		data := record.Value
+		switch record.Type {
+		case model.DNSRecordTypeMX:
+			data = fmt.Sprintf("%d %s", record.Priority, record.Value)
+		case model.DNSRecordTypeTXT:
+			data = quoteTXT(record.Value)
+		}
+		fmt.Fprintf(buffer, "%s\t%d\tIN\t%s\t%s\n", record.Name, record.TTL, record.Type, data)
+	}
+	return buffer.Flush()
+}
+
+// quoteTXT returns the quoted character strings of the TXT value.
+// The long values are split to 255 bytes long strings.
+func quoteTXT(value string) string {
+	chunks := []string{}
+	for len(value) > 0 {
+		size := txtChunkSize
+		if len(value) < size {
+			size = len(value)
+		}
+		chunk := strings.ReplaceAll(value[:size], "\\", "\\\\")
+		chunk = strings.ReplaceAll(chunk, "\"", "\\\"")
+		chunks = append(chunks, "\""+chunk+"\"")
+		value = value[size:]
+	}
+	return strings.Join(chunks, " ")
+}
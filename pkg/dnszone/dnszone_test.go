@@ -0,0 +1,177 @@
+package dnszone
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+const testZoneFile = `$ORIGIN example.com.
+$TTL 1h
+; the SOA and NS records are skipped
+@	IN	SOA	ns1.example.com. hostmaster.example.com. (
+		2024010101 ; serial
+		7200 3600 1209600 300 )
+	IN	NS	ns1.example.com.
+@	300	IN	A	192.0.2.1
+	IN	AAAA	2001:db8::1
+www	IN	CNAME	@
+mail.example.com.	IN 600	A	192.0.2.2
+@	MX	10	mail
+@	TXT	"v=spf1 mx" " -all" ; comment
+_dmarc	TXT	"v=DMARC1; p=none; rua=\"mailto:dmarc@example.com\""
+`
+
+// TestParse tests the zone file parsing.
+func TestParse(t *testing.T) {
+	zone, err := Parse(strings.NewReader(testZoneFile), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if zone.Origin != "example.com" || zone.TTL != 3600 {
+		t.Errorf("Zone is not set properly. Got: %s %d", zone.Origin, zone.TTL)
+	}
+	if len(zone.Skipped) != 2 {
+		t.Errorf("Skipped records are not set properly. Got: %v", zone.Skipped)
+	}
+	expected := model.DNSRecords{
+		{Name: "@", Type: "A", TTL: 300, Value: "192.0.2.1"},
+		{Name: "@", Type: "AAAA", TTL: 3600, Value: "2001:db8::1"},
+		{Name: "www", Type: "CNAME", TTL: 3600, Value: "example.com."},
+		{Name: "mail", Type: "A", TTL: 600, Value: "192.0.2.2"},
+		{Name: "@", Type: "MX", TTL: 3600, Priority: 10, Value: "mail.example.com."},
+		{Name: "@", Type: "TXT", TTL: 3600, Value: "v=spf1 mx -all"},
+		{Name: "_dmarc", Type: "TXT", TTL: 3600, Value: "v=DMARC1; p=none; rua=\"mailto:dmarc@example.com\""},
+	}
+	if len(zone.Records) != len(expected) {
+		t.Fatalf("Records are not set properly. Got: %d records", len(zone.Records))
+	}
+	for i, record := range zone.Records {
+		if !record.Equals(expected[i]) || record.TTL != expected[i].TTL {
+			t.Errorf("Record %d is not set properly. Expected: %v, got: %v", i, expected[i], record)
+		}
+	}
+}
+
+// TestParseMultipleOrigins tests that the names are relative to the zone origin after the $ORIGIN directives.
+func TestParseMultipleOrigins(t *testing.T) {
+	data := `$ORIGIN example.com.
+www	A	192.0.2.1
+$ORIGIN sub.example.com.
+api	A	192.0.2.2
+	MX	10	mail
+@	CNAME	www.example.com.
+`
+	zone, err := Parse(strings.NewReader(data), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if zone.Origin != "example.com" {
+		t.Errorf("Zone origin is not set properly. Got: %s", zone.Origin)
+	}
+	expected := model.DNSRecords{
+		{Name: "www", Type: "A", Value: "192.0.2.1"},
+		{Name: "api.sub", Type: "A", Value: "192.0.2.2"},
+		{Name: "api.sub", Type: "MX", Priority: 10, Value: "mail.sub.example.com."},
+		{Name: "sub", Type: "CNAME", Value: "www.example.com."},
+	}
+	if len(zone.Records) != len(expected) {
+		t.Fatalf("Records are not set properly. Got: %v", zone.Records)
+	}
+	for i, record := range zone.Records {
+		if !record.Equals(expected[i]) {
+			t.Errorf("Record %d is not set properly. Expected: %v, got: %v", i, expected[i], record)
+		}
+	}
+	// the given origin is the zone, the $ORIGIN directives only resolve the relative names.
+	zone, err = Parse(strings.NewReader("$ORIGIN sub.example.com.\napi A 192.0.2.2\n"), "example.com")
+	if err != nil || zone.Origin != "example.com" || len(zone.Records) != 1 || zone.Records[0].Name != "api.sub" {
+		t.Errorf("Given origin is not kept. Got: %v, %v", zone, err)
+	}
+	if _, err := Parse(strings.NewReader("$ORIGIN example.com.\nwww A 192.0.2.1\n$ORIGIN example.org.\nwww A 192.0.2.1\n"), ""); err == nil {
+		t.Error("Error is expected for the $ORIGIN out of the zone.")
+	}
+}
+
+// TestParseErrors tests the invalid zone files.
+func TestParseErrors(t *testing.T) {
+	testData := map[string]string{
+		"missing origin":  "www IN A 192.0.2.1\n",
+		"invalid ipv4":    "$ORIGIN example.com.\nwww IN A 2001:db8::1\n",
+		"invalid ipv6":    "$ORIGIN example.com.\nwww IN AAAA 192.0.2.1\n",
+		"out of zone":     "$ORIGIN example.com.\nwww.example.org. IN A 192.0.2.1\n",
+		"unbalanced":      "$ORIGIN example.com.\n@ IN SOA ns1 host ( 1 2 3\n",
+		"missing mx host": "$ORIGIN example.com.\n@ IN MX 10\n",
+		"unterminated":    "$ORIGIN example.com.\n@ IN TXT \"abc\n",
+		"include":         "$INCLUDE other.zone\n",
+	}
+	for name, data := range testData {
+		if _, err := Parse(strings.NewReader(data), ""); err == nil {
+			t.Errorf("Error is expected for %s", name)
+		}
+	}
+}
+
+// TestExport tests that the exported zone file could be parsed to the same records.
+func TestExport(t *testing.T) {
+	zone, err := Parse(strings.NewReader(testZoneFile), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	longTXT := model.DNSRecord{Name: "long", Type: "TXT", TTL: 60, Value: strings.Repeat("a", 300)}
+	records := append(zone.Records, &longTXT)
+	zone.TTL = 7200
+	var buffer bytes.Buffer
+	if err := Export(&buffer, "example.com.", zone.TTL, records); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buffer.String(), "$ORIGIN example.com.\n$TTL 7200\n@\t") {
+		t.Errorf("Export header is not set properly. Got: %s", buffer.String())
+	}
+	exported, err := Parse(&buffer, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported.TTL != zone.TTL {
+		t.Errorf("Exported zone ttl is not set properly. Want: %d, Got: %d", zone.TTL, exported.TTL)
+	}
+	if len(exported.Records) != len(records) {
+		t.Fatalf("Exported records are not set properly. Got: %d records", len(exported.Records))
+	}
+	for _, record := range records {
+		if !exported.Records.Contains(record) {
+			t.Errorf("Record is missing from the export: %v", record)
+		}
+	}
+}
+
+// TestNormalizeRecord tests the normalization of the user given records.
+func TestNormalizeRecord(t *testing.T) {
+	record := &model.DNSRecord{Name: "WWW.Example.com.", Type: "cname", Value: "web"}
+	if err := NormalizeRecord(record, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if record.Name != "www" || record.Type != "CNAME" || record.Value != "web.example.com." {
+		t.Errorf("Record is not normalized properly. Got: %v", record)
+	}
+	if err := NormalizeRecord(&model.DNSRecord{Name: "www.example.org."}, "example.com"); err == nil {
+		t.Error("Error is expected for out of zone name")
+	}
+}
+
+// TestParseTTL tests the ttl parsing with units.
+func TestParseTTL(t *testing.T) {
+	testData := map[string]int{"300": 300, "1h": 3600, "1h30m": 5400, "1W": 604800, "2d": 172800}
+	for value, expected := range testData {
+		if ttl, err := parseTTL(value); err != nil || ttl != expected {
+			t.Errorf("Invalid ttl for %s. Expected: %d, got: %d (%v)", value, expected, ttl, err)
+		}
+	}
+	for _, value := range []string{"A", "MX", "1x", "h"} {
+		if _, err := parseTTL(value); err == nil {
+			t.Errorf("Error is expected for %s", value)
+		}
+	}
+}
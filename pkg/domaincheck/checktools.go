@@ -4,8 +4,10 @@ package domaincheck
 
 import (
 	"crypto/tls"
+	"net"
 	"time"
 
+	"github.com/akosgarai/projectregister/pkg/certificate"
 	"github.com/akosgarai/projectregister/pkg/model"
 )
 
@@ -48,3 +50,26 @@ func hasSSL(domain string) bool {
 
 	return true
 }
+
+// LiveCertificateFingerprint returns the SHA-256 fingerprint of the leaf certificate
+// that is served by the domain. The certificate is not verified, because the untrusted
+// certificates also has to be compared with the registered ones.
+// It returns empty string if the certificate could not be fetched.
+func LiveCertificateFingerprint(domain *model.Domain) string {
+	return liveCertificateFingerprint(domain.Name + ":443")
+}
+
+// liveCertificateFingerprint returns the fingerprint of the leaf certificate served on the address.
+func liveCertificateFingerprint(address string) string {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	conn, err := tls.DialWithDialer(dialer, "tcp", address, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		return ""
+	}
+	defer conn.Close()
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return ""
+	}
+	return certificate.Fingerprint(certs[0])
+}
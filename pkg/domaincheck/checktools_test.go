@@ -0,0 +1,23 @@
+package domaincheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akosgarai/projectregister/pkg/certificate"
+)
+
+// TestLiveCertificateFingerprint tests the fingerprint of the served certificate.
+func TestLiveCertificateFingerprint(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	expected := certificate.Fingerprint(server.Certificate())
+	if fingerprint := liveCertificateFingerprint(server.Listener.Addr().String()); fingerprint != expected {
+		t.Errorf("Invalid fingerprint. Expected: %s, got: %s", expected, fingerprint)
+	}
+	server.Close()
+	if fingerprint := liveCertificateFingerprint(server.Listener.Addr().String()); fingerprint != "" {
+		t.Errorf("Fingerprint should be empty for closed server. Got: %s", fingerprint)
+	}
+}
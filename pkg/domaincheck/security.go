@@ -0,0 +1,223 @@
+package domaincheck
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+const (
+	// SecurityGradeA is the grade of the domains without (or with negligible) security issues.
+	SecurityGradeA = "A"
+	// SecurityGradeB is the grade of the domains with minor security issues.
+	SecurityGradeB = "B"
+	// SecurityGradeC is the grade of the domains with security issues.
+	SecurityGradeC = "C"
+	// SecurityGradeD is the grade of the domains with serious security issues.
+	SecurityGradeD = "D"
+	// SecurityGradeF is the grade of the domains that failed the audit.
+	SecurityGradeF = "F"
+
+	// hstsMinMaxAge is the minimum accepted max-age of the HSTS header in seconds (180 days).
+	hstsMinMaxAge = 15552000
+)
+
+// SecurityReport is the result of the security audit of a domain.
+// The score starts from 100, every finding decreases it.
+type SecurityReport struct {
+	Score    int
+	Grade    string
+	Findings []string
+}
+
+// newSecurityReport returns a report without findings.
+func newSecurityReport() *SecurityReport {
+	return &SecurityReport{
+		Score:    100,
+		Findings: []string{},
+	}
+}
+
+// addFinding stores the finding and decreases the score with the given penalty.
+func (r *SecurityReport) addFinding(penalty int, format string, args ...interface{}) {
+	r.Score -= penalty
+	if r.Score < 0 {
+		r.Score = 0
+	}
+	r.Findings = append(r.Findings, fmt.Sprintf(format, args...))
+}
+
+// Apply sets the audit result fields of the domain.
+func (r *SecurityReport) Apply(domain *model.Domain) {
+	domain.SecurityScore = r.Score
+	domain.SecurityGrade = r.Grade
+	domain.SecurityFindings = strings.Join(r.Findings, "\n")
+}
+
+// GradeFromScore returns the letter grade of the security score.
+func GradeFromScore(score int) string {
+	switch {
+	case score >= 90:
+		return SecurityGradeA
+	case score >= 80:
+		return SecurityGradeB
+	case score >= 65:
+		return SecurityGradeC
+	case score >= 50:
+		return SecurityGradeD
+	}
+	return SecurityGradeF
+}
+
+// securityAuditor executes the security checks against host:port.
+// The rootCAs is used for the certificate verification, nil means the system pool.
+type securityAuditor struct {
+	port    string
+	rootCAs *x509.CertPool
+	timeout time.Duration
+}
+
+// AuditSecurity audits the HTTP security headers, the cookie flags,
+// the supported TLS protocol versions and the weak cipher suites of the domain.
+func AuditSecurity(domain *model.Domain) *SecurityReport {
+	auditor := &securityAuditor{
+		port:    "443",
+		rootCAs: nil,
+		timeout: 10 * time.Second,
+	}
+	return auditor.audit(domain.Name)
+}
+
+// audit executes every check and calculates the grade.
+func (a *securityAuditor) audit(host string) *SecurityReport {
+	report := newSecurityReport()
+	// Without valid certificate the rest of the checks are pointless.
+	if err := a.handshake(host, &tls.Config{}); err != nil {
+		report.addFinding(100, "Valid TLS certificate is missing: %s", err.Error())
+		report.Grade = SecurityGradeF
+		return report
+	}
+	a.checkProtocols(host, report)
+	a.checkCipherSuites(host, report)
+	a.checkHeaders(host, report)
+	report.Grade = GradeFromScore(report.Score)
+	return report
+}
+
+// handshake connects to the host with the given tls config.
+func (a *securityAuditor) handshake(host string, config *tls.Config) error {
+	config.ServerName = host
+	config.RootCAs = a.rootCAs
+	dialer := &net.Dialer{Timeout: a.timeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", net.JoinHostPort(host, a.port), config)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// checkProtocols checks the supported TLS protocol versions.
+// The TLS 1.0 and 1.1 are deprecated, the TLS 1.3 is expected.
+func (a *securityAuditor) checkProtocols(host string, report *SecurityReport) {
+	legacyVersions := []uint16{tls.VersionTLS10, tls.VersionTLS11}
+	for _, version := range legacyVersions {
+		if a.handshake(host, &tls.Config{MinVersion: version, MaxVersion: version, InsecureSkipVerify: true}) == nil {
+			report.addFinding(10, "Deprecated protocol is enabled: %s", tls.VersionName(version))
+		}
+	}
+	if a.handshake(host, &tls.Config{MinVersion: tls.VersionTLS13, MaxVersion: tls.VersionTLS13, InsecureSkipVerify: true}) != nil {
+		report.addFinding(5, "Protocol is not supported: %s", tls.VersionName(tls.VersionTLS13))
+	}
+}
+
+// checkCipherSuites checks whether the server accepts the insecure cipher suites.
+// The cipher suites are not configurable in TLS 1.3, so the check is limited to TLS 1.2 and lower.
+func (a *securityAuditor) checkCipherSuites(host string, report *SecurityReport) {
+	for _, suite := range tls.InsecureCipherSuites() {
+		config := &tls.Config{
+			MinVersion:         tls.VersionTLS10,
+			MaxVersion:         tls.VersionTLS12,
+			CipherSuites:       []uint16{suite.ID},
+			InsecureSkipVerify: true,
+		}
+		if a.handshake(host, config) == nil {
+			report.addFinding(20, "Weak cipher suite is accepted: %s", suite.Name)
+			return
+		}
+	}
+}
+
+// checkHeaders requests the root page of the domain and checks the security headers and cookie flags.
+func (a *securityAuditor) checkHeaders(host string, report *SecurityReport) {
+	client := &http.Client{
+		Timeout:   a.timeout,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: a.rootCAs}},
+		// The headers of the first response are checked, the redirects are not followed.
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Get("https://" + net.JoinHostPort(host, a.port) + "/")
+	if err != nil {
+		report.addFinding(30, "Failed to request the page: %s", err.Error())
+		return
+	}
+	defer resp.Body.Close()
+
+	hsts := resp.Header.Get("Strict-Transport-Security")
+	if hsts == "" {
+		report.addFinding(20, "Strict-Transport-Security header is missing")
+	} else if maxAge := hstsMaxAge(hsts); maxAge < hstsMinMaxAge {
+		report.addFinding(10, "Strict-Transport-Security max-age is too short: %d", maxAge)
+	}
+	csp := resp.Header.Get("Content-Security-Policy")
+	if csp == "" {
+		report.addFinding(15, "Content-Security-Policy header is missing")
+	}
+	if resp.Header.Get("X-Frame-Options") == "" && !strings.Contains(csp, "frame-ancestors") {
+		report.addFinding(10, "X-Frame-Options header is missing")
+	}
+	if !strings.EqualFold(resp.Header.Get("X-Content-Type-Options"), "nosniff") {
+		report.addFinding(10, "X-Content-Type-Options header is not nosniff")
+	}
+	for _, cookie := range resp.Cookies() {
+		missingFlags := []string{}
+		if !cookie.Secure {
+			missingFlags = append(missingFlags, "Secure")
+		}
+		if !cookie.HttpOnly {
+			missingFlags = append(missingFlags, "HttpOnly")
+		}
+		// The zero value means the attribute is missing.
+		if cookie.SameSite == 0 || cookie.SameSite == http.SameSiteDefaultMode {
+			missingFlags = append(missingFlags, "SameSite")
+		}
+		if len(missingFlags) > 0 {
+			report.addFinding(5, "Cookie %s is missing flags: %s", cookie.Name, strings.Join(missingFlags, ", "))
+		}
+	}
+}
+
+// hstsMaxAge returns the max-age directive of the HSTS header value.
+// It returns 0 if the directive is missing or invalid.
+func hstsMaxAge(header string) int {
+	for _, directive := range strings.Split(header, ";") {
+		name, value, found := strings.Cut(strings.TrimSpace(directive), "=")
+		if !found || !strings.EqualFold(name, "max-age") {
+			continue
+		}
+		maxAge, err := strconv.Atoi(strings.Trim(value, "\""))
+		if err != nil {
+			return 0
+		}
+		return maxAge
+	}
+	return 0
+}
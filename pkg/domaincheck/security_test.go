@@ -0,0 +1,101 @@
+package domaincheck
+
+import (
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestAuditor returns an auditor that trusts the certificate of the test server.
+func newTestAuditor(t *testing.T, server *httptest.Server) (*securityAuditor, string) {
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+	return &securityAuditor{port: port, rootCAs: rootCAs, timeout: 5 * time.Second}, host
+}
+
+// TestAuditSecurityHardenedServer tests the audit of a server with every security header.
+func TestAuditSecurityHardenedServer(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+		w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "test", Secure: true, HttpOnly: true, SameSite: http.SameSiteStrictMode})
+	}))
+	defer server.Close()
+	auditor, host := newTestAuditor(t, server)
+	report := auditor.audit(host)
+	if report.Grade != SecurityGradeA {
+		t.Errorf("Grade is not set properly. Got: %s, findings: %v", report.Grade, report.Findings)
+	}
+	if report.Score != 100 {
+		t.Errorf("Score is not set properly. Got: %d, findings: %v", report.Score, report.Findings)
+	}
+}
+
+// TestAuditSecurityMissingHeaders tests the audit of a server without security headers.
+func TestAuditSecurityMissingHeaders(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "test"})
+	}))
+	defer server.Close()
+	auditor, host := newTestAuditor(t, server)
+	report := auditor.audit(host)
+	// hsts: 20, csp: 15, x-frame-options: 10, x-content-type-options: 10, cookie: 5
+	if report.Score != 40 {
+		t.Errorf("Score is not set properly. Got: %d, findings: %v", report.Score, report.Findings)
+	}
+	if report.Grade != SecurityGradeF {
+		t.Errorf("Grade is not set properly. Got: %s", report.Grade)
+	}
+	if len(report.Findings) != 5 {
+		t.Errorf("Findings are not set properly. Got: %v", report.Findings)
+	}
+}
+
+// TestAuditSecurityUntrustedCertificate tests the audit of a server with untrusted certificate.
+func TestAuditSecurityUntrustedCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	auditor, host := newTestAuditor(t, server)
+	auditor.rootCAs = x509.NewCertPool()
+	report := auditor.audit(host)
+	if report.Score != 0 || report.Grade != SecurityGradeF {
+		t.Errorf("Report is not set properly. Got: %d %s", report.Score, report.Grade)
+	}
+}
+
+// TestGradeFromScore tests the score to grade conversion.
+func TestGradeFromScore(t *testing.T) {
+	testData := map[int]string{
+		100: SecurityGradeA, 90: SecurityGradeA, 89: SecurityGradeB, 80: SecurityGradeB,
+		79: SecurityGradeC, 65: SecurityGradeC, 64: SecurityGradeD, 50: SecurityGradeD,
+		49: SecurityGradeF, 0: SecurityGradeF,
+	}
+	for score, expected := range testData {
+		if grade := GradeFromScore(score); grade != expected {
+			t.Errorf("Invalid grade for %d. Expected: %s, got: %s", score, expected, grade)
+		}
+	}
+}
+
+// TestHstsMaxAge tests the max-age parsing of the HSTS header.
+func TestHstsMaxAge(t *testing.T) {
+	testData := map[string]int{
+		"max-age=31536000":                   31536000,
+		"includeSubDomains; max-age=\"300\"": 300,
+		"max-age=invalid":                    0,
+		"includeSubDomains":                  0,
+	}
+	for header, expected := range testData {
+		if maxAge := hstsMaxAge(header); maxAge != expected {
+			t.Errorf("Invalid max-age for %s. Expected: %d, got: %d", header, expected, maxAge)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package event
+
+// This package contains the internal event bus. The repositories publish the resource change events,
+// the subscribers, like the webhook dispatcher, react on them.
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+const (
+	// ActionCreated is the action of the resource creation.
+	ActionCreated = "created"
+	// ActionUpdated is the action of the resource update.
+	ActionUpdated = "updated"
+	// ActionDeleted is the action of the resource deletion.
+	ActionDeleted = "deleted"
+
+	// ResourceApplication is the resource name of the applications.
+	ResourceApplication = "application"
+	// ResourceCertificate is the resource name of the certificates.
+	ResourceCertificate = "certificate"
+	// ResourceClient is the resource name of the clients.
+	ResourceClient = "client"
+	// ResourceDatabase is the resource name of the databases.
+	ResourceDatabase = "database"
+	// ResourceDNSRecord is the resource name of the dns records.
+	ResourceDNSRecord = "dns_record"
+	// ResourceDomain is the resource name of the domains.
+	ResourceDomain = "domain"
+	// ResourceEnvironment is the resource name of the environments.
+	ResourceEnvironment = "environment"
+	// ResourceFramework is the resource name of the frameworks.
+	ResourceFramework = "framework"
+	// ResourcePool is the resource name of the pools.
+	ResourcePool = "pool"
+	// ResourceProject is the resource name of the projects.
+	ResourceProject = "project"
+	// ResourceRole is the resource name of the roles.
+	ResourceRole = "role"
+	// ResourceRuntime is the resource name of the runtimes.
+	ResourceRuntime = "runtime"
+	// ResourceServer is the resource name of the servers.
+	ResourceServer = "server"
+	// ResourceUser is the resource name of the users.
+	ResourceUser = "user"
+)
+
+var (
+	// Resources is the list of the resources that emit events.
+	Resources = []string{
+		ResourceApplication, ResourceCertificate, ResourceClient, ResourceDatabase, ResourceDNSRecord,
+		ResourceDomain, ResourceEnvironment, ResourceFramework, ResourcePool, ResourceProject,
+		ResourceRole, ResourceRuntime, ResourceServer, ResourceUser,
+	}
+	// Actions is the list of the actions of the resources.
+	Actions = []string{ActionCreated, ActionUpdated, ActionDeleted}
+)
+
+// Types returns every event type in resource.action format.
+func Types() []string {
+	types := []string{}
+	for _, resource := range Resources {
+		for _, action := range Actions {
+			types = append(types, resource+"."+action)
+		}
+	}
+	return types
+}
+
+// Event type is a resource change event.
+// The Data is the resource after the change, it is nil for the deleted resources.
+type Event struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	Resource   string      `json:"resource"`
+	Action     string      `json:"action"`
+	ResourceID int64       `json:"resource_id"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data,omitempty"`
+}
+
+// New creates a new event with unique id.
+func New(resource, action string, resourceID int64, data interface{}) *Event {
+	return &Event{
+		ID:         newID(),
+		Type:       resource + "." + action,
+		Resource:   resource,
+		Action:     action,
+		ResourceID: resourceID,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+// newID returns a random identifier.
+func newID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return time.Now().UTC().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(id)
+}
+
+// Handler is the function that is called with the published events.
+// It is called synchronously, so it must not block.
+type Handler func(e *Event)
+
+// Bus type is the event bus.
+// The nil bus is valid, it drops the published events.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+// NewBus creates a new event bus.
+func NewBus() *Bus {
+	return &Bus{
+		handlers: []Handler{},
+	}
+}
+
+// Subscribe registers the handler for every event.
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish sends the event to the subscribers.
+func (b *Bus) Publish(e *Event) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, handler := range b.handlers {
+		handler(e)
+	}
+}
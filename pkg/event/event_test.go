@@ -0,0 +1,37 @@
+package event
+
+import (
+	"testing"
+)
+
+// TestPublish tests that the subscribers get the published events.
+func TestPublish(t *testing.T) {
+	bus := NewBus()
+	received := []*Event{}
+	bus.Subscribe(func(e *Event) { received = append(received, e) })
+	bus.Subscribe(func(e *Event) { received = append(received, e) })
+	bus.Publish(New(ResourceDomain, ActionCreated, 1, nil))
+	if len(received) != 2 {
+		t.Fatalf("Every subscriber has to get the event. Got: %d", len(received))
+	}
+	if received[0].Type != "domain.created" || received[0].ResourceID != 1 || received[0].ID == "" {
+		t.Errorf("The event is not set properly. Got: %+v", received[0])
+	}
+}
+
+// TestPublishNilBus tests that the nil bus drops the events.
+func TestPublishNilBus(t *testing.T) {
+	var bus *Bus
+	bus.Publish(New(ResourceDomain, ActionDeleted, 1, nil))
+}
+
+// TestTypes tests the event type list.
+func TestTypes(t *testing.T) {
+	types := Types()
+	if len(types) != len(Resources)*len(Actions) {
+		t.Errorf("Invalid number of types. Got: %d", len(types))
+	}
+	if types[0] != "application.created" {
+		t.Errorf("Invalid first type. Got: %s", types[0])
+	}
+}
@@ -0,0 +1,144 @@
+package health
+
+// This package contains the readiness checks of the application dependencies.
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// StatusOK is the status of the passed checks and the ready application.
+	StatusOK = "ok"
+	// StatusFailed is the status of the failed checks and the not ready application.
+	StatusFailed = "failed"
+)
+
+// CheckFunc checks a dependency. It returns an error if the dependency is not available.
+type CheckFunc func(ctx context.Context) error
+
+// check is a named dependency check.
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Result type is the result of a check.
+type Result struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Report type is the breakdown of the readiness checks.
+// The Status is ok only if every check is passed.
+type Report struct {
+	Status string    `json:"status"`
+	Checks []*Result `json:"checks"`
+}
+
+// OK returns true if every check is passed.
+func (r *Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Checker type runs the registered checks with timeout.
+type Checker struct {
+	timeout time.Duration
+	checks  []*check
+}
+
+// NewChecker creates a new checker. The timeout is applied to every check.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{
+		timeout: timeout,
+		checks:  []*check{},
+	}
+}
+
+// Add registers a check with the given name. The checks are reported in the order of the registration.
+func (c *Checker) Add(name string, fn CheckFunc) {
+	c.checks = append(c.checks, &check{name: name, fn: fn})
+}
+
+// Run executes the checks concurrently and returns the report.
+// The check that does not return until the timeout is reported as failed.
+func (c *Checker) Run(ctx context.Context) *Report {
+	report := &Report{Status: StatusOK, Checks: make([]*Result, len(c.checks))}
+	var wg sync.WaitGroup
+	for i, chk := range c.checks {
+		wg.Add(1)
+		go func(i int, chk *check) {
+			defer wg.Done()
+			report.Checks[i] = c.run(ctx, chk)
+		}(i, chk)
+	}
+	wg.Wait()
+	for _, result := range report.Checks {
+		if result.Status != StatusOK {
+			report.Status = StatusFailed
+		}
+	}
+	return report
+}
+
+// run executes a check with the timeout of the checker.
+func (c *Checker) run(ctx context.Context, chk *check) *Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- chk.fn(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timeout after %s", c.timeout)
+	}
+	result := &Result{Name: chk.name, Status: StatusOK, DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// DirectoryWritable returns a check that creates and removes a temporary file in the directory.
+func DirectoryWritable(path string) CheckFunc {
+	return func(ctx context.Context) error {
+		file, err := os.CreateTemp(path, ".health-*")
+		if err != nil {
+			return err
+		}
+		name := file.Name()
+		if err := file.Close(); err != nil {
+			os.Remove(name)
+			return err
+		}
+		return os.Remove(name)
+	}
+}
+
+// MigrationVersion returns a check that compares the applied migration version with the latest one.
+// The current function returns the applied version and the dirty flag.
+func MigrationVersion(current func(ctx context.Context) (uint, bool, error), latest uint) CheckFunc {
+	return func(ctx context.Context) error {
+		version, dirty, err := current(ctx)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("migration %d is dirty", version)
+		}
+		if version != latest {
+			return fmt.Errorf("migration version is %d, expected %d", version, latest)
+		}
+		return nil
+	}
+}
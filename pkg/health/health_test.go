@@ -0,0 +1,88 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestCheckerRun tests the report of the passed, failed and timed out checks.
+func TestCheckerRun(t *testing.T) {
+	checker := NewChecker(50 * time.Millisecond)
+	checker.Add("passed", func(ctx context.Context) error { return nil })
+	checker.Add("failed", func(ctx context.Context) error { return errors.New("connection refused") })
+	checker.Add("slow", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+	report := checker.Run(context.Background())
+	if report.OK() || report.Status != StatusFailed {
+		t.Errorf("The report has to be failed. Got: %s", report.Status)
+	}
+	if len(report.Checks) != 3 {
+		t.Fatalf("Invalid number of checks. Got: %d", len(report.Checks))
+	}
+	expected := []struct {
+		name   string
+		status string
+		err    string
+	}{
+		{"passed", StatusOK, ""},
+		{"failed", StatusFailed, "connection refused"},
+		{"slow", StatusFailed, "timeout after 50ms"},
+	}
+	for i, tt := range expected {
+		result := report.Checks[i]
+		if result.Name != tt.name || result.Status != tt.status || result.Error != tt.err {
+			t.Errorf("Invalid result. Expected: %+v, got: %+v", tt, result)
+		}
+	}
+	if report := NewChecker(time.Second).Run(context.Background()); !report.OK() {
+		t.Errorf("The checker without checks has to be ok. Got: %s", report.Status)
+	}
+}
+
+// TestDirectoryWritable tests the writable and the missing directory.
+func TestDirectoryWritable(t *testing.T) {
+	dir := t.TempDir()
+	if err := DirectoryWritable(dir)(context.Background()); err != nil {
+		t.Errorf("The temporary directory has to be writable. Got: %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("The check file has to be removed. Got: %d files", len(entries))
+	}
+	if err := DirectoryWritable(filepath.Join(dir, "missing"))(context.Background()); err == nil {
+		t.Error("The missing directory must not be writable.")
+	}
+}
+
+// TestMigrationVersion tests the current, the outdated and the dirty migration version.
+func TestMigrationVersion(t *testing.T) {
+	testData := []struct {
+		version  uint
+		dirty    bool
+		err      error
+		expected string
+	}{
+		{26, false, nil, ""},
+		{25, false, nil, "migration version is 25, expected 26"},
+		{26, true, nil, "migration 26 is dirty"},
+		{0, false, errors.New("relation does not exist"), "relation does not exist"},
+	}
+	for _, tt := range testData {
+		current := func(ctx context.Context) (uint, bool, error) {
+			return tt.version, tt.dirty, tt.err
+		}
+		err := MigrationVersion(current, 26)(context.Background())
+		if tt.expected == "" && err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if tt.expected != "" && (err == nil || err.Error() != tt.expected) {
+			t.Errorf("Invalid error. Expected: %s, got: %v", tt.expected, err)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package httpsd
+
+// This package builds the prometheus http service discovery targets from the applications.
+// https://prometheus.io/docs/prometheus/latest/http_sd/
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+const (
+	// TargetTypeDomain is the target type label value of the application domains.
+	TargetTypeDomain = "domain"
+	// TargetTypeServer is the target type label value of the environment servers.
+	TargetTypeServer = "server"
+)
+
+// TargetGroup type is a target group of the http_sd_config format.
+type TargetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// applicationLabels returns the common labels of the application targets.
+func applicationLabels(application *model.Application) map[string]string {
+	return map[string]string{
+		"target_type":    TargetTypeDomain,
+		"application_id": strconv.FormatInt(application.ID, 10),
+		"client":         application.Client.Name,
+		"project":        application.Project.Name,
+		"environment":    application.Environment.Name,
+		"runtime":        application.Runtime.Name,
+		"pool":           application.Pool.Name,
+		"framework":      application.Framework.Name,
+	}
+}
+
+// FromApplications returns the target groups of the application domains.
+// The domains of an application are grouped by the domain role and the scheme,
+// so that the probes could be configured with relabeling. The scheme is https if the domain has ssl.
+// The role is looked up by the domain id, the domains without role are aliases.
+func FromApplications(applications *model.Applications) []*TargetGroup {
+	groups := []*TargetGroup{}
+	for _, application := range *applications {
+		applicationGroups := map[string]*TargetGroup{}
+		keys := []string{}
+		for _, domain := range application.Domains {
+			role := application.DomainRole(domain.ID).Role
+			scheme := "http"
+			if domain.HasSSL {
+				scheme = "https"
+			}
+			key := role + ":" + scheme
+			group, ok := applicationGroups[key]
+			if !ok {
+				group = &TargetGroup{Targets: []string{}, Labels: applicationLabels(application)}
+				group.Labels["domain_role"] = role
+				group.Labels["scheme"] = scheme
+				applicationGroups[key] = group
+				keys = append(keys, key)
+			}
+			group.Targets = append(group.Targets, domain.Name)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			groups = append(groups, applicationGroups[key])
+		}
+	}
+	return groups
+}
+
+// FromServers returns the target groups of the servers of the environments.
+// The environments without servers are skipped.
+func FromServers(environments []*model.Environment) []*TargetGroup {
+	groups := []*TargetGroup{}
+	for _, environment := range environments {
+		targets := []string{}
+		for _, server := range environment.Servers {
+			if server.RemoteAddr != "" {
+				targets = append(targets, server.RemoteAddr)
+			}
+		}
+		if len(targets) == 0 {
+			continue
+		}
+		groups = append(groups, &TargetGroup{
+			Targets: targets,
+			Labels: map[string]string{
+				"target_type": TargetTypeServer,
+				"environment": environment.Name,
+			},
+		})
+	}
+	return groups
+}
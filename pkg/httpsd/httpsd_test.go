@@ -0,0 +1,66 @@
+package httpsd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// TestFromApplications tests the grouping of the application domains.
+func TestFromApplications(t *testing.T) {
+	applications := &model.Applications{
+		{
+			ID:          1,
+			Client:      &model.Client{Name: "client"},
+			Project:     &model.Project{Name: "project"},
+			Environment: &model.Environment{Name: "prod"},
+			Runtime:     &model.Runtime{Name: "php"},
+			Pool:        &model.Pool{Name: "pool"},
+			Framework:   &model.Framework{Name: "laravel"},
+			Domains: []*model.Domain{
+				{ID: 1, Name: "example.com", HasSSL: true},
+				{ID: 2, Name: "www.example.com", HasSSL: true},
+				{ID: 3, Name: "old.example.com", HasSSL: false},
+			},
+			// the roles are not in the order of the domains, the domain without role is an alias.
+			DomainRoles: []*model.ApplicationDomain{
+				{DomainID: 2, Role: model.ApplicationDomainRoleAlias},
+				{DomainID: 1, Role: model.ApplicationDomainRolePrimary},
+			},
+		},
+	}
+	groups := FromApplications(applications)
+	if len(groups) != 3 {
+		t.Fatalf("Invalid number of groups. Got: %d", len(groups))
+	}
+	// the groups are ordered by the role and the scheme.
+	if groups[0].Labels["domain_role"] != "alias" || groups[0].Labels["scheme"] != "http" || groups[0].Targets[0] != "old.example.com" {
+		t.Errorf("Invalid first group: %+v", groups[0])
+	}
+	if groups[2].Labels["domain_role"] != "primary" || groups[2].Targets[0] != "example.com" {
+		t.Errorf("Invalid last group: %+v", groups[2])
+	}
+	for _, label := range []string{"client", "project", "environment", "runtime", "pool", "framework"} {
+		if groups[2].Labels[label] == "" {
+			t.Errorf("Missing label: %s", label)
+		}
+	}
+}
+
+// TestFromServers tests the server target groups and the json format.
+func TestFromServers(t *testing.T) {
+	environments := []*model.Environment{
+		{Name: "prod", Servers: []*model.Server{{RemoteAddr: "10.0.0.1"}, {RemoteAddr: ""}}},
+		{Name: "empty"},
+	}
+	groups := FromServers(environments)
+	output, err := json.Marshal(groups)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	expected := `[{"targets":["10.0.0.1"],"labels":{"environment":"prod","target_type":"server"}}]`
+	if string(output) != expected {
+		t.Errorf("Invalid output. Expected: %s, Got: %s", expected, string(output))
+	}
+}
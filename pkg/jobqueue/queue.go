@@ -0,0 +1,280 @@
+package jobqueue
+
+// This package contains the database backed background job queue. The jobs are stored in the jobs table,
+// the workers claim them with SELECT ... FOR UPDATE SKIP LOCKED, so that several workers and instances
+// could process the queue without processing a job twice. The running jobs are leased by their worker,
+// the jobs of the crashed workers are claimed again when their lease expires.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+const (
+	// progressFlushInterval is the minimum interval of the progress updates in the database.
+	progressFlushInterval = 500 * time.Millisecond
+	// logTimeFormat is the time format of the log lines.
+	logTimeFormat = "15:04:05"
+	// jobLease is the time until a running job is owned by its worker. It is renewed in every third of the lease.
+	jobLease = time.Minute
+)
+
+var (
+	// ErrCancelled is returned by the progress updates if the job is cancelled. The handler has to return it.
+	ErrCancelled = errors.New("the job is cancelled")
+	// ErrUnknownType is returned by the Enqueue if there is no handler for the job type.
+	ErrUnknownType = errors.New("unknown job type")
+)
+
+// Handler processes a job. The payload of the job could be decoded with the DecodePayload,
+// the progress and the log messages are reported with the progress.
+// It returns error if the job failed. The context is cancelled on shutdown.
+type Handler func(ctx context.Context, job *model.Job, progress *Progress) error
+
+// Queue type stores the jobs and runs them with the registered handlers.
+type Queue struct {
+	jobs     model.JobRepository
+	handlers map[string]Handler
+	logger   *slog.Logger
+	// wake notifies a local worker about a new job, so that it does not wait for the next poll.
+	wake chan struct{}
+}
+
+// NewQueue creates a new job queue.
+func NewQueue(jobs model.JobRepository, logger *slog.Logger) *Queue {
+	return &Queue{
+		jobs:     jobs,
+		handlers: make(map[string]Handler),
+		logger:   logger,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Register sets the handler of the job type.
+func (q *Queue) Register(jobType string, handler Handler) {
+	q.handlers[jobType] = handler
+}
+
+// Enqueue stores a new job with the json encoded payload.
+// The createdBy is the id of the user who started the job, 0 if it is started by the system.
+func (q *Queue) Enqueue(jobType string, payload interface{}, createdBy int64) (*model.Job, error) {
+	if _, ok := q.handlers[jobType]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, jobType)
+	}
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the payload: %w", err)
+	}
+	job, err := q.jobs.CreateJob(jobType, string(encoded), createdBy)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return job, nil
+}
+
+// Work processes the queued jobs until the context is cancelled. The queue is checked with the poll interval,
+// and when a job is enqueued by this instance. The running job gets the cancelled context on shutdown.
+func (q *Queue) Work(ctx context.Context, pollInterval time.Duration) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		for ctx.Err() == nil && q.RunNext(ctx) {
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+// RunNext claims and processes the oldest queued job. It returns false if there was no job to process.
+func (q *Queue) RunNext(ctx context.Context) bool {
+	job, err := q.jobs.ClaimJob(jobLease)
+	if errors.Is(err, model.ErrNotFound) {
+		return false
+	}
+	if err != nil {
+		q.logger.Error("jobqueue: failed to claim a job", "error", err)
+		return false
+	}
+	q.process(ctx, job)
+	return true
+}
+
+// process runs the handler of the job and stores the result. The lease of the job is renewed until the handler returns.
+// The interrupted jobs are failed, they could be retried from the jobs page.
+// As the handlers are not idempotent, the retried and the reclaimed jobs resume after the processed items of the progress.
+func (q *Queue) process(ctx context.Context, job *model.Job) {
+	logger := q.logger.With("job_id", job.ID, "job_type", job.Type)
+	logger.Info("jobqueue: job started", "attempt", job.Attempts)
+	progress := newProgress(q.jobs, job)
+	if job.Attempts > 1 {
+		progress.Logf("attempt %d, resuming after %d of %d items", job.Attempts, job.Progress, job.Total)
+	}
+	stopHeartbeat := q.heartbeat(job.ID, logger)
+	err := q.run(ctx, job, progress)
+	stopHeartbeat()
+	status := model.JobStatusSucceeded
+	errorMessage := ""
+	switch {
+	case errors.Is(err, ErrCancelled):
+		logger.Info("jobqueue: job cancelled")
+		return
+	case err != nil && ctx.Err() != nil:
+		status = model.JobStatusFailed
+		errorMessage = "interrupted by the shutdown: " + err.Error()
+	case err != nil:
+		status = model.JobStatusFailed
+		errorMessage = err.Error()
+	}
+	if _, err := q.jobs.FinishJob(job.ID, progress.done, status, errorMessage, progress.pendingLog()); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			logger.Info("jobqueue: job cancelled")
+			return
+		}
+		logger.Error("jobqueue: failed to finish the job", "error", err)
+		return
+	}
+	logger.Info("jobqueue: job finished", "status", status, "error", errorMessage)
+}
+
+// heartbeat renews the lease of the running job until the returned stop function is called.
+func (q *Queue) heartbeat(jobID int64, logger *slog.Logger) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(jobLease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				// the job that is not running anymore, eg. the cancelled one is stopped on its next progress update.
+				if _, err := q.jobs.RenewJobLease(jobID, jobLease); err != nil && !errors.Is(err, model.ErrNotFound) {
+					logger.Error("jobqueue: failed to renew the lease", "error", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// run calls the handler of the job. The panics of the handler are returned as errors.
+func (q *Queue) run(ctx context.Context, job *model.Job, progress *Progress) (err error) {
+	handler, ok := q.handlers[job.Type]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownType, job.Type)
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("the job panicked: %v", recovered)
+		}
+	}()
+	return handler(ctx, job, progress)
+}
+
+// DecodePayload decodes the json payload of the job to the target.
+func DecodePayload(job *model.Job, target interface{}) error {
+	if err := json.Unmarshal([]byte(job.Payload), target); err != nil {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+	return nil
+}
+
+// Progress type collects the progress and the log lines of a running job.
+// The changes are stored at most once in every progressFlushInterval, and with the result of the job.
+type Progress struct {
+	jobs      model.JobRepository
+	jobID     int64
+	done      int
+	total     int
+	lines     []string
+	flushedAt time.Time
+}
+
+// newProgress creates the progress of the job.
+func newProgress(jobs model.JobRepository, job *model.Job) *Progress {
+	return &Progress{
+		jobs:      jobs,
+		jobID:     job.ID,
+		done:      job.Progress,
+		total:     job.Total,
+		lines:     []string{},
+		flushedAt: time.Now(),
+	}
+}
+
+// SetTotal sets the number of the items of the job, and stores it.
+// It returns ErrCancelled if the job is cancelled.
+func (p *Progress) SetTotal(total int) error {
+	p.total = total
+	return p.flush()
+}
+
+// Done returns the number of the processed items. It is not 0 if the job is retried or reclaimed,
+// the handlers that are not idempotent skip the processed items.
+func (p *Progress) Done() int {
+	return p.done
+}
+
+// Save stores the progress immediately. The handlers that are not idempotent call it after every item,
+// so that the retried or reclaimed job does not process the stored items again.
+// It returns ErrCancelled if the job is cancelled.
+func (p *Progress) Save() error {
+	return p.flush()
+}
+
+// Logf adds a line to the log of the job. It is stored with the next progress update.
+func (p *Progress) Logf(format string, args ...interface{}) {
+	p.lines = append(p.lines, time.Now().Format(logTimeFormat)+" "+fmt.Sprintf(format, args...))
+}
+
+// Advance increments the number of the processed items.
+// It returns ErrCancelled if the job is cancelled, the handler has to stop then.
+func (p *Progress) Advance() error {
+	p.done++
+	if time.Since(p.flushedAt) < progressFlushInterval {
+		return nil
+	}
+	return p.flush()
+}
+
+// flush stores the progress and the pending log lines.
+func (p *Progress) flush() error {
+	_, err := p.jobs.UpdateJobProgress(p.jobID, p.done, p.total, p.pendingLog())
+	if errors.Is(err, model.ErrNotFound) {
+		return ErrCancelled
+	}
+	if err != nil {
+		return err
+	}
+	p.lines = p.lines[:0]
+	p.flushedAt = time.Now()
+	return nil
+}
+
+// pendingLog returns the log lines that are not stored yet.
+func (p *Progress) pendingLog() string {
+	if len(p.lines) == 0 {
+		return ""
+	}
+	return strings.Join(p.lines, "\n") + "\n"
+}
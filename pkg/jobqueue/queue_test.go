@@ -0,0 +1,302 @@
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// memoryJobs is an in memory job repository with the status rules of the database repository.
+type memoryJobs struct {
+	mu   sync.Mutex
+	jobs []*model.Job
+}
+
+func (m *memoryJobs) CreateJob(jobType, payload string, createdBy int64) (*model.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job := &model.Job{ID: int64(len(m.jobs) + 1), Type: jobType, Payload: payload, Status: model.JobStatusQueued, CreatedBy: createdBy}
+	m.jobs = append(m.jobs, job)
+	copied := *job
+	return &copied, nil
+}
+
+func (m *memoryJobs) GetJobByID(id int64) (*model.Job, error) {
+	return m.update(id, nil, func(job *model.Job) {})
+}
+
+func (m *memoryJobs) GetJobs(filter *model.JobFilter) (*model.Jobs, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *memoryJobs) ClaimJob(lease time.Duration) (*model.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now().Format(model.JobTimeFormat)
+	for _, job := range m.jobs {
+		if job.Status == model.JobStatusQueued || (job.Status == model.JobStatusRunning && job.LeaseExpiresAt < now) {
+			job.LeaseExpiresAt = time.Now().Add(lease).Format(model.JobTimeFormat)
+			job.Status = model.JobStatusRunning
+			job.Attempts++
+			copied := *job
+			return &copied, nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+func (m *memoryJobs) RenewJobLease(id int64, lease time.Duration) (*model.Job, error) {
+	return m.update(id, []string{model.JobStatusRunning}, func(job *model.Job) {
+		job.LeaseExpiresAt = time.Now().Add(lease).Format(model.JobTimeFormat)
+	})
+}
+
+func (m *memoryJobs) UpdateJobProgress(id int64, progress, total int, logLines string) (*model.Job, error) {
+	return m.update(id, []string{model.JobStatusRunning}, func(job *model.Job) {
+		job.Progress = progress
+		job.Total = total
+		job.Log += logLines
+	})
+}
+
+func (m *memoryJobs) FinishJob(id int64, progress int, status, errorMessage, logLines string) (*model.Job, error) {
+	return m.update(id, []string{model.JobStatusRunning}, func(job *model.Job) {
+		job.Progress = progress
+		job.Status = status
+		job.Error = errorMessage
+		job.Log += logLines
+	})
+}
+
+func (m *memoryJobs) RetryJob(id int64) (*model.Job, error) {
+	return m.update(id, []string{model.JobStatusFailed, model.JobStatusCancelled}, func(job *model.Job) {
+		job.Status = model.JobStatusQueued
+	})
+}
+
+func (m *memoryJobs) CancelJob(id int64) (*model.Job, error) {
+	return m.update(id, []string{model.JobStatusQueued, model.JobStatusRunning}, func(job *model.Job) {
+		job.Status = model.JobStatusCancelled
+	})
+}
+
+// update applies the change on the job if it is in one of the statuses. The nil statuses means any status.
+func (m *memoryJobs) update(id int64, statuses []string, change func(job *model.Job)) (*model.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, job := range m.jobs {
+		if job.ID != id {
+			continue
+		}
+		allowed := statuses == nil
+		for _, status := range statuses {
+			allowed = allowed || job.Status == status
+		}
+		if !allowed {
+			return nil, model.ErrNotFound
+		}
+		change(job)
+		copied := *job
+		return &copied, nil
+	}
+	return nil, model.ErrNotFound
+}
+
+// newTestQueue returns a queue with an in memory repository.
+func newTestQueue() (*Queue, *memoryJobs) {
+	jobs := &memoryJobs{}
+	return NewQueue(jobs, slog.New(slog.NewTextHandler(io.Discard, nil))), jobs
+}
+
+// TestRunNextSucceeded tests that the payload is passed to the handler, and the progress and the log are stored.
+func TestRunNextSucceeded(t *testing.T) {
+	queue, jobs := newTestQueue()
+	queue.Register("count", func(ctx context.Context, job *model.Job, progress *Progress) error {
+		var payload struct{ Items []string }
+		if err := DecodePayload(job, &payload); err != nil {
+			return err
+		}
+		if err := progress.SetTotal(len(payload.Items)); err != nil {
+			return err
+		}
+		for _, item := range payload.Items {
+			progress.Logf("processed %s", item)
+			if err := progress.Advance(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	job, err := queue.Enqueue("count", map[string][]string{"Items": {"a", "b"}}, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !queue.RunNext(context.Background()) {
+		t.Fatal("Expected a processed job.")
+	}
+	if queue.RunNext(context.Background()) {
+		t.Error("Expected empty queue.")
+	}
+	job, _ = jobs.GetJobByID(job.ID)
+	if job.Status != model.JobStatusSucceeded || job.Progress != 2 || job.Total != 2 || job.Percent() != 100 {
+		t.Errorf("Invalid finished job: %+v", job)
+	}
+	if !strings.Contains(job.Log, "processed a\n") || !strings.Contains(job.Log, "processed b\n") {
+		t.Errorf("Invalid log: %s", job.Log)
+	}
+}
+
+// TestRunNextFailed tests that the errors and the panics of the handler fail the job.
+func TestRunNextFailed(t *testing.T) {
+	queue, jobs := newTestQueue()
+	queue.Register("error", func(ctx context.Context, job *model.Job, progress *Progress) error {
+		return errors.New("broken")
+	})
+	queue.Register("panic", func(ctx context.Context, job *model.Job, progress *Progress) error {
+		panic("boom")
+	})
+	failed, _ := queue.Enqueue("error", nil, 0)
+	panicked, _ := queue.Enqueue("panic", nil, 0)
+	for queue.RunNext(context.Background()) {
+	}
+	testData := []struct {
+		id            int64
+		expectedError string
+	}{
+		{failed.ID, "broken"},
+		{panicked.ID, "the job panicked: boom"},
+	}
+	for _, tt := range testData {
+		job, _ := jobs.GetJobByID(tt.id)
+		if job.Status != model.JobStatusFailed || job.Error != tt.expectedError {
+			t.Errorf("Expected failed job with %s, got %+v", tt.expectedError, job)
+		}
+		if !job.CanRetry() || job.CanCancel() {
+			t.Errorf("Expected retryable job, got %+v", job)
+		}
+	}
+}
+
+// TestRunNextCancelled tests that the cancelled job is stopped on the next progress update.
+func TestRunNextCancelled(t *testing.T) {
+	queue, jobs := newTestQueue()
+	var handlerErr error
+	queue.Register("cancel", func(ctx context.Context, job *model.Job, progress *Progress) error {
+		if _, err := jobs.CancelJob(job.ID); err != nil {
+			return err
+		}
+		handlerErr = progress.SetTotal(1)
+		return handlerErr
+	})
+	job, _ := queue.Enqueue("cancel", nil, 0)
+	queue.RunNext(context.Background())
+	if !errors.Is(handlerErr, ErrCancelled) {
+		t.Errorf("Expected cancelled error, got %v", handlerErr)
+	}
+	job, _ = jobs.GetJobByID(job.ID)
+	if job.Status != model.JobStatusCancelled {
+		t.Errorf("Expected cancelled job, got %s", job.Status)
+	}
+}
+
+// TestRunNextResumed tests that the retried and the reclaimed jobs resume after the stored progress.
+func TestRunNextResumed(t *testing.T) {
+	queue, jobs := newTestQueue()
+	var processed []string
+	failAt := "b"
+	queue.Register("resume", func(ctx context.Context, job *model.Job, progress *Progress) error {
+		items := []string{"a", "b", "c"}
+		if err := progress.SetTotal(len(items)); err != nil {
+			return err
+		}
+		for _, item := range items[progress.Done():] {
+			if item == failAt {
+				return errors.New("broken")
+			}
+			processed = append(processed, item)
+			if err := progress.Advance(); err != nil {
+				return err
+			}
+			if err := progress.Save(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	job, _ := queue.Enqueue("resume", nil, 0)
+	queue.RunNext(context.Background())
+	failAt = ""
+	if _, err := jobs.RetryJob(job.ID); err != nil {
+		t.Fatalf("Unexpected retry error: %v", err)
+	}
+	queue.RunNext(context.Background())
+	job, _ = jobs.GetJobByID(job.ID)
+	if job.Status != model.JobStatusSucceeded || job.Progress != 3 || strings.Join(processed, "") != "abc" {
+		t.Errorf("Expected resumed job, got %+v, processed %v", job, processed)
+	}
+	if !strings.Contains(job.Log, "attempt 2, resuming after 1 of 3 items") {
+		t.Errorf("Invalid log: %s", job.Log)
+	}
+	// the running job of a crashed worker is claimed again after its lease expired.
+	crashed, _ := queue.Enqueue("resume", nil, 0)
+	jobs.update(crashed.ID, nil, func(job *model.Job) {
+		job.Status = model.JobStatusRunning
+		job.LeaseExpiresAt = time.Now().Add(-time.Second).Format(model.JobTimeFormat)
+	})
+	if !queue.RunNext(context.Background()) {
+		t.Fatal("Expected the reclaimed job.")
+	}
+	crashed, _ = jobs.GetJobByID(crashed.ID)
+	if crashed.Status != model.JobStatusSucceeded {
+		t.Errorf("Expected succeeded reclaimed job, got %+v", crashed)
+	}
+}
+
+// TestEnqueueUnknownType tests that the jobs without handler are not stored.
+func TestEnqueueUnknownType(t *testing.T) {
+	queue, jobs := newTestQueue()
+	if _, err := queue.Enqueue("missing", nil, 0); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("Expected unknown type error, got %v", err)
+	}
+	if len(jobs.jobs) != 0 {
+		t.Errorf("Expected no stored job, got %d", len(jobs.jobs))
+	}
+}
+
+// TestWork tests that the enqueued job is processed without waiting for the poll, and the worker stops on cancel.
+func TestWork(t *testing.T) {
+	queue, _ := newTestQueue()
+	processed := make(chan int64, 1)
+	queue.Register("notify", func(ctx context.Context, job *model.Job, progress *Progress) error {
+		processed <- job.ID
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		queue.Work(ctx, time.Hour)
+		close(stopped)
+	}()
+	job, _ := queue.Enqueue("notify", nil, 0)
+	select {
+	case id := <-processed:
+		if id != job.ID {
+			t.Errorf("Expected job %d, got %d", job.ID, id)
+		}
+	case <-time.After(time.Second):
+		t.Error("The job is not processed.")
+	}
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Error("The worker is not stopped.")
+	}
+}
@@ -0,0 +1,166 @@
+package logging
+
+// This package contains the structured logger of the application.
+// The request scoped attributes (request id, route, user id) are stored in the request context,
+// and they are added to every log line that is written with the context.
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+const (
+	// FormatText is the key=value log format.
+	FormatText = "text"
+	// FormatJSON is the json log format.
+	FormatJSON = "json"
+
+	// RequestIDHeader is the header of the request id.
+	RequestIDHeader = "X-Request-ID"
+	// maxRequestIDLength is the maximum length of the accepted incoming request id.
+	maxRequestIDLength = 128
+)
+
+// ParseLevel returns the log level of the name. The unknown names are mapped to info.
+func ParseLevel(name string) slog.Level {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+// New creates a new logger that writes to the output in the given format with the given minimum level.
+// The unknown formats are mapped to text.
+func New(output io.Writer, format, level string) *slog.Logger {
+	options := &slog.HandlerOptions{Level: ParseLevel(level)}
+	var handler slog.Handler
+	if strings.ToLower(format) == FormatJSON {
+		handler = slog.NewJSONHandler(output, options)
+	} else {
+		handler = slog.NewTextHandler(output, options)
+	}
+	return slog.New(&contextHandler{handler})
+}
+
+// RequestInfo type holds the request scoped log attributes.
+// The user id is set later than the request id, after the authentication, so that it is guarded.
+type RequestInfo struct {
+	mu        sync.Mutex
+	requestID string
+	route     string
+	userID    int64
+}
+
+// requestInfoKey is the context key of the request info.
+type requestInfoKey struct{}
+
+// NewContext returns a new context with the request info.
+func NewContext(ctx context.Context, requestID, route string) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, &RequestInfo{requestID: requestID, route: route})
+}
+
+// fromContext returns the request info of the context or nil.
+func fromContext(ctx context.Context) *RequestInfo {
+	if ctx == nil {
+		return nil
+	}
+	info, _ := ctx.Value(requestInfoKey{}).(*RequestInfo)
+	return info
+}
+
+// RequestID returns the request id of the context. It is empty outside of the requests.
+func RequestID(ctx context.Context) string {
+	info := fromContext(ctx)
+	if info == nil {
+		return ""
+	}
+	info.mu.Lock()
+	defer info.mu.Unlock()
+	return info.requestID
+}
+
+// SetUserID sets the id of the authenticated user in the request info of the context.
+func SetUserID(ctx context.Context, userID int64) {
+	info := fromContext(ctx)
+	if info == nil {
+		return
+	}
+	info.mu.Lock()
+	defer info.mu.Unlock()
+	info.userID = userID
+}
+
+// attrs returns the log attributes of the request info.
+func (i *RequestInfo) attrs() []slog.Attr {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	attrs := []slog.Attr{slog.String("request_id", i.requestID)}
+	if i.route != "" {
+		attrs = append(attrs, slog.String("route", i.route))
+	}
+	if i.userID != 0 {
+		attrs = append(attrs, slog.Int64("user_id", i.userID))
+	}
+	return attrs
+}
+
+// RequestIDFromHeader returns the incoming request id if it is acceptable, otherwise a new generated one.
+// The accepted ids are limited in length and in characters, so that they are safe in the log lines.
+func RequestIDFromHeader(header string) string {
+	if header != "" && len(header) <= maxRequestIDLength && isSafeRequestID(header) {
+		return header
+	}
+	return NewRequestID()
+}
+
+// isSafeRequestID checks that the id contains only letters, digits and the -_.: characters.
+func isSafeRequestID(id string) bool {
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("-_.:", c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewRequestID returns a new random request id.
+func NewRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// contextHandler adds the request info attributes of the context to the records.
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle adds the request attributes to the record, and calls the wrapped handler.
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if info := fromContext(ctx); info != nil {
+		record.AddAttrs(info.attrs()...)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs returns a new handler with the attributes.
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a new handler with the group.
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{h.Handler.WithGroup(name)}
+}
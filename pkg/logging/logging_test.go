@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestParseLevel tests the level names.
+func TestParseLevel(t *testing.T) {
+	testData := map[string]slog.Level{
+		"debug":   slog.LevelDebug,
+		"INFO":    slog.LevelInfo,
+		"warn":    slog.LevelWarn,
+		"error":   slog.LevelError,
+		"unknown": slog.LevelInfo,
+	}
+	for name, expected := range testData {
+		if ParseLevel(name) != expected {
+			t.Errorf("Invalid level of %s. Expected: %v, got: %v", name, expected, ParseLevel(name))
+		}
+	}
+}
+
+// TestNewJSONWithRequestInfo tests that the request attributes are added to the log lines.
+func TestNewJSONWithRequestInfo(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, FormatJSON, "info")
+	ctx := NewContext(context.Background(), "req-1", "/admin/user/view/{userId}")
+	SetUserID(ctx, 42)
+	logger.DebugContext(ctx, "hidden")
+	logger.InfoContext(ctx, "visible", "key", "value")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("The debug line has to be skipped. Got: %v", lines)
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("The line is not json: %v", err)
+	}
+	if record["msg"] != "visible" || record["request_id"] != "req-1" || record["route"] != "/admin/user/view/{userId}" || record["user_id"] != float64(42) || record["key"] != "value" {
+		t.Errorf("Invalid record: %v", record)
+	}
+	if RequestID(ctx) != "req-1" || RequestID(context.Background()) != "" {
+		t.Errorf("Invalid request id from the context.")
+	}
+}
+
+// TestNewText tests the text format without request info.
+func TestNewText(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, FormatText, "debug")
+	logger.Debug("message", "key", "value")
+	if !strings.Contains(buf.String(), "level=DEBUG msg=message key=value") {
+		t.Errorf("Invalid text output: %s", buf.String())
+	}
+}
+
+// TestRequestIDFromHeader tests the validation of the incoming request ids.
+func TestRequestIDFromHeader(t *testing.T) {
+	if RequestIDFromHeader("abc-123") != "abc-123" {
+		t.Errorf("The valid request id has to be kept.")
+	}
+	for _, header := range []string{"", "bad id", "bad\nid", strings.Repeat("a", 129)} {
+		id := RequestIDFromHeader(header)
+		if id == header || len(id) != 32 {
+			t.Errorf("The invalid request id %q has to be replaced. Got: %s", header, id)
+		}
+	}
+}
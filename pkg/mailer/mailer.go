@@ -0,0 +1,131 @@
+package mailer
+
+// This package contains the email sending. The messages are sent over smtp
+// as multipart emails with a plain text and a html part.
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNoRecipient is returned if the message has no recipient.
+	ErrNoRecipient = errors.New("the message has no recipient")
+)
+
+// Message type is an email message.
+// The HTML part is optional.
+type Message struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer interface is implemented by the email senders.
+type Mailer interface {
+	Send(message *Message) error
+}
+
+// SMTPMailer type sends the messages over smtp. It implements the Mailer interface.
+type SMTPMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer creates a new smtp mailer.
+// If the username is empty, the messages are sent without authentication.
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPMailer{
+		addr: net.JoinHostPort(host, port),
+		from: from,
+		auth: auth,
+	}
+}
+
+// Send sends the message.
+func (m *SMTPMailer) Send(message *Message) error {
+	if len(message.To) == 0 {
+		return ErrNoRecipient
+	}
+	body, err := m.build(message, time.Now())
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(m.addr, m.auth, m.from, message.To, body)
+}
+
+// build returns the message in MIME format.
+func (m *SMTPMailer) build(message *Message, now time.Time) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := multipart.NewWriter(&buffer)
+	header := []string{
+		"From: " + m.from,
+		"To: " + strings.Join(message.To, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", message.Subject),
+		"Date: " + now.Format(time.RFC1123Z),
+		"Message-ID: " + messageID(m.from),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + writer.Boundary(),
+	}
+	buffer.WriteString(strings.Join(header, "\r\n") + "\r\n\r\n")
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.HTML},
+	}
+	for _, part := range parts {
+		if part.content == "" {
+			continue
+		}
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoder := quotedprintable.NewWriter(partWriter)
+		if _, err := encoder.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// messageID returns a unique message id in the domain of the sender address.
+func messageID(from string) string {
+	domain := "localhost"
+	if index := strings.LastIndex(from, "@"); index >= 0 {
+		domain = strings.Trim(from[index+1:], "> ")
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Sprintf("<%d@%s>", time.Now().UnixNano(), domain)
+	}
+	return "<" + hex.EncodeToString(id) + "@" + domain + ">"
+}
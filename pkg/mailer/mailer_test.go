@@ -0,0 +1,146 @@
+package mailer
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/akosgarai/projectregister/web"
+)
+
+// smtpStandIn is a minimal smtp server that accepts one message.
+type smtpStandIn struct {
+	listener   net.Listener
+	from       string
+	recipients []string
+	data       string
+	done       chan struct{}
+}
+
+// newSMTPStandIn starts the smtp stand-in on a random local port.
+func newSMTPStandIn(t *testing.T) *smtpStandIn {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := &smtpStandIn{listener: listener, done: make(chan struct{})}
+	go server.serve()
+	return server
+}
+
+// serve handles one smtp session.
+func (s *smtpStandIn) serve() {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 localhost ESMTP stand-in")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			s.from = strings.Trim(strings.TrimSpace(line)[10:], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			s.recipients = append(s.recipients, strings.Trim(strings.TrimSpace(line)[8:], "<>"))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			s.data = data.String()
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// TestSMTPMailerSend tests the message sending with the smtp stand-in.
+func TestSMTPMailerSend(t *testing.T) {
+	server := newSMTPStandIn(t)
+	defer server.listener.Close()
+	host, port, _ := net.SplitHostPort(server.listener.Addr().String())
+	mailer := NewSMTPMailer(host, port, "", "", "projectregister@example.com")
+	err := mailer.Send(&Message{
+		To:      []string{"user@example.com"},
+		Subject: "Certificate expires",
+		Text:    "plain body",
+		HTML:    "<p>html body</p>",
+	})
+	if err != nil {
+		t.Fatalf("Failed to send the message: %v", err)
+	}
+	<-server.done
+	if server.from != "projectregister@example.com" {
+		t.Errorf("Invalid sender. Got: %s", server.from)
+	}
+	if len(server.recipients) != 1 || server.recipients[0] != "user@example.com" {
+		t.Errorf("Invalid recipients. Got: %v", server.recipients)
+	}
+	for _, expected := range []string{"Subject: Certificate expires", "multipart/alternative", "text/plain", "plain body", "text/html", "<p>html body</p>"} {
+		if !strings.Contains(server.data, expected) {
+			t.Errorf("The message does not contain %q. Got: %s", expected, server.data)
+		}
+	}
+}
+
+// TestSMTPMailerSendWithoutRecipient tests that the message without recipient is rejected.
+func TestSMTPMailerSendWithoutRecipient(t *testing.T) {
+	mailer := NewSMTPMailer("127.0.0.1", "25", "", "", "projectregister@example.com")
+	if err := mailer.Send(&Message{Subject: "test"}); err != ErrNoRecipient {
+		t.Errorf("Expected ErrNoRecipient, got %v", err)
+	}
+}
+
+// TestTemplatesRender tests the rendering of the email templates.
+func TestTemplatesRender(t *testing.T) {
+	templates := NewTemplates(web.Templates())
+	templates.SetBaseTemplate("email/base.html.tmpl")
+	templates.AddTemplate("notification", "email/notification.txt.tmpl", "email/notification.html.tmpl")
+	data := map[string]interface{}{
+		"Title":       "Daily digest",
+		"Intro":       "The following alerts are active.",
+		"SettingsURL": "http://localhost/admin/notification/settings",
+		"User":        map[string]string{"Name": "Tester"},
+		"Alerts": []map[string]string{
+			{"Subject": "Certificate <example.com> expires", "Detail": "in 3 days", "Link": "http://localhost/admin/certificate/view/1"},
+		},
+	}
+	text, html, err := templates.Render("notification", data)
+	if err != nil {
+		t.Fatalf("Failed to render the templates: %v", err)
+	}
+	if !strings.Contains(text, "Certificate <example.com> expires: in 3 days") {
+		t.Errorf("Invalid text part: %s", text)
+	}
+	if !strings.Contains(html, "Certificate &lt;example.com&gt; expires") || !strings.Contains(html, "<h2>Daily digest</h2>") {
+		t.Errorf("Invalid html part: %s", html)
+	}
+	if _, _, err := templates.Render("missing", data); err == nil {
+		t.Errorf("Expected error for the unknown template")
+	}
+}
@@ -0,0 +1,68 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	texttemplate "text/template"
+)
+
+const (
+	// textTemplateName is the name of the entry template of the text part.
+	textTemplateName = "text"
+	// htmlTemplateName is the name of the entry template of the html part.
+	htmlTemplateName = "html"
+)
+
+// Templates is a struct that holds the email templates.
+// The template files are loaded from the file system, the paths are relative to its root.
+// Every email template has a text and a html version. The text file has to define the "text" template.
+// The html part is rendered from the "html" template, it is defined by the base template as the layout,
+// that includes the "content" template of the html file.
+type Templates struct {
+	fileSystem   fs.FS
+	baseTemplate string
+	text         map[string]*texttemplate.Template
+	html         map[string]*htmltemplate.Template
+}
+
+// NewTemplates creates a new Templates struct with the template files of the file system.
+func NewTemplates(fileSystem fs.FS) *Templates {
+	return &Templates{
+		fileSystem:   fileSystem,
+		baseTemplate: "",
+		text:         make(map[string]*texttemplate.Template),
+		html:         make(map[string]*htmltemplate.Template),
+	}
+}
+
+// SetBaseTemplate sets the base template of the html part.
+func (t *Templates) SetBaseTemplate(baseTemplate string) {
+	t.baseTemplate = baseTemplate
+}
+
+// AddTemplate adds the text and the html templates of the email.
+func (t *Templates) AddTemplate(name, textFile, htmlFile string) {
+	t.text[name] = texttemplate.Must(texttemplate.New(name).ParseFS(t.fileSystem, textFile))
+	htmlFiles := []string{htmlFile}
+	if t.baseTemplate != "" {
+		htmlFiles = append(htmlFiles, t.baseTemplate)
+	}
+	t.html[name] = htmltemplate.Must(htmltemplate.New(name).ParseFS(t.fileSystem, htmlFiles...))
+}
+
+// Render renders the text and the html parts of the email.
+func (t *Templates) Render(name string, data interface{}) (string, string, error) {
+	if _, ok := t.text[name]; !ok {
+		return "", "", fmt.Errorf("unknown email template: %s", name)
+	}
+	var text, html bytes.Buffer
+	if err := t.text[name].ExecuteTemplate(&text, textTemplateName, data); err != nil {
+		return "", "", err
+	}
+	if err := t.html[name].ExecuteTemplate(&html, htmlTemplateName, data); err != nil {
+		return "", "", err
+	}
+	return text.String(), html.String(), nil
+}
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"database/sql"
+	"sort"
+	"strconv"
+
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// NewDBStatsCollector returns the collector of the database connection pool statistics.
+func NewDBStatsCollector(stats func() sql.DBStats) Collector {
+	return CollectorFunc(func() ([]*Family, error) {
+		s := stats()
+		gauge := func(name, help string, value float64) *Family {
+			family := NewFamily(name, help, TypeGauge)
+			family.Add(value)
+			return family
+		}
+		counter := func(name, help string, value float64) *Family {
+			family := NewFamily(name, help, TypeCounter)
+			family.Add(value)
+			return family
+		}
+		return []*Family{
+			gauge("db_max_open_connections", "The maximum number of the open database connections.", float64(s.MaxOpenConnections)),
+			gauge("db_open_connections", "The number of the open database connections.", float64(s.OpenConnections)),
+			gauge("db_in_use_connections", "The number of the database connections in use.", float64(s.InUse)),
+			gauge("db_idle_connections", "The number of the idle database connections.", float64(s.Idle)),
+			counter("db_wait_count_total", "The number of the waits for a database connection.", float64(s.WaitCount)),
+			counter("db_wait_duration_seconds_total", "The total time blocked waiting for a database connection.", s.WaitDuration.Seconds()),
+			counter("db_max_idle_closed_total", "The number of the connections closed due to the idle limit.", float64(s.MaxIdleClosed)),
+			counter("db_max_idle_time_closed_total", "The number of the connections closed due to the idle time limit.", float64(s.MaxIdleTimeClosed)),
+			counter("db_max_lifetime_closed_total", "The number of the connections closed due to the lifetime limit.", float64(s.MaxLifetimeClosed)),
+		}, nil
+	})
+}
+
+// NewBusinessCollector returns the collector of the inventory gauges.
+// The applications with lower score than the scoreThreshold are counted as low score applications.
+func NewBusinessCollector(repositoryContainer model.RepositoryContainer, scoreThreshold int) Collector {
+	return CollectorFunc(func() ([]*Family, error) {
+		applications, err := repositoryContainer.GetApplicationRepository().GetApplications(model.NewApplicationFilter())
+		if err != nil {
+			return nil, err
+		}
+		domains, err := repositoryContainer.GetDomainRepository().GetDomains(model.NewDomainFilter())
+		if err != nil {
+			return nil, err
+		}
+		byEnvironment := map[string]int{}
+		byRuntime := map[string]int{}
+		byFramework := map[string]int{}
+		belowScore := 0
+		for _, application := range *applications {
+			byEnvironment[application.Environment.Name]++
+			byRuntime[application.Runtime.Name]++
+			byFramework[application.Framework.Name]++
+			if application.Score() < scoreThreshold {
+				belowScore++
+			}
+		}
+		withoutSSL := 0
+		for _, domain := range *domains {
+			if !domain.HasSSL {
+				withoutSSL++
+			}
+		}
+		lowScore := NewFamily("applications_below_score", "The number of the applications with lower score than the threshold.", TypeGauge)
+		lowScore.Add(float64(belowScore), "threshold", strconv.Itoa(scoreThreshold))
+		noSSL := NewFamily("domains_without_ssl", "The number of the domains without ssl.", TypeGauge)
+		noSSL.Add(float64(withoutSSL))
+		return []*Family{
+			countFamily("applications_by_environment", "The number of the applications by environment.", "environment", byEnvironment),
+			countFamily("applications_by_runtime", "The number of the applications by runtime.", "runtime", byRuntime),
+			countFamily("applications_by_framework", "The number of the applications by framework.", "framework", byFramework),
+			lowScore,
+			noSSL,
+		}, nil
+	})
+}
+
+// countFamily returns a gauge family with a sample for every counted label value, ordered by the label values.
+func countFamily(name, help, labelName string, counts map[string]int) *Family {
+	family := NewFamily(name, help, TypeGauge)
+	values := make([]string, 0, len(counts))
+	for value := range counts {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	for _, value := range values {
+		family.Add(float64(counts[value]), labelName, value)
+	}
+	return family
+}
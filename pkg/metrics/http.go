@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	// DefaultBuckets are the upper bounds of the request duration histogram buckets in seconds.
+	DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+)
+
+// requestKey is the label set of the request counter.
+type requestKey struct {
+	method string
+	route  string
+	status int
+}
+
+// durationKey is the label set of the request duration histogram.
+type durationKey struct {
+	method string
+	route  string
+}
+
+// histogram holds the cumulative bucket counts of the observed values.
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// HTTPMetrics type collects the request counts and the request durations by route.
+type HTTPMetrics struct {
+	mu        sync.Mutex
+	buckets   []float64
+	requests  map[requestKey]uint64
+	durations map[durationKey]*histogram
+}
+
+// NewHTTPMetrics creates a new http metrics collector with the given histogram buckets.
+func NewHTTPMetrics(buckets []float64) *HTTPMetrics {
+	sortedBuckets := append([]float64{}, buckets...)
+	sort.Float64s(sortedBuckets)
+	return &HTTPMetrics{
+		buckets:   sortedBuckets,
+		requests:  map[requestKey]uint64{},
+		durations: map[durationKey]*histogram{},
+	}
+}
+
+// Observe stores a served request.
+// The route has to be the route template, so that the number of the label values is limited.
+func (m *HTTPMetrics) Observe(method, route string, status int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[requestKey{method, route, status}]++
+	key := durationKey{method, route}
+	h, ok := m.durations[key]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.durations[key] = h
+	}
+	seconds := duration.Seconds()
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// Collect returns the request counter and the request duration histogram.
+func (m *HTTPMetrics) Collect() ([]*Family, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	requests := NewFamily("http_requests_total", "The number of the served http requests.", TypeCounter)
+	requestKeys := make([]requestKey, 0, len(m.requests))
+	for key := range m.requests {
+		requestKeys = append(requestKeys, key)
+	}
+	sort.Slice(requestKeys, func(i, j int) bool {
+		if requestKeys[i].route != requestKeys[j].route {
+			return requestKeys[i].route < requestKeys[j].route
+		}
+		if requestKeys[i].method != requestKeys[j].method {
+			return requestKeys[i].method < requestKeys[j].method
+		}
+		return requestKeys[i].status < requestKeys[j].status
+	})
+	for _, key := range requestKeys {
+		requests.Add(float64(m.requests[key]), "method", key.method, "route", key.route, "status", strconv.Itoa(key.status))
+	}
+	durations := NewFamily("http_request_duration_seconds", "The duration of the served http requests.", TypeHistogram)
+	durationKeys := make([]durationKey, 0, len(m.durations))
+	for key := range m.durations {
+		durationKeys = append(durationKeys, key)
+	}
+	sort.Slice(durationKeys, func(i, j int) bool {
+		if durationKeys[i].route != durationKeys[j].route {
+			return durationKeys[i].route < durationKeys[j].route
+		}
+		return durationKeys[i].method < durationKeys[j].method
+	})
+	for _, key := range durationKeys {
+		h := m.durations[key]
+		for i, bound := range m.buckets {
+			durations.AddWithSuffix("_bucket", float64(h.counts[i]), "method", key.method, "route", key.route, "le", formatValue(bound))
+		}
+		durations.AddWithSuffix("_bucket", float64(h.count), "method", key.method, "route", key.route, "le", "+Inf")
+		durations.AddWithSuffix("_sum", h.sum, "method", key.method, "route", key.route)
+		durations.AddWithSuffix("_count", float64(h.count), "method", key.method, "route", key.route)
+	}
+	return []*Family{requests, durations}, nil
+}
@@ -0,0 +1,207 @@
+package metrics
+
+// This package exposes the metrics of the application in the prometheus text format.
+// The collectors are evaluated on every scrape.
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// Namespace is the prefix of the metric names.
+	Namespace = "projectregister_"
+
+	// TypeCounter is the type of the counter metrics.
+	TypeCounter = "counter"
+	// TypeGauge is the type of the gauge metrics.
+	TypeGauge = "gauge"
+	// TypeHistogram is the type of the histogram metrics.
+	TypeHistogram = "histogram"
+
+	// ContentType is the content type of the text exposition format.
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// Label type is a name value pair of a sample.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample type is a value of a metric family.
+// The Suffix is appended to the family name, it is used by the histograms (_bucket, _sum, _count).
+type Sample struct {
+	Suffix string
+	Labels []Label
+	Value  float64
+}
+
+// Family type is a metric with its samples.
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []*Sample
+}
+
+// NewFamily creates a new metric family. The name is prefixed with the namespace.
+func NewFamily(name, help, metricType string) *Family {
+	return &Family{
+		Name:    Namespace + name,
+		Help:    help,
+		Type:    metricType,
+		Samples: []*Sample{},
+	}
+}
+
+// Add adds a sample to the family. The labels are given as name, value pairs.
+func (f *Family) Add(value float64, labels ...string) {
+	f.AddWithSuffix("", value, labels...)
+}
+
+// AddWithSuffix adds a sample with name suffix to the family. The labels are given as name, value pairs.
+func (f *Family) AddWithSuffix(suffix string, value float64, labels ...string) {
+	sample := &Sample{Suffix: suffix, Labels: []Label{}, Value: value}
+	for i := 0; i+1 < len(labels); i += 2 {
+		sample.Labels = append(sample.Labels, Label{Name: labels[i], Value: labels[i+1]})
+	}
+	f.Samples = append(f.Samples, sample)
+}
+
+// Collector interface is implemented by the metric sources.
+type Collector interface {
+	Collect() ([]*Family, error)
+}
+
+// CollectorFunc type is an adapter to use functions as collectors.
+type CollectorFunc func() ([]*Family, error)
+
+// Collect calls the function.
+func (f CollectorFunc) Collect() ([]*Family, error) {
+	return f()
+}
+
+// NewGaugeFunc returns a collector of a single gauge. The value is returned by the given function.
+func NewGaugeFunc(name, help string, value func() float64) Collector {
+	return CollectorFunc(func() ([]*Family, error) {
+		family := NewFamily(name, help, TypeGauge)
+		family.Add(value())
+		return []*Family{family}, nil
+	})
+}
+
+// Registry type holds the collectors.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []Collector
+}
+
+// NewRegistry creates a new registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		collectors: []Collector{},
+	}
+}
+
+// Register adds the collector to the registry.
+func (r *Registry) Register(collector Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, collector)
+}
+
+// Gather collects the metric families of every collector.
+// The failing collectors are skipped, the first error is returned with the rest of the families.
+func (r *Registry) Gather() ([]*Family, error) {
+	r.mu.Lock()
+	collectors := append([]Collector{}, r.collectors...)
+	r.mu.Unlock()
+	families := []*Family{}
+	var firstErr error
+	for _, collector := range collectors {
+		collected, err := collector.Collect()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		families = append(families, collected...)
+	}
+	sort.SliceStable(families, func(i, j int) bool {
+		return families[i].Name < families[j].Name
+	})
+	return families, firstErr
+}
+
+// ServeHTTP writes the metrics in the text exposition format.
+// The failed collectors are reported in the scrape_error gauge, so that the other metrics are still available.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	families, err := r.Gather()
+	scrapeError := NewFamily("scrape_error", "1 if a collector failed during the scrape.", TypeGauge)
+	if err != nil {
+		scrapeError.Add(1)
+	} else {
+		scrapeError.Add(0)
+	}
+	families = append(families, scrapeError)
+	w.Header().Set("Content-Type", ContentType)
+	Write(w, families)
+}
+
+// Write writes the families in the text exposition format.
+func Write(w io.Writer, families []*Family) error {
+	for _, family := range families {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", family.Name, escapeHelp(family.Help), family.Name, family.Type); err != nil {
+			return err
+		}
+		for _, sample := range family.Samples {
+			if _, err := fmt.Fprintf(w, "%s%s%s %s\n", family.Name, sample.Suffix, formatLabels(sample.Labels), formatValue(sample.Value)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// formatLabels returns the labels in the {name="value",...} format.
+func formatLabels(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	parts := make([]string, len(labels))
+	for i, label := range labels {
+		parts[i] = label.Name + "=\"" + escapeLabelValue(label.Value) + "\""
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// formatValue returns the value in the text exposition format.
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// escapeHelp escapes the backslashes and the line feeds of the help text.
+func escapeHelp(help string) string {
+	return strings.NewReplacer("\\", `\\`, "\n", `\n`).Replace(help)
+}
+
+// escapeLabelValue escapes the backslashes, the double quotes and the line feeds of the label value.
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer("\\", `\\`, "\"", `\"`, "\n", `\n`).Replace(value)
+}
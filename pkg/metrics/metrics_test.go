@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWrite tests the text exposition format.
+func TestWrite(t *testing.T) {
+	family := NewFamily("test_total", "Test\nhelp.", TypeCounter)
+	family.Add(3, "name", "a \"quoted\" value")
+	family.Add(1.5)
+	var buf bytes.Buffer
+	if err := Write(&buf, []*Family{family}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	expected := "# HELP projectregister_test_total Test\\nhelp.\n" +
+		"# TYPE projectregister_test_total counter\n" +
+		"projectregister_test_total{name=\"a \\\"quoted\\\" value\"} 3\n" +
+		"projectregister_test_total 1.5\n"
+	if buf.String() != expected {
+		t.Errorf("Invalid output. Expected:\n%s\nGot:\n%s", expected, buf.String())
+	}
+}
+
+// TestHTTPMetrics tests the request counter and the duration histogram.
+func TestHTTPMetrics(t *testing.T) {
+	m := NewHTTPMetrics([]float64{0.1, 1})
+	m.Observe("GET", "/admin/user/view/{userId}", 200, 50*time.Millisecond)
+	m.Observe("GET", "/admin/user/view/{userId}", 200, 500*time.Millisecond)
+	m.Observe("GET", "/admin/user/view/{userId}", 404, 2*time.Second)
+	families, err := m.Collect()
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	var buf bytes.Buffer
+	Write(&buf, families)
+	output := buf.String()
+	expectedLines := []string{
+		`projectregister_http_requests_total{method="GET",route="/admin/user/view/{userId}",status="200"} 2`,
+		`projectregister_http_requests_total{method="GET",route="/admin/user/view/{userId}",status="404"} 1`,
+		`projectregister_http_request_duration_seconds_bucket{method="GET",route="/admin/user/view/{userId}",le="0.1"} 1`,
+		`projectregister_http_request_duration_seconds_bucket{method="GET",route="/admin/user/view/{userId}",le="1"} 2`,
+		`projectregister_http_request_duration_seconds_bucket{method="GET",route="/admin/user/view/{userId}",le="+Inf"} 3`,
+		`projectregister_http_request_duration_seconds_sum{method="GET",route="/admin/user/view/{userId}"} 2.55`,
+		`projectregister_http_request_duration_seconds_count{method="GET",route="/admin/user/view/{userId}"} 3`,
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(output, line+"\n") {
+			t.Errorf("Missing line: %s\nGot:\n%s", line, output)
+		}
+	}
+}
+
+// TestRegistryServeHTTP tests that the failed collector does not hide the other metrics.
+func TestRegistryServeHTTP(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(NewGaugeFunc("active_sessions", "Sessions.", func() float64 { return 2 }))
+	registry.Register(NewDBStatsCollector(func() sql.DBStats { return sql.DBStats{OpenConnections: 3, InUse: 1} }))
+	registry.Register(CollectorFunc(func() ([]*Family, error) { return nil, errors.New("failed") }))
+	rr := httptest.NewRecorder()
+	registry.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rr.Header().Get("Content-Type") != ContentType {
+		t.Errorf("Invalid content type: %s", rr.Header().Get("Content-Type"))
+	}
+	output := rr.Body.String()
+	for _, line := range []string{"projectregister_active_sessions 2", "projectregister_db_open_connections 3", "projectregister_db_in_use_connections 1", "projectregister_scrape_error 1"} {
+		if !strings.Contains(output, line+"\n") {
+			t.Errorf("Missing line: %s\nGot:\n%s", line, output)
+		}
+	}
+}
@@ -1,5 +1,83 @@
 package model
 
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+const (
+	// ApplicationDomainRolePrimary is the role of the canonical domain of the application.
+	ApplicationDomainRolePrimary = "primary"
+	// ApplicationDomainRoleAlias is the role of the domains that serve the same content as the primary domain.
+	ApplicationDomainRoleAlias = "alias"
+	// ApplicationDomainRoleRedirect is the role of the domains that only redirect to the target url.
+	ApplicationDomainRoleRedirect = "redirect"
+)
+
+var (
+	// ApplicationDomainRoles is the list of the supported application domain roles.
+	ApplicationDomainRoles = []string{ApplicationDomainRolePrimary, ApplicationDomainRoleAlias, ApplicationDomainRoleRedirect}
+	// ApplicationDomainRedirectStatuses is the list of the supported redirect status codes.
+	ApplicationDomainRedirectStatuses = []int{301, 302, 307, 308}
+
+	// ErrApplicationPrimaryDomainMissing is returned if the application has domains, but none of them is primary.
+	ErrApplicationPrimaryDomainMissing = errors.New("the application has to have a primary domain")
+	// ErrApplicationPrimaryDomainMultiple is returned if the application has more than one primary domain.
+	ErrApplicationPrimaryDomainMultiple = errors.New("the application can have only one primary domain")
+)
+
+// ApplicationDomain type is the role of a domain in an application.
+// The RedirectTarget and the RedirectStatus are only used by the redirect role.
+type ApplicationDomain struct {
+	DomainID       int64
+	Role           string
+	RedirectTarget string
+	RedirectStatus int
+}
+
+// String returns the human readable form of the role.
+func (d *ApplicationDomain) String() string {
+	if d.Role == ApplicationDomainRoleRedirect {
+		return fmt.Sprintf("%s %d to %s", d.Role, d.RedirectStatus, d.RedirectTarget)
+	}
+	return d.Role
+}
+
+// Validate checks the role and the redirect settings.
+func (d *ApplicationDomain) Validate() error {
+	switch d.Role {
+	case ApplicationDomainRolePrimary, ApplicationDomainRoleAlias:
+		return nil
+	case ApplicationDomainRoleRedirect:
+		target, err := url.Parse(d.RedirectTarget)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return fmt.Errorf("invalid redirect target: %q", d.RedirectTarget)
+		}
+		for _, status := range ApplicationDomainRedirectStatuses {
+			if status == d.RedirectStatus {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid redirect status: %d", d.RedirectStatus)
+	}
+	return fmt.Errorf("invalid domain role: %q", d.Role)
+}
+
+// NewApplicationDomains returns the roles of the domains.
+// The first domain is the primary, the others are aliases.
+func NewApplicationDomains(domainIDs []int64) []*ApplicationDomain {
+	domains := []*ApplicationDomain{}
+	for index, domainID := range domainIDs {
+		role := ApplicationDomainRoleAlias
+		if index == 0 {
+			role = ApplicationDomainRolePrimary
+		}
+		domains = append(domains, &ApplicationDomain{DomainID: domainID, Role: role})
+	}
+	return domains
+}
+
 // Application type
 type Application struct {
 	ID          int64
@@ -20,7 +98,65 @@ type Application struct {
 	CreatedAt string
 	UpdatedAt string
 
-	Domains []*Domain
+	Domains     []*Domain
+	DomainRoles []*ApplicationDomain
+}
+
+// HasDomainID checks if the application has the domain with the given id
+func (a *Application) HasDomainID(domainID int64) bool {
+	for _, d := range a.Domains {
+		if d.ID == domainID {
+			return true
+		}
+	}
+	return false
+}
+
+// DomainRole returns the role of the domain in the application.
+// The domains without role are aliases.
+func (a *Application) DomainRole(domainID int64) *ApplicationDomain {
+	for _, role := range a.DomainRoles {
+		if role.DomainID == domainID {
+			return role
+		}
+	}
+	return &ApplicationDomain{DomainID: domainID, Role: ApplicationDomainRoleAlias}
+}
+
+// PrimaryDomain returns the primary domain of the application.
+// It returns nil if the application does not have primary domain.
+func (a *Application) PrimaryDomain() *Domain {
+	for _, d := range a.Domains {
+		if a.DomainRole(d.ID).Role == ApplicationDomainRolePrimary {
+			return d
+		}
+	}
+	return nil
+}
+
+// ValidateDomainRoles checks the roles of the application domains.
+// The application without domains is valid, otherwise exactly one primary domain is required.
+func (a *Application) ValidateDomainRoles() error {
+	if len(a.Domains) == 0 {
+		return nil
+	}
+	primaries := 0
+	for _, d := range a.Domains {
+		role := a.DomainRole(d.ID)
+		if err := role.Validate(); err != nil {
+			return err
+		}
+		if role.Role == ApplicationDomainRolePrimary {
+			primaries++
+		}
+	}
+	if primaries == 0 {
+		return ErrApplicationPrimaryDomainMissing
+	}
+	if primaries > 1 {
+		return ErrApplicationPrimaryDomainMultiple
+	}
+	return nil
 }
 
 // HasDomain checks if the application has the domain
@@ -33,6 +169,39 @@ func (a *Application) HasDomain(domain string) bool {
 	return false
 }
 
+// Score returns the score of the application.
+// It is the lowest score of the environment, the runtime and the framework.
+func (a *Application) Score() int {
+	score := a.Environment.Score
+	if a.Runtime.Score < score {
+		score = a.Runtime.Score
+	}
+	if a.Framework.Score < score {
+		score = a.Framework.Score
+	}
+	return score
+}
+
+// SecurityScore returns the security score of the primary domain of the application.
+// The second return value is false if the primary domain is not audited.
+func (a *Application) SecurityScore() (int, bool) {
+	primary := a.PrimaryDomain()
+	if primary == nil || !primary.IsAudited() {
+		return 0, false
+	}
+	return primary.SecurityScore, true
+}
+
+// SecurityGrade returns the security grade of the primary domain of the application.
+// It returns empty string if the primary domain is not audited.
+func (a *Application) SecurityGrade() string {
+	primary := a.PrimaryDomain()
+	if primary == nil {
+		return ""
+	}
+	return primary.SecurityGrade
+}
+
 // Applications type is a slice of Application
 type Applications []*Application
 
@@ -104,7 +273,7 @@ func (f *ApplicationFilter) IsVisibleColumn(column string) bool {
 
 // ApplicationRepository interface
 type ApplicationRepository interface {
-	CreateApplication(clientID, projectID, environmentID, databaseID, runtimeID, poolID, frameworkID int64, repository, branch, dbName, dbUser, docRoot string, domains []int64) (*Application, error)
+	CreateApplication(clientID, projectID, environmentID, databaseID, runtimeID, poolID, frameworkID int64, repository, branch, dbName, dbUser, docRoot string, domains []*ApplicationDomain) (*Application, error)
 	GetApplicationByID(id int64) (*Application, error)
 	UpdateApplication(application *Application) error
 	DeleteApplication(id int64) error
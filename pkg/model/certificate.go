@@ -0,0 +1,122 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Certificate type
+// The DNSNames contains the subject alternative names of the certificate.
+// The NotBefore and NotAfter are RFC3339 formatted timestamps.
+// The PEM contains the certificate chain, the private key is never stored.
+type Certificate struct {
+	ID           int64
+	Name         string
+	CommonName   string
+	Issuer       string
+	SerialNumber string
+	Fingerprint  string
+	DNSNames     []string
+	NotBefore    string
+	NotAfter     string
+	PEM          string
+	CreatedAt    string
+	UpdatedAt    string
+
+	Domains []*Domain
+}
+
+// Covers checks if the domain name matches any of the SAN entries of the certificate.
+// The wildcard entries match only one label, like the browsers do.
+func (c *Certificate) Covers(domainName string) bool {
+	domainName = strings.ToLower(strings.TrimSuffix(domainName, "."))
+	for _, dnsName := range c.DNSNames {
+		dnsName = strings.ToLower(strings.TrimSuffix(dnsName, "."))
+		if dnsName == domainName {
+			return true
+		}
+		if !strings.HasPrefix(dnsName, "*.") {
+			continue
+		}
+		label, parent, found := strings.Cut(domainName, ".")
+		if found && label != "" && parent == dnsName[2:] {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpiresAt returns the expiration time of the certificate.
+// It returns the zero time if the NotAfter is not a valid timestamp.
+func (c *Certificate) ExpiresAt() time.Time {
+	expiresAt, err := time.Parse(time.RFC3339, c.NotAfter)
+	if err != nil {
+		return time.Time{}
+	}
+	return expiresAt
+}
+
+// IsExpired checks if the certificate is expired at the given time.
+func (c *Certificate) IsExpired(now time.Time) bool {
+	return now.After(c.ExpiresAt())
+}
+
+// HasDomain checks if the certificate is linked to the domain
+func (c *Certificate) HasDomain(domainID int64) bool {
+	for _, d := range c.Domains {
+		if d.ID == domainID {
+			return true
+		}
+	}
+	return false
+}
+
+// Certificates type is a slice of Certificate
+type Certificates []*Certificate
+
+// ToMap converts the Certificates to a map
+// The key is the certificate ID
+// The value is the certificate Name
+func (c Certificates) ToMap() map[int64]string {
+	result := make(map[int64]string)
+	for _, certificate := range c {
+		result[certificate.ID] = certificate.Name
+	}
+	return result
+}
+
+// ForDomain returns the certificates that are linked to the domain
+func (c Certificates) ForDomain(domainID int64) Certificates {
+	result := Certificates{}
+	for _, certificate := range c {
+		if certificate.HasDomain(domainID) {
+			result = append(result, certificate)
+		}
+	}
+	return result
+}
+
+// CertificateFilter type is the filter for the certificates
+// It contains the name and the domain filter
+type CertificateFilter struct {
+	Name      string
+	DomainIDs []string
+}
+
+// NewCertificateFilter creates a new certificate filter
+func NewCertificateFilter() *CertificateFilter {
+	return &CertificateFilter{
+		Name:      "",
+		DomainIDs: []string{},
+	}
+}
+
+// CertificateRepository interface
+type CertificateRepository interface {
+	CreateCertificate(name, commonName, issuer, serialNumber, fingerprint string, dnsNames []string, notBefore, notAfter, pemData string, domainIDs []int64) (*Certificate, error)
+	GetCertificateByID(id int64) (*Certificate, error)
+	GetCertificateByFingerprint(fingerprint string) (*Certificate, error)
+	UpdateCertificate(certificate *Certificate) error
+	DeleteCertificate(id int64) error
+	GetCertificates(filter *CertificateFilter) (*Certificates, error)
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DNSRecordTypeA is the type of the IPv4 address records.
+	DNSRecordTypeA = "A"
+	// DNSRecordTypeAAAA is the type of the IPv6 address records.
+	DNSRecordTypeAAAA = "AAAA"
+	// DNSRecordTypeCNAME is the type of the canonical name records.
+	DNSRecordTypeCNAME = "CNAME"
+	// DNSRecordTypeMX is the type of the mail exchange records.
+	DNSRecordTypeMX = "MX"
+	// DNSRecordTypeTXT is the type of the text records.
+	DNSRecordTypeTXT = "TXT"
+
+	// DNSRecordApexName is the name of the records that belong to the zone apex.
+	DNSRecordApexName = "@"
+	// DNSRecordDefaultTTL is the default time to live of the records in seconds.
+	DNSRecordDefaultTTL = 3600
+)
+
+var (
+	// DNSRecordTypes is the list of the supported record types.
+	DNSRecordTypes = []string{DNSRecordTypeA, DNSRecordTypeAAAA, DNSRecordTypeCNAME, DNSRecordTypeMX, DNSRecordTypeTXT}
+)
+
+// DNSRecord type
+// The record belongs to the zone of the Domain.
+// The Name is relative to the zone apex, the apex itself is "@".
+// The Priority is used only by the MX records.
+type DNSRecord struct {
+	ID        int64
+	DomainID  int64
+	Name      string
+	Type      string
+	TTL       int
+	Priority  int
+	Value     string
+	CreatedAt string
+	UpdatedAt string
+}
+
+// FQDN returns the fully qualified name of the record in the zone.
+func (r *DNSRecord) FQDN(zone string) string {
+	zone = strings.TrimSuffix(zone, ".")
+	if r.Name == DNSRecordApexName || r.Name == "" {
+		return zone
+	}
+	return r.Name + "." + zone
+}
+
+// String returns the record in the zone file presentation format without the owner name.
+func (r *DNSRecord) String() string {
+	if r.Type == DNSRecordTypeMX {
+		return fmt.Sprintf("%d IN %s %d %s", r.TTL, r.Type, r.Priority, r.Value)
+	}
+	return fmt.Sprintf("%d IN %s %s", r.TTL, r.Type, r.Value)
+}
+
+// Equals checks if the records have the same name, type and data.
+// The TTL is not compared.
+func (r *DNSRecord) Equals(other *DNSRecord) bool {
+	return strings.EqualFold(r.Name, other.Name) && r.Type == other.Type && r.Priority == other.Priority && r.Value == other.Value
+}
+
+// DNSRecords type is a slice of DNSRecord
+type DNSRecords []*DNSRecord
+
+// Contains checks if the records contain an equal record.
+func (r DNSRecords) Contains(record *DNSRecord) bool {
+	for _, current := range r {
+		if current.Equals(record) {
+			return true
+		}
+	}
+	return false
+}
+
+// DNSRecordFilter type is the filter for the dns records
+// It contains the domain and the type filter
+type DNSRecordFilter struct {
+	DomainIDs []string
+	Type      string
+}
+
+// NewDNSRecordFilter creates a new dns record filter
+func NewDNSRecordFilter() *DNSRecordFilter {
+	return &DNSRecordFilter{
+		DomainIDs: []string{},
+		Type:      "",
+	}
+}
+
+// DNSRecordRepository interface
+type DNSRecordRepository interface {
+	CreateDNSRecord(domainID int64, name, recordType string, ttl, priority int, value string) (*DNSRecord, error)
+	GetDNSRecordByID(id int64) (*DNSRecord, error)
+	UpdateDNSRecord(record *DNSRecord) error
+	DeleteDNSRecord(id int64) error
+	GetDNSRecords(filter *DNSRecordFilter) (*DNSRecords, error)
+}
@@ -1,5 +1,16 @@
 package model
 
+import (
+	"time"
+)
+
+const (
+	// DomainDateFormat is the format of the registration and the expiry dates.
+	DomainDateFormat = "2006-01-02"
+	// DomainCheckTimeFormat is the format of the check times.
+	DomainCheckTimeFormat = "2006-01-02 15:04:05"
+)
+
 // Domain type
 type Domain struct {
 	ID        int64
@@ -7,6 +18,76 @@ type Domain struct {
 	HasSSL    bool
 	CreatedAt string
 	UpdatedAt string
+
+	// The result of the latest security audit.
+	// The grade is empty until the first audit.
+	SecurityScore    int
+	SecurityGrade    string
+	SecurityFindings string
+
+	// The SHA-256 fingerprint of the certificate that is served by the domain.
+	// It is set by the ssl check.
+	LiveCertificateFingerprint string
+	// The time of the latest ssl check. It is empty until the first check.
+	SSLCheckedAt string
+
+	// The registration data. The dates are empty if they are unknown.
+	// The BillingClient is nil if it is not set.
+	Registrar     string
+	RegisteredAt  string
+	ExpiresAt     string
+	AutoRenew     bool
+	BillingClient *Client
+
+	// The default ttl of the zone in seconds. It is set by the zone import.
+	ZoneTTL int
+}
+
+// SSLCheckFailed checks if the latest ssl check failed.
+// The unchecked domains are not failed.
+func (d *Domain) SSLCheckFailed() bool {
+	return d.SSLCheckedAt != "" && !d.HasSSL
+}
+
+// ExpiryDate returns the expiry date of the domain.
+// The second return value is false if the expiry date is unknown.
+func (d *Domain) ExpiryDate() (time.Time, bool) {
+	expiresAt, err := time.Parse(DomainDateFormat, d.ExpiresAt)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return expiresAt, true
+}
+
+// DaysUntilExpiry returns the number of the days until the expiry date.
+// It is negative if the domain is already expired.
+func (d *Domain) DaysUntilExpiry(now time.Time) int {
+	expiresAt, ok := d.ExpiryDate()
+	if !ok {
+		return 0
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return int(expiresAt.Sub(today).Hours() / 24)
+}
+
+// IsAudited checks if the domain has security audit result
+func (d *Domain) IsAudited() bool {
+	return d.SecurityGrade != ""
+}
+
+// HasCertificateMismatch checks if the domain serves a certificate
+// that is not registered for the domain.
+// It returns false if the live certificate is unknown or no certificate is registered.
+func (d *Domain) HasCertificateMismatch(certificates Certificates) bool {
+	if d.LiveCertificateFingerprint == "" || len(certificates) == 0 {
+		return false
+	}
+	for _, certificate := range certificates {
+		if certificate.Fingerprint == d.LiveCertificateFingerprint {
+			return false
+		}
+	}
+	return true
 }
 
 // Domains type is a slice of Domain
@@ -24,21 +105,26 @@ func (d Domains) ToMap() map[int64]string {
 }
 
 // DomainFilter type is the filter for the domains
-// It contains the name filter
+// It contains the name and the expiry filter
+// The ExpiresBefore is a date, if it is set, only the domains with known expiry date
+// before it are returned ordered by the expiry date.
 type DomainFilter struct {
-	Name string
+	Name          string
+	ExpiresBefore string
 }
 
 // NewDomainFilter creates a new domain filter
 func NewDomainFilter() *DomainFilter {
 	return &DomainFilter{
-		Name: "",
+		Name:          "",
+		ExpiresBefore: "",
 	}
 }
 
 // DomainRepository interface
 type DomainRepository interface {
 	CreateDomain(name string) (*Domain, error)
+	CreateDomainWithRegistration(domain *Domain) (*Domain, error)
 	GetDomainByName(name string) (*Domain, error)
 	GetDomainByID(id int64) (*Domain, error)
 	UpdateDomain(client *Domain) error
@@ -0,0 +1,15 @@
+package model
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNotFound is returned by the repositories if the requested item does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned by the repositories if the item violates a unique constraint, eg. the name is already used.
+	ErrConflict = errors.New("conflict")
+	// ErrForeignKey is returned by the repositories if the item references a missing item,
+	// or it is still referenced by other items on delete.
+	ErrForeignKey = errors.New("foreign key violation")
+)
@@ -3,8 +3,10 @@ package model
 // RepositoryContainer interface
 type RepositoryContainer interface {
 	GetApplicationRepository() ApplicationRepository
+	GetCertificateRepository() CertificateRepository
 	GetClientRepository() ClientRepository
 	GetDatabaseRepository() DatabaseRepository
+	GetDNSRecordRepository() DNSRecordRepository
 	GetDomainRepository() DomainRepository
 	GetEnvironmentRepository() EnvironmentRepository
 	GetPoolRepository() PoolRepository
@@ -15,4 +17,11 @@ type RepositoryContainer interface {
 	GetServerRepository() ServerRepository
 	GetUserRepository() UserRepository
 	GetFrameworkRepository() FrameworkRepository
+	GetWebhookRepository() WebhookRepository
+	GetWebhookDeliveryRepository() WebhookDeliveryRepository
+	GetNotificationSubscriptionRepository() NotificationSubscriptionRepository
+	GetNotificationDeliveryRepository() NotificationDeliveryRepository
+	GetJobRepository() JobRepository
+	GetScheduledTaskRepository() ScheduledTaskRepository
+	GetStatisticsSnapshotRepository() StatisticsSnapshotRepository
 }
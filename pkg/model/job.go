@@ -0,0 +1,122 @@
+package model
+
+import "time"
+
+const (
+	// JobStatusQueued is the status of the jobs that are waiting for a worker.
+	JobStatusQueued = "queued"
+	// JobStatusRunning is the status of the jobs that are processed by a worker.
+	JobStatusRunning = "running"
+	// JobStatusSucceeded is the status of the successfully finished jobs.
+	JobStatusSucceeded = "succeeded"
+	// JobStatusFailed is the status of the failed jobs.
+	JobStatusFailed = "failed"
+	// JobStatusCancelled is the status of the jobs that are cancelled by a user.
+	JobStatusCancelled = "cancelled"
+
+	// JobTypeApplicationImport is the type of the application import jobs.
+	JobTypeApplicationImport = "application_import"
+	// JobTypeDomainCheck is the type of the domain ssl and security check jobs.
+	JobTypeDomainCheck = "domain_check"
+	// JobTypeResourceImport is the type of the server, environment, domain and user import jobs.
+	JobTypeResourceImport = "resource_import"
+
+	// JobTimeFormat is the format of the start and the finish times.
+	JobTimeFormat = "2006-01-02 15:04:05"
+)
+
+var (
+	// JobStatuses is the list of the job statuses in the order of the lifecycle.
+	JobStatuses = []string{JobStatusQueued, JobStatusRunning, JobStatusSucceeded, JobStatusFailed, JobStatusCancelled}
+)
+
+// Job type is a background job. The Payload is the json encoded input of the job,
+// the Progress and the Total are the processed and the total number of the items,
+// the Log contains the messages of the job, one per line.
+// The StartedAt and the FinishedAt are empty until the job is started and finished.
+// The LeaseExpiresAt is the time until the running job is owned by its worker, it is renewed while the job runs.
+type Job struct {
+	ID         int64
+	Type       string
+	Payload    string
+	Status     string
+	Progress   int
+	Total      int
+	Log        string
+	Error      string
+	Attempts   int
+	CreatedBy  int64
+	CreatedAt  string
+	StartedAt  string
+	FinishedAt string
+	UpdatedAt  string
+
+	LeaseExpiresAt string
+}
+
+// Percent returns the progress of the job in percent.
+// The succeeded job without items is complete.
+func (j *Job) Percent() int {
+	if j.Total <= 0 {
+		if j.Status == JobStatusSucceeded {
+			return 100
+		}
+		return 0
+	}
+	return j.Progress * 100 / j.Total
+}
+
+// CanRetry checks if the job could be queued again.
+func (j *Job) CanRetry() bool {
+	return j.Status == JobStatusFailed || j.Status == JobStatusCancelled
+}
+
+// CanCancel checks if the job could be cancelled.
+func (j *Job) CanCancel() bool {
+	return j.Status == JobStatusQueued || j.Status == JobStatusRunning
+}
+
+// DomainCheckPayload type is the payload of the domain check jobs.
+// The SSL flag enables the ssl check, the Security flag enables the security audit.
+type DomainCheckPayload struct {
+	DomainIDs []int64 `json:"domain_ids"`
+	SSL       bool    `json:"ssl"`
+	Security  bool    `json:"security"`
+}
+
+// Jobs type is a slice of Job
+type Jobs []*Job
+
+// JobFilter type is the filter for the jobs
+// The Limit is the maximum number of the returned jobs, 0 means no limit.
+type JobFilter struct {
+	Type   string
+	Status string
+	Limit  int
+}
+
+// NewJobFilter creates a new job filter
+func NewJobFilter() *JobFilter {
+	return &JobFilter{
+		Type:   "",
+		Status: "",
+		Limit:  0,
+	}
+}
+
+// JobRepository interface
+// The ClaimJob returns ErrNotFound if there is no queued job nor running job with expired lease.
+// The RenewJobLease, the UpdateJobProgress and the FinishJob return ErrNotFound if the job is not running anymore, eg. it is cancelled.
+// The RetryJob and the CancelJob return ErrNotFound if the job is not in a retryable or cancellable status.
+// The RetryJob keeps the progress, so that the job could resume after the processed items.
+type JobRepository interface {
+	CreateJob(jobType, payload string, createdBy int64) (*Job, error)
+	GetJobByID(id int64) (*Job, error)
+	GetJobs(filter *JobFilter) (*Jobs, error)
+	ClaimJob(lease time.Duration) (*Job, error)
+	RenewJobLease(id int64, lease time.Duration) (*Job, error)
+	UpdateJobProgress(id int64, progress, total int, logLines string) (*Job, error)
+	FinishJob(id int64, progress int, status, errorMessage, logLines string) (*Job, error)
+	RetryJob(id int64) (*Job, error)
+	CancelJob(id int64) (*Job, error)
+}
@@ -0,0 +1,94 @@
+package model
+
+const (
+	// NotificationRuleCertificateExpiry is the rule of the certificates that expire in less than threshold days.
+	NotificationRuleCertificateExpiry = "certificate_expiry"
+	// NotificationRuleSSLCheckFailed is the rule of the domains where the latest ssl check failed.
+	NotificationRuleSSLCheckFailed = "ssl_check_failed"
+	// NotificationRuleApplicationScore is the rule of the applications with score below the threshold.
+	NotificationRuleApplicationScore = "application_score"
+)
+
+// NotificationRule type describes a rule that the users could subscribe to.
+// The ThresholdLabel is empty if the rule has no threshold.
+type NotificationRule struct {
+	Name             string
+	Label            string
+	ThresholdLabel   string
+	DefaultThreshold int
+}
+
+// HasThreshold checks if the rule is parametrized with a threshold.
+func (r *NotificationRule) HasThreshold() bool {
+	return r.ThresholdLabel != ""
+}
+
+var (
+	// NotificationRules is the list of the available notification rules.
+	NotificationRules = []*NotificationRule{
+		{Name: NotificationRuleCertificateExpiry, Label: "Certificate expires soon", ThresholdLabel: "Days before the expiry", DefaultThreshold: 14},
+		{Name: NotificationRuleSSLCheckFailed, Label: "SSL check failed"},
+		{Name: NotificationRuleApplicationScore, Label: "Application score is low", ThresholdLabel: "Minimum score", DefaultThreshold: 50},
+	}
+)
+
+// GetNotificationRule returns the rule with the given name or nil if it does not exist.
+func GetNotificationRule(name string) *NotificationRule {
+	for _, rule := range NotificationRules {
+		if rule.Name == name {
+			return rule
+		}
+	}
+	return nil
+}
+
+// NotificationSubscription type is the subscription of a user to a notification rule.
+// If the Digest is true, the alerts are sent in the daily digest, otherwise immediately.
+type NotificationSubscription struct {
+	ID        int64
+	UserID    int64
+	Rule      string
+	Threshold int
+	Digest    bool
+	CreatedAt string
+	UpdatedAt string
+}
+
+// NotificationSubscriptions type is a slice of NotificationSubscription
+type NotificationSubscriptions []*NotificationSubscription
+
+// ByRule returns the subscription of the rule or nil if the rule is not subscribed.
+func (s NotificationSubscriptions) ByRule(rule string) *NotificationSubscription {
+	for _, subscription := range s {
+		if subscription.Rule == rule {
+			return subscription
+		}
+	}
+	return nil
+}
+
+// NotificationSubscriptionFilter type is the filter for the notification subscriptions
+type NotificationSubscriptionFilter struct {
+	UserIDs []string
+}
+
+// NewNotificationSubscriptionFilter creates a new notification subscription filter
+func NewNotificationSubscriptionFilter() *NotificationSubscriptionFilter {
+	return &NotificationSubscriptionFilter{
+		UserIDs: []string{},
+	}
+}
+
+// NotificationSubscriptionRepository interface
+type NotificationSubscriptionRepository interface {
+	GetNotificationSubscriptions(filter *NotificationSubscriptionFilter) (*NotificationSubscriptions, error)
+	SaveNotificationSubscriptions(userID int64, subscriptions NotificationSubscriptions) error
+}
+
+// NotificationDeliveryRepository interface
+// The deliveries are the keys of the alerts and the digests that are already sent to the user.
+type NotificationDeliveryRepository interface {
+	GetNotificationDeliveryKeys(userID int64) ([]string, error)
+	CreateNotificationDelivery(userID int64, key string) error
+	DeleteNotificationDelivery(userID int64, key string) error
+}
@@ -0,0 +1,77 @@
+package model
+
+import "time"
+
+const (
+	// ScheduledTaskStatusRunning is the status of the tasks that are running on an instance.
+	ScheduledTaskStatusRunning = "running"
+	// ScheduledTaskStatusSucceeded is the status of the tasks that are finished successfully on the last run.
+	ScheduledTaskStatusSucceeded = "succeeded"
+	// ScheduledTaskStatusFailed is the status of the tasks that are failed on the last run.
+	ScheduledTaskStatusFailed = "failed"
+
+	// ScheduledTaskDomainChecks is the name of the task that queues the ssl and security checks of the domains.
+	ScheduledTaskDomainChecks = "domain_checks"
+	// ScheduledTaskUploadCleanup is the name of the task that deletes the stale uploaded files.
+	ScheduledTaskUploadCleanup = "upload_cleanup"
+	// ScheduledTaskExpiryNotifications is the name of the task that sends the email notifications.
+	ScheduledTaskExpiryNotifications = "expiry_notifications"
+	// ScheduledTaskStatisticsSnapshot is the name of the task that stores the inventory statistics.
+	ScheduledTaskStatisticsSnapshot = "statistics_snapshot"
+
+	// ScheduledTaskTimeFormat is the format of the run times.
+	ScheduledTaskTimeFormat = "2006-01-02 15:04"
+)
+
+// ScheduledTask type is a periodic maintenance task. The Schedule is a cron expression.
+// The LastRunAt, the LastStatus and the LastMessage are empty until the first run,
+// the NextRunAt is empty if the task is disabled.
+type ScheduledTask struct {
+	ID             int64
+	Name           string
+	Description    string
+	Schedule       string
+	Enabled        bool
+	LastRunAt      string
+	LastStatus     string
+	LastMessage    string
+	LastDurationMs int64
+	NextRunAt      string
+	UpdatedAt      string
+}
+
+// ScheduledTasks type is a slice of ScheduledTask
+type ScheduledTasks []*ScheduledTask
+
+// ScheduledTaskRepository interface
+// The tasks are created by the migrations, only their schedule and their state could be changed.
+// The zero nextRunAt means that the task is not scheduled.
+// The StartScheduledTask returns ErrNotFound if the task is not enabled or it is not due at the startedAt time,
+// eg. it is already started by another instance.
+type ScheduledTaskRepository interface {
+	GetScheduledTaskByID(id int64) (*ScheduledTask, error)
+	GetScheduledTasks() (*ScheduledTasks, error)
+	UpdateScheduledTask(id int64, schedule string, enabled bool, nextRunAt time.Time) (*ScheduledTask, error)
+	StartScheduledTask(id int64, startedAt, nextRunAt time.Time) (*ScheduledTask, error)
+	FinishScheduledTask(id int64, status, message string, durationMs int64) (*ScheduledTask, error)
+}
+
+// StatisticsSnapshot type is the inventory counts at a point of time.
+type StatisticsSnapshot struct {
+	ID                int64
+	Clients           int64
+	Projects          int64
+	Environments      int64
+	Applications      int64
+	Domains           int64
+	DomainsWithoutSSL int64
+	Servers           int64
+	Databases         int64
+	Certificates      int64
+	CreatedAt         string
+}
+
+// StatisticsSnapshotRepository interface
+type StatisticsSnapshotRepository interface {
+	CreateStatisticsSnapshot() (*StatisticsSnapshot, error)
+}
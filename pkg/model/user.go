@@ -9,7 +9,8 @@ type User struct {
 	UpdatedAt string
 	Role      *Role
 
-	Password string
+	// the password hash is never part of the published events
+	Password string `json:"-"`
 }
 
 // HasPrivilege checks if the user has the privilege
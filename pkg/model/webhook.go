@@ -0,0 +1,96 @@
+package model
+
+// Webhook type is an outgoing webhook registration.
+// The EventTypes are the subscribed event types in resource.action format.
+// The Secret is used for signing the payloads.
+type Webhook struct {
+	ID         int64
+	Name       string
+	URL        string
+	Secret     string
+	EventTypes []string
+	Active     bool
+	CreatedAt  string
+	UpdatedAt  string
+}
+
+// Subscribes checks if the webhook is subscribed to the event type.
+func (w *Webhook) Subscribes(eventType string) bool {
+	for _, current := range w.EventTypes {
+		if current == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhooks type is a slice of Webhook
+type Webhooks []*Webhook
+
+// WebhookFilter type is the filter for the webhooks
+// The EventType filter returns the active webhooks that are subscribed to the event type.
+type WebhookFilter struct {
+	Name      string
+	EventType string
+}
+
+// NewWebhookFilter creates a new webhook filter
+func NewWebhookFilter() *WebhookFilter {
+	return &WebhookFilter{
+		Name:      "",
+		EventType: "",
+	}
+}
+
+// WebhookRepository interface
+type WebhookRepository interface {
+	CreateWebhook(name, url, secret string, eventTypes []string, active bool) (*Webhook, error)
+	GetWebhookByID(id int64) (*Webhook, error)
+	UpdateWebhook(webhook *Webhook) error
+	DeleteWebhook(id int64) error
+	GetWebhooks(filter *WebhookFilter) (*Webhooks, error)
+}
+
+// WebhookDelivery type is a delivery attempt of an event to a webhook.
+// The StatusCode is 0 if the request failed without response, the Error contains the reason.
+type WebhookDelivery struct {
+	ID         int64
+	WebhookID  int64
+	EventID    string
+	EventType  string
+	Payload    string
+	Attempt    int
+	StatusCode int
+	Error      string
+	DurationMs int64
+	CreatedAt  string
+}
+
+// Succeeded checks if the delivery attempt got a successful response.
+func (d *WebhookDelivery) Succeeded() bool {
+	return d.StatusCode >= 200 && d.StatusCode < 300
+}
+
+// WebhookDeliveries type is a slice of WebhookDelivery
+type WebhookDeliveries []*WebhookDelivery
+
+// WebhookDeliveryFilter type is the filter for the webhook deliveries
+// The Limit is the maximum number of the returned deliveries, 0 means no limit.
+type WebhookDeliveryFilter struct {
+	WebhookIDs []string
+	Limit      int
+}
+
+// NewWebhookDeliveryFilter creates a new webhook delivery filter
+func NewWebhookDeliveryFilter() *WebhookDeliveryFilter {
+	return &WebhookDeliveryFilter{
+		WebhookIDs: []string{},
+		Limit:      0,
+	}
+}
+
+// WebhookDeliveryRepository interface
+type WebhookDeliveryRepository interface {
+	CreateWebhookDelivery(delivery *WebhookDelivery) (*WebhookDelivery, error)
+	GetWebhookDeliveries(filter *WebhookDeliveryFilter) (*WebhookDeliveries, error)
+}
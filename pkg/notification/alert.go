@@ -0,0 +1,83 @@
+package notification
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// Alert type is an active alert of a notification rule.
+// The Key identifies the alert, it is used for sending the alert only once.
+// The Value is compared to the threshold of the subscription.
+type Alert struct {
+	Rule    string
+	Key     string
+	Subject string
+	Detail  string
+	Link    string
+	Value   int
+}
+
+// Matches checks if the alert is subscribed with the subscription.
+func (a *Alert) Matches(subscription *model.NotificationSubscription) bool {
+	if subscription == nil || subscription.Rule != a.Rule {
+		return false
+	}
+	switch a.Rule {
+	case model.NotificationRuleCertificateExpiry, model.NotificationRuleApplicationScore:
+		return a.Value < subscription.Threshold
+	}
+	return true
+}
+
+// Evaluate returns the active alerts of every rule, without the thresholds.
+// The threshold based rules return alert for every item, the subscriptions filter them.
+// The baseURL is the public url of the application, it is used for the links.
+func Evaluate(certificates *model.Certificates, domains *model.Domains, applications *model.Applications, now time.Time, baseURL string) []*Alert {
+	alerts := []*Alert{}
+	for _, certificate := range *certificates {
+		expiresAt := certificate.ExpiresAt()
+		if expiresAt.IsZero() {
+			continue
+		}
+		daysLeft := int(math.Ceil(expiresAt.Sub(now).Hours() / 24))
+		detail := fmt.Sprintf("expires in %d days, on %s", daysLeft, expiresAt.Format(model.DomainDateFormat))
+		if daysLeft <= 0 {
+			detail = fmt.Sprintf("expired on %s", expiresAt.Format(model.DomainDateFormat))
+		}
+		alerts = append(alerts, &Alert{
+			Rule:    model.NotificationRuleCertificateExpiry,
+			Key:     fmt.Sprintf("%s:certificate:%d", model.NotificationRuleCertificateExpiry, certificate.ID),
+			Subject: "Certificate " + certificate.Name + " expires",
+			Detail:  detail,
+			Link:    fmt.Sprintf("%s/admin/certificate/view/%d", baseURL, certificate.ID),
+			Value:   daysLeft,
+		})
+	}
+	for _, domain := range *domains {
+		if !domain.SSLCheckFailed() {
+			continue
+		}
+		alerts = append(alerts, &Alert{
+			Rule:    model.NotificationRuleSSLCheckFailed,
+			Key:     fmt.Sprintf("%s:domain:%d", model.NotificationRuleSSLCheckFailed, domain.ID),
+			Subject: "SSL check failed on " + domain.Name,
+			Detail:  "the latest ssl check failed at " + domain.SSLCheckedAt,
+			Link:    fmt.Sprintf("%s/admin/domain/view/%d", baseURL, domain.ID),
+		})
+	}
+	for _, application := range *applications {
+		score := application.Score()
+		alerts = append(alerts, &Alert{
+			Rule:    model.NotificationRuleApplicationScore,
+			Key:     fmt.Sprintf("%s:application:%d", model.NotificationRuleApplicationScore, application.ID),
+			Subject: fmt.Sprintf("Application %d (%s / %s / %s) has low score", application.ID, application.Client.Name, application.Project.Name, application.Environment.Name),
+			Detail:  fmt.Sprintf("the score is %d", score),
+			Link:    fmt.Sprintf("%s/admin/application/view/%d", baseURL, application.ID),
+			Value:   score,
+		})
+	}
+	return alerts
+}
@@ -0,0 +1,240 @@
+package notification
+
+// This package contains the rule based email notifications. The notifier evaluates the rules
+// on every run of the scheduled task, and sends the new alerts to the subscribed users immediately or in the daily digest.
+
+import (
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/mailer"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+const (
+	// TemplateName is the name of the notification email template.
+	TemplateName = "notification"
+	// SettingsPath is the path of the notification settings page.
+	SettingsPath = "/admin/notification/settings"
+
+	// digestKeyPrefix is the prefix of the delivery keys of the digests.
+	digestKeyPrefix = "digest:"
+	// subjectPrefix is the prefix of the email subjects.
+	subjectPrefix = "[Project Register] "
+)
+
+// NewTemplates returns the email templates of the notifications from the template file system.
+func NewTemplates(fileSystem fs.FS) *mailer.Templates {
+	templates := mailer.NewTemplates(fileSystem)
+	templates.SetBaseTemplate("email/base.html.tmpl")
+	templates.AddTemplate(TemplateName, "email/notification.txt.tmpl", "email/notification.html.tmpl")
+	return templates
+}
+
+// emailData is the data of the notification email template.
+type emailData struct {
+	Title       string
+	Intro       string
+	SettingsURL string
+	User        *model.User
+	Alerts      []*Alert
+}
+
+// Plan type is the result of the comparison of the active alerts and the already sent ones for a user.
+type Plan struct {
+	// Immediate is the list of the new alerts that have to be sent now.
+	Immediate []*Alert
+	// Digest is the list of the alerts of the digest. It is empty if the digest is not due.
+	Digest []*Alert
+	// DigestKey is the delivery key of the digest. It is empty if the digest is not due.
+	DigestKey string
+	// Resolved is the list of the delivery keys that are not active anymore.
+	Resolved []string
+}
+
+// NewPlan compares the active alerts with the sent delivery keys of the user.
+// The digest is due once a day, after the digest hour.
+func NewPlan(subscriptions model.NotificationSubscriptions, alerts []*Alert, sentKeys []string, now time.Time, digestHour int) *Plan {
+	plan := &Plan{Immediate: []*Alert{}, Digest: []*Alert{}, Resolved: []string{}}
+	sent := map[string]bool{}
+	for _, key := range sentKeys {
+		sent[key] = true
+	}
+	digestKey := digestKeyPrefix + now.Format(model.DomainDateFormat)
+	digestDue := now.Hour() >= digestHour && !sent[digestKey]
+	if digestDue {
+		plan.DigestKey = digestKey
+	}
+	active := map[string]bool{}
+	for _, alert := range alerts {
+		subscription := subscriptions.ByRule(alert.Rule)
+		if !alert.Matches(subscription) {
+			continue
+		}
+		active[alert.Key] = true
+		if subscription.Digest {
+			if digestDue {
+				plan.Digest = append(plan.Digest, alert)
+			}
+			continue
+		}
+		if !sent[alert.Key] {
+			plan.Immediate = append(plan.Immediate, alert)
+		}
+	}
+	// the resolved alerts are forgotten, so that they are sent again if they become active.
+	// The digests of the previous days are also removed.
+	for _, key := range sentKeys {
+		if strings.HasPrefix(key, digestKeyPrefix) {
+			if key != digestKey {
+				plan.Resolved = append(plan.Resolved, key)
+			}
+			continue
+		}
+		if !active[key] {
+			plan.Resolved = append(plan.Resolved, key)
+		}
+	}
+	return plan
+}
+
+// Notifier type evaluates the notification rules and sends the emails.
+type Notifier struct {
+	repositoryContainer model.RepositoryContainer
+	mailer              mailer.Mailer
+	templates           *mailer.Templates
+	baseURL             string
+	digestHour          int
+	logger              *slog.Logger
+}
+
+// NewNotifier creates a new notifier.
+// The baseURL is the public url of the application, the digestHour is the hour of the day when the digest is sent.
+func NewNotifier(repositoryContainer model.RepositoryContainer, mailer mailer.Mailer, templates *mailer.Templates, baseURL string, digestHour int, logger *slog.Logger) *Notifier {
+	return &Notifier{
+		repositoryContainer: repositoryContainer,
+		mailer:              mailer,
+		templates:           templates,
+		baseURL:             strings.TrimSuffix(baseURL, "/"),
+		digestHour:          digestHour,
+		logger:              logger,
+	}
+}
+
+// Run evaluates the rules and sends the notifications to the subscribed users.
+// The failed user notifications are logged, the other users are still notified.
+func (n *Notifier) Run(now time.Time) error {
+	alerts, err := n.alerts(now)
+	if err != nil {
+		return err
+	}
+	subscriptions, err := n.repositoryContainer.GetNotificationSubscriptionRepository().GetNotificationSubscriptions(model.NewNotificationSubscriptionFilter())
+	if err != nil {
+		return fmt.Errorf("failed to get the subscriptions: %w", err)
+	}
+	// group the subscriptions by the users
+	userSubscriptions := map[int64]model.NotificationSubscriptions{}
+	for _, subscription := range *subscriptions {
+		userSubscriptions[subscription.UserID] = append(userSubscriptions[subscription.UserID], subscription)
+	}
+	if len(userSubscriptions) == 0 {
+		return nil
+	}
+	users, err := n.repositoryContainer.GetUserRepository().GetUsers(model.NewUserFilter())
+	if err != nil {
+		return fmt.Errorf("failed to get the users: %w", err)
+	}
+	for _, user := range users {
+		userSubscription, ok := userSubscriptions[user.ID]
+		if !ok || user.Email == "" {
+			continue
+		}
+		if err := n.notify(user, userSubscription, alerts, now); err != nil {
+			n.logger.Error("notification: failed to notify the user", "user_id", user.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// alerts returns the active alerts.
+func (n *Notifier) alerts(now time.Time) ([]*Alert, error) {
+	certificates, err := n.repositoryContainer.GetCertificateRepository().GetCertificates(model.NewCertificateFilter())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the certificates: %w", err)
+	}
+	domains, err := n.repositoryContainer.GetDomainRepository().GetDomains(model.NewDomainFilter())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the domains: %w", err)
+	}
+	applications, err := n.repositoryContainer.GetApplicationRepository().GetApplications(model.NewApplicationFilter())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the applications: %w", err)
+	}
+	return Evaluate(certificates, domains, applications, now, n.baseURL), nil
+}
+
+// notify sends the due notifications of the user and stores the deliveries.
+func (n *Notifier) notify(user *model.User, subscriptions model.NotificationSubscriptions, alerts []*Alert, now time.Time) error {
+	deliveryRepository := n.repositoryContainer.GetNotificationDeliveryRepository()
+	sentKeys, err := deliveryRepository.GetNotificationDeliveryKeys(user.ID)
+	if err != nil {
+		return err
+	}
+	plan := NewPlan(subscriptions, alerts, sentKeys, now, n.digestHour)
+	if len(plan.Immediate) > 0 {
+		subject := fmt.Sprintf("%d new alerts", len(plan.Immediate))
+		if len(plan.Immediate) == 1 {
+			subject = plan.Immediate[0].Subject
+		}
+		if err := n.send(user, subject, "The following alerts are triggered by your notification rules.", plan.Immediate); err != nil {
+			return err
+		}
+		for _, alert := range plan.Immediate {
+			if err := deliveryRepository.CreateNotificationDelivery(user.ID, alert.Key); err != nil {
+				return err
+			}
+		}
+	}
+	if plan.DigestKey != "" {
+		// the empty digest is not sent, but the day is marked as done.
+		if len(plan.Digest) > 0 {
+			subject := "Daily digest " + now.Format(model.DomainDateFormat)
+			if err := n.send(user, subject, "The following alerts are active.", plan.Digest); err != nil {
+				return err
+			}
+		}
+		if err := deliveryRepository.CreateNotificationDelivery(user.ID, plan.DigestKey); err != nil {
+			return err
+		}
+	}
+	for _, key := range plan.Resolved {
+		if err := deliveryRepository.DeleteNotificationDelivery(user.ID, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send renders and sends the notification email.
+func (n *Notifier) send(user *model.User, subject, intro string, alerts []*Alert) error {
+	data := &emailData{
+		Title:       subject,
+		Intro:       intro,
+		SettingsURL: n.baseURL + SettingsPath,
+		User:        user,
+		Alerts:      alerts,
+	}
+	text, html, err := n.templates.Render(TemplateName, data)
+	if err != nil {
+		return err
+	}
+	return n.mailer.Send(&mailer.Message{
+		To:      []string{user.Email},
+		Subject: subjectPrefix + subject,
+		Text:    text,
+		HTML:    html,
+	})
+}
@@ -0,0 +1,110 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// testAlerts returns the alerts of a certificate that expires in 10 days,
+// a failed ssl check and an application with score 30.
+func testAlerts(now time.Time) []*Alert {
+	certificates := &model.Certificates{
+		{ID: 1, Name: "example.com", NotAfter: now.Add(10 * 24 * time.Hour).Format(time.RFC3339)},
+		{ID: 2, Name: "invalid", NotAfter: ""},
+	}
+	domains := &model.Domains{
+		{ID: 1, Name: "example.com", HasSSL: false, SSLCheckedAt: "2026-10-18 10:00:00"},
+		{ID: 2, Name: "unchecked.com", HasSSL: false},
+		{ID: 3, Name: "secure.com", HasSSL: true, SSLCheckedAt: "2026-10-18 10:00:00"},
+	}
+	applications := &model.Applications{
+		{
+			ID:          1,
+			Client:      &model.Client{Name: "client"},
+			Project:     &model.Project{Name: "project"},
+			Environment: &model.Environment{Name: "prod", Score: 80},
+			Runtime:     &model.Runtime{Score: 30},
+			Framework:   &model.Framework{Score: 90},
+		},
+	}
+	return Evaluate(certificates, domains, applications, now, "http://localhost")
+}
+
+// TestEvaluate tests the alert generation.
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	alerts := testAlerts(now)
+	if len(alerts) != 3 {
+		t.Fatalf("Invalid number of alerts. Got: %d", len(alerts))
+	}
+	if alerts[0].Rule != model.NotificationRuleCertificateExpiry || alerts[0].Value != 10 || alerts[0].Link != "http://localhost/admin/certificate/view/1" {
+		t.Errorf("Invalid certificate alert: %+v", alerts[0])
+	}
+	if alerts[1].Rule != model.NotificationRuleSSLCheckFailed || alerts[1].Key != "ssl_check_failed:domain:1" {
+		t.Errorf("Invalid ssl alert: %+v", alerts[1])
+	}
+	if alerts[2].Rule != model.NotificationRuleApplicationScore || alerts[2].Value != 30 {
+		t.Errorf("Invalid application alert: %+v", alerts[2])
+	}
+}
+
+// TestAlertMatches tests the threshold handling of the subscriptions.
+func TestAlertMatches(t *testing.T) {
+	alert := &Alert{Rule: model.NotificationRuleCertificateExpiry, Value: 10}
+	testData := []struct {
+		subscription *model.NotificationSubscription
+		expected     bool
+	}{
+		{nil, false},
+		{&model.NotificationSubscription{Rule: model.NotificationRuleSSLCheckFailed}, false},
+		{&model.NotificationSubscription{Rule: model.NotificationRuleCertificateExpiry, Threshold: 14}, true},
+		{&model.NotificationSubscription{Rule: model.NotificationRuleCertificateExpiry, Threshold: 10}, false},
+	}
+	for _, tt := range testData {
+		if alert.Matches(tt.subscription) != tt.expected {
+			t.Errorf("Invalid match with %+v. Expected: %t", tt.subscription, tt.expected)
+		}
+	}
+}
+
+// TestNewPlanImmediate tests that the immediate alerts are sent only once, and the resolved ones are forgotten.
+func TestNewPlanImmediate(t *testing.T) {
+	now := time.Date(2026, 10, 18, 6, 0, 0, 0, time.UTC)
+	subscriptions := model.NotificationSubscriptions{
+		{Rule: model.NotificationRuleCertificateExpiry, Threshold: 14},
+		{Rule: model.NotificationRuleSSLCheckFailed},
+	}
+	sentKeys := []string{"ssl_check_failed:domain:1", "application_score:application:1", "digest:2026-10-17"}
+	plan := NewPlan(subscriptions, testAlerts(now), sentKeys, now, 8)
+	if len(plan.Immediate) != 1 || plan.Immediate[0].Key != "certificate_expiry:certificate:1" {
+		t.Errorf("Only the new alert has to be sent. Got: %+v", plan.Immediate)
+	}
+	if plan.DigestKey != "" || len(plan.Digest) != 0 {
+		t.Errorf("The digest is not due before the digest hour. Got: %+v", plan)
+	}
+	if len(plan.Resolved) != 2 || plan.Resolved[0] != "application_score:application:1" || plan.Resolved[1] != "digest:2026-10-17" {
+		t.Errorf("Invalid resolved keys. Got: %v", plan.Resolved)
+	}
+}
+
+// TestNewPlanDigest tests that the digest contains every active alert once a day.
+func TestNewPlanDigest(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	subscriptions := model.NotificationSubscriptions{
+		{Rule: model.NotificationRuleCertificateExpiry, Threshold: 14, Digest: true},
+		{Rule: model.NotificationRuleApplicationScore, Threshold: 50, Digest: true},
+	}
+	plan := NewPlan(subscriptions, testAlerts(now), []string{}, now, 8)
+	if len(plan.Immediate) != 0 {
+		t.Errorf("The digest alerts must not be sent immediately. Got: %+v", plan.Immediate)
+	}
+	if plan.DigestKey != "digest:2026-10-18" || len(plan.Digest) != 2 {
+		t.Errorf("Invalid digest. Got: %+v", plan)
+	}
+	plan = NewPlan(subscriptions, testAlerts(now), []string{"digest:2026-10-18"}, now, 8)
+	if plan.DigestKey != "" || len(plan.Digest) != 0 {
+		t.Errorf("The digest is sent once a day. Got: %+v", plan)
+	}
+}
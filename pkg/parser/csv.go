@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// DelimiterSemicolon is the semicolon delimiter, it is used if the delimiter could not be detected.
+	DelimiterSemicolon = "semicolon"
+	// DelimiterComma is the comma delimiter.
+	DelimiterComma = "comma"
+	// DelimiterTab is the tab delimiter.
+	DelimiterTab = "tab"
+
+	// delimiterSampleRows is the number of the rows that are used for the delimiter detection.
+	delimiterSampleRows = 10
+)
+
+var (
+	// CSVDelimiters are the selectable delimiters of the csv files. The empty delimiter is detected.
+	CSVDelimiters = []string{DelimiterSemicolon, DelimiterComma, DelimiterTab}
+
+	// delimiterRunes maps the delimiter names to the separator characters.
+	delimiterRunes = map[string]rune{
+		DelimiterSemicolon: ';',
+		DelimiterComma:     ',',
+		DelimiterTab:       '\t',
+	}
+)
+
+// DetectDelimiter returns the delimiter that splits the first rows to the same, and the most columns.
+// The semicolon is returned if none of them splits the rows.
+func DetectDelimiter(content string) string {
+	best, bestColumns := DelimiterSemicolon, 1
+	for _, delimiter := range CSVDelimiters {
+		reader := newCSVReader(strings.NewReader(content), delimiterRunes[delimiter])
+		columns, consistent := 0, true
+		for i := 0; i < delimiterSampleRows; i++ {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil || (i > 0 && len(record) != columns) {
+				consistent = false
+				break
+			}
+			columns = len(record)
+		}
+		if consistent && columns > bestColumns {
+			best, bestColumns = delimiter, columns
+		}
+	}
+	return best
+}
+
+// ParseCSV decodes the content with the encoding, and returns the records separated with the delimiter.
+// The empty delimiter and encoding are detected.
+// The rows could have different number of columns, the missing columns are reported by the mapping.
+func ParseCSV(content []byte, delimiter, encoding string) ([][]string, error) {
+	if _, ok := delimiterRunes[delimiter]; delimiter != "" && !ok {
+		return nil, fmt.Errorf("unknown delimiter %q", delimiter)
+	}
+	text, err := Decode(content, encoding)
+	if err != nil {
+		return nil, err
+	}
+	if delimiter == "" {
+		delimiter = DetectDelimiter(text)
+	}
+	return newCSVReader(strings.NewReader(text), delimiterRunes[delimiter]).ReadAll()
+}
+
+// newCSVReader returns a csv reader that accepts the ragged rows and the bare quotes in the fields.
+func newCSVReader(r io.Reader, delimiter rune) *csv.Reader {
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	return reader
+}
+
+// SplitList splits the list of the field with the separator, eg. the domains of an application.
+// The empty or whitespace separator splits at any whitespace. The items are trimmed and the empty ones are skipped.
+func SplitList(value, separator string) []string {
+	if strings.TrimSpace(separator) == "" {
+		return strings.Fields(value)
+	}
+	items := []string{}
+	for _, item := range strings.Split(value, separator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
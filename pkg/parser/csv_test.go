@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestDetectDelimiter tests that the delimiter with the most consistent columns is detected.
+func TestDetectDelimiter(t *testing.T) {
+	testData := []struct {
+		content  string
+		expected string
+	}{
+		{"client;project;domains\nc1;p1;a.hu b.hu\n", DelimiterSemicolon},
+		{"client,project,domains\nc1,p1,a.hu\n", DelimiterComma},
+		{"client\tproject\nc1\tp1\n", DelimiterTab},
+		// the commas of the quoted fields are not delimiters.
+		{"client;domains\nc1;\"a.hu,b.hu\"\nc2;\"c.hu,d.hu,e.hu\"\n", DelimiterSemicolon},
+		{"client,domains\n\"c1;c2\",a.hu\n", DelimiterComma},
+		{"client\nc1\n", DelimiterSemicolon},
+	}
+	for _, tt := range testData {
+		if got := DetectDelimiter(tt.content); got != tt.expected {
+			t.Errorf("DetectDelimiter(%q): expected %s, got %s", tt.content, tt.expected, got)
+		}
+	}
+}
+
+// TestParseCSV tests the encodings, the byte order mark and the ragged rows.
+func TestParseCSV(t *testing.T) {
+	testData := []struct {
+		name     string
+		content  []byte
+		options  ImportOptions
+		expected [][]string
+	}{
+		{"utf-8 with bom", []byte("\xEF\xBB\xBFclient,project\nGyőr,p1\n"), ImportOptions{}, [][]string{{"client", "project"}, {"Győr", "p1"}}},
+		{"detected windows-1250", []byte("client;project\nGy\xF5r \x8A\x9A;p1\n"), ImportOptions{}, [][]string{{"client", "project"}, {"Győr Šš", "p1"}}},
+		{"iso-8859-2", []byte("client;project\nGy\xF5r \xA9\xB9;p1\n"), ImportOptions{Encoding: EncodingISO88592}, [][]string{{"client", "project"}, {"Győr Šš", "p1"}}},
+		{"ragged rows", []byte("a;b;c\nd\ne;f\n"), ImportOptions{Delimiter: DelimiterSemicolon}, [][]string{{"a", "b", "c"}, {"d"}, {"e", "f"}}},
+		{"selected delimiter", []byte("a;b,c\n"), ImportOptions{Delimiter: DelimiterComma}, [][]string{{"a;b", "c"}}},
+	}
+	for _, tt := range testData {
+		records, err := ParseCSV(tt.content, tt.options.Delimiter, tt.options.Encoding)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(records, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, records)
+		}
+	}
+}
+
+// TestParseCSVErrors tests the unknown delimiter and encoding, and the invalid utf-8 content.
+func TestParseCSVErrors(t *testing.T) {
+	if _, err := ParseCSV([]byte("a\xF5"), "", EncodingUTF8); !errors.Is(err, ErrInvalidUTF8) {
+		t.Errorf("Expected invalid utf-8 error, got %v", err)
+	}
+	if _, err := ParseCSV([]byte("a"), "pipe", ""); err == nil {
+		t.Error("Expected unknown delimiter error.")
+	}
+	if _, err := ParseCSV([]byte("a"), "", "latin1"); err == nil {
+		t.Error("Expected unknown encoding error.")
+	}
+}
+
+// TestSplitList tests the list splitting with the whitespace and the custom separators.
+func TestSplitList(t *testing.T) {
+	testData := []struct {
+		value     string
+		separator string
+		expected  []string
+	}{
+		{"a.hu  b.hu\tc.hu", " ", []string{"a.hu", "b.hu", "c.hu"}},
+		{"a.hu, b.hu,,", ",", []string{"a.hu", "b.hu"}},
+		{"a.hu|b.hu", "", []string{"a.hu|b.hu"}},
+		{"", ",", []string{}},
+		{"", "", []string{}},
+	}
+	for _, tt := range testData {
+		got := SplitList(tt.value, tt.separator)
+		if len(got) != len(tt.expected) || (len(got) > 0 && !reflect.DeepEqual(got, tt.expected)) {
+			t.Errorf("SplitList(%q, %q): expected %v, got %v", tt.value, tt.separator, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// EncodingUTF8 is the utf-8 encoding, the byte order mark is removed.
+	EncodingUTF8 = "utf-8"
+	// EncodingWindows1250 is the central european windows code page, eg. the default of the hungarian excel exports.
+	EncodingWindows1250 = "windows-1250"
+	// EncodingISO88592 is the iso latin 2 encoding.
+	EncodingISO88592 = "iso-8859-2"
+)
+
+var (
+	// CSVEncodings are the selectable encodings of the csv files.
+	// The empty encoding is detected, the valid utf-8 content is utf-8, the others are windows-1250.
+	CSVEncodings = []string{EncodingUTF8, EncodingWindows1250, EncodingISO88592}
+	// ErrInvalidUTF8 is returned if the content of the utf-8 file is not valid utf-8.
+	ErrInvalidUTF8 = errors.New("the content is not valid utf-8")
+
+	// utf8BOM is the byte order mark of the utf-8 files, eg. the excel exports start with it.
+	utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+	// windows1250High is the unicode code points of the 0x80-0xFF bytes of the windows-1250.
+	// The undefined bytes are the replacement character.
+	windows1250High = [128]rune{
+		0x20AC, 0xFFFD, 0x201A, 0xFFFD, 0x201E, 0x2026, 0x2020, 0x2021, 0xFFFD, 0x2030, 0x0160, 0x2039, 0x015A, 0x0164, 0x017D, 0x0179,
+		0xFFFD, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014, 0xFFFD, 0x2122, 0x0161, 0x203A, 0x015B, 0x0165, 0x017E, 0x017A,
+		0x00A0, 0x02C7, 0x02D8, 0x0141, 0x00A4, 0x0104, 0x00A6, 0x00A7, 0x00A8, 0x00A9, 0x015E, 0x00AB, 0x00AC, 0x00AD, 0x00AE, 0x017B,
+		0x00B0, 0x00B1, 0x02DB, 0x0142, 0x00B4, 0x00B5, 0x00B6, 0x00B7, 0x00B8, 0x0105, 0x015F, 0x00BB, 0x013D, 0x02DD, 0x013E, 0x017C,
+		0x0154, 0x00C1, 0x00C2, 0x0102, 0x00C4, 0x0139, 0x0106, 0x00C7, 0x010C, 0x00C9, 0x0118, 0x00CB, 0x011A, 0x00CD, 0x00CE, 0x010E,
+		0x0110, 0x0143, 0x0147, 0x00D3, 0x00D4, 0x0150, 0x00D6, 0x00D7, 0x0158, 0x016E, 0x00DA, 0x0170, 0x00DC, 0x00DD, 0x0162, 0x00DF,
+		0x0155, 0x00E1, 0x00E2, 0x0103, 0x00E4, 0x013A, 0x0107, 0x00E7, 0x010D, 0x00E9, 0x0119, 0x00EB, 0x011B, 0x00ED, 0x00EE, 0x010F,
+		0x0111, 0x0144, 0x0148, 0x00F3, 0x00F4, 0x0151, 0x00F6, 0x00F7, 0x0159, 0x016F, 0x00FA, 0x0171, 0x00FC, 0x00FD, 0x0163, 0x02D9,
+	}
+	// iso88592High is the unicode code points of the 0x80-0xFF bytes of the iso-8859-2.
+	// The 0x80-0x9F bytes are the control characters, the 0xC0-0xFF bytes are the same as in the windows-1250.
+	iso88592High = [128]rune{
+		0x0080, 0x0081, 0x0082, 0x0083, 0x0084, 0x0085, 0x0086, 0x0087, 0x0088, 0x0089, 0x008A, 0x008B, 0x008C, 0x008D, 0x008E, 0x008F,
+		0x0090, 0x0091, 0x0092, 0x0093, 0x0094, 0x0095, 0x0096, 0x0097, 0x0098, 0x0099, 0x009A, 0x009B, 0x009C, 0x009D, 0x009E, 0x009F,
+		0x00A0, 0x0104, 0x02D8, 0x0141, 0x00A4, 0x013D, 0x015A, 0x00A7, 0x00A8, 0x0160, 0x015E, 0x0164, 0x0179, 0x00AD, 0x017D, 0x017B,
+		0x00B0, 0x0105, 0x02DB, 0x0142, 0x00B4, 0x013E, 0x015B, 0x02C7, 0x00B8, 0x0161, 0x015F, 0x0165, 0x017A, 0x02DD, 0x017E, 0x017C,
+		0x0154, 0x00C1, 0x00C2, 0x0102, 0x00C4, 0x0139, 0x0106, 0x00C7, 0x010C, 0x00C9, 0x0118, 0x00CB, 0x011A, 0x00CD, 0x00CE, 0x010E,
+		0x0110, 0x0143, 0x0147, 0x00D3, 0x00D4, 0x0150, 0x00D6, 0x00D7, 0x0158, 0x016E, 0x00DA, 0x0170, 0x00DC, 0x00DD, 0x0162, 0x00DF,
+		0x0155, 0x00E1, 0x00E2, 0x0103, 0x00E4, 0x013A, 0x0107, 0x00E7, 0x010D, 0x00E9, 0x0119, 0x00EB, 0x011B, 0x00ED, 0x00EE, 0x010F,
+		0x0111, 0x0144, 0x0148, 0x00F3, 0x00F4, 0x0151, 0x00F6, 0x00F7, 0x0159, 0x016F, 0x00FA, 0x0171, 0x00FC, 0x00FD, 0x0163, 0x02D9,
+	}
+)
+
+// DetectEncoding returns the utf-8 for the valid utf-8 content, otherwise the windows-1250.
+func DetectEncoding(content []byte) string {
+	if utf8.Valid(content) {
+		return EncodingUTF8
+	}
+	return EncodingWindows1250
+}
+
+// Decode converts the content of the encoding to string. The empty encoding is detected.
+// The utf-8 byte order mark is removed.
+func Decode(content []byte, encoding string) (string, error) {
+	if encoding == "" {
+		encoding = DetectEncoding(content)
+	}
+	switch strings.ToLower(encoding) {
+	case EncodingUTF8:
+		content = bytes.TrimPrefix(content, utf8BOM)
+		if !utf8.Valid(content) {
+			return "", ErrInvalidUTF8
+		}
+		return string(content), nil
+	case EncodingWindows1250:
+		return decodeSingleByte(content, &windows1250High), nil
+	case EncodingISO88592:
+		return decodeSingleByte(content, &iso88592High), nil
+	}
+	return "", fmt.Errorf("unknown encoding %q", encoding)
+}
+
+// decodeSingleByte converts the content of a single byte encoding, the ascii bytes are kept.
+func decodeSingleByte(content []byte, high *[128]rune) string {
+	var builder strings.Builder
+	builder.Grow(len(content))
+	for _, b := range content {
+		if b < 0x80 {
+			builder.WriteByte(b)
+			continue
+		}
+		builder.WriteRune(high[b-0x80])
+	}
+	return builder.String()
+}
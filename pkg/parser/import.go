@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// FormatCSV is the format of the delimited text files.
+	FormatCSV = "csv"
+	// FormatXLSX is the format of the excel workbooks.
+	FormatXLSX = "xlsx"
+	// FormatJSON is the format of the json arrays of objects.
+	FormatJSON = "json"
+)
+
+var (
+	// ImportFormats are the supported formats of the import files.
+	ImportFormats = []string{FormatCSV, FormatXLSX, FormatJSON}
+	// ErrInvalidFile is returned if the content could not be parsed in the format of the file.
+	ErrInvalidFile = errors.New("invalid import file")
+)
+
+// ImportOptions are the parsing options of the import files.
+// The delimiter and the encoding are used by the csv files, the sheet by the xlsx files.
+// The empty format is the csv, the empty delimiter and encoding are detected, and the empty sheet is the first one.
+type ImportOptions struct {
+	Format    string `json:"format"`
+	Delimiter string `json:"delimiter"`
+	Encoding  string `json:"encoding"`
+	Sheet     string `json:"sheet"`
+}
+
+// Validate checks that the options are known format, delimiter and encoding names.
+func (o ImportOptions) Validate() error {
+	if !contains(ImportFormats, o.Format) {
+		return fmt.Errorf("unknown format %q", o.Format)
+	}
+	if !contains(CSVDelimiters, o.Delimiter) {
+		return fmt.Errorf("unknown delimiter %q", o.Delimiter)
+	}
+	if !contains(CSVEncodings, o.Encoding) {
+		return fmt.Errorf("unknown encoding %q", o.Encoding)
+	}
+	return nil
+}
+
+// HasHeader returns true if the first row of the parsed file is always the header, eg. the keys of the json objects.
+func (o ImportOptions) HasHeader() bool {
+	return o.Format == FormatJSON
+}
+
+// ParseImport parses the content in the format of the options, and returns the rows.
+// The first row of the json files is the header. The parse errors are ErrInvalidFile.
+func ParseImport(content []byte, options ImportOptions) ([][]string, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	var rows [][]string
+	var err error
+	switch options.Format {
+	case FormatXLSX:
+		rows, err = ParseXLSX(content, options.Sheet)
+	case FormatJSON:
+		rows, err = ParseJSON(content)
+	default:
+		rows, err = ParseCSV(content, options.Delimiter, options.Encoding)
+	}
+	if errors.Is(err, ErrInvalidFile) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+	return rows, nil
+}
+
+// contains returns true if the value is empty or it is one of the names.
+func contains(names []string, value string) bool {
+	if value == "" {
+		return true
+	}
+	for _, name := range names {
+		if name == value {
+			return true
+		}
+	}
+	return false
+}
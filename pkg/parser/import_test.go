@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestImportOptionsValidate tests the known and the unknown option names.
+func TestImportOptionsValidate(t *testing.T) {
+	valid := []ImportOptions{
+		{},
+		{Format: FormatCSV, Delimiter: DelimiterTab, Encoding: EncodingWindows1250},
+		{Format: FormatXLSX, Sheet: "Applications"},
+		{Format: FormatJSON},
+	}
+	for _, options := range valid {
+		if err := options.Validate(); err != nil {
+			t.Errorf("%+v: unexpected error: %v", options, err)
+		}
+	}
+	invalid := []ImportOptions{{Format: "xls"}, {Delimiter: "pipe"}, {Encoding: "latin1"}}
+	for _, options := range invalid {
+		if err := options.Validate(); err == nil {
+			t.Errorf("%+v: expected error", options)
+		}
+	}
+}
+
+// TestParseImport tests that the formats are parsed to the same rows, and the parse errors are invalid file errors.
+func TestParseImport(t *testing.T) {
+	expected := [][]string{{"client", "project"}, {"c1", "p1"}}
+	testData := []struct {
+		content []byte
+		options ImportOptions
+	}{
+		{[]byte("client;project\nc1;p1\n"), ImportOptions{}},
+		{[]byte("client,project\nc1,p1\n"), ImportOptions{Format: FormatCSV, Delimiter: DelimiterComma}},
+		{[]byte(`[{"client": "c1", "project": "p1"}]`), ImportOptions{Format: FormatJSON}},
+	}
+	for _, tt := range testData {
+		rows, err := ParseImport(tt.content, tt.options)
+		if err != nil || !reflect.DeepEqual(rows, expected) {
+			t.Errorf("%+v: expected %v, got %v, %v", tt.options, expected, rows, err)
+		}
+	}
+	rows, err := ParseImport(testWorkbook(t), ImportOptions{Format: FormatXLSX, Sheet: "Servers"})
+	if err != nil || !reflect.DeepEqual(rows, [][]string{{"web1"}}) {
+		t.Errorf("xlsx: unexpected rows %v, %v", rows, err)
+	}
+	if _, err := ParseImport([]byte("client"), ImportOptions{Format: FormatXLSX}); !errors.Is(err, ErrInvalidFile) {
+		t.Errorf("Expected invalid file error, got %v", err)
+	}
+	if _, err := ParseImport([]byte("client"), ImportOptions{Format: "xls"}); err == nil || errors.Is(err, ErrInvalidFile) {
+		t.Errorf("Expected unknown format error, got %v", err)
+	}
+	if !(ImportOptions{Format: FormatJSON}).HasHeader() || (ImportOptions{Format: FormatXLSX}).HasHeader() {
+		t.Error("Expected only the json files to have header.")
+	}
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidJSON is returned if the json content is not an array of objects.
+var ErrInvalidJSON = errors.New("the json has to be an array of objects")
+
+// ParseJSON converts the array of objects to rows. The first row is the keys of the objects in the order of their
+// first appearance, and the other rows are the values of the objects. The missing keys are empty values,
+// the lists are separated with space, eg. the domains, and the nested objects are kept as json.
+func ParseJSON(content []byte) ([][]string, error) {
+	content = bytes.TrimPrefix(content, utf8BOM)
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, ErrInvalidJSON
+	}
+	keys := []string{}
+	keyIndexes := map[string]int{}
+	objects := []map[string]string{}
+	for decoder.More() {
+		if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+			return nil, ErrInvalidJSON
+		}
+		object := map[string]string{}
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := token.(string)
+			var value interface{}
+			if err := decoder.Decode(&value); err != nil {
+				return nil, err
+			}
+			if _, ok := keyIndexes[key]; !ok {
+				keyIndexes[key] = len(keys)
+				keys = append(keys, key)
+			}
+			if object[key], err = jsonValue(value); err != nil {
+				return nil, err
+			}
+		}
+		// the closing brace of the object.
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		objects = append(objects, object)
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	rows := [][]string{keys}
+	for _, object := range objects {
+		row := make([]string, len(keys))
+		for key, value := range object {
+			row[keyIndexes[key]] = value
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// jsonValue converts the decoded json value to the field of the row.
+func jsonValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case []interface{}:
+		items := []string{}
+		for _, item := range v {
+			field, err := jsonValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, field)
+		}
+		return strings.Join(items, " "), nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode the value: %w", err)
+	}
+	return string(encoded), nil
+}
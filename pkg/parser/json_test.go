@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestParseJSON tests the header of the keys, the missing keys and the value conversions.
+func TestParseJSON(t *testing.T) {
+	content := []byte("\xEF\xBB\xBF" + `[
+		{"client": "Győr", "project": "p1", "domains": ["a.hu", "b.hu"], "port": 8080},
+		{"project": "p2", "client": null, "active": true, "extra": {"a": 1}}
+	]`)
+	rows, err := ParseJSON(content)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := [][]string{
+		{"client", "project", "domains", "port", "active", "extra"},
+		{"Győr", "p1", "a.hu b.hu", "8080", "", ""},
+		{"", "p2", "", "", "true", `{"a":1}`},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected %v, got %v", expected, rows)
+	}
+	rows, err = ParseJSON([]byte("[]"))
+	if err != nil || !reflect.DeepEqual(rows, [][]string{{}}) {
+		t.Errorf("Empty array: expected only the empty header, got %v, %v", rows, err)
+	}
+}
+
+// TestParseJSONErrors tests that only the arrays of objects are accepted.
+func TestParseJSONErrors(t *testing.T) {
+	for _, content := range []string{`{"client": "c1"}`, `["c1"]`, `[{"client": "c1"}`, ``} {
+		if _, err := ParseJSON([]byte(content)); err == nil {
+			t.Errorf("ParseJSON(%s): expected error", content)
+		}
+	}
+	if _, err := ParseJSON([]byte(`[1]`)); !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("Expected invalid json error, got %v", err)
+	}
+}
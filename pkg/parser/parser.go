@@ -1,6 +1,10 @@
 package parser
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/akosgarai/projectregister/pkg/model"
 )
 
@@ -54,6 +58,52 @@ func NewMappingRule() *MappingRule {
 	}
 }
 
+// ImportRow is the mapped row of the generic import.
+// The row data maps the field names to the mapped values, the error message is set if the row could not be mapped.
+type ImportRow struct {
+	ErrorMessage string
+	RowData      map[string]string
+}
+
+// ImportMapping is the struct for the generic import mapping.
+// The key is the field name of the imported resource, the value is the mapping rule.
+type ImportMapping map[string]*MappingRule
+
+// NewImportMapping is a constructor for the ImportMapping struct.
+// It maps the fields to the -1 index.
+func NewImportMapping(fields []string) ImportMapping {
+	mapping := ImportMapping{}
+	for _, field := range fields {
+		mapping[field] = NewMappingRule()
+	}
+	return mapping
+}
+
+// MapRow maps the row data to the import row.
+// The mapped columns that are missing from the row are listed in the error message of the import row.
+func (m ImportMapping) MapRow(row []string) *ImportRow {
+	importRow := &ImportRow{RowData: map[string]string{}}
+	missing := []string{}
+	for key, rule := range m {
+		if rule.ColumnIndex == -1 {
+			importRow.RowData[key] = rule.CustomValue
+		} else if rule.ColumnIndex < 0 || rule.ColumnIndex >= len(row) {
+			importRow.RowData[key] = ""
+			missing = append(missing, fmt.Sprintf("%s (column %d)", key, rule.ColumnIndex+1))
+		} else {
+			importRow.RowData[key] = row[rule.ColumnIndex]
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		importRow.ErrorMessage = fmt.Sprintf("the row has %d columns, missing: %s", len(row), strings.Join(missing, ", "))
+	}
+	return importRow
+}
+
+// ApplicationImportFields are the mapped fields of the application import in the order of the mapping form.
+var ApplicationImportFields = []string{"client", "project", "runtime", "pool", "domains", "framework", "database", "database_name", "database_user", "doc_root", "repository", "branch"}
+
 // ApplicationImportMapping is the struct for the application import mapping.
 // It contains the mapping of the column names and the column indexes.
 // The key is the column name, the value is the mapping rule.
@@ -63,32 +113,16 @@ type ApplicationImportMapping map[string]*MappingRule
 // It returns a new ApplicationImportMapping instance with default (empty) values.
 // Maps the column names to the -1 index.
 func NewApplicationImportMapping() ApplicationImportMapping {
-	return ApplicationImportMapping{
-		"client":        NewMappingRule(),
-		"project":       NewMappingRule(),
-		"runtime":       NewMappingRule(),
-		"pool":          NewMappingRule(),
-		"domains":       NewMappingRule(),
-		"framework":     NewMappingRule(),
-		"database":      NewMappingRule(),
-		"database_name": NewMappingRule(),
-		"database_user": NewMappingRule(),
-		"doc_root":      NewMappingRule(),
-		"repository":    NewMappingRule(),
-		"branch":        NewMappingRule(),
-	}
+	return ApplicationImportMapping(NewImportMapping(ApplicationImportFields))
 }
 
 // MapRow maps the row data to the application import row.
 // It uses the mapping to set the values.
+// The mapped columns that are missing from the row are listed in the error message of the import row.
 func (m ApplicationImportMapping) MapRow(row []string) *ApplicationImportRow {
+	mappedRow := ImportMapping(m).MapRow(row)
 	importRow := NewApplicationImportRow()
-	for key, rule := range m {
-		if rule.ColumnIndex == -1 {
-			importRow.RowData[key] = rule.CustomValue
-		} else {
-			importRow.RowData[key] = row[rule.ColumnIndex]
-		}
-	}
+	importRow.RowData = mappedRow.RowData
+	importRow.ErrorMessage = mappedRow.ErrorMessage
 	return importRow
 }
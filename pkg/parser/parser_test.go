@@ -140,3 +140,41 @@ func TestMapRowWithMapping(t *testing.T) {
 		t.Errorf("The branch value is not correct.")
 	}
 }
+
+// TestMapRowMissingColumns tests that the short rows are reported in the error message instead of a panic.
+func TestMapRowMissingColumns(t *testing.T) {
+	mapping := NewApplicationImportMapping()
+	mapping["client"].ColumnIndex = 0
+	mapping["project"].ColumnIndex = 1
+	mapping["domains"].ColumnIndex = 4
+	importRow := mapping.MapRow([]string{"client"})
+	expected := "the row has 1 columns, missing: domains (column 5), project (column 2)"
+	if importRow.ErrorMessage != expected {
+		t.Errorf("Expected %s, got %s", expected, importRow.ErrorMessage)
+	}
+	if importRow.RowData["client"] != "client" || importRow.RowData["project"] != "" {
+		t.Errorf("Unexpected row data: %v", importRow.RowData)
+	}
+}
+
+// TestImportMappingMapRow tests the generic mapping with the custom values and the missing columns.
+func TestImportMappingMapRow(t *testing.T) {
+	mapping := NewImportMapping([]string{"name", "remote_address", "runtimes"})
+	if len(mapping) != 3 || mapping["name"].ColumnIndex != -1 {
+		t.Fatalf("Unexpected mapping: %v", mapping)
+	}
+	mapping["name"].ColumnIndex = 0
+	mapping["remote_address"].ColumnIndex = 2
+	mapping["runtimes"].CustomValue = "php8.3 nodejs"
+	importRow := mapping.MapRow([]string{"web1", "ignored", "10.0.0.1"})
+	if importRow.ErrorMessage != "" {
+		t.Errorf("Unexpected error message: %s", importRow.ErrorMessage)
+	}
+	if importRow.RowData["name"] != "web1" || importRow.RowData["remote_address"] != "10.0.0.1" || importRow.RowData["runtimes"] != "php8.3 nodejs" {
+		t.Errorf("Unexpected row data: %v", importRow.RowData)
+	}
+	importRow = mapping.MapRow([]string{"web2"})
+	if expected := "the row has 1 columns, missing: remote_address (column 3)"; importRow.ErrorMessage != expected {
+		t.Errorf("Expected %s, got %s", expected, importRow.ErrorMessage)
+	}
+}
@@ -0,0 +1,226 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// xlsxMaxPartSize is the maximum uncompressed size of the parts of the workbook, so that the zip bombs are rejected.
+	xlsxMaxPartSize = 64 << 20
+	// xlsxMaxColumns is the maximum number of the columns of the imported sheets.
+	// The rows are padded to the column of their last cell, so a far right cell reference would allocate the whole row.
+	xlsxMaxColumns = 1024
+	// xlsxMaxCells is the maximum number of the cells of the imported sheets, including the padding of the skipped cells.
+	xlsxMaxCells = 1 << 21
+	// xlsxWorkbookPath is the path of the workbook part in the archive.
+	xlsxWorkbookPath = "xl/workbook.xml"
+	// xlsxRelationshipsPath is the path of the relationships of the workbook part.
+	xlsxRelationshipsPath = "xl/_rels/workbook.xml.rels"
+	// xlsxSharedStringsPath is the path of the shared strings part, the workbooks without text cells do not have it.
+	xlsxSharedStringsPath = "xl/sharedStrings.xml"
+)
+
+// ErrSheetNotFound is returned if the selected sheet is not in the workbook.
+var ErrSheetNotFound = errors.New("sheet not found")
+
+// xlsxWorkbook is the list of the sheets of the workbook part.
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// xlsxRelationships maps the relationship ids of the workbook to the parts.
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText is a text with the rich text runs.
+type xlsxText struct {
+	T string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+// String returns the text with the runs.
+func (t xlsxText) String() string {
+	text := t.T
+	for _, run := range t.R {
+		text += run.T
+	}
+	return text
+}
+
+// xlsxSharedStrings is the shared strings part, the text cells refer to them with their index.
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+// xlsxWorksheet is the cell data of a worksheet part.
+type xlsxWorksheet struct {
+	Rows []struct {
+		Cells []struct {
+			Reference string   `xml:"r,attr"`
+			Type      string   `xml:"t,attr"`
+			Value     string   `xml:"v"`
+			Inline    xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ParseXLSX returns the rows of the sheet of the workbook. The empty sheet name is the first sheet.
+// The cells are the displayed text of the strings, the numbers are not formatted, eg. the dates are serial numbers.
+// The sheets with more than xlsxMaxColumns columns or xlsxMaxCells cells are ErrInvalidFile.
+func ParseXLSX(content []byte, sheet string) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+	var workbook xlsxWorkbook
+	if err := xlsxDecode(archive, xlsxWorkbookPath, &workbook); err != nil {
+		return nil, err
+	}
+	if len(workbook.Sheets) == 0 {
+		return nil, ErrSheetNotFound
+	}
+	relationshipID := ""
+	names := []string{}
+	for _, s := range workbook.Sheets {
+		if (sheet == "" && relationshipID == "") || s.Name == sheet {
+			relationshipID = s.ID
+		}
+		names = append(names, s.Name)
+	}
+	if relationshipID == "" {
+		return nil, fmt.Errorf("%w: %q, the sheets are: %s", ErrSheetNotFound, sheet, strings.Join(names, ", "))
+	}
+	var relationships xlsxRelationships
+	if err := xlsxDecode(archive, xlsxRelationshipsPath, &relationships); err != nil {
+		return nil, err
+	}
+	sheetPath := ""
+	for _, relationship := range relationships.Relationships {
+		if relationship.ID == relationshipID {
+			sheetPath = xlsxPartPath(relationship.Target)
+		}
+	}
+	var sharedStrings xlsxSharedStrings
+	if err := xlsxDecode(archive, xlsxSharedStringsPath, &sharedStrings); err != nil && !errors.Is(err, errXLSXPartNotFound) {
+		return nil, err
+	}
+	var worksheet xlsxWorksheet
+	if err := xlsxDecode(archive, sheetPath, &worksheet); err != nil {
+		return nil, err
+	}
+	rows := [][]string{}
+	cells := 0
+	for _, sheetRow := range worksheet.Rows {
+		row := []string{}
+		for _, cell := range sheetRow.Cells {
+			column := len(row)
+			if cell.Reference != "" {
+				if column, err = xlsxColumnIndex(cell.Reference); err != nil {
+					return nil, err
+				}
+			}
+			if column >= xlsxMaxColumns {
+				return nil, fmt.Errorf("%w: the sheet has more than %d columns", ErrInvalidFile, xlsxMaxColumns)
+			}
+			if column >= len(row) {
+				// the row is padded to the column of the cell.
+				if cells += column + 1 - len(row); cells > xlsxMaxCells {
+					return nil, fmt.Errorf("%w: the sheet has more than %d cells", ErrInvalidFile, xlsxMaxCells)
+				}
+			}
+			value := cell.Value
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err != nil || index < 0 || index >= len(sharedStrings.Items) {
+					return nil, fmt.Errorf("invalid shared string %q in %s", cell.Value, cell.Reference)
+				}
+				value = sharedStrings.Items[index].String()
+			case "inlineStr":
+				value = cell.Inline.String()
+			case "b":
+				value = strconv.FormatBool(cell.Value == "1")
+			}
+			for len(row) < column {
+				row = append(row, "")
+			}
+			if column < len(row) {
+				row[column] = value
+			} else {
+				row = append(row, value)
+			}
+		}
+		// the empty rows are counted as a cell, so that the number of the rows is also limited.
+		if len(row) == 0 {
+			if cells++; cells > xlsxMaxCells {
+				return nil, fmt.Errorf("%w: the sheet has more than %d cells", ErrInvalidFile, xlsxMaxCells)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// errXLSXPartNotFound is returned if the part is missing from the archive.
+var errXLSXPartNotFound = errors.New("the part is missing from the workbook")
+
+// xlsxDecode decodes the xml part of the archive. The part is limited to the maximum part size.
+func xlsxDecode(archive *zip.Reader, name string, v interface{}) error {
+	for _, file := range archive.File {
+		if file.Name != name {
+			continue
+		}
+		if file.UncompressedSize64 > xlsxMaxPartSize {
+			return fmt.Errorf("the %s is too large", name)
+		}
+		r, err := file.Open()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		return xml.NewDecoder(io.LimitReader(r, xlsxMaxPartSize)).Decode(v)
+	}
+	return fmt.Errorf("%w: %s", errXLSXPartNotFound, name)
+}
+
+// xlsxPartPath returns the archive path of the relationship target. The relative targets are in the xl directory.
+func xlsxPartPath(target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join("xl", target)
+}
+
+// xlsxColumnIndex returns the 0 based column index of the cell reference, eg. 1 for B7.
+func xlsxColumnIndex(reference string) (int, error) {
+	index := 0
+	letters := 0
+	for _, r := range reference {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A'+1)
+		letters++
+	}
+	if letters == 0 || letters > 3 {
+		return 0, fmt.Errorf("invalid cell reference %q", reference)
+	}
+	return index - 1, nil
+}
@@ -0,0 +1,136 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testWorkbook returns an xlsx archive with a Servers and an Applications sheet.
+// The second sheet has shared strings, rich text, inline strings, booleans, numbers and skipped cells.
+func testWorkbook(t *testing.T) []byte {
+	t.Helper()
+	return testArchive(t, testWorkbookParts())
+}
+
+// testWorkbookParts returns the parts of the test workbook.
+func testWorkbookParts() map[string]string {
+	return map[string]string{
+		"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Servers" sheetId="1" r:id="rId1"/><sheet name="Applications" sheetId="2" r:id="rId2"/></sheets>
+</workbook>`,
+		"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet2.xml"/>
+</Relationships>`,
+		"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>client</t></si><si><t>project</t></si><si><r><t>Gy</t></r><r><t>őr</t></r></si><si><t>web1</t></si>
+</sst>`,
+		"xl/worksheets/sheet1.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>3</v></c></row>
+</sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>
+<row r="2"><c r="A2" t="s"><v>2</v></c><c r="C2" t="inlineStr"><is><t>a.hu b.hu</t></is></c></row>
+<row r="3"><c r="B3"><v>42.5</v></c><c r="AA3" t="b"><v>1</v></c></row>
+</sheetData></worksheet>`,
+	}
+}
+
+// testArchive returns the zip archive of the parts.
+func testArchive(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	for name, content := range parts {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("Failed to close the archive: %v", err)
+	}
+	return buffer.Bytes()
+}
+
+// TestParseXLSX tests the sheet selection and the cell types.
+func TestParseXLSX(t *testing.T) {
+	content := testWorkbook(t)
+	rows, err := ParseXLSX(content, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := [][]string{{"web1"}}; !reflect.DeepEqual(rows, expected) {
+		t.Errorf("First sheet: expected %v, got %v", expected, rows)
+	}
+	rows, err = ParseXLSX(content, "Applications")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	thirdRow := make([]string, 27)
+	thirdRow[1], thirdRow[26] = "42.5", "true"
+	expected := [][]string{{"client", "project"}, {"Győr", "", "a.hu b.hu"}, thirdRow}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Applications sheet: expected %v, got %v", expected, rows)
+	}
+}
+
+// TestParseXLSXErrors tests the missing sheet and the invalid archive.
+func TestParseXLSXErrors(t *testing.T) {
+	if _, err := ParseXLSX(testWorkbook(t), "Domains"); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("Expected sheet not found error, got %v", err)
+	}
+	if _, err := ParseXLSX([]byte("client;project\n"), ""); err == nil {
+		t.Error("Expected invalid archive error.")
+	}
+}
+
+// TestParseXLSXLimits tests that the far right cell references and the too many cells are rejected before the rows are padded.
+func TestParseXLSXLimits(t *testing.T) {
+	parts := testWorkbookParts()
+	parts["xl/worksheets/sheet1.xml"] = `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="XFD1"><v>1</v></c></row>
+</sheetData></worksheet>`
+	if _, err := ParseXLSX(testArchive(t, parts), ""); !errors.Is(err, ErrInvalidFile) {
+		t.Errorf("Far right cell: expected invalid file error, got %v", err)
+	}
+	tooManyCells := strings.Repeat(`<row><c r="AMJ1"><v>1</v></c></row>`, xlsxMaxCells/xlsxMaxColumns+1)
+	parts["xl/worksheets/sheet1.xml"] = `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + tooManyCells + `</sheetData></worksheet>`
+	if _, err := ParseXLSX(testArchive(t, parts), ""); !errors.Is(err, ErrInvalidFile) {
+		t.Errorf("Too many cells: expected invalid file error, got %v", err)
+	}
+	parts["xl/worksheets/sheet1.xml"] = `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="AMJ1"><v>1</v></c></row>
+</sheetData></worksheet>`
+	rows, err := ParseXLSX(testArchive(t, parts), "")
+	if err != nil || len(rows) != 1 || len(rows[0]) != xlsxMaxColumns {
+		t.Errorf("Last allowed column: unexpected result %d rows, %v", len(rows), err)
+	}
+}
+
+// TestXLSXColumnIndex tests the conversion of the cell references.
+func TestXLSXColumnIndex(t *testing.T) {
+	testData := map[string]int{"A1": 0, "B7": 1, "Z2": 25, "AA3": 26, "AZ1": 51, "XFD1": 16383}
+	for reference, expected := range testData {
+		if got, err := xlsxColumnIndex(reference); err != nil || got != expected {
+			t.Errorf("xlsxColumnIndex(%s): expected %d, got %d, %v", reference, expected, got, err)
+		}
+	}
+	for _, reference := range []string{"1", "ABCD1", ""} {
+		if _, err := xlsxColumnIndex(reference); err == nil {
+			t.Errorf("xlsxColumnIndex(%s): expected error", reference)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package rdap
+
+// This package contains the RDAP (Registration Data Access Protocol) domain lookup.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// eventRegistration is the event action of the registration date.
+	eventRegistration = "registration"
+	// eventExpiration is the event action of the expiration date.
+	eventExpiration = "expiration"
+	// roleRegistrar is the role of the registrar entity.
+	roleRegistrar = "registrar"
+)
+
+var (
+	// ErrNotFound is returned if the registry does not know the domain.
+	ErrNotFound = errors.New("the domain is not found in the registry")
+)
+
+// Registration type is the registration data of a domain.
+// The dates are zero if the registry does not publish them.
+type Registration struct {
+	Registrar    string
+	RegisteredAt time.Time
+	ExpiresAt    time.Time
+}
+
+// Lookup interface is implemented by the registration data sources.
+type Lookup interface {
+	LookupDomain(ctx context.Context, name string) (*Registration, error)
+}
+
+// Client type is the RDAP client. It implements the Lookup interface.
+// The base url is the url of an RDAP server or bootstrap redirector, like https://rdap.org.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new RDAP client.
+func NewClient(baseURL string, timeout time.Duration) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// domainResponse is the relevant part of the RDAP domain response.
+type domainResponse struct {
+	Events []struct {
+		EventAction string `json:"eventAction"`
+		EventDate   string `json:"eventDate"`
+	} `json:"events"`
+	Entities []struct {
+		Roles      []string        `json:"roles"`
+		VCardArray json.RawMessage `json:"vcardArray"`
+		PublicIDs  []struct {
+			Identifier string `json:"identifier"`
+		} `json:"publicIds"`
+	} `json:"entities"`
+}
+
+// LookupDomain gets the registration data of the domain.
+func (c *Client) LookupDomain(ctx context.Context, name string) (*Registration, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/domain/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/rdap+json")
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected RDAP response status: %d", response.StatusCode)
+	}
+	var data domainResponse
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	registration := &Registration{}
+	for _, event := range data.Events {
+		date, err := time.Parse(time.RFC3339, event.EventDate)
+		if err != nil {
+			continue
+		}
+		switch event.EventAction {
+		case eventRegistration:
+			registration.RegisteredAt = date
+		case eventExpiration:
+			registration.ExpiresAt = date
+		}
+	}
+	for _, entity := range data.Entities {
+		if !hasRole(entity.Roles, roleRegistrar) {
+			continue
+		}
+		registration.Registrar = vCardName(entity.VCardArray)
+		if registration.Registrar == "" && len(entity.PublicIDs) > 0 {
+			registration.Registrar = entity.PublicIDs[0].Identifier
+		}
+		break
+	}
+	return registration, nil
+}
+
+// LookupRegistrable gets the registration data of the domain. If the registry does not know the name,
+// the lookup is repeated with the parent domains, so the subdomains resolve to their registered domain.
+func LookupRegistrable(ctx context.Context, lookup Lookup, name string) (*Registration, error) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	for {
+		registration, err := lookup.LookupDomain(ctx, name)
+		if !errors.Is(err, ErrNotFound) {
+			return registration, err
+		}
+		_, parent, found := strings.Cut(name, ".")
+		// the top level domains are not looked up
+		if !found || !strings.Contains(parent, ".") {
+			return nil, err
+		}
+		name = parent
+	}
+}
+
+// hasRole checks if the role list contains the role.
+func hasRole(roles []string, role string) bool {
+	for _, current := range roles {
+		if current == role {
+			return true
+		}
+	}
+	return false
+}
+
+// vCardName returns the formatted name (fn) of the jCard array.
+// The format is ["vcard", [["fn", {}, "text", "Name"], ...]].
+func vCardName(raw json.RawMessage) string {
+	var vcard []json.RawMessage
+	if err := json.Unmarshal(raw, &vcard); err != nil || len(vcard) < 2 {
+		return ""
+	}
+	var properties [][]json.RawMessage
+	if err := json.Unmarshal(vcard[1], &properties); err != nil {
+		return ""
+	}
+	for _, property := range properties {
+		if len(property) < 4 {
+			continue
+		}
+		var propertyName, value string
+		if json.Unmarshal(property[0], &propertyName) != nil || propertyName != "fn" {
+			continue
+		}
+		if json.Unmarshal(property[3], &value) == nil {
+			return value
+		}
+	}
+	return ""
+}
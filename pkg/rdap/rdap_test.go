@@ -0,0 +1,99 @@
+package rdap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testDomainResponse = `{
+	"objectClassName": "domain",
+	"ldhName": "example.com",
+	"events": [
+		{"eventAction": "registration", "eventDate": "1995-08-14T04:00:00Z"},
+		{"eventAction": "expiration", "eventDate": "2025-08-13T04:00:00Z"},
+		{"eventAction": "last changed", "eventDate": "2024-08-14T07:01:34Z"}
+	],
+	"entities": [
+		{"roles": ["technical"], "vcardArray": ["vcard", [["fn", {}, "text", "Tech Contact"]]]},
+		{"roles": ["registrar"], "publicIds": [{"type": "IANA Registrar ID", "identifier": "376"}],
+		 "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Example Registrar, Inc."]]]}
+	]
+}`
+
+// newTestServer returns a local RDAP server that knows only the example.com domain.
+func newTestServer(t *testing.T) (*httptest.Server, *[]string) {
+	requested := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		switch r.URL.Path {
+		case "/domain/example.com":
+			w.Header().Set("Content-Type", "application/rdap+json")
+			w.Write([]byte(testDomainResponse))
+		case "/domain/broken.com":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requested
+}
+
+// TestLookupDomain tests the parsing of the RDAP domain response.
+func TestLookupDomain(t *testing.T) {
+	server, _ := newTestServer(t)
+	client := NewClient(server.URL+"/", 5*time.Second)
+	registration, err := client.LookupDomain(context.Background(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if registration.Registrar != "Example Registrar, Inc." {
+		t.Errorf("Registrar is not set properly. Got: %s", registration.Registrar)
+	}
+	if !registration.RegisteredAt.Equal(time.Date(1995, 8, 14, 4, 0, 0, 0, time.UTC)) {
+		t.Errorf("RegisteredAt is not set properly. Got: %v", registration.RegisteredAt)
+	}
+	if !registration.ExpiresAt.Equal(time.Date(2025, 8, 13, 4, 0, 0, 0, time.UTC)) {
+		t.Errorf("ExpiresAt is not set properly. Got: %v", registration.ExpiresAt)
+	}
+}
+
+// TestLookupDomainErrors tests the not found and the failed lookups.
+func TestLookupDomainErrors(t *testing.T) {
+	server, _ := newTestServer(t)
+	client := NewClient(server.URL, 5*time.Second)
+	if _, err := client.LookupDomain(context.Background(), "unknown.com"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Invalid error. Expected: %v, got: %v", ErrNotFound, err)
+	}
+	if _, err := client.LookupDomain(context.Background(), "broken.com"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Server error is expected. Got: %v", err)
+	}
+}
+
+// TestLookupRegistrable tests that the subdomains are resolved to the registered domain.
+func TestLookupRegistrable(t *testing.T) {
+	server, requested := newTestServer(t)
+	client := NewClient(server.URL, 5*time.Second)
+	registration, err := LookupRegistrable(context.Background(), client, "www.shop.Example.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if registration.Registrar != "Example Registrar, Inc." {
+		t.Errorf("Registrar is not set properly. Got: %s", registration.Registrar)
+	}
+	if len(*requested) != 3 {
+		t.Errorf("Invalid lookups: %v", *requested)
+	}
+	*requested = []string{}
+	if _, err := LookupRegistrable(context.Background(), client, "www.unknown.org"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Invalid error. Expected: %v, got: %v", ErrNotFound, err)
+	}
+	// the top level domain is not looked up
+	if len(*requested) != 2 {
+		t.Errorf("Invalid lookups: %v", *requested)
+	}
+}
@@ -2,18 +2,66 @@ package render
 
 import (
 	"encoding/json"
-	"io"
+	"io/fs"
+	"log/slog"
 	"net/http"
 	"os"
 
 	"github.com/akosgarai/projectregister/pkg/config"
+	"github.com/akosgarai/projectregister/pkg/logging"
+	"github.com/akosgarai/projectregister/web"
 )
 
 const (
-	// BaseTemplatePath is the base template.
-	BaseTemplatePath = "web/template/base.html.tmpl"
+	// ErrorPageTemplate is the name of the error page template.
+	ErrorPageTemplate = "error-page.html"
 )
 
+// ErrorRecorder is implemented by the response writers that log the request with its error.
+type ErrorRecorder interface {
+	RecordError(message string, err error)
+}
+
+// JSONErrorWriter is implemented by the response writers that expect the errors in json format.
+type JSONErrorWriter interface {
+	JSONErrors() bool
+}
+
+// ErrorBody type is the json error envelope.
+type ErrorBody struct {
+	Error *ErrorDetails `json:"error"`
+}
+
+// ErrorDetails type is the content of the json error envelope.
+// The Details is set only in development mode.
+type ErrorDetails struct {
+	Status    int    `json:"status"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+	Details   string `json:"details,omitempty"`
+}
+
+// ErrorPageHeader type is the header of the error page.
+type ErrorPageHeader struct {
+	Title   string
+	Buttons []*ErrorPageButton
+}
+
+// ErrorPageButton type is a link in the header of the error page.
+type ErrorPageButton struct {
+	Href string
+	Text string
+}
+
+// ErrorPage type is the data of the error page template.
+// The side menu is empty, as the error page does not depend on the current user.
+type ErrorPage struct {
+	Title    string
+	Header   *ErrorPageHeader
+	SideMenu []*ErrorPageButton
+	Error    *ErrorDetails
+}
+
 // Renderer is the renderer.
 type Renderer struct {
 	// templateDirectoryPath is the path to the template directory.
@@ -21,18 +69,35 @@ type Renderer struct {
 
 	// staticDirectoryPath is the path to the static directory.
 	staticDirectoryPath string
+	// staticFiles is the file system of the static files. It is the embedded static directory,
+	// or the static directory on the disk if the assets are loaded from the disk.
+	staticFiles fs.FS
+
+	// logger is the structured logger of the application.
+	logger *slog.Logger
+	// showErrorDetails is true in development mode, the error details are sent to the users.
+	showErrorDetails bool
 
 	// the compiled templates
 	Template TemplateInterface
 }
 
 // NewRenderer creates a new renderer.
+// The base template path is relative to the root of the template file system.
 func NewRenderer(envConfig *config.Environment, t TemplateInterface) *Renderer {
-	t.SetBaseTemplate(envConfig.GetRenderTemplateDirectoryPath() + "/" + envConfig.GetRenderBaseTemplate())
+	t.SetBaseTemplate(envConfig.GetRenderBaseTemplate())
+	staticFiles := web.Static()
+	if envConfig.GetAssetsFromDisk() {
+		staticFiles = os.DirFS(envConfig.GetStaticDirectoryPath())
+	}
 	return &Renderer{
 		templateDirectoryPath: envConfig.GetRenderTemplateDirectoryPath(),
 
 		staticDirectoryPath: envConfig.GetStaticDirectoryPath(),
+		staticFiles:         staticFiles,
+
+		logger:           logging.New(os.Stdout, envConfig.GetLogFormat(), envConfig.GetLogLevel()),
+		showErrorDetails: envConfig.GetAppEnv() == config.AppEnvDevelopment,
 
 		Template: t,
 	}
@@ -48,6 +113,11 @@ func (r *Renderer) GetStaticDirectoryPath() string {
 	return r.staticDirectoryPath
 }
 
+// GetStaticFiles returns the file system of the static files.
+func (r *Renderer) GetStaticFiles() fs.FS {
+	return r.staticFiles
+}
+
 // JSON renders a JSON response.
 func (r *Renderer) JSON(w http.ResponseWriter, status int, v interface{}) {
 	// check that v is marshalable
@@ -67,14 +137,62 @@ func (r *Renderer) Status(w http.ResponseWriter, status int) {
 }
 
 // Error renders an error response.
+// The details are logged with the request if the writer records the errors, otherwise they are logged directly.
+// The details are sent to the client only in development mode.
+// The api requests get json error envelope, the others get the error page.
+// If the error page is not available, the message is sent as plain text.
 func (r *Renderer) Error(w http.ResponseWriter, status int, message string, details error) {
+	errorDetails := &ErrorDetails{
+		Status:    status,
+		Message:   message,
+		RequestID: w.Header().Get(logging.RequestIDHeader),
+	}
 	if details != nil {
-		message += " " + details.Error()
+		if recorder, ok := w.(ErrorRecorder); ok {
+			recorder.RecordError(message, details)
+		} else {
+			r.logger.Error(message, "status", status, "error", details)
+		}
+		if r.showErrorDetails {
+			errorDetails.Details = details.Error()
+		}
+	}
+	if jsonWriter, ok := w.(JSONErrorWriter); ok && jsonWriter.JSONErrors() {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(&ErrorBody{Error: errorDetails})
+		return
+	}
+	if !r.Template.HasTemplate(ErrorPageTemplate) {
+		text := message
+		if errorDetails.Details != "" {
+			text += " " + errorDetails.Details
+		}
+		http.Error(w, text, status)
+		return
+	}
+	title := http.StatusText(status)
+	if title == "" {
+		title = "Error"
+	}
+	page := &ErrorPage{
+		Title: title,
+		Header: &ErrorPageHeader{
+			Title:   title,
+			Buttons: []*ErrorPageButton{{Href: "/admin/dashboard", Text: "Dashboard"}},
+		},
+		SideMenu: []*ErrorPageButton{},
+		Error:    errorDetails,
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	if err := r.Template.RenderTemplate(w, ErrorPageTemplate, page); err != nil {
+		r.logger.Error("failed to render the error page", "error", err)
 	}
-	http.Error(w, message, status)
 }
 
-// GetLogOutput returns the log output.
-func (r *Renderer) GetLogOutput() io.Writer {
-	return os.Stdout
+// GetLogger returns the structured logger.
+func (r *Renderer) GetLogger() *slog.Logger {
+	return r.logger
 }
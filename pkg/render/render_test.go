@@ -113,6 +113,7 @@ func TestErrorWithoutDetails(t *testing.T) {
 }
 
 // TestErrorWithDetails is a test function for the Error function.
+// The details are not sent to the client.
 func TestErrorWithDetails(t *testing.T) {
 	testConfig := config.NewEnvironment(testhelper.TestConfigData)
 	renderer := NewRenderer(testConfig, NewTemplates())
@@ -125,8 +126,71 @@ func TestErrorWithDetails(t *testing.T) {
 		if w.Code != code {
 			t.Errorf("The status is not correct. Expected: %d, got: %d", code, w.Code)
 		}
-		expected := testMessage + " " + details.Error()
-		if w.Body.String() != expected+"\n" {
+		if w.Body.String() != testMessage+"\n" {
+			t.Errorf("The body is not correct. Expected: '%s', got: '%s'", testMessage, w.Body.String())
+		}
+	}
+}
+
+// errorRecorderMock is a response writer that records the error details.
+type errorRecorderMock struct {
+	*httptest.ResponseRecorder
+	message string
+	err     error
+}
+
+// RecordError stores the error.
+func (e *errorRecorderMock) RecordError(message string, err error) {
+	e.message = message
+	e.err = err
+}
+
+// TestErrorWithRecorder is a test function for the Error function.
+// The details are recorded by the writer.
+func TestErrorWithRecorder(t *testing.T) {
+	testConfig := config.NewEnvironment(testhelper.TestConfigData)
+	renderer := NewRenderer(testConfig, NewTemplates())
+	w := &errorRecorderMock{ResponseRecorder: httptest.NewRecorder()}
+	details := errors.New("test error")
+	renderer.Error(w, 500, "test message", details)
+	if w.err != details || w.message != "test message" {
+		t.Errorf("The error is not recorded. Got: '%s' '%v'", w.message, w.err)
+	}
+	if w.Body.String() != "test message\n" {
+		t.Errorf("The body is not correct. Got: '%s'", w.Body.String())
+	}
+}
+
+// jsonErrorWriterMock is a response writer that expects the errors in json format.
+type jsonErrorWriterMock struct {
+	*httptest.ResponseRecorder
+}
+
+// JSONErrors returns true.
+func (j *jsonErrorWriterMock) JSONErrors() bool {
+	return true
+}
+
+// TestErrorJSON is a test function for the Error function with json error writer.
+// The details are sent only in development mode.
+func TestErrorJSON(t *testing.T) {
+	for _, appEnv := range []string{config.AppEnvProduction, config.AppEnvDevelopment} {
+		testConfigData := map[string]string{config.AppEnvEnvName: appEnv}
+		for key, value := range testhelper.TestConfigData {
+			testConfigData[key] = value
+		}
+		renderer := NewRenderer(config.NewEnvironment(testConfigData), NewTemplates())
+		w := &jsonErrorWriterMock{ResponseRecorder: httptest.NewRecorder()}
+		w.Header().Set("X-Request-ID", "test-request")
+		renderer.Error(w, 404, "test message", errors.New("test error"))
+		if w.Code != 404 {
+			t.Errorf("The status is not correct. Expected: %d, got: %d", 404, w.Code)
+		}
+		expected := `{"error":{"status":404,"message":"test message","request_id":"test-request"}}` + "\n"
+		if appEnv == config.AppEnvDevelopment {
+			expected = `{"error":{"status":404,"message":"test message","request_id":"test-request","details":"test error"}}` + "\n"
+		}
+		if w.Body.String() != expected {
 			t.Errorf("The body is not correct. Expected: '%s', got: '%s'", expected, w.Body.String())
 		}
 	}
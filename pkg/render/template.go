@@ -1,27 +1,48 @@
 package render
 
 import (
+	"fmt"
 	"html/template"
+	"io/fs"
 	"net/http"
+	"sync"
+
+	"github.com/akosgarai/projectregister/web"
 )
 
 // TemplateInterface is an interface for Templates.
 type TemplateInterface interface {
 	SetBaseTemplate(baseTemplate string)
 	AddTemplate(name string, files []string)
+	HasTemplate(name string) bool
 	RenderTemplate(w http.ResponseWriter, name string, data interface{}) error
 }
 
 // Templates is a struct that holds the templates that we want to use.
+// The template files are loaded from the file system, the paths are relative to its root.
+// With hot reload the templates are parsed on every render, so that the changes are visible without restart.
 type Templates struct {
+	fileSystem   fs.FS
+	hotReload    bool
 	baseTemplate string
-	templates    map[string]*template.Template
+
+	mu        sync.RWMutex
+	files     map[string][]string
+	templates map[string]*template.Template
 }
 
-// NewTemplates creates a new Templates struct.
+// NewTemplates creates a new Templates struct with the embedded template files.
 func NewTemplates() *Templates {
+	return NewTemplatesFS(web.Templates(), false)
+}
+
+// NewTemplatesFS creates a new Templates struct with the template files of the file system.
+func NewTemplatesFS(fileSystem fs.FS, hotReload bool) *Templates {
 	return &Templates{
+		fileSystem:   fileSystem,
+		hotReload:    hotReload,
 		baseTemplate: "",
+		files:        make(map[string][]string),
 		templates:    make(map[string]*template.Template),
 	}
 }
@@ -32,11 +53,50 @@ func (t *Templates) SetBaseTemplate(baseTemplate string) {
 }
 
 // AddTemplate adds the templates to the Templates struct.
+// The templates are parsed immediately, so that the invalid templates are reported on startup.
 func (t *Templates) AddTemplate(name string, files []string) {
-	t.templates[name] = template.Must(template.New(name).ParseFiles(append(files, t.baseTemplate)...))
+	files = append(files, t.baseTemplate)
+	tmpl := template.Must(t.parse(name, files))
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.files[name] = files
+	t.templates[name] = tmpl
+}
+
+// HasTemplate checks if the template is added.
+func (t *Templates) HasTemplate(name string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	_, ok := t.templates[name]
+	return ok
 }
 
 // RenderTemplate renders the template.
+// It returns error if the template is not added.
 func (t *Templates) RenderTemplate(w http.ResponseWriter, name string, data interface{}) error {
-	return t.templates[name].ExecuteTemplate(w, "base.html", data)
+	tmpl, err := t.template(name)
+	if err != nil {
+		return err
+	}
+	return tmpl.ExecuteTemplate(w, "base.html", data)
+}
+
+// template returns the parsed template. With hot reload the files are parsed again.
+func (t *Templates) template(name string) (*template.Template, error) {
+	t.mu.RLock()
+	tmpl, ok := t.templates[name]
+	files := t.files[name]
+	t.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("template %s is not found", name)
+	}
+	if !t.hotReload {
+		return tmpl, nil
+	}
+	return t.parse(name, files)
+}
+
+// parse parses the template files from the file system.
+func (t *Templates) parse(name string, files []string) (*template.Template, error) {
+	return template.New(name).ParseFS(t.fileSystem, files...)
 }
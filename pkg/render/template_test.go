@@ -0,0 +1,46 @@
+package render
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestNewTemplatesEmbedded tests that the embedded base template is parsed.
+func TestNewTemplatesEmbedded(t *testing.T) {
+	templates := NewTemplates()
+	templates.SetBaseTemplate("base.html.tmpl")
+	templates.AddTemplate("error-page.html", []string{"frontend-components/header.html.tmpl", "pages/error.html.tmpl"})
+	if !templates.HasTemplate("error-page.html") {
+		t.Error("The embedded template has to be added.")
+	}
+}
+
+// TestTemplatesHotReload tests that the templates are parsed again on every render with hot reload.
+func TestTemplatesHotReload(t *testing.T) {
+	dir := t.TempDir()
+	baseTemplate := filepath.Join(dir, "base.html.tmpl")
+	for _, hotReload := range []bool{false, true} {
+		if err := os.WriteFile(baseTemplate, []byte(`{{define "base.html"}}first{{end}}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		templates := NewTemplatesFS(os.DirFS(dir), hotReload)
+		templates.SetBaseTemplate("base.html.tmpl")
+		templates.AddTemplate("page", []string{})
+		if err := os.WriteFile(baseTemplate, []byte(`{{define "base.html"}}second{{end}}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		if err := templates.RenderTemplate(rr, "page", nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := "first"
+		if hotReload {
+			expected = "second"
+		}
+		if rr.Body.String() != expected {
+			t.Errorf("Invalid body with hot reload %t. Expected: %s, got: %s", hotReload, expected, rr.Body.String())
+		}
+	}
+}
@@ -25,6 +25,14 @@ const (
 	ApplicationResource = "application"
 	// FrameworkResource is the resource name for the framework.
 	FrameworkResource = "framework"
+	// CertificateResource is the resource name for the certificate.
+	CertificateResource = "certificate"
+	// WebhookResource is the resource name for the webhook.
+	WebhookResource = "webhook"
+	// JobResource is the resource name for the background job.
+	JobResource = "job"
+	// ScheduledTaskResource is the resource name for the scheduled task.
+	ScheduledTaskResource = "scheduled-task"
 
 	// UsersPrivilege is the privilege name for the users.
 	UsersPrivilege = "users"
@@ -50,29 +58,42 @@ const (
 	ApplicationsPrivilege = "applications"
 	// FrameworksPrivilege is the privilege name for the frameworks.
 	FrameworksPrivilege = "frameworks"
+	// CertificatesPrivilege is the privilege name for the certificates.
+	CertificatesPrivilege = "certificates"
+	// WebhooksPrivilege is the privilege name for the webhooks.
+	WebhooksPrivilege = "webhooks"
+	// JobsPrivilege is the privilege name for the background jobs.
+	JobsPrivilege = "jobs"
+	// ScheduledTasksPrivilege is the privilege name for the scheduled tasks.
+	ScheduledTasksPrivilege = "scheduled_tasks"
 )
 
 var (
 	// ResourcePrivileges is a map for the resources and the necessary privileges.
 	ResourcePrivileges = map[string]string{
-		UserResource:        UsersPrivilege,
-		RoleResource:        RolesPrivilege,
-		ClientResource:      ClientsPrivilege,
-		ProjectResource:     ProjectsPrivilege,
-		DomainResource:      DomainsPrivilege,
-		EnvironmentResource: EnvironmentsPrivilege,
-		RuntimeResource:     RuntimesPrivilege,
-		PoolResource:        PoolsPrivilege,
-		DatabaseResource:    DatabasesPrivilege,
-		ServerResource:      ServersPrivilege,
-		ApplicationResource: ApplicationsPrivilege,
-		FrameworkResource:   FrameworksPrivilege,
+		UserResource:          UsersPrivilege,
+		RoleResource:          RolesPrivilege,
+		ClientResource:        ClientsPrivilege,
+		ProjectResource:       ProjectsPrivilege,
+		DomainResource:        DomainsPrivilege,
+		EnvironmentResource:   EnvironmentsPrivilege,
+		RuntimeResource:       RuntimesPrivilege,
+		PoolResource:          PoolsPrivilege,
+		DatabaseResource:      DatabasesPrivilege,
+		ServerResource:        ServersPrivilege,
+		ApplicationResource:   ApplicationsPrivilege,
+		FrameworkResource:     FrameworksPrivilege,
+		CertificateResource:   CertificatesPrivilege,
+		WebhookResource:       WebhooksPrivilege,
+		JobResource:           JobsPrivilege,
+		ScheduledTaskResource: ScheduledTasksPrivilege,
 	}
 
 	// Resources is a slice of the resource names.
 	Resources = []string{
 		UserResource, RoleResource, ClientResource, ProjectResource, DomainResource,
 		EnvironmentResource, RuntimeResource, PoolResource, DatabaseResource,
-		ServerResource, ApplicationResource, FrameworkResource,
+		ServerResource, ApplicationResource, FrameworkResource, CertificateResource,
+		WebhookResource, JobResource, ScheduledTaskResource,
 	}
 )
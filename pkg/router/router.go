@@ -2,99 +2,256 @@ package router
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"runtime/debug"
 	"time"
 
 	"github.com/gorilla/mux"
 
 	"github.com/akosgarai/projectregister/pkg/controller"
+	"github.com/akosgarai/projectregister/pkg/health"
+	"github.com/akosgarai/projectregister/pkg/jobqueue"
+	"github.com/akosgarai/projectregister/pkg/logging"
+	"github.com/akosgarai/projectregister/pkg/metrics"
 	"github.com/akosgarai/projectregister/pkg/model"
+	"github.com/akosgarai/projectregister/pkg/rdap"
 	"github.com/akosgarai/projectregister/pkg/render"
 	"github.com/akosgarai/projectregister/pkg/session"
 	"github.com/akosgarai/projectregister/pkg/storage"
 )
 
+const (
+	// InternalServerErrorMessage is the error message of the recovered panics.
+	InternalServerErrorMessage = "Internal server error"
+	// NotFoundErrorMessage is the error message of the unknown pages.
+	NotFoundErrorMessage = "Page not found"
+
+	// ContentSecurityPolicy allows only the resources of the application. The inline scripts are not allowed.
+	ContentSecurityPolicy = "default-src 'self'; img-src 'self' data:; object-src 'none'; base-uri 'self'; form-action 'self'; frame-ancestors 'none'"
+	// StrictTransportSecurity is the hsts header value. It is sent only on the https requests.
+	StrictTransportSecurity = "max-age=31536000; includeSubDomains"
+)
+
 // LoggingResponseWriter is a wrapper for the http.ResponseWriter to store the status code.
+// It also stores the error that is passed to the renderer, so that it is logged with the request.
 type LoggingResponseWriter struct {
 	http.ResponseWriter
 	statusCode   int
 	bytesWritten int
+	wroteHeader  bool
+	errorMessage string
+	err          error
+	// jsonErrors is true on the api routes, the renderer sends the errors in json format.
+	jsonErrors bool
 }
 
 // NewLoggingResponseWriter is a constructor for the LoggingResponseWriter.
 func NewLoggingResponseWriter(w http.ResponseWriter) *LoggingResponseWriter {
-	return &LoggingResponseWriter{w, http.StatusOK, 0}
+	return &LoggingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 }
 
 // WriteHeader is a wrapper for the http.ResponseWriter WriteHeader method.
 func (lrw *LoggingResponseWriter) WriteHeader(code int) {
 	lrw.statusCode = code
+	lrw.wroteHeader = true
 	lrw.ResponseWriter.WriteHeader(code)
 }
 
 // Write is a wrapper for the http.ResponseWriter Write method.
 func (lrw *LoggingResponseWriter) Write(b []byte) (int, error) {
+	lrw.wroteHeader = true
 	n, err := lrw.ResponseWriter.Write(b)
 	lrw.bytesWritten += n
 	return n, err
 }
 
-// LoggingMiddleware is a middleware for logging the requests.
-func LoggingMiddleware(logger *log.Logger) func(http.Handler) http.Handler {
+// RecordError stores the error of the request. It implements the render.ErrorRecorder interface.
+func (lrw *LoggingResponseWriter) RecordError(message string, err error) {
+	lrw.errorMessage = message
+	lrw.err = err
+}
+
+// JSONErrors returns true if the errors are expected in json format. It implements the render.JSONErrorWriter interface.
+func (lrw *LoggingResponseWriter) JSONErrors() bool {
+	return lrw.jsonErrors
+}
+
+// RecoveryMiddleware is a middleware for recovering the panics of the handlers.
+// The panic is logged with the stack trace, and the internal server error is rendered,
+// unless the response is already started. It has to be added after the LoggingMiddleware.
+func RecoveryMiddleware(renderer *render.Renderer) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			statuscode := 200
-			returnSize := "-"
 			defer func() {
-				now := time.Now()
-				// log the request in apache combined log format
-				logger.Printf("%s - - [%s] \"%s %s %s\" %d %s \"%s\" \"%s\"\n",
-					r.RemoteAddr,
-					now.Format("02/Jan/2006:15:04:05 -0700"),
-					r.Method,
-					r.RequestURI,
-					r.Proto,
-					statuscode,
-					returnSize,
-					r.Header.Get("Referer"),
-					r.Header.Get("User-Agent"),
-				)
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				// the aborted handlers are handled by the http server.
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+				renderer.GetLogger().ErrorContext(r.Context(), "panic recovered", "panic", fmt.Sprint(recovered), "stack", string(debug.Stack()))
+				if lrw, ok := w.(*LoggingResponseWriter); ok && lrw.wroteHeader {
+					lrw.RecordError(InternalServerErrorMessage, fmt.Errorf("panic: %v", recovered))
+					return
+				}
+				renderer.Error(w, http.StatusInternalServerError, InternalServerErrorMessage, fmt.Errorf("panic: %v", recovered))
 			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// JSONErrorMiddleware is a middleware for the api routes, the errors are rendered in json format.
+// It has to be added after the LoggingMiddleware.
+func JSONErrorMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if lrw, ok := w.(*LoggingResponseWriter); ok {
+			lrw.jsonErrors = true
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// SecurityHeadersMiddleware is a middleware for adding the security headers to every response.
+// The hsts header is added only to the https requests, including the ones that are terminated by a proxy.
+func SecurityHeadersMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		header.Set("Content-Security-Policy", ContentSecurityPolicy)
+		header.Set("X-Frame-Options", "DENY")
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+		if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+			header.Set("Strict-Transport-Security", StrictTransportSecurity)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RedirectHTTPSHandler returns a handler that redirects the requests to the same url with https on the given port.
+// The default https port is omitted from the url.
+func RedirectHTTPSHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			host = hostname
+		}
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
+
+// LoggingMiddleware is a middleware for logging the requests.
+// Every request gets a request id, the incoming X-Request-ID is kept if it is valid.
+// The request id and the route are stored in the request context, so that they are added to every log line of the request.
+// The requests are also counted in the http metrics by the route template, if the metrics are given.
+func LoggingMiddleware(logger *slog.Logger, httpMetrics *metrics.HTTPMetrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			requestID := logging.RequestIDFromHeader(r.Header.Get(logging.RequestIDHeader))
+			route := routeTemplate(r)
+			r = r.WithContext(logging.NewContext(r.Context(), requestID, route))
+			w.Header().Set(logging.RequestIDHeader, requestID)
 			loggedResponse := NewLoggingResponseWriter(w)
+			defer func() {
+				duration := time.Since(start)
+				if httpMetrics != nil {
+					httpMetrics.Observe(r.Method, route, loggedResponse.statusCode, duration)
+				}
+				level := slog.LevelInfo
+				if loggedResponse.statusCode >= http.StatusInternalServerError {
+					level = slog.LevelError
+				} else if loggedResponse.statusCode >= http.StatusBadRequest {
+					level = slog.LevelWarn
+				}
+				attrs := []slog.Attr{
+					slog.String("method", r.Method),
+					slog.String("uri", r.RequestURI),
+					slog.String("proto", r.Proto),
+					slog.Int("status", loggedResponse.statusCode),
+					slog.Int("bytes", loggedResponse.bytesWritten),
+					slog.Duration("duration", duration),
+					slog.String("remote_addr", r.RemoteAddr),
+					slog.String("referer", r.Header.Get("Referer")),
+					slog.String("user_agent", r.Header.Get("User-Agent")),
+				}
+				if loggedResponse.err != nil {
+					attrs = append(attrs, slog.String("error_message", loggedResponse.errorMessage), slog.String("error", loggedResponse.err.Error()))
+				}
+				logger.LogAttrs(r.Context(), level, "request", attrs...)
+			}()
 
 			next.ServeHTTP(loggedResponse, r)
-			// get the status code
-			statuscode = loggedResponse.statusCode
-			// get the response size
-			if loggedResponse.bytesWritten > 0 {
-				returnSize = fmt.Sprintf("%d", loggedResponse.bytesWritten)
-			}
 		})
 	}
 }
 
+// routeTemplate returns the path template of the matched route.
+// The unmatched requests are grouped, so that the random paths do not create new metrics.
+func routeTemplate(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return "unmatched"
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return "unmatched"
+	}
+	return template
+}
+
 // New creates a new instance of the router gorilla/mux router.
 func New(
 	repositoryContainer model.RepositoryContainer,
 	sessionStore *session.Store,
 	csvStorage storage.CSVStorage,
 	renderer *render.Renderer,
+	registrationLookup rdap.Lookup,
+	calendarFeedToken string,
+	metricsRegistry *metrics.Registry,
+	metricsToken string,
+	healthChecker *health.Checker,
+	jobQueue *jobqueue.Queue,
 ) *mux.Router {
 	r := mux.NewRouter()
-	// add logger middleware. The logger default flags has to be empty, because the apache log format is used.
-	r.Use(LoggingMiddleware(log.New(renderer.GetLogOutput(), "", 0)))
+	httpMetrics := metrics.NewHTTPMetrics(metrics.DefaultBuckets)
+	metricsRegistry.Register(httpMetrics)
+	// add logger middleware.
+	r.Use(LoggingMiddleware(renderer.GetLogger(), httpMetrics))
+	// the panics are recovered inside the logger middleware, so that the failed requests are also logged.
+	r.Use(RecoveryMiddleware(renderer))
+	r.Use(SecurityHeadersMiddleware)
 	// handle the static files
-	r.PathPrefix("/static/").Handler(http.StripPrefix("/static/", http.FileServer(http.Dir(renderer.GetStaticDirectoryPath()))))
+	r.PathPrefix("/static/").Handler(http.StripPrefix("/static/", http.FileServer(http.FS(renderer.GetStaticFiles()))))
 	routerController := controller.New(
 		repositoryContainer,
 		sessionStore,
 		csvStorage,
 		renderer,
+		registrationLookup,
+		calendarFeedToken,
+		metricsRegistry,
+		metricsToken,
+		healthChecker,
+		jobQueue,
 	)
-	r.HandleFunc("/health", routerController.HealthController)
+	routerController.RegisterJobHandlers()
+	// the /health is kept as the alias of the liveness check for the existing probes.
+	r.HandleFunc("/health", routerController.HealthLiveController)
+	r.HandleFunc("/health/live", routerController.HealthLiveController).Methods("GET")
+	r.HandleFunc("/health/ready", routerController.HealthReadyController).Methods("GET")
+	r.HandleFunc("/metrics", routerController.MetricsController).Methods("GET")
+	r.HandleFunc("/sd/targets", routerController.ServiceDiscoveryController).Methods("GET")
 	r.HandleFunc("/login", routerController.LoginPageController)
 	r.HandleFunc("/auth/login", routerController.LoginActionController).Methods("POST")
+	r.HandleFunc("/calendar/expiry.ics", routerController.CalendarFeedController).Methods("GET")
 	adminRouter := r.PathPrefix("/admin").Subrouter()
 	adminRouter.Use(routerController.AuthMiddleware)
 	adminRouter.HandleFunc("/dashboard", routerController.DashboardController)
@@ -129,6 +286,15 @@ func New(
 	adminRouter.HandleFunc("/domain/delete/{domainId}", routerController.DomainDeleteViewController).Methods("POST")
 	adminRouter.HandleFunc("/domain/list", routerController.DomainListViewController).Methods("GET", "POST")
 	adminRouter.HandleFunc("/domain/check-ssl/{domainId}", routerController.DomainCheckSSLViewController).Methods("GET")
+	adminRouter.HandleFunc("/domain/check-security/{domainId}", routerController.DomainCheckSecurityViewController).Methods("GET")
+	adminRouter.HandleFunc("/domain/zone-import", routerController.DomainZoneImportViewController).Methods("GET", "POST")
+	adminRouter.HandleFunc("/domain/zone-export/{domainId}", routerController.DomainZoneExportController).Methods("GET")
+	adminRouter.HandleFunc("/domain/rdap-lookup/{domainId}", routerController.DomainRDAPLookupViewController).Methods("GET")
+	adminRouter.HandleFunc("/domain/expiring", routerController.DomainExpiringViewController).Methods("GET", "POST")
+	adminRouter.HandleFunc("/dns-record/list/{domainId}", routerController.DNSRecordListViewController).Methods("GET", "POST")
+	adminRouter.HandleFunc("/dns-record/create/{domainId}", routerController.DNSRecordCreateViewController).Methods("GET", "POST")
+	adminRouter.HandleFunc("/dns-record/update/{recordId}", routerController.DNSRecordUpdateViewController).Methods("GET", "POST")
+	adminRouter.HandleFunc("/dns-record/delete/{recordId}", routerController.DNSRecordDeleteViewController).Methods("POST")
 
 	adminRouter.HandleFunc("/environment/create", routerController.EnvironmentCreateViewController).Methods("GET", "POST")
 	adminRouter.HandleFunc("/environment/view/{environmentId}", routerController.EnvironmentViewController)
@@ -166,15 +332,49 @@ func New(
 	adminRouter.HandleFunc("/framework/delete/{frameworkId}", routerController.FrameworkDeleteViewController).Methods("POST")
 	adminRouter.HandleFunc("/framework/list", routerController.FrameworkListViewController).Methods("GET", "POST")
 
+	adminRouter.HandleFunc("/certificate/create", routerController.CertificateCreateViewController).Methods("GET", "POST")
+	adminRouter.HandleFunc("/certificate/view/{certificateId}", routerController.CertificateViewController)
+	adminRouter.HandleFunc("/certificate/update/{certificateId}", routerController.CertificateUpdateViewController).Methods("GET", "POST")
+	adminRouter.HandleFunc("/certificate/delete/{certificateId}", routerController.CertificateDeleteViewController).Methods("POST")
+	adminRouter.HandleFunc("/certificate/list", routerController.CertificateListViewController).Methods("GET", "POST")
+
 	adminRouter.HandleFunc("/application/create", routerController.ApplicationCreateViewController).Methods("GET", "POST")
 	adminRouter.HandleFunc("/application/view/{applicationId}", routerController.ApplicationViewController)
 	adminRouter.HandleFunc("/application/update/{applicationId}", routerController.ApplicationUpdateViewController).Methods("GET", "POST")
 	adminRouter.HandleFunc("/application/delete/{applicationId}", routerController.ApplicationDeleteViewController).Methods("POST")
 	adminRouter.HandleFunc("/application/list", routerController.ApplicationListViewController).Methods("GET", "POST")
+	adminRouter.HandleFunc("/application/domain-roles/{applicationId}", routerController.ApplicationDomainRolesViewController).Methods("GET", "POST")
+	adminRouter.HandleFunc("/application/check/{applicationId}", routerController.ApplicationCheckViewController).Methods("GET")
 	adminRouter.HandleFunc("/application/import-to-environment/{environmentId}", routerController.ApplicationImportToEnvironmentFormController).Methods("GET", "POST")
 	adminRouter.HandleFunc("/application/mapping-to-environment/{environmentId}/{fileId}", routerController.ApplicationMappingToEnvironmentFormController).Methods("GET", "POST")
+	// the generic import wizard of the servers, environments, domains and users.
+	adminRouter.HandleFunc("/{resource:server|environment|domain|user}/import", routerController.ImportViewController).Methods("GET", "POST")
+	adminRouter.HandleFunc("/{resource:server|environment|domain|user}/import-mapping/{fileId}", routerController.ImportMappingViewController).Methods("GET", "POST")
+	adminRouter.HandleFunc("/{resource:server|environment|domain|user}/import-commit/{fileId}", routerController.ImportCommitController).Methods("POST")
+
+	adminRouter.HandleFunc("/webhook/create", routerController.WebhookCreateViewController).Methods("GET", "POST")
+	adminRouter.HandleFunc("/webhook/view/{webhookId}", routerController.WebhookViewController)
+	adminRouter.HandleFunc("/webhook/update/{webhookId}", routerController.WebhookUpdateViewController).Methods("GET", "POST")
+	adminRouter.HandleFunc("/webhook/delete/{webhookId}", routerController.WebhookDeleteViewController).Methods("POST")
+	adminRouter.HandleFunc("/webhook/list", routerController.WebhookListViewController).Methods("GET", "POST")
+	adminRouter.HandleFunc("/webhook/deliveries/{webhookId}", routerController.WebhookDeliveryListViewController).Methods("GET")
+
+	// the /jobs is the short alias of the job list.
+	adminRouter.HandleFunc("/jobs", routerController.JobListViewController).Methods("GET", "POST")
+	adminRouter.HandleFunc("/job/list", routerController.JobListViewController).Methods("GET", "POST")
+	adminRouter.HandleFunc("/job/view/{jobId}", routerController.JobViewController).Methods("GET")
+	adminRouter.HandleFunc("/job/retry/{jobId}", routerController.JobRetryViewController).Methods("POST")
+	adminRouter.HandleFunc("/job/cancel/{jobId}", routerController.JobCancelViewController).Methods("POST")
+
+	adminRouter.HandleFunc("/scheduled-task/list", routerController.ScheduledTaskListViewController).Methods("GET")
+	adminRouter.HandleFunc("/scheduled-task/update/{taskId}", routerController.ScheduledTaskUpdateViewController).Methods("GET", "POST")
+	adminRouter.HandleFunc("/scheduled-task/enable/{taskId}", routerController.ScheduledTaskEnableViewController).Methods("POST")
+	adminRouter.HandleFunc("/scheduled-task/disable/{taskId}", routerController.ScheduledTaskDisableViewController).Methods("POST")
+
+	adminRouter.HandleFunc("/notification/settings", routerController.NotificationSettingsViewController).Methods("GET", "POST")
 
 	apiRouter := r.PathPrefix("/api").Subrouter()
+	apiRouter.Use(JSONErrorMiddleware)
 	apiRouter.Use(routerController.AuthMiddleware)
 	apiRouter.HandleFunc("/user/create", routerController.UserCreateAPIController).Methods("POST")
 	apiRouter.HandleFunc("/user/view/{userId}", routerController.UserViewAPIController)
@@ -183,7 +383,9 @@ func New(
 	apiRouter.HandleFunc("/user/list", routerController.UserListAPIController)
 
 	// Re-define the default NotFound handler, so that the logger middleware can log the 404 status code.
-	r.NotFoundHandler = r.NewRoute().HandlerFunc(http.NotFound).GetHandler()
+	r.NotFoundHandler = r.NewRoute().HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		renderer.Error(w, http.StatusNotFound, NotFoundErrorMessage, nil)
+	}).GetHandler()
 
 	routerController.CacheTemplates()
 	return r
@@ -1,9 +1,17 @@
 package router
 
 import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/akosgarai/projectregister/pkg/config"
+	"github.com/akosgarai/projectregister/pkg/health"
+	"github.com/akosgarai/projectregister/pkg/jobqueue"
+	"github.com/akosgarai/projectregister/pkg/metrics"
 	"github.com/akosgarai/projectregister/pkg/render"
 	"github.com/akosgarai/projectregister/pkg/session"
 	"github.com/akosgarai/projectregister/pkg/testhelper"
@@ -19,13 +27,23 @@ func TestNew(t *testing.T) {
 		testhelper.NewRepositoryContainerMock(),
 		sessionStore,
 		testhelper.CSVStorageMock{},
-		render.NewRenderer(config.NewEnvironment(testhelper.TestConfigData), render.NewTemplates()))
+		render.NewRenderer(config.NewEnvironment(testhelper.TestConfigData), render.NewTemplates()),
+		nil,
+		"",
+		metrics.NewRegistry(),
+		"",
+		health.NewChecker(time.Second),
+		jobqueue.NewQueue(nil, slog.Default()))
 	if router == nil {
 		t.Error("New router is nil")
 	}
 	// check the routes
 	routesToCheck := []string{
 		"/health",
+		"/health/live",
+		"/health/ready",
+		"/metrics",
+		"/sd/targets",
 		"/login",
 		"/auth/login",
 
@@ -42,6 +60,17 @@ func TestNew(t *testing.T) {
 		"/admin/role/delete/{roleId}",
 		"/admin/role/list",
 
+		"/admin/jobs",
+		"/admin/job/list",
+		"/admin/job/view/{jobId}",
+		"/admin/job/retry/{jobId}",
+		"/admin/job/cancel/{jobId}",
+
+		"/admin/scheduled-task/list",
+		"/admin/scheduled-task/update/{taskId}",
+		"/admin/scheduled-task/enable/{taskId}",
+		"/admin/scheduled-task/disable/{taskId}",
+
 		"/api/user/create",
 		"/api/user/view/{userId}",
 		"/api/user/update/{userId}",
@@ -54,3 +83,72 @@ func TestNew(t *testing.T) {
 		}
 	}
 }
+
+// TestRecoveryMiddleware tests that the panics are rendered as internal server errors.
+// The api routes get json error envelope with the request id.
+func TestRecoveryMiddleware(t *testing.T) {
+	renderer := render.NewRenderer(config.NewEnvironment(testhelper.TestConfigData), render.NewTemplates())
+	panicHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("test panic")
+	})
+	handler := LoggingMiddleware(renderer.GetLogger(), nil)(RecoveryMiddleware(renderer)(JSONErrorMiddleware(panicHandler)))
+	req := httptest.NewRequest("GET", "/api/user/list", nil)
+	req.Header.Set("X-Request-ID", "test-request")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Invalid status code. Expected: %d, got: %d", http.StatusInternalServerError, rr.Code)
+	}
+	var body render.ErrorBody
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("The body is not json: %s", rr.Body.String())
+	}
+	if body.Error.Message != InternalServerErrorMessage || body.Error.RequestID != "test-request" || body.Error.Details != "" {
+		t.Errorf("Invalid error body: %+v", body.Error)
+	}
+}
+
+// TestSecurityHeadersMiddleware tests that the security headers are added, and the hsts is sent only over https.
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	handler := SecurityHeadersMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	testData := []struct {
+		forwardedProto string
+		expectedHSTS   string
+	}{
+		{"", ""},
+		{"https", StrictTransportSecurity},
+	}
+	for _, tt := range testData {
+		req := httptest.NewRequest("GET", "/login", nil)
+		req.Header.Set("X-Forwarded-Proto", tt.forwardedProto)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Header().Get("Content-Security-Policy") != ContentSecurityPolicy || rr.Header().Get("X-Frame-Options") != "DENY" || rr.Header().Get("Referrer-Policy") == "" {
+			t.Errorf("Missing security headers: %v", rr.Header())
+		}
+		if rr.Header().Get("Strict-Transport-Security") != tt.expectedHSTS {
+			t.Errorf("Invalid hsts header. Expected: %s, got: %s", tt.expectedHSTS, rr.Header().Get("Strict-Transport-Security"))
+		}
+	}
+}
+
+// TestRedirectHTTPSHandler tests the redirect urls.
+func TestRedirectHTTPSHandler(t *testing.T) {
+	testData := []struct {
+		httpsPort string
+		host      string
+		expected  string
+	}{
+		{"443", "example.com:80", "https://example.com/admin/dashboard?page=2"},
+		{"8443", "example.com", "https://example.com:8443/admin/dashboard?page=2"},
+	}
+	for _, tt := range testData {
+		req := httptest.NewRequest("GET", "/admin/dashboard?page=2", nil)
+		req.Host = tt.host
+		rr := httptest.NewRecorder()
+		RedirectHTTPSHandler(tt.httpsPort).ServeHTTP(rr, req)
+		if rr.Code != http.StatusMovedPermanently || rr.Header().Get("Location") != tt.expected {
+			t.Errorf("Invalid redirect. Expected: %s, got: %d %s", tt.expected, rr.Code, rr.Header().Get("Location"))
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears is the limit of the next run search, so that the impossible schedules (eg. 30th of February) terminate.
+const maxSearchYears = 5
+
+// macros are the shorthands of the common schedules.
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field is the definition of a cron field.
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	dayField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// the 7 is also sunday.
+	weekdayField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// Schedule type is a parsed cron expression. The fields are bit sets of the allowed values.
+type Schedule struct {
+	minute, hour, day, month, weekday uint64
+	// the day of month and the day of week are combined with or, unless one of them is a wildcard.
+	dayWildcard, weekdayWildcard bool
+}
+
+// ParseSchedule parses the standard 5 field cron expression (minute, hour, day of month, month, day of week).
+// The fields support the lists, the ranges, the steps and the month and weekday names.
+// The @yearly, @monthly, @weekly, @daily and @hourly macros are also supported.
+func ParseSchedule(expression string) (*Schedule, error) {
+	expression = strings.TrimSpace(strings.ToLower(expression))
+	if macro, ok := macros[expression]; ok {
+		expression = macro
+	}
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("the cron expression has to contain 5 fields, got %d", len(fields))
+	}
+	schedule := &Schedule{
+		dayWildcard:     strings.HasPrefix(fields[2], "*"),
+		weekdayWildcard: strings.HasPrefix(fields[4], "*"),
+	}
+	var err error
+	if schedule.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if schedule.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if schedule.day, err = dayField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if schedule.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if schedule.weekday, err = weekdayField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	// the sunday is stored as 0.
+	if schedule.weekday&(1<<7) != 0 {
+		schedule.weekday |= 1
+	}
+	return schedule, nil
+}
+
+// parse returns the bit set of the comma separated list of the field.
+func (f field) parse(value string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(value, ",") {
+		partBits, err := f.parsePart(part)
+		if err != nil {
+			return 0, err
+		}
+		bits |= partBits
+	}
+	return bits, nil
+}
+
+// parsePart returns the bit set of a list item, that is a wildcard, a value or a range with an optional step.
+func (f field) parsePart(part string) (uint64, error) {
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+	step := 1
+	if hasStep {
+		var err error
+		step, err = strconv.Atoi(stepPart)
+		if err != nil || step < 1 {
+			return 0, fmt.Errorf("invalid %s step: %s", f.name, part)
+		}
+	}
+	start, end := f.min, f.max
+	if rangePart != "*" {
+		startPart, endPart, isRange := strings.Cut(rangePart, "-")
+		var err error
+		if start, err = f.value(startPart); err != nil {
+			return 0, err
+		}
+		end = start
+		if isRange {
+			if end, err = f.value(endPart); err != nil {
+				return 0, err
+			}
+		} else if hasStep {
+			// the a/n means from a to the maximum with step n.
+			end = f.max
+		}
+		if start > end {
+			return 0, fmt.Errorf("invalid %s range: %s", f.name, part)
+		}
+	}
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << uint(i)
+	}
+	return bits, nil
+}
+
+// value returns the numeric value of a field item, the names are also accepted.
+func (f field) value(item string) (int, error) {
+	if value, ok := f.names[item]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(item)
+	if err != nil || value < f.min || value > f.max {
+		return 0, fmt.Errorf("invalid %s: %s", f.name, item)
+	}
+	return value, nil
+}
+
+// matchesDay checks the day of month and the day of week of the time.
+func (s *Schedule) matchesDay(t time.Time) bool {
+	dayMatches := s.day&(1<<uint(t.Day())) != 0
+	weekdayMatches := s.weekday&(1<<uint(t.Weekday())) != 0
+	if s.dayWildcard || s.weekdayWildcard {
+		return dayMatches && weekdayMatches
+	}
+	return dayMatches || weekdayMatches
+}
+
+// Next returns the first matching minute after the time in the location of the time.
+// It returns the zero time if the schedule does not match in the next years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	location := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, location)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, location)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, location)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, location)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
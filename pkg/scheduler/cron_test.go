@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseScheduleInvalid tests that the invalid expressions are rejected.
+func TestParseScheduleInvalid(t *testing.T) {
+	testData := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@every",
+	}
+	for _, expression := range testData {
+		if _, err := ParseSchedule(expression); err == nil {
+			t.Errorf("Expected error for %q", expression)
+		}
+	}
+}
+
+// TestScheduleNext tests the next run calculation.
+func TestScheduleNext(t *testing.T) {
+	// 2024-05-15 is a wednesday.
+	from := time.Date(2024, 5, 15, 10, 7, 30, 0, time.UTC)
+	testData := []struct {
+		expression string
+		expected   time.Time
+	}{
+		{"* * * * *", time.Date(2024, 5, 15, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 5, 15, 10, 15, 0, 0, time.UTC)},
+		{"30 * * * *", time.Date(2024, 5, 15, 10, 30, 0, 0, time.UTC)},
+		{"7 10 * * *", time.Date(2024, 5, 16, 10, 7, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2024, 5, 16, 3, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 5, 15, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 5, 16, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2024, 5, 19, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 9-17/4 * * mon-fri", time.Date(2024, 5, 15, 13, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 5, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,20 feb,jun *", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		// the day of month and the day of week are combined with or: the 20th or the next friday.
+		{"0 0 20 * fri", time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range testData {
+		schedule, err := ParseSchedule(tt.expression)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %v", tt.expression, err)
+		}
+		if next := schedule.Next(from); !next.Equal(tt.expected) {
+			t.Errorf("Expected %v for %q, got %v", tt.expected, tt.expression, next)
+		}
+	}
+}
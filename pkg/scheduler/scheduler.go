@@ -0,0 +1,166 @@
+package scheduler
+
+// This package contains the scheduler of the periodic maintenance tasks. The tasks and their cron schedules
+// are stored in the database, the scheduler runs the due tasks that have a registered function.
+// A task is started under an advisory lock and only if it is still due, so that it runs only once,
+// even if several instances share the database.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// lockKeyPrefix is the prefix of the advisory lock keys of the tasks.
+const lockKeyPrefix = "scheduled_task:"
+
+// Task is the function of a scheduled task. It returns the outcome message of the run.
+// It has to return when the context is cancelled.
+type Task func(ctx context.Context) (string, error)
+
+// Locker runs the function while it holds the lock of the key.
+// It returns false without running the function if the lock is held by someone else.
+type Locker func(ctx context.Context, key int64, fn func()) (bool, error)
+
+// Scheduler type runs the due scheduled tasks.
+type Scheduler struct {
+	tasks    model.ScheduledTaskRepository
+	locker   Locker
+	logger   *slog.Logger
+	mu       sync.RWMutex
+	handlers map[string]Task
+	// now returns the current time, it is replaced in the tests.
+	now func() time.Time
+}
+
+// NewScheduler creates a new scheduler. The locker is usually the advisory lock of the database.
+func NewScheduler(tasks model.ScheduledTaskRepository, locker Locker, logger *slog.Logger) *Scheduler {
+	return &Scheduler{
+		tasks:    tasks,
+		locker:   locker,
+		logger:   logger,
+		handlers: make(map[string]Task),
+		now:      time.Now,
+	}
+}
+
+// Register sets the function of the named task. The tasks without function are not executed.
+func (s *Scheduler) Register(name string, task Task) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[name] = task
+}
+
+// handler returns the function of the named task.
+func (s *Scheduler) handler(name string) (Task, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	task, ok := s.handlers[name]
+	return task, ok
+}
+
+// Run checks the tasks with the given interval until the context is cancelled.
+// The running tasks are finished before it returns.
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.RunDue(ctx); err != nil {
+			s.logger.Error("scheduler: failed to run the tasks", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue runs the enabled tasks that are due, one after the other.
+// The enabled tasks without next run are scheduled first. The failed tasks are logged, the others are still executed.
+func (s *Scheduler) RunDue(ctx context.Context) error {
+	tasks, err := s.tasks.GetScheduledTasks()
+	if err != nil {
+		return fmt.Errorf("failed to get the tasks: %w", err)
+	}
+	for _, task := range *tasks {
+		if ctx.Err() != nil {
+			return nil
+		}
+		fn, ok := s.handler(task.Name)
+		if !task.Enabled || !ok {
+			continue
+		}
+		schedule, err := ParseSchedule(task.Schedule)
+		if err != nil {
+			s.logger.Error("scheduler: invalid schedule", "task", task.Name, "schedule", task.Schedule, "error", err)
+			continue
+		}
+		if task.NextRunAt == "" {
+			if _, err := s.tasks.UpdateScheduledTask(task.ID, task.Schedule, task.Enabled, schedule.Next(s.now())); err != nil {
+				s.logger.Error("scheduler: failed to schedule the task", "task", task.Name, "error", err)
+			}
+			continue
+		}
+		locked, err := s.locker(ctx, lockKey(task.Name), func() {
+			s.run(ctx, task, schedule, fn)
+		})
+		if err != nil {
+			s.logger.Error("scheduler: failed to lock the task", "task", task.Name, "error", err)
+			continue
+		}
+		if !locked {
+			s.logger.Debug("scheduler: the task is running on another instance", "task", task.Name)
+		}
+	}
+	return nil
+}
+
+// run starts the task if it is still due, executes its function and stores the outcome.
+func (s *Scheduler) run(ctx context.Context, task *model.ScheduledTask, schedule *Schedule, fn Task) {
+	startedAt := s.now()
+	if _, err := s.tasks.StartScheduledTask(task.ID, startedAt, schedule.Next(startedAt)); err != nil {
+		if !errors.Is(err, model.ErrNotFound) {
+			s.logger.Error("scheduler: failed to start the task", "task", task.Name, "error", err)
+		}
+		// the task is not due, eg. it is already executed by another instance.
+		return
+	}
+	s.logger.Info("scheduler: task started", "task", task.Name)
+	message, err := call(ctx, fn)
+	status := model.ScheduledTaskStatusSucceeded
+	if err != nil {
+		status = model.ScheduledTaskStatusFailed
+		message = err.Error()
+		s.logger.Error("scheduler: task failed", "task", task.Name, "error", err)
+	}
+	durationMs := s.now().Sub(startedAt).Milliseconds()
+	if _, err := s.tasks.FinishScheduledTask(task.ID, status, message, durationMs); err != nil {
+		s.logger.Error("scheduler: failed to store the outcome", "task", task.Name, "error", err)
+		return
+	}
+	s.logger.Info("scheduler: task finished", "task", task.Name, "status", status, "duration_ms", durationMs)
+}
+
+// call executes the task function, the panic is returned as an error.
+func call(ctx context.Context, fn Task) (message string, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("the task panicked: %v", recovered)
+		}
+	}()
+	return fn(ctx)
+}
+
+// lockKey returns the advisory lock key of the named task.
+func lockKey(name string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(lockKeyPrefix + name))
+	return int64(hash.Sum64())
+}
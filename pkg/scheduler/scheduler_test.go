@@ -0,0 +1,230 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// testTimeFormat is the format of the stored times, it has seconds, unlike the model.ScheduledTaskTimeFormat.
+const testTimeFormat = "2006-01-02 15:04:05"
+
+// memoryTasks is an in memory task repository with the due rule of the database repository.
+type memoryTasks struct {
+	mu    sync.Mutex
+	tasks []*model.ScheduledTask
+}
+
+func (m *memoryTasks) GetScheduledTaskByID(id int64) (*model.ScheduledTask, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, task := range m.tasks {
+		if task.ID == id {
+			copied := *task
+			return &copied, nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+func (m *memoryTasks) GetScheduledTasks() (*model.ScheduledTasks, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tasks := model.ScheduledTasks{}
+	for _, task := range m.tasks {
+		copied := *task
+		tasks = append(tasks, &copied)
+	}
+	return &tasks, nil
+}
+
+func (m *memoryTasks) UpdateScheduledTask(id int64, schedule string, enabled bool, nextRunAt time.Time) (*model.ScheduledTask, error) {
+	return m.update(id, func(task *model.ScheduledTask) bool {
+		task.Schedule = schedule
+		task.Enabled = enabled
+		task.NextRunAt = formatTime(nextRunAt)
+		return true
+	})
+}
+
+func (m *memoryTasks) StartScheduledTask(id int64, startedAt, nextRunAt time.Time) (*model.ScheduledTask, error) {
+	return m.update(id, func(task *model.ScheduledTask) bool {
+		if !task.Enabled || task.NextRunAt == "" || task.NextRunAt > startedAt.Format(testTimeFormat) {
+			return false
+		}
+		task.LastRunAt = formatTime(startedAt)
+		task.LastStatus = model.ScheduledTaskStatusRunning
+		task.NextRunAt = formatTime(nextRunAt)
+		return true
+	})
+}
+
+func (m *memoryTasks) FinishScheduledTask(id int64, status, message string, durationMs int64) (*model.ScheduledTask, error) {
+	return m.update(id, func(task *model.ScheduledTask) bool {
+		task.LastStatus = status
+		task.LastMessage = message
+		task.LastDurationMs = durationMs
+		return true
+	})
+}
+
+// update applies the change on the task. The change returns false if the task is not in the proper state.
+func (m *memoryTasks) update(id int64, change func(task *model.ScheduledTask) bool) (*model.ScheduledTask, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, task := range m.tasks {
+		if task.ID == id && change(task) {
+			copied := *task
+			return &copied, nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+// formatTime formats the time, the zero time is empty.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(testTimeFormat)
+}
+
+// memoryLocker is an in memory advisory lock.
+type memoryLocker struct {
+	mu     sync.Mutex
+	locked map[int64]bool
+}
+
+func (l *memoryLocker) lock(ctx context.Context, key int64, fn func()) (bool, error) {
+	l.mu.Lock()
+	if l.locked[key] {
+		l.mu.Unlock()
+		return false, nil
+	}
+	l.locked[key] = true
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		delete(l.locked, key)
+		l.mu.Unlock()
+	}()
+	fn()
+	return true, nil
+}
+
+// newTestScheduler returns a scheduler with an in memory repository and locker, and a fixed time.
+func newTestScheduler(now time.Time, tasks ...*model.ScheduledTask) (*Scheduler, *memoryTasks, *memoryLocker) {
+	repository := &memoryTasks{tasks: tasks}
+	locker := &memoryLocker{locked: map[int64]bool{}}
+	scheduler := NewScheduler(repository, locker.lock, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	scheduler.now = func() time.Time { return now }
+	return scheduler, repository, locker
+}
+
+// TestRunDue tests that only the enabled and due tasks with function are executed, and their outcome is stored.
+func TestRunDue(t *testing.T) {
+	now := time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)
+	scheduler, repository, _ := newTestScheduler(now,
+		&model.ScheduledTask{ID: 1, Name: "due", Schedule: "*/15 * * * *", Enabled: true, NextRunAt: "2024-05-15 10:00:00"},
+		&model.ScheduledTask{ID: 2, Name: "later", Schedule: "*/15 * * * *", Enabled: true, NextRunAt: "2024-05-15 10:15:00"},
+		&model.ScheduledTask{ID: 3, Name: "disabled", Schedule: "*/15 * * * *", Enabled: false, NextRunAt: "2024-05-15 09:00:00"},
+		&model.ScheduledTask{ID: 4, Name: "failing", Schedule: "@hourly", Enabled: true, NextRunAt: "2024-05-15 09:00:00"},
+		&model.ScheduledTask{ID: 5, Name: "unscheduled", Schedule: "@daily", Enabled: true},
+		&model.ScheduledTask{ID: 6, Name: "unregistered", Schedule: "@daily", Enabled: true, NextRunAt: "2024-05-15 09:00:00"},
+	)
+	executed := map[string]int{}
+	for _, name := range []string{"due", "later", "disabled", "unscheduled"} {
+		name := name
+		scheduler.Register(name, func(ctx context.Context) (string, error) {
+			executed[name]++
+			return name + " done", nil
+		})
+	}
+	scheduler.Register("failing", func(ctx context.Context) (string, error) {
+		executed["failing"]++
+		return "", errors.New("broken")
+	})
+	if err := scheduler.RunDue(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedRuns := map[string]int{"due": 1, "later": 0, "disabled": 0, "failing": 1, "unscheduled": 0}
+	for name, count := range expectedRuns {
+		if executed[name] != count {
+			t.Errorf("Expected %d runs of %s, got %d", count, name, executed[name])
+		}
+	}
+	testData := []struct {
+		id                int64
+		expectedStatus    string
+		expectedMessage   string
+		expectedNextRunAt string
+	}{
+		{1, model.ScheduledTaskStatusSucceeded, "due done", "2024-05-15 10:15:00"},
+		{2, "", "", "2024-05-15 10:15:00"},
+		{3, "", "", "2024-05-15 09:00:00"},
+		{4, model.ScheduledTaskStatusFailed, "broken", "2024-05-15 11:00:00"},
+		{5, "", "", "2024-05-16 00:00:00"},
+		{6, "", "", "2024-05-15 09:00:00"},
+	}
+	for _, tt := range testData {
+		task, _ := repository.GetScheduledTaskByID(tt.id)
+		if task.LastStatus != tt.expectedStatus || task.LastMessage != tt.expectedMessage || task.NextRunAt != tt.expectedNextRunAt {
+			t.Errorf("Expected %s, %q, %s for %s, got %+v", tt.expectedStatus, tt.expectedMessage, tt.expectedNextRunAt, task.Name, task)
+		}
+	}
+}
+
+// TestRunDueOnce tests that a task is executed only once by the schedulers that share the repository and the locker.
+// The first scheduler holds the lock while the second one checks the task, then the second one finds the task not due.
+func TestRunDueOnce(t *testing.T) {
+	now := time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)
+	first, repository, locker := newTestScheduler(now,
+		&model.ScheduledTask{ID: 1, Name: "cleanup", Schedule: "@hourly", Enabled: true, NextRunAt: "2024-05-15 10:00:00"},
+	)
+	second := NewScheduler(repository, locker.lock, first.logger)
+	second.now = first.now
+	runs := 0
+	second.Register("cleanup", func(ctx context.Context) (string, error) {
+		runs++
+		return "", nil
+	})
+	first.Register("cleanup", func(ctx context.Context) (string, error) {
+		runs++
+		// the lock is held by the first scheduler.
+		if err := second.RunDue(ctx); err != nil {
+			return "", err
+		}
+		return "", nil
+	})
+	if err := first.RunDue(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := second.RunDue(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if runs != 1 {
+		t.Errorf("Expected 1 run, got %d", runs)
+	}
+}
+
+// TestRunDuePanic tests that the panicking task is stored as failed.
+func TestRunDuePanic(t *testing.T) {
+	now := time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)
+	scheduler, repository, _ := newTestScheduler(now,
+		&model.ScheduledTask{ID: 1, Name: "panic", Schedule: "@hourly", Enabled: true, NextRunAt: "2024-05-15 10:00:00"},
+	)
+	scheduler.Register("panic", func(ctx context.Context) (string, error) {
+		panic("boom")
+	})
+	scheduler.RunDue(context.Background())
+	task, _ := repository.GetScheduledTaskByID(1)
+	if task.LastStatus != model.ScheduledTaskStatusFailed || task.LastMessage != "the task panicked: boom" {
+		t.Errorf("Expected failed task, got %+v", task)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/akosgarai/projectregister/pkg/config"
@@ -30,7 +31,9 @@ func (s *Session) GetUser() *model.User {
 }
 
 // Store type a simple in memory session store
+// The sessions are guarded with the mutex, as the metrics are read in parallel with the requests.
 type Store struct {
+	mu        sync.RWMutex
 	sessions  map[string]*Session
 	length    time.Duration
 	keyLength int
@@ -49,7 +52,9 @@ func NewStore(c *config.Environment) *Store {
 
 // Get gets a session from the store
 func (s *Store) Get(id string) (*Session, error) {
+	s.mu.RLock()
 	session, ok := s.sessions[id]
+	s.mu.RUnlock()
 	if !ok {
 		return nil, fmt.Errorf("session not found")
 	}
@@ -65,15 +70,33 @@ func (s *Store) Get(id string) (*Session, error) {
 // if the session already exists, it will be overwritten
 // the last activity time will be updated
 func (s *Store) Set(id string, session *Session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.sessions[id] = session
 	s.sessions[id].lastActivity = time.Now()
 }
 
 // Delete deletes a session from the store
 func (s *Store) Delete(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	delete(s.sessions, id)
 }
 
+// Count returns the number of the active sessions.
+// The expired sessions are not counted, but they are deleted only on the next access.
+func (s *Store) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	count := 0
+	for _, session := range s.sessions {
+		if time.Since(session.lastActivity) <= s.length {
+			count++
+		}
+	}
+	return count
+}
+
 // GenerateSessionKey returns a securely generated random string.
 // It will return an error if the system's secure random
 // number generator fails to function correctly, in which
@@ -102,6 +102,19 @@ func TestStoreDelete(t *testing.T) {
 	}
 }
 
+// TestStoreCount tests the Store.Count function
+func TestStoreCount(t *testing.T) {
+	envConfig := config.DefaultEnvironment()
+	store := NewStore(envConfig)
+	user := &model.User{ID: 1, Name: "test", Email: "test@email.com", Password: "password", CreatedAt: "2020-01-01", UpdatedAt: "2020-01-01"}
+	store.Set("active", New(user))
+	store.Set("expired", New(user))
+	store.sessions["expired"].lastActivity = time.Now().Add(-store.length - time.Minute)
+	if store.Count() != 1 {
+		t.Errorf("Expected 1, got %v", store.Count())
+	}
+}
+
 // TestGenerateSessionKey tests the GenerateSessionKey function
 func TestGenerateSessionKey(t *testing.T) {
 	envConfig := config.DefaultEnvironment()
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/config"
+)
+
+var (
+	// ErrBlobNotFound is returned if the blob does not exist. It is also a fs.ErrNotExist.
+	ErrBlobNotFound = fmt.Errorf("blob not found: %w", fs.ErrNotExist)
+	// ErrInvalidKey is returned if the key is not a relative slash separated path.
+	ErrInvalidKey = errors.New("invalid blob key")
+	// ErrPresignNotSupported is returned by the backends that are not able to create presigned urls.
+	ErrPresignNotSupported = errors.New("the presigned urls are not supported by the storage")
+
+	// keyPattern is the format of the keys, the segments are separated with slash.
+	keyPattern = regexp.MustCompile("^[A-Za-z0-9_.-]+(/[A-Za-z0-9_.-]+)*$")
+)
+
+// BlobInfo is the metadata of a stored blob.
+type BlobInfo struct {
+	Key        string
+	Size       int64
+	ModifiedAt time.Time
+}
+
+// BlobStorage interface for storing binary objects with slash separated keys, eg. imports/1_abc.csv.
+// The local implementation stores them in a directory, the s3 implementation in a bucket,
+// so that they survive the container restarts and they are shared between the instances.
+type BlobStorage interface {
+	// Put stores the content with the given size under the key. The existing blob is overwritten.
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	// Get returns the content of the blob. It has to be closed by the caller.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete deletes the blob. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+	// List returns the blobs with the given key prefix.
+	List(ctx context.Context, prefix string) ([]BlobInfo, error)
+	// Presign returns an url that could be used to download the blob without credentials until it expires.
+	Presign(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+// ValidKey checks if the key is a relative slash separated path without parent and hidden segments,
+// so that it could not point outside of the storage.
+func ValidKey(key string) bool {
+	if !keyPattern.MatchString(key) {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return false
+		}
+	}
+	return true
+}
+
+// NewBlobStorage creates the blob storage of the configured backend.
+func NewBlobStorage(envConfig *config.Environment) (BlobStorage, error) {
+	switch strings.ToLower(envConfig.GetStorageBackend()) {
+	case config.StorageBackendLocal:
+		return NewLocalBlobStorage(envConfig.GetUploadDirectoryPath()), nil
+	case config.StorageBackendS3:
+		return NewS3BlobStorage(envConfig.GetS3Endpoint(), envConfig.GetS3Region(), envConfig.GetS3Bucket(), envConfig.GetS3AccessKey(), envConfig.GetS3SecretKey())
+	}
+	return nil, fmt.Errorf("unknown storage backend %q", envConfig.GetStorageBackend())
+}
@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LocalBlobStorage stores the blobs in a local directory. The key segments are the subdirectories.
+type LocalBlobStorage struct {
+	basePath string
+}
+
+// NewLocalBlobStorage creates a new LocalBlobStorage in the directory.
+func NewLocalBlobStorage(basePath string) *LocalBlobStorage {
+	return &LocalBlobStorage{basePath: basePath}
+}
+
+// path returns the file path of the key. It returns ErrInvalidKey if the key could point outside of the directory.
+func (s *LocalBlobStorage) path(key string) (string, error) {
+	if !ValidKey(key) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.basePath, filepath.FromSlash(key)), nil
+}
+
+// notFound maps the missing file error to ErrBlobNotFound.
+func notFound(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrBlobNotFound
+	}
+	return err
+}
+
+// Put stores the content in a temporary file, and renames it to the file of the key,
+// so that the partially written blobs are not visible. The files are readable only by the application.
+func (s *LocalBlobStorage) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), target)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+// Get opens the file of the key.
+func (s *LocalBlobStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(target)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return f, nil
+}
+
+// Delete deletes the file of the key. Deleting a missing file is not an error.
+func (s *LocalBlobStorage) Delete(ctx context.Context, key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List walks the directory and returns the files with the key prefix.
+// The hidden files, eg. the .gitkeep and the temporary files of the Put are skipped.
+func (s *LocalBlobStorage) List(ctx context.Context, prefix string) ([]BlobInfo, error) {
+	blobs := []BlobInfo{}
+	err := filepath.WalkDir(s.basePath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(entry.Name(), ".") && path != s.basePath {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		relative, err := filepath.Rel(s.basePath, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(relative)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		blobs = append(blobs, BlobInfo{Key: key, Size: info.Size(), ModifiedAt: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return blobs, nil
+}
+
+// Presign is not supported by the local storage, the files are served only through the application.
+func (s *LocalBlobStorage) Presign(ctx context.Context, key string, expires time.Duration) (string, error) {
+	return "", ErrPresignNotSupported
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestValidKey tests that the keys could not point outside of the storage.
+func TestValidKey(t *testing.T) {
+	testData := []struct {
+		key      string
+		expected bool
+	}{
+		{"imports/1_abc.csv", true},
+		{"a.csv", true},
+		{"../a.csv", false},
+		{"imports/../../a.csv", false},
+		{"/etc/passwd", false},
+		{"imports//a.csv", false},
+		{"imports/.gitkeep", false},
+		{"imports/a b.csv", false},
+		{"", false},
+	}
+	for _, tt := range testData {
+		if got := ValidKey(tt.key); got != tt.expected {
+			t.Errorf("ValidKey(%q): expected %t, got %t", tt.key, tt.expected, got)
+		}
+	}
+}
+
+// TestLocalBlobStorage tests the put, get, list and delete of the local storage.
+// The hidden files and the other prefixes are not listed.
+func TestLocalBlobStorage(t *testing.T) {
+	directory := t.TempDir()
+	if err := os.WriteFile(filepath.Join(directory, ".gitkeep"), nil, 0600); err != nil {
+		t.Fatalf("Failed to create the file: %v", err)
+	}
+	s := NewLocalBlobStorage(directory)
+	ctx := context.Background()
+	for _, key := range []string{"imports/1_a.csv", "imports/2_b.csv", "other/c.csv"} {
+		if err := s.Put(ctx, key, strings.NewReader("a;b"), 3, "text/csv"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	content, err := s.Get(ctx, "imports/1_a.csv")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, _ := io.ReadAll(content)
+	content.Close()
+	if string(data) != "a;b" {
+		t.Errorf("Expected a;b, got %s", data)
+	}
+	blobs, err := s.List(ctx, "imports/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(blobs) != 2 || blobs[0].Key != "imports/1_a.csv" || blobs[1].Key != "imports/2_b.csv" || blobs[0].Size != 3 {
+		t.Errorf("Unexpected blobs: %v", blobs)
+	}
+	if blobs, _ := s.List(ctx, ""); len(blobs) != 3 {
+		t.Errorf("Expected 3 blobs without the hidden file, got %v", blobs)
+	}
+	if err := s.Delete(ctx, "imports/1_a.csv"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := s.Delete(ctx, "imports/1_a.csv"); err != nil {
+		t.Errorf("Expected no error for a missing blob, got %v", err)
+	}
+	if _, err := s.Get(ctx, "imports/1_a.csv"); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+	if err := s.Put(ctx, "../a.csv", strings.NewReader("a"), 1, ""); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Expected invalid key error, got %v", err)
+	}
+	if _, err := s.Presign(ctx, "imports/2_b.csv", 0); !errors.Is(err, ErrPresignNotSupported) {
+		t.Errorf("Expected presign not supported error, got %v", err)
+	}
+}
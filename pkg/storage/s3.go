@@ -0,0 +1,312 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// s3RequestTimeout is the timeout of the s3 requests.
+	s3RequestTimeout = time.Minute
+	// s3Algorithm is the signing algorithm of the requests.
+	s3Algorithm = "AWS4-HMAC-SHA256"
+	// s3DateFormat is the format of the x-amz-date header.
+	s3DateFormat = "20060102T150405Z"
+	// s3UnsignedPayload is used as the payload hash of the streamed uploads and the presigned urls.
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	// s3EmptyPayload is the sha256 hash of the empty body.
+	s3EmptyPayload = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	// s3MaxPresignExpiry is the longest expiry of the presigned urls.
+	s3MaxPresignExpiry = 7 * 24 * time.Hour
+)
+
+// S3BlobStorage stores the blobs in a bucket of an s3 compatible object storage, eg. aws s3 or minio.
+// The requests are signed with the signature version 4, and the bucket is addressed in the path of the endpoint.
+type S3BlobStorage struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+	// now returns the signing time.
+	now func() time.Time
+}
+
+// s3Error is the error response of the s3 api.
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// s3ListResult is the response of the list objects v2 request.
+type s3ListResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key          string `xml:"Key"`
+		Size         int64  `xml:"Size"`
+		LastModified string `xml:"LastModified"`
+	} `xml:"Contents"`
+}
+
+// NewS3BlobStorage creates a new S3BlobStorage for the bucket of the endpoint, eg. http://minio:9000.
+func NewS3BlobStorage(endpoint, region, bucket, accessKey, secretKey string) (*S3BlobStorage, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", endpoint)
+	}
+	if bucket == "" {
+		return nil, fmt.Errorf("the s3 bucket is required")
+	}
+	return &S3BlobStorage{
+		endpoint:  u,
+		region:    region,
+		bucket:    bucket,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		client:    &http.Client{Timeout: s3RequestTimeout},
+		now:       time.Now,
+	}, nil
+}
+
+// objectURL returns the url of the key in the bucket. The empty key is the bucket itself.
+func (s *S3BlobStorage) objectURL(key string, query url.Values) *url.URL {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawPath = ""
+	u.RawQuery = s3CanonicalQuery(query)
+	return &u
+}
+
+// do sends the signed request, and returns the response with a successful status.
+// The missing objects are ErrBlobNotFound, the other failures contain the code and the message of the s3 error.
+func (s *S3BlobStorage) do(ctx context.Context, method string, u *url.URL, body io.Reader, size int64, contentType string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	payloadHash := s3EmptyPayload
+	if body != nil {
+		req.ContentLength = size
+		payloadHash = s3UnsignedPayload
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req, payloadHash, s.now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrBlobNotFound
+	}
+	var apiError s3Error
+	xml.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&apiError)
+	return nil, fmt.Errorf("s3 %s %s: %s %s %s", method, u.Path, resp.Status, apiError.Code, apiError.Message)
+}
+
+// Put uploads the content to the key. The size has to be the exact length of the content.
+func (s *S3BlobStorage) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+	resp, err := s.do(ctx, http.MethodPut, s.objectURL(key, nil), content, size, contentType)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get downloads the object of the key.
+func (s *S3BlobStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if !ValidKey(key) {
+		return nil, ErrInvalidKey
+	}
+	resp, err := s.do(ctx, http.MethodGet, s.objectURL(key, nil), nil, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Delete deletes the object of the key. Deleting a missing object is not an error.
+func (s *S3BlobStorage) Delete(ctx context.Context, key string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+	resp, err := s.do(ctx, http.MethodDelete, s.objectURL(key, nil), nil, 0, "")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// List returns the objects with the key prefix. The truncated results are continued until the last page.
+func (s *S3BlobStorage) List(ctx context.Context, prefix string) ([]BlobInfo, error) {
+	blobs := []BlobInfo{}
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+	for {
+		resp, err := s.do(ctx, http.MethodGet, s.objectURL("", query), nil, 0, "")
+		if err != nil {
+			return nil, err
+		}
+		var result s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, content := range result.Contents {
+			modifiedAt, err := time.Parse(time.RFC3339, content.LastModified)
+			if err != nil {
+				return nil, err
+			}
+			blobs = append(blobs, BlobInfo{Key: content.Key, Size: content.Size, ModifiedAt: modifiedAt})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return blobs, nil
+		}
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
+
+// Presign returns a presigned download url of the key. The expiry is at most 7 days.
+func (s *S3BlobStorage) Presign(ctx context.Context, key string, expires time.Duration) (string, error) {
+	if !ValidKey(key) {
+		return "", ErrInvalidKey
+	}
+	if expires <= 0 || expires > s3MaxPresignExpiry {
+		return "", fmt.Errorf("invalid presign expiry %s", expires)
+	}
+	return s.presign(s.objectURL(key, nil), expires, s.now()), nil
+}
+
+// scope returns the credential scope of the signing date.
+func (s *S3BlobStorage) scope(t time.Time) string {
+	return t.Format("20060102") + "/" + s.region + "/s3/aws4_request"
+}
+
+// signature returns the signature of the canonical request.
+func (s *S3BlobStorage) signature(t time.Time, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := s3Algorithm + "\n" + t.Format(s3DateFormat) + "\n" + s.scope(t) + "\n" + hex.EncodeToString(hash[:])
+	key := s3HMAC([]byte("AWS4"+s.secretKey), t.Format("20060102"))
+	key = s3HMAC(key, s.region)
+	key = s3HMAC(key, "s3")
+	key = s3HMAC(key, "aws4_request")
+	return hex.EncodeToString(s3HMAC(key, stringToSign))
+}
+
+// sign sets the authorization header of the request. The host and every header of the request are signed.
+func (s *S3BlobStorage) sign(req *http.Request, payloadHash string, t time.Time) {
+	t = t.UTC()
+	req.Header.Set("X-Amz-Date", t.Format(s3DateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	canonicalHeaders := ""
+	for _, name := range names {
+		canonicalHeaders += name + ":" + headers[name] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3CanonicalPath(req.URL),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.accessKey, s.scope(t), signedHeaders, s.signature(t, canonicalRequest)))
+}
+
+// presign returns the url with the signature in the query, only the host header is signed.
+func (s *S3BlobStorage) presign(u *url.URL, expires time.Duration, t time.Time) string {
+	t = t.UTC()
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", s.accessKey+"/"+s.scope(t))
+	query.Set("X-Amz-Date", t.Format(s3DateFormat))
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expires/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+	signed := *u
+	signed.RawQuery = s3CanonicalQuery(query)
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		s3CanonicalPath(&signed),
+		signed.RawQuery,
+		"host:" + signed.Host + "\n",
+		"host",
+		s3UnsignedPayload,
+	}, "\n")
+	signed.RawQuery += "&X-Amz-Signature=" + s.signature(t, canonicalRequest)
+	return signed.String()
+}
+
+// s3CanonicalPath returns the escaped path of the url, the empty path is the root.
+func s3CanonicalPath(u *url.URL) string {
+	if path := u.EscapedPath(); path != "" {
+		return path
+	}
+	return "/"
+}
+
+// s3CanonicalQuery returns the query sorted by the names, with the names and the values escaped as the signature requires.
+func s3CanonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := []string{}
+	for _, name := range names {
+		values := append([]string{}, query[name]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, s3Escape(name)+"="+s3Escape(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3Escape escapes the value with the unreserved characters of the rfc 3986.
+func s3Escape(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+// s3HMAC returns the hmac sha256 of the data with the key.
+func s3HMAC(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
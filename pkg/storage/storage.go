@@ -1,109 +1,241 @@
 package storage
 
 import (
-	"encoding/csv"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"io"
-	"math/rand"
+	"mime"
 	"mime/multipart"
-	"os"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/akosgarai/projectregister/pkg/config"
+	"github.com/akosgarai/projectregister/pkg/parser"
 )
 
-const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+// fileIDBytes is the number of the random bytes of the file ids, the ids are hex encoded.
+const fileIDBytes = 32
+
+var (
+	// ErrInvalidFileID is returned if the file id is not in the format of the generated ids.
+	ErrInvalidFileID = errors.New("invalid file id")
+	// ErrFileTooLarge is returned if the uploaded file is larger than the maximum size.
+	ErrFileTooLarge = errors.New("the file is too large")
+	// ErrInvalidFileType is returned if the uploaded file is not a text file.
+	ErrInvalidFileType = errors.New("the file type is not allowed")
+
+	// fileIDPattern is the format of the generated file ids.
+	fileIDPattern = regexp.MustCompile("^[0-9a-f]{64}$")
+	// importExtensions maps the extensions of the uploaded file names to the import formats.
+	importExtensions = map[string]string{
+		".csv":  parser.FormatCSV,
+		".txt":  parser.FormatCSV,
+		".xlsx": parser.FormatXLSX,
+		".json": parser.FormatJSON,
+	}
+	// importContentTypes are the accepted declared content types of the uploads of the formats.
+	// The browsers send the files with different types, the content is also checked.
+	importContentTypes = map[string]map[string]bool{
+		parser.FormatCSV: {
+			"text/csv":                 true,
+			"text/plain":               true,
+			"application/csv":          true,
+			"application/vnd.ms-excel": true,
+			"application/octet-stream": true,
+		},
+		parser.FormatXLSX: {
+			"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": true,
+			"application/zip":              true,
+			"application/x-zip-compressed": true,
+			"application/octet-stream":     true,
+		},
+		parser.FormatJSON: {
+			"application/json":         true,
+			"text/json":                true,
+			"text/plain":               true,
+			"application/octet-stream": true,
+		},
+	}
+	// importSniffedTypes are the prefixes of the detected content types of the formats.
+	importSniffedTypes = map[string]string{
+		parser.FormatCSV:  "text/plain",
+		parser.FormatXLSX: "application/zip",
+		parser.FormatJSON: "text/plain",
+	}
+	// importStoredTypes are the content types of the stored files of the formats.
+	importStoredTypes = map[string]string{
+		parser.FormatCSV:  "text/csv",
+		parser.FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		parser.FormatJSON: "application/json",
+	}
+)
+
+// ValidFileID checks if the file id is in the format of the generated ids,
+// so that it could not point outside of the upload directory.
+func ValidFileID(fileID string) bool {
+	return fileIDPattern.MatchString(fileID)
+}
 
-// CSVStorage interface for storing csv files
+// CSVStorage interface for storing the import files, the csv, xlsx and json files.
+// The files are bound to the uploading user, they are accessible only with the same owner id.
 type CSVStorage interface {
-	Save(file multipart.File) (string, error)
-	Delete(fileName string) error
-	Read(fileName string) ([][]string, error)
+	Save(ctx context.Context, ownerID int64, file multipart.File, header *multipart.FileHeader) (string, string, error)
+	Delete(ctx context.Context, ownerID int64, fileID string) error
+	Read(ctx context.Context, ownerID int64, fileID string, options parser.ImportOptions) ([][]string, error)
+	MaxSize() int64
 }
 
-type storage struct {
-	basePath string
+// csvKeyPrefix is the key prefix of the uploaded import files in the blob storage.
+const csvKeyPrefix = "imports/"
+
+// CSVFileStorage stores the uploaded import files in the blob storage.
+type CSVFileStorage struct {
+	blobs BlobStorage
+	// maxSize is the maximum size of the stored files in bytes.
+	maxSize int64
 }
 
-// generates a random filename
-func (s *storage) generateFileName(length int) string {
-	rand.Seed(time.Now().UnixNano())
-	b := make([]byte, length)
-	for i := range b {
-		b[i] = letters[rand.Int63()%int64(len(letters))]
+// NewCSVFileStorage creates a new CSVFileStorage on the blob storage.
+func NewCSVFileStorage(envConfig *config.Environment, blobs BlobStorage) *CSVFileStorage {
+	return &CSVFileStorage{
+		blobs:   blobs,
+		maxSize: envConfig.GetUploadMaxSize() << 20,
 	}
-	return string(b)
 }
 
-// save saves the data to a file
-func (s *storage) saveMultipartFile(file multipart.File, extenstion string) (string, error) {
-	// generate a unique file name
-	fileName := s.generateFileName(64)
-	defer file.Close()
-	targetFileName := s.basePath + "/" + fileName + "." + extenstion
-
-	// create a new file
-	f, err := os.OpenFile(targetFileName, os.O_WRONLY|os.O_CREATE, 0666)
-	if err != nil {
+// generateFileID generates a crypto random file id
+func generateFileID() (string, error) {
+	b := make([]byte, fileIDBytes)
+	if _, err := rand.Read(b); err != nil {
 		return "", err
 	}
-	defer f.Close()
+	return hex.EncodeToString(b), nil
+}
 
-	// copy the file to the target file
-	_, err = io.Copy(f, file)
-	if err != nil {
-		return "", err
+// key returns the blob key of the file of the owner. It returns ErrInvalidFileID if the id is not a generated one.
+func (s *CSVFileStorage) key(ownerID int64, fileID string) (string, error) {
+	if !ValidFileID(fileID) {
+		return "", ErrInvalidFileID
 	}
-
-	return fileName, nil
+	return fmt.Sprintf("%s%d_%s", csvKeyPrefix, ownerID, fileID), nil
 }
 
-// deleteFile deletes the file
-func (s *storage) deleteFile(fileName string) error {
-	targetFileName := s.basePath + "/" + fileName
-	return os.Remove(targetFileName)
+// MaxSize returns the maximum size of the uploaded files in bytes.
+func (s *CSVFileStorage) MaxSize() int64 {
+	return s.maxSize
 }
 
-// readFile reads the file
-func (s *storage) readFile(fileName string) (string, error) {
-	targetFileName := s.basePath + "/" + fileName
-	fileContent, err := os.ReadFile(targetFileName)
+// Save saves the uploaded import file of the owner. Returns the file id, the format of the file and an error.
+// The format is the extension of the file name.
+// It returns ErrFileTooLarge if the file is larger than the maximum size, and ErrInvalidFileType
+// if the extension is not an import format, or the declared type or the content does not match the format.
+func (s *CSVFileStorage) Save(ctx context.Context, ownerID int64, file multipart.File, header *multipart.FileHeader) (string, string, error) {
+	defer file.Close()
+	if header.Size > s.maxSize {
+		return "", "", ErrFileTooLarge
+	}
+	format, ok := importExtensions[strings.ToLower(path.Ext(header.Filename))]
+	if !ok {
+		return "", "", ErrInvalidFileType
+	}
+	if declared := header.Header.Get("Content-Type"); declared != "" {
+		mediaType, _, err := mime.ParseMediaType(declared)
+		if err != nil || !importContentTypes[format][mediaType] {
+			return "", "", ErrInvalidFileType
+		}
+	}
+	// one more byte is read than the limit, so that the larger files are detected.
+	content, err := io.ReadAll(io.LimitReader(file, s.maxSize+1))
 	if err != nil {
-		return "", err
+		return "", "", err
+	}
+	if int64(len(content)) > s.maxSize {
+		return "", "", ErrFileTooLarge
+	}
+	// the content type is detected from the first 512 bytes.
+	if !strings.HasPrefix(http.DetectContentType(content), importSniffedTypes[format]) {
+		return "", "", ErrInvalidFileType
+	}
+	fileID, err := generateFileID()
+	if err != nil {
+		return "", "", err
+	}
+	key, err := s.key(ownerID, fileID)
+	if err != nil {
+		return "", "", err
+	}
+	if err := s.blobs.Put(ctx, key, bytes.NewReader(content), int64(len(content)), importStoredTypes[format]); err != nil {
+		return "", "", err
 	}
-	return string(fileContent), nil
+	return fileID, format, nil
 }
 
-// CSVFileStorage type
-type CSVFileStorage struct {
-	storage
+// Delete deletes the file of the owner
+func (s *CSVFileStorage) Delete(ctx context.Context, ownerID int64, fileID string) error {
+	key, err := s.key(ownerID, fileID)
+	if err != nil {
+		return err
+	}
+	return s.blobs.Delete(ctx, key)
 }
 
-// NewCSVFileStorage creates a new CSVFileStorage
-func NewCSVFileStorage(envConfig *config.Environment) *CSVFileStorage {
-	return &CSVFileStorage{
-		storage: storage{
-			basePath: envConfig.GetUploadDirectoryPath(),
-		},
-	}
+// Check lists the uploaded files, so that the unreachable storage, eg. the missing bucket is reported by the readiness check.
+func (s *CSVFileStorage) Check(ctx context.Context) error {
+	_, err := s.blobs.List(ctx, csvKeyPrefix)
+	return err
 }
 
-// Save saves the data to a file. Returns the filename and an error
-func (s *CSVFileStorage) Save(file multipart.File) (string, error) {
-	return s.saveMultipartFile(file, "csv")
+// UploadedFile type identifies an uploaded file with its owner and its id.
+type UploadedFile struct {
+	OwnerID int64
+	FileID  string
 }
 
-// Delete deletes the file
-func (s *CSVFileStorage) Delete(fileName string) error {
-	return s.deleteFile(fileName)
+// DeleteOlderThan deletes the uploaded files that are modified before the given time.
+// The kept files are not deleted, eg. the files that are not imported yet.
+// It returns the number of the deleted files.
+func (s *CSVFileStorage) DeleteOlderThan(ctx context.Context, before time.Time, keep []UploadedFile) (int, error) {
+	keptKeys := make(map[string]struct{})
+	for _, file := range keep {
+		if key, err := s.key(file.OwnerID, file.FileID); err == nil {
+			keptKeys[key] = struct{}{}
+		}
+	}
+	blobs, err := s.blobs.List(ctx, csvKeyPrefix)
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, blob := range blobs {
+		if !blob.ModifiedAt.Before(before) {
+			continue
+		}
+		if _, ok := keptKeys[blob.Key]; ok {
+			continue
+		}
+		if err := s.blobs.Delete(ctx, blob.Key); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
 }
 
-// Read reads the file
-func (s *CSVFileStorage) Read(fileName string) ([][]string, error) {
-	targetFileName := s.basePath + "/" + fileName
-	// os.Open() opens specific file in
-	// read-only mode and this return
-	// a pointer of type os.File
-	file, err := os.Open(targetFileName)
+// Read reads the file of the owner, and parses it with the import options.
+// The file of an other owner is not found.
+func (s *CSVFileStorage) Read(ctx context.Context, ownerID int64, fileID string, options parser.ImportOptions) ([][]string, error) {
+	key, err := s.key(ownerID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	file, err := s.blobs.Get(ctx, key)
 	if err != nil {
 		return nil, err
 	}
@@ -111,15 +243,10 @@ func (s *CSVFileStorage) Read(fileName string) ([][]string, error) {
 	// close the file
 	defer file.Close()
 
-	// The csv.NewReader() function is called in
-	// which the object os.File passed as its parameter
-	// and this creates a new csv.Reader that reads
-	// from the file
-	reader := csv.NewReader(file)
-	reader.Comma = ';'
-
-	// ReadAll reads all the records from the CSV file
-	// and Returns them as slice of slices of string
-	// and an error if any
-	return reader.ReadAll()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	// The delimiter and the encoding of the csv files are detected, unless they are set in the options.
+	return parser.ParseImport(content, options)
 }
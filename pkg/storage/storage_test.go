@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/akosgarai/projectregister/pkg/config"
+	"github.com/akosgarai/projectregister/pkg/parser"
+)
+
+// newTestStorage creates a storage in a temporary directory with the given max size in megabytes.
+// It returns the storage and the directory.
+func newTestStorage(t *testing.T, maxSize string) (*CSVFileStorage, string) {
+	directory := t.TempDir()
+	envConfig := config.NewEnvironment(map[string]string{config.UploadMaxSizeEnvName: maxSize})
+	return NewCSVFileStorage(envConfig, NewLocalBlobStorage(directory)), directory
+}
+
+// newUpload returns the uploaded file of a multipart request with the given file name, content type and content.
+func newUpload(t *testing.T, filename, contentType string, content []byte) (multipart.File, *multipart.FileHeader) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="csvfile"; filename="`+filename+`"`)
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		t.Fatalf("Failed to create the part: %v", err)
+	}
+	part.Write(content)
+	writer.Close()
+	r, err := http.NewRequest(http.MethodPost, "/", body)
+	if err != nil {
+		t.Fatalf("Failed to create the request: %v", err)
+	}
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+	file, fileHeader, err := r.FormFile("csvfile")
+	if err != nil {
+		t.Fatalf("Failed to get the file: %v", err)
+	}
+	return file, fileHeader
+}
+
+// TestValidFileID tests that only the generated file id format is accepted.
+func TestValidFileID(t *testing.T) {
+	testData := []struct {
+		fileID   string
+		expected bool
+	}{
+		{strings.Repeat("a1", 32), true},
+		{strings.Repeat("a1", 31), false},
+		{strings.Repeat("A1", 32), false},
+		{"../../etc/passwd", false},
+		{strings.Repeat("a1", 31) + "/.", false},
+		{"", false},
+	}
+	for _, tt := range testData {
+		if got := ValidFileID(tt.fileID); got != tt.expected {
+			t.Errorf("ValidFileID(%q): expected %t, got %t", tt.fileID, tt.expected, got)
+		}
+	}
+}
+
+// TestCSVFileStorageSave tests that the saved file could be read and deleted only by its owner.
+func TestCSVFileStorageSave(t *testing.T) {
+	s, directory := newTestStorage(t, "1")
+	file, header := newUpload(t, "import.csv", "text/csv", []byte("client;project\nc1;p1\n"))
+	fileID, format, err := s.Save(context.Background(), 1, file, header)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if format != parser.FormatCSV {
+		t.Errorf("Expected csv format, got %s", format)
+	}
+	if !ValidFileID(fileID) {
+		t.Errorf("The file id is not valid: %s", fileID)
+	}
+	info, err := os.Stat(directory + "/imports/1_" + fileID)
+	if err != nil {
+		t.Fatalf("The file is not stored: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+	}
+	data, err := s.Read(context.Background(), 1, fileID, parser.ImportOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(data) != 2 || data[1][1] != "p1" {
+		t.Errorf("Unexpected data: %v", data)
+	}
+	if _, err := s.Read(context.Background(), 2, fileID, parser.ImportOptions{}); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected not exist error for an other owner, got %v", err)
+	}
+	if err := s.Delete(context.Background(), 2, fileID); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := s.Read(context.Background(), 1, fileID, parser.ImportOptions{}); err != nil {
+		t.Errorf("The file is deleted by an other owner: %v", err)
+	}
+	if err := s.Delete(context.Background(), 1, fileID); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := s.Read(context.Background(), 1, fileID, parser.ImportOptions{}); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected not exist error after the delete, got %v", err)
+	}
+	if _, err := s.Read(context.Background(), 1, "../"+fileID, parser.ImportOptions{}); !errors.Is(err, ErrInvalidFileID) {
+		t.Errorf("Expected invalid file id error, got %v", err)
+	}
+}
+
+// TestCSVFileStorageSaveFormats tests that the format is detected from the extension, and the file is parsed with it.
+func TestCSVFileStorageSaveFormats(t *testing.T) {
+	// the content is sniffed as zip by its local file header signature, but it is not a workbook.
+	emptyZip := []byte("PK\x03\x04" + strings.Repeat("\x00", 26))
+	testData := []struct {
+		filename    string
+		contentType string
+		content     []byte
+		format      string
+	}{
+		{"IMPORT.TXT", "text/plain", []byte("client;project\nc1;p1\n"), parser.FormatCSV},
+		{"import.json", "application/json", []byte(`[{"client": "c1", "project": "p1"}]`), parser.FormatJSON},
+		{"import.xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", emptyZip, parser.FormatXLSX},
+	}
+	for _, tt := range testData {
+		s, _ := newTestStorage(t, "1")
+		file, header := newUpload(t, tt.filename, tt.contentType, tt.content)
+		fileID, format, err := s.Save(context.Background(), 1, file, header)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.filename, err)
+		}
+		if format != tt.format {
+			t.Errorf("%s: expected %s format, got %s", tt.filename, tt.format, format)
+		}
+		data, err := s.Read(context.Background(), 1, fileID, parser.ImportOptions{Format: format})
+		if format == parser.FormatXLSX {
+			if !errors.Is(err, parser.ErrInvalidFile) {
+				t.Errorf("%s: expected invalid file error, got %v", tt.filename, err)
+			}
+			continue
+		}
+		if err != nil || len(data) != 2 || data[1][1] != "p1" {
+			t.Errorf("%s: unexpected data: %v, %v", tt.filename, data, err)
+		}
+	}
+}
+
+// TestCSVFileStorageSaveRejected tests that the too large files and the files with unexpected type are not stored.
+func TestCSVFileStorageSaveRejected(t *testing.T) {
+	testData := []struct {
+		name        string
+		filename    string
+		contentType string
+		content     []byte
+		expected    error
+	}{
+		{"too large", "import.csv", "text/csv", bytes.Repeat([]byte("a;b\n"), 1<<18+1), ErrFileTooLarge},
+		{"declared image", "import.csv", "image/png", []byte("a;b\n"), ErrInvalidFileType},
+		{"binary content", "import.csv", "application/octet-stream", []byte("\x89PNG\r\n\x1a\n\x00\x00"), ErrInvalidFileType},
+		{"invalid content type", "import.csv", "text/csv;;", []byte("a;b\n"), ErrInvalidFileType},
+		{"unknown extension", "import.xls", "application/vnd.ms-excel", []byte("a;b\n"), ErrInvalidFileType},
+		{"text workbook", "import.xlsx", "application/octet-stream", []byte("a;b\n"), ErrInvalidFileType},
+		{"declared csv json", "import.json", "text/csv", []byte("[]"), ErrInvalidFileType},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			s, directory := newTestStorage(t, "1")
+			file, header := newUpload(t, tt.filename, tt.contentType, tt.content)
+			if _, _, err := s.Save(context.Background(), 1, file, header); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+			entries, err := os.ReadDir(directory)
+			if err != nil {
+				t.Fatalf("Failed to read the directory: %v", err)
+			}
+			if len(entries) != 0 {
+				t.Errorf("Expected no stored file, got %d", len(entries))
+			}
+		})
+	}
+}
+
+// TestCSVFileStorageSaveLimitedContent tests that the content is limited even if the declared size is smaller.
+func TestCSVFileStorageSaveLimitedContent(t *testing.T) {
+	s, directory := newTestStorage(t, "1")
+	file, header := newUpload(t, "import.csv", "", bytes.Repeat([]byte("a;b\n"), 1<<18+1))
+	header.Size = 10
+	if _, _, err := s.Save(context.Background(), 1, file, header); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("Expected %v, got %v", ErrFileTooLarge, err)
+	}
+	entries, _ := os.ReadDir(directory)
+	if len(entries) != 0 {
+		t.Errorf("Expected no stored file, got %d", len(entries))
+	}
+}
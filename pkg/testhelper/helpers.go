@@ -1,6 +1,7 @@
 package testhelper
 
 import (
+	"context"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
@@ -9,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/akosgarai/projectregister/pkg/model"
+	"github.com/akosgarai/projectregister/pkg/parser"
 )
 
 var (
@@ -261,6 +263,11 @@ func (r *DomainRepositoryMock) CreateDomain(name string) (*model.Domain, error)
 	return r.LatestDomain, r.Error
 }
 
+// CreateDomainWithRegistration mocks the CreateDomainWithRegistration method.
+func (r *DomainRepositoryMock) CreateDomainWithRegistration(domain *model.Domain) (*model.Domain, error) {
+	return r.LatestDomain, r.Error
+}
+
 // GetDomainByName mocks the GetDomainByName method.
 func (r *DomainRepositoryMock) GetDomainByName(name string) (*model.Domain, error) {
 	return r.LatestDomain, r.Error
@@ -661,24 +668,30 @@ func (r *RepositoryContainerMock) GetUserRepository() model.UserRepository {
 type CSVStorageMock struct {
 	Error    error
 	FileName string
+	Format   string
 	Data     [][]string
 }
 
 // Save mocks the Save method.
-func (c CSVStorageMock) Save(file multipart.File) (string, error) {
-	return c.FileName, c.Error
+func (c CSVStorageMock) Save(ctx context.Context, ownerID int64, file multipart.File, header *multipart.FileHeader) (string, string, error) {
+	return c.FileName, c.Format, c.Error
 }
 
 // Delete mocks the Delete method.
-func (c CSVStorageMock) Delete(fileName string) error {
+func (c CSVStorageMock) Delete(ctx context.Context, ownerID int64, fileID string) error {
 	return c.Error
 }
 
 // Read mocks the Read method.
-func (c CSVStorageMock) Read(fileName string) ([][]string, error) {
+func (c CSVStorageMock) Read(ctx context.Context, ownerID int64, fileID string, options parser.ImportOptions) ([][]string, error) {
 	return c.Data, c.Error
 }
 
+// MaxSize mocks the MaxSize method.
+func (c CSVStorageMock) MaxSize() int64 {
+	return 10 << 20
+}
+
 // NewRequestWithSessionCookie creates a new request with the session cookie.
 func NewRequestWithSessionCookie(method, url string) (*http.Request, error) {
 	req, err := http.NewRequest(method, url, nil)
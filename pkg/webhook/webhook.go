@@ -0,0 +1,214 @@
+package webhook
+
+// This package contains the outgoing webhook dispatcher. It subscribes to the event bus,
+// and sends the events to the registered webhooks as signed JSON payloads.
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/event"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+const (
+	// SignatureHeader is the header of the payload signature.
+	SignatureHeader = "X-Webhook-Signature"
+	// EventHeader is the header of the event type.
+	EventHeader = "X-Webhook-Event"
+	// DeliveryHeader is the header of the event id. It is the same for the retries,
+	// so that the receivers could deduplicate the deliveries.
+	DeliveryHeader = "X-Webhook-Delivery"
+
+	// DefaultMaxAttempts is the default number of the delivery attempts.
+	DefaultMaxAttempts = 5
+	// DefaultBackoff is the default wait time before the first retry. It is doubled after every attempt.
+	DefaultBackoff = time.Second
+	// DefaultTimeout is the default timeout of a delivery attempt.
+	DefaultTimeout = 10 * time.Second
+
+	// maxErrorLength is the maximum length of the stored error message.
+	maxErrorLength = 512
+)
+
+// Sign returns the HMAC-SHA256 signature of the body in sha256=<hex> format.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatcher type delivers the events to the subscribed webhooks.
+// The deliveries are sent in the background, every attempt is logged to the delivery repository.
+type Dispatcher struct {
+	webhooks   model.WebhookRepository
+	deliveries model.WebhookDeliveryRepository
+	client     *http.Client
+	logger     *slog.Logger
+
+	// MaxAttempts is the number of the delivery attempts.
+	MaxAttempts int
+	// Backoff is the wait time before the first retry.
+	Backoff time.Duration
+
+	// ctx is cancelled by the Stop, so that the retries are not waited for on shutdown.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// mu guards the closed flag and the start of the dispatches, so that the Stop waits for every started one.
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher creates a new webhook dispatcher.
+// If the client is nil, a client with the default timeout is used.
+func NewDispatcher(webhooks model.WebhookRepository, deliveries model.WebhookDeliveryRepository, client *http.Client, logger *slog.Logger) *Dispatcher {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		ctx:         ctx,
+		cancel:      cancel,
+		webhooks:    webhooks,
+		deliveries:  deliveries,
+		client:      client,
+		logger:      logger,
+		MaxAttempts: DefaultMaxAttempts,
+		Backoff:     DefaultBackoff,
+	}
+}
+
+// Handle is the event bus handler. It starts the deliveries in the background, as the handler must not block
+// the publisher, eg. the request that changed the data. The subscribed webhooks are also queried there.
+// The payload is marshalled immediately, so that the later changes of the data are not sent.
+func (d *Dispatcher) Handle(e *event.Event) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		d.logger.Error("webhook: failed to marshal the event", "event", e.Type, "error", err)
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		d.logger.Warn("webhook: the dispatcher is stopped, the event is not delivered", "event", e.Type, "delivery", e.ID)
+		return
+	}
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.dispatch(d.ctx, e, payload)
+	}()
+}
+
+// dispatch gets the webhooks that are subscribed to the event, and delivers the payload to them concurrently.
+func (d *Dispatcher) dispatch(ctx context.Context, e *event.Event, payload []byte) {
+	filter := model.NewWebhookFilter()
+	filter.EventType = e.Type
+	webhooks, err := d.webhooks.GetWebhooks(filter)
+	if err != nil {
+		d.logger.Error("webhook: failed to get the webhooks of the event", "event", e.Type, "error", err)
+		return
+	}
+	for _, webhook := range *webhooks {
+		d.wg.Add(1)
+		go func(webhook *model.Webhook) {
+			defer d.wg.Done()
+			d.deliver(ctx, webhook, e, payload)
+		}(webhook)
+	}
+}
+
+// Close waits for the running deliveries, including their retries.
+func (d *Dispatcher) Close() {
+	d.wg.Wait()
+}
+
+// Stop stops the retries of the deliveries, and waits for the running attempts. It is called on shutdown,
+// so that the backoff of the failed deliveries does not delay it. The events that are published after the Stop are not delivered.
+func (d *Dispatcher) Stop() {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+	d.cancel()
+	d.wg.Wait()
+}
+
+// deliver sends the payload to the webhook until it succeeds, the attempts are exhausted or the context is cancelled.
+// The cancelled context stops only the wait before the next retry, the running attempt is finished and logged.
+func (d *Dispatcher) deliver(ctx context.Context, webhook *model.Webhook, e *event.Event, payload []byte) {
+	backoff := d.Backoff
+	for attempt := 1; attempt <= d.MaxAttempts; attempt++ {
+		delivery := d.attempt(webhook, e, payload)
+		delivery.Attempt = attempt
+		if _, err := d.deliveries.CreateWebhookDelivery(delivery); err != nil {
+			d.logger.Error("webhook: failed to log the delivery", "delivery", e.ID, "webhook_id", webhook.ID, "error", err)
+		}
+		if delivery.Succeeded() {
+			return
+		}
+		if attempt < d.MaxAttempts {
+			select {
+			case <-ctx.Done():
+				d.logger.Warn("webhook: the retries are stopped by the shutdown", "delivery", e.ID, "webhook_id", webhook.ID, "attempts", attempt)
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+	}
+	d.logger.Warn("webhook: giving up the delivery", "delivery", e.ID, "webhook_id", webhook.ID, "attempts", d.MaxAttempts)
+}
+
+// attempt sends the payload once and returns the result of the attempt.
+func (d *Dispatcher) attempt(webhook *model.Webhook, e *event.Event, payload []byte) *model.WebhookDelivery {
+	delivery := &model.WebhookDelivery{
+		WebhookID: webhook.ID,
+		EventID:   e.ID,
+		EventType: e.Type,
+		Payload:   string(payload),
+	}
+	request, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		delivery.Error = truncate(err.Error())
+		return delivery
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "projectregister-webhook")
+	request.Header.Set(EventHeader, e.Type)
+	request.Header.Set(DeliveryHeader, e.ID)
+	request.Header.Set(SignatureHeader, Sign(webhook.Secret, payload))
+	start := time.Now()
+	response, err := d.client.Do(request)
+	delivery.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		delivery.Error = truncate(err.Error())
+		return delivery
+	}
+	defer response.Body.Close()
+	// the body is drained for the connection reuse
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+	delivery.StatusCode = response.StatusCode
+	if !delivery.Succeeded() {
+		delivery.Error = "unexpected status code: " + strconv.Itoa(response.StatusCode)
+	}
+	return delivery
+}
+
+// truncate shortens the error message to the stored length.
+func truncate(message string) string {
+	if len(message) > maxErrorLength {
+		return message[:maxErrorLength]
+	}
+	return message
+}
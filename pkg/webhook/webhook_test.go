@@ -0,0 +1,201 @@
+package webhook
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akosgarai/projectregister/pkg/event"
+	"github.com/akosgarai/projectregister/pkg/model"
+)
+
+// webhookRepositoryStub returns the same webhooks for every filter.
+// If the blocked channel is set, the query waits until it is closed.
+type webhookRepositoryStub struct {
+	model.WebhookRepository
+	webhooks model.Webhooks
+	blocked  chan struct{}
+}
+
+func (r *webhookRepositoryStub) GetWebhooks(filter *model.WebhookFilter) (*model.Webhooks, error) {
+	if r.blocked != nil {
+		<-r.blocked
+	}
+	webhooks := model.Webhooks{}
+	for _, webhook := range r.webhooks {
+		if webhook.Active && webhook.Subscribes(filter.EventType) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return &webhooks, nil
+}
+
+// deliveryRepositoryStub stores the deliveries in memory.
+type deliveryRepositoryStub struct {
+	mu         sync.Mutex
+	deliveries model.WebhookDeliveries
+}
+
+func (r *deliveryRepositoryStub) CreateWebhookDelivery(delivery *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, delivery)
+	return delivery, nil
+}
+
+func (r *deliveryRepositoryStub) GetWebhookDeliveries(filter *model.WebhookDeliveryFilter) (*model.WebhookDeliveries, error) {
+	return &r.deliveries, nil
+}
+
+// TestSign tests the signature format.
+func TestSign(t *testing.T) {
+	signature := Sign("secret", []byte("payload"))
+	expected := "sha256=b82fcb791acec57859b989b430a826488ce2e479fdf92326bd0a2e8375a42ba4"
+	if signature != expected {
+		t.Errorf("Invalid signature. Expected: %s, got: %s", expected, signature)
+	}
+}
+
+// TestDispatcherHandle tests the signed delivery, the retry and the delivery log.
+func TestDispatcherHandle(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(SignatureHeader) != Sign("secret", body) {
+			t.Errorf("Invalid signature header: %s", r.Header.Get(SignatureHeader))
+		}
+		if r.Header.Get(EventHeader) != "domain.created" {
+			t.Errorf("Invalid event header: %s", r.Header.Get(EventHeader))
+		}
+		// the first attempt fails
+		if requests == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhooks := &webhookRepositoryStub{webhooks: model.Webhooks{
+		{ID: 1, URL: server.URL, Secret: "secret", EventTypes: []string{"domain.created"}, Active: true},
+		{ID: 2, URL: server.URL, Secret: "secret", EventTypes: []string{"domain.deleted"}, Active: true},
+		{ID: 3, URL: server.URL, Secret: "secret", EventTypes: []string{"domain.created"}, Active: false},
+	}}
+	deliveries := &deliveryRepositoryStub{}
+	dispatcher := NewDispatcher(webhooks, deliveries, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	dispatcher.Backoff = 0
+	dispatcher.Handle(event.New(event.ResourceDomain, event.ActionCreated, 1, &model.Domain{ID: 1, Name: "example.com"}))
+	dispatcher.Close()
+
+	if requests != 2 {
+		t.Fatalf("The failed delivery has to be retried. Requests: %d", requests)
+	}
+	if len(deliveries.deliveries) != 2 {
+		t.Fatalf("Every attempt has to be logged. Deliveries: %d", len(deliveries.deliveries))
+	}
+	first, second := deliveries.deliveries[0], deliveries.deliveries[1]
+	if first.Attempt != 1 || first.StatusCode != http.StatusInternalServerError || first.Error == "" {
+		t.Errorf("Invalid first delivery: %+v", first)
+	}
+	if second.Attempt != 2 || !second.Succeeded() || second.Error != "" || second.WebhookID != 1 {
+		t.Errorf("Invalid second delivery: %+v", second)
+	}
+	if first.EventID != second.EventID {
+		t.Errorf("The retries have to keep the event id. Got: %s, %s", first.EventID, second.EventID)
+	}
+}
+
+// TestDispatcherGivesUp tests that the delivery stops after the max attempts.
+func TestDispatcherGivesUp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	webhooks := &webhookRepositoryStub{webhooks: model.Webhooks{
+		{ID: 1, URL: server.URL, EventTypes: []string{"client.deleted"}, Active: true},
+	}}
+	deliveries := &deliveryRepositoryStub{}
+	dispatcher := NewDispatcher(webhooks, deliveries, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	dispatcher.Backoff = 0
+	dispatcher.MaxAttempts = 3
+	dispatcher.Handle(event.New(event.ResourceClient, event.ActionDeleted, 1, nil))
+	dispatcher.Close()
+
+	if len(deliveries.deliveries) != 3 {
+		t.Errorf("Invalid number of attempts: %d", len(deliveries.deliveries))
+	}
+}
+
+// TestDispatcherHandleDoesNotBlock tests that the webhooks are queried in the background, not in the publisher.
+func TestDispatcherHandleDoesNotBlock(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhooks := &webhookRepositoryStub{
+		webhooks: model.Webhooks{{ID: 1, URL: server.URL, EventTypes: []string{"client.created"}, Active: true}},
+		blocked:  make(chan struct{}),
+	}
+	deliveries := &deliveryRepositoryStub{}
+	dispatcher := NewDispatcher(webhooks, deliveries, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	handled := make(chan struct{})
+	go func() {
+		dispatcher.Handle(event.New(event.ResourceClient, event.ActionCreated, 1, nil))
+		close(handled)
+	}()
+	select {
+	case <-handled:
+	case <-time.After(time.Second):
+		t.Fatal("The handler is blocked by the webhook query.")
+	}
+	close(webhooks.blocked)
+	dispatcher.Close()
+
+	if len(deliveries.deliveries) != 1 {
+		t.Errorf("Invalid number of deliveries: %d", len(deliveries.deliveries))
+	}
+}
+
+// TestDispatcherStop tests that the Stop does not wait for the backoff of the failed deliveries,
+// and the events after the Stop are not delivered.
+func TestDispatcherStop(t *testing.T) {
+	attempted := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		attempted <- struct{}{}
+	}))
+	defer server.Close()
+
+	webhooks := &webhookRepositoryStub{webhooks: model.Webhooks{
+		{ID: 1, URL: server.URL, EventTypes: []string{"client.created"}, Active: true},
+	}}
+	deliveries := &deliveryRepositoryStub{}
+	dispatcher := NewDispatcher(webhooks, deliveries, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	dispatcher.Backoff = time.Hour
+	dispatcher.Handle(event.New(event.ResourceClient, event.ActionCreated, 1, nil))
+	<-attempted
+	stopped := make(chan struct{})
+	go func() {
+		dispatcher.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("The Stop waits for the backoff.")
+	}
+	dispatcher.Handle(event.New(event.ResourceClient, event.ActionCreated, 2, nil))
+	if len(deliveries.deliveries) != 1 {
+		t.Errorf("Invalid number of deliveries: %d", len(deliveries.deliveries))
+	}
+}
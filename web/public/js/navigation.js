@@ -0,0 +1,8 @@
+// The side menu toggle. It is not inlined, as the content security policy blocks the inline scripts.
+document.addEventListener('DOMContentLoaded', function () {
+	document.querySelectorAll('.nav-toggle a').forEach(function (toggle) {
+		toggle.addEventListener('click', function () {
+			document.querySelector('.container').classList.toggle('closed');
+		});
+	});
+});
@@ -0,0 +1,35 @@
+package web
+
+// This package contains the embedded templates and static files of the application,
+// so that the binary does not depend on the web directory.
+
+import (
+	"embed"
+	"io/fs"
+)
+
+var (
+	//go:embed template
+	templateFiles embed.FS
+	//go:embed public
+	staticFiles embed.FS
+)
+
+// Templates returns the embedded template directory.
+func Templates() fs.FS {
+	return mustSub(templateFiles, "template")
+}
+
+// Static returns the embedded static directory.
+func Static() fs.FS {
+	return mustSub(staticFiles, "public")
+}
+
+// mustSub returns the subdirectory of the embedded files. The directories are embedded, so it could not fail.
+func mustSub(files embed.FS, dir string) fs.FS {
+	sub, err := fs.Sub(files, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}